@@ -0,0 +1,75 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/umutaraz/tradesman-app/internal/config"
+	"github.com/umutaraz/tradesman-app/internal/database"
+)
+
+const adminUsage = `Kullanım: tradesman-app admin <komut>
+
+Komutlar:
+  list               sistem yöneticilerini listeler
+  grant <e-posta>    hesaba sistem yöneticiliği verir (yedekleri yönetebilir)
+  revoke <e-posta>   hesabın sistem yöneticiliğini geri alır`
+
+// runAdmin "admin" alt komutunu çalıştırır; veritabanı yolu DATABASE_PATH ile belirlenir
+func runAdmin(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New(adminUsage)
+	}
+
+	db, err := database.Initialize(cfg.DatabasePath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return adminCommand(db, os.Stdout, args)
+}
+
+// adminCommand yönetici komutunu açık veritabanında çalıştırır ve sonucu out'a yazar
+func adminCommand(db *database.DB, out io.Writer, args []string) error {
+	switch args[0] {
+	case "list":
+		admins, err := db.SystemAdmins()
+		if err != nil {
+			return err
+		}
+		if len(admins) == 0 {
+			fmt.Fprintln(out, "Sistem yöneticisi yok; yedekler yönetilemez ve otomatik yedek alınmaz")
+			return nil
+		}
+		w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tAD\tE-POSTA")
+		for _, u := range admins {
+			fmt.Fprintf(w, "%d\t%s\t%s\n", u.ID, u.Name, u.Email)
+		}
+		return w.Flush()
+	case "grant", "revoke":
+		if len(args) < 2 {
+			return errors.New(adminUsage)
+		}
+		grant := args[0] == "grant"
+		user, err := db.SetSystemAdmin(args[1], grant)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("bu e-posta adresiyle kayıtlı hesap yok: %s", args[1])
+		}
+		if err != nil {
+			return err
+		}
+		if grant {
+			fmt.Fprintf(out, "%s <%s> sistem yöneticisi yapıldı\n", user.Name, user.Email)
+		} else {
+			fmt.Fprintf(out, "%s <%s> artık sistem yöneticisi değil\n", user.Name, user.Email)
+		}
+		return nil
+	}
+	return errors.New(adminUsage)
+}
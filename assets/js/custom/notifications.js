@@ -0,0 +1,55 @@
+// Kenar menüdeki okunmamış bildirim rozeti ve anlık bildirimler (Server-Sent Events).
+// Yeni bildirimler toastr ile gösterilir ve "notification:received" olayıyla sayfaya iletilir.
+(function () {
+    if (!window.EventSource) {
+        return;
+    }
+
+    const menuLink = document.querySelector('a.menu-link[href="/notifications"]');
+    let badge = null;
+    let unread = 0;
+
+    function setUnread(count) {
+        unread = Math.max(0, count);
+        if (!menuLink) {
+            return;
+        }
+        if (!badge) {
+            badge = document.createElement('span');
+            badge.className = 'menu-badge';
+            badge.innerHTML = '<span class="badge badge-circle badge-danger"></span>';
+            menuLink.appendChild(badge);
+        }
+        badge.firstChild.textContent = unread > 99 ? '99+' : unread;
+        badge.style.display = unread > 0 ? '' : 'none';
+    }
+
+    // Sayfadaki işlemler (okundu işaretleme vb.) rozeti güncelleyebilsin
+    window.setUnreadNotifications = setUnread;
+
+    const toastTypes = { success: 'success', warning: 'warning', danger: 'error', info: 'info' };
+
+    const source = new EventSource('/api/v1/notifications/stream');
+
+    source.addEventListener('unread', function (e) {
+        setUnread(JSON.parse(e.data).unread_count);
+    });
+
+    source.addEventListener('notification', function (e) {
+        const notification = JSON.parse(e.data);
+        setUnread(unread + 1);
+
+        if (window.toastr) {
+            const show = toastr[toastTypes[notification.severity] || 'info'];
+            show(notification.message, notification.title, {
+                onclick: notification.link ? function () { window.location.href = notification.link; } : null
+            });
+        }
+
+        document.dispatchEvent(new CustomEvent('notification:received', { detail: notification }));
+    });
+
+    window.addEventListener('beforeunload', function () {
+        source.close();
+    });
+})();
@@ -0,0 +1,93 @@
+// Üst menüdeki arama kutusu: yazılan metni /api/v1/search ile arar ve sonuçları kutunun altında
+// listeler. Enter ilk sonuca gider; sonuçlar kullanıcının yetkili olduğu kayıtlarla sınırlıdır.
+(function () {
+    const container = document.getElementById('kt_header_search');
+    if (!container) {
+        return;
+    }
+    const form = container.querySelector('form');
+    const input = container.querySelector('input[name="search"]');
+    if (!form || !input) {
+        return;
+    }
+
+    const typeLabels = { customer: 'Müşteri', product: 'Ürün', order: 'Sipariş', invoice: 'Fatura' };
+    const typeIcons = { customer: 'ki-people', product: 'ki-package', order: 'ki-handcart', invoice: 'ki-document' };
+
+    const menu = document.createElement('div');
+    menu.className = 'position-absolute w-100 bg-body shadow rounded py-2 mt-1 d-none';
+    menu.style.zIndex = 1000;
+    menu.style.top = '100%';
+    form.appendChild(menu);
+
+    let results = [];
+    let timer = null;
+    let pending = null;
+
+    function escapeHTML(value) {
+        const div = document.createElement('div');
+        div.textContent = value || '';
+        return div.innerHTML;
+    }
+
+    function render() {
+        if (!input.value.trim()) {
+            menu.classList.add('d-none');
+            return;
+        }
+        if (results.length === 0) {
+            menu.innerHTML = '<div class="px-4 py-2 text-muted">Sonuç bulunamadı</div>';
+        } else {
+            menu.innerHTML = results.map(function (r) {
+                return '<a href="' + r.url + '" class="d-flex align-items-center px-4 py-2 text-gray-800 text-hover-primary bg-hover-light">' +
+                    '<i class="ki-outline ' + (typeIcons[r.type] || 'ki-magnifier') + ' fs-2 text-gray-500 me-3"></i>' +
+                    '<span class="d-flex flex-column">' +
+                    '<span class="fw-semibold">' + escapeHTML(r.title) + '</span>' +
+                    '<span class="text-muted fs-7">' + escapeHTML(typeLabels[r.type] || r.type) +
+                    (r.subtitle ? ' · ' + escapeHTML(r.subtitle) : '') + '</span>' +
+                    '</span></a>';
+            }).join('');
+        }
+        menu.classList.remove('d-none');
+    }
+
+    function search() {
+        const query = input.value.trim();
+        if (pending) {
+            pending.abort();
+        }
+        if (!query) {
+            results = [];
+            render();
+            return;
+        }
+
+        pending = new AbortController();
+        fetch('/api/v1/search?limit=10&q=' + encodeURIComponent(query), { signal: pending.signal })
+            .then(response => response.ok ? response.json() : [])
+            .then(data => {
+                results = data;
+                render();
+            })
+            .catch(() => {});
+    }
+
+    input.addEventListener('input', function () {
+        clearTimeout(timer);
+        timer = setTimeout(search, 200);
+    });
+
+    form.addEventListener('submit', function (e) {
+        e.preventDefault();
+        if (results.length > 0) {
+            window.location.href = results[0].url;
+        }
+    });
+
+    document.addEventListener('click', function (e) {
+        if (!container.contains(e.target)) {
+            menu.classList.add('d-none');
+        }
+    });
+    input.addEventListener('focus', render);
+})();
@@ -5,8 +5,13 @@ import (
 	"time"
 
 	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/money"
+	"golang.org/x/crypto/bcrypt"
 )
 
+// Örnek kullanıcının giriş şifresi
+const demoPassword = "esnaf123"
+
 func main() {
 	// Veritabanını başlat
 	db, err := database.Initialize("./tradesman.db")
@@ -16,14 +21,25 @@ func main() {
 	defer db.Close()
 
 	// Örnek kullanıcı
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(demoPassword), bcrypt.DefaultCost)
+	if err != nil {
+		log.Fatal("Şifre oluşturma hatası:", err)
+	}
+
 	_, err = db.Exec(`
 		INSERT OR IGNORE INTO users (id, name, email, password_hash, business_name, phone, address) 
-		VALUES (1, 'Ahmet Yılmaz', 'ahmet@example.com', 'hash123', 'Yılmaz Elektrik', '0532 123 45 67', 'İstanbul/Kadıköy')
-	`)
+		VALUES (1, 'Ahmet Yılmaz', 'ahmet@example.com', ?, 'Yılmaz Elektrik', '0532 123 45 67', 'İstanbul/Kadıköy')
+	`, string(passwordHash))
 	if err != nil {
 		log.Printf("Kullanıcı ekleme hatası: %v", err)
 	}
 
+	// Eski seed ile oluşturulmuş, giriş yapılamayan yer tutucu şifreyi güncelle
+	_, err = db.Exec("UPDATE users SET password_hash = ? WHERE id = 1 AND password_hash = 'hash123'", string(passwordHash))
+	if err != nil {
+		log.Printf("Kullanıcı şifresi güncelleme hatası: %v", err)
+	}
+
 	// Örnek müşteriler
 	customers := []struct {
 		name    string
@@ -53,55 +69,144 @@ func main() {
 	products := []struct {
 		name        string
 		description string
-		price       float64
+		price       money.Money
 		category    string
 		stock       int
 		unit        string
+		isService   bool
 	}{
-		{"LED Ampul 12W", "Beyaz ışık LED ampul", 25.50, "Aydınlatma", 100, "adet"},
-		{"Elektrik Kablosu 2.5mm", "NYA kablo 2.5mm²", 5.75, "Kablo", 500, "metre"},
-		{"Priz Takımı", "Beyaz priz ve anahtar takımı", 35.00, "Elektrik Malzemesi", 50, "takım"},
-		{"Elektrik Panosu", "6'lı sigorta panosu", 120.00, "Panel", 20, "adet"},
-		{"Tesisat Hizmeti", "Ev elektrik tesisatı kurulumu", 500.00, "Hizmet", 0, "iş"},
-		{"Spot LED", "3W spot LED", 15.00, "Aydınlatma", 80, "adet"},
-		{"Kablo Kanalı", "16x16 beyaz kablo kanalı", 8.50, "Aksesuar", 200, "metre"},
-		{"Dimmer Anahtar", "LED uyumlu dimmer", 85.00, "Elektrik Malzemesi", 25, "adet"},
+		{"LED Ampul 12W", "Beyaz ışık LED ampul", money.TL(2550), "Aydınlatma", 100, "adet", false},
+		{"Elektrik Kablosu 2.5mm", "NYA kablo 2.5mm²", money.TL(575), "Kablo", 500, "metre", false},
+		{"Priz Takımı", "Beyaz priz ve anahtar takımı", money.TL(3500), "Elektrik Malzemesi", 50, "takım", false},
+		{"Elektrik Panosu", "6'lı sigorta panosu", money.TL(12000), "Panel", 20, "adet", false},
+		{"Tesisat Hizmeti", "Ev elektrik tesisatı kurulumu", money.TL(50000), "Hizmet", 0, "iş", true},
+		{"Spot LED", "3W spot LED", money.TL(1500), "Aydınlatma", 80, "adet", false},
+		{"Kablo Kanalı", "16x16 beyaz kablo kanalı", money.TL(850), "Aksesuar", 200, "metre", false},
+		{"Dimmer Anahtar", "LED uyumlu dimmer", money.TL(8500), "Elektrik Malzemesi", 25, "adet", false},
 	}
 
 	for _, product := range products {
 		_, err = db.Exec(`
-			INSERT OR IGNORE INTO products (user_id, name, description, price, category, stock_quantity, unit, created_at, updated_at) 
-			VALUES (1, ?, ?, ?, ?, ?, ?, ?, ?)
-		`, product.name, product.description, product.price, product.category, product.stock, product.unit, time.Now(), time.Now())
+			INSERT OR IGNORE INTO products (user_id, name, description, price, category, stock_quantity, unit, is_service, created_at, updated_at) 
+			VALUES (1, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, product.name, product.description, product.price, product.category, product.stock, product.unit, product.isService, time.Now(), time.Now())
 		if err != nil {
 			log.Printf("Ürün ekleme hatası: %v", err)
 		}
 	}
 
-	// Örnek siparişler
+	// Örnek siparişler; tutarlar kalemlerden hesaplanır ve stoktan düşülür
+	type orderLine struct {
+		productName string
+		quantity    int
+	}
 	orders := []struct {
 		customerID   int
 		orderNumber  string
 		status       string
-		totalAmount  float64
 		notes        string
 		orderDate    time.Time
 		deliveryDate *time.Time
+		items        []orderLine
 	}{
-		{1, "SIP-2024-001", "completed", 250.00, "Acil teslimat", time.Now().AddDate(0, 0, -5), nil},
-		{2, "SIP-2024-002", "pending", 180.50, "Stok bekliyor", time.Now().AddDate(0, 0, -2), nil},
-		{3, "SIP-2024-003", "processing", 95.00, "Hazırlanıyor", time.Now().AddDate(0, 0, -1), nil},
-		{4, "SIP-2024-004", "shipped", 420.00, "Kargo verildi", time.Now(), nil},
-		{1, "SIP-2024-005", "pending", 75.25, "Yeni sipariş", time.Now(), nil},
+		{1, "SIP-2024-001", "completed", "Acil teslimat", time.Now().AddDate(0, 0, -5), nil,
+			[]orderLine{{"LED Ampul 12W", 4}, {"Priz Takımı", 2}, {"Kablo Kanalı", 10}}},
+		{2, "SIP-2024-002", "pending", "Stok bekliyor", time.Now().AddDate(0, 0, -2), nil,
+			[]orderLine{{"Elektrik Kablosu 2.5mm", 20}, {"Spot LED", 4}}},
+		{3, "SIP-2024-003", "processing", "Hazırlanıyor", time.Now().AddDate(0, 0, -1), nil,
+			[]orderLine{{"Dimmer Anahtar", 1}}},
+		{4, "SIP-2024-004", "shipped", "Kargo verildi", time.Now(), nil,
+			[]orderLine{{"Elektrik Panosu", 2}, {"Priz Takımı", 4}}},
+		{1, "SIP-2024-005", "pending", "Yeni sipariş", time.Now(), nil,
+			[]orderLine{{"Spot LED", 5}}},
 	}
 
 	for _, order := range orders {
-		_, err = db.Exec(`
+		tx, err := db.Begin()
+		if err != nil {
+			log.Fatal("İşlem başlatma hatası:", err)
+		}
+
+		result, err := tx.Exec(`
 			INSERT OR IGNORE INTO orders (user_id, customer_id, order_number, status, total_amount, notes, order_date, delivery_date, created_at, updated_at) 
-			VALUES (1, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-		`, order.customerID, order.orderNumber, order.status, order.totalAmount, order.notes, order.orderDate, order.deliveryDate, time.Now(), time.Now())
+			VALUES (1, ?, ?, ?, 0, ?, ?, ?, ?, ?)
+		`, order.customerID, order.orderNumber, order.status, order.notes, order.orderDate, order.deliveryDate, time.Now(), time.Now())
 		if err != nil {
 			log.Printf("Sipariş ekleme hatası: %v", err)
+			tx.Rollback()
+			continue
+		}
+
+		// Sipariş zaten varsa kalemleri tekrar ekleme
+		if n, _ := result.RowsAffected(); n == 0 {
+			tx.Rollback()
+			continue
+		}
+		orderID, _ := result.LastInsertId()
+
+		var total money.Money
+		for _, line := range order.items {
+			var productID int
+			var price money.Money
+			err := tx.QueryRow("SELECT id, price FROM products WHERE user_id = 1 AND name = ? ORDER BY id LIMIT 1", line.productName).
+				Scan(&productID, &price)
+			if err != nil {
+				log.Printf("Ürün bulunamadı (%s): %v", line.productName, err)
+				continue
+			}
+
+			lineTotal := price.Times(line.quantity)
+			total = total.Add(lineTotal)
+
+			_, err = tx.Exec(`
+				INSERT INTO order_items (order_id, product_id, quantity, unit_price, total_price)
+				VALUES (?, ?, ?, ?, ?)
+			`, orderID, productID, line.quantity, price, lineTotal)
+			if err != nil {
+				log.Printf("Sipariş kalemi ekleme hatası: %v", err)
+			}
+
+			_, err = tx.Exec("UPDATE products SET stock_quantity = stock_quantity - ? WHERE id = ? AND is_service = 0", line.quantity, productID)
+			if err != nil {
+				log.Printf("Stok güncelleme hatası: %v", err)
+			}
+		}
+
+		if _, err := tx.Exec("UPDATE orders SET total_amount = ? WHERE id = ?", total, orderID); err != nil {
+			log.Printf("Sipariş tutarı güncelleme hatası: %v", err)
+		}
+
+		// Tamamlanan siparişin gelir kaydı
+		if order.status == "completed" {
+			_, err = tx.Exec(`
+				INSERT INTO transactions (user_id, type, category, amount, description, transaction_date, order_id, created_at) 
+				VALUES (1, 'income', 'Satış', ?, ?, ?, ?, ?)
+			`, total, "Sipariş "+order.orderNumber, order.orderDate, orderID, time.Now())
+			if err != nil {
+				log.Printf("Gelir kaydı ekleme hatası: %v", err)
+			}
+		}
+
+		// Durum geçmişinin ilk kaydı
+		_, err = tx.Exec(`
+			INSERT INTO order_status_history (order_id, from_status, to_status, changed_by, note, created_at)
+			VALUES (?, '', ?, 1, '', ?)
+		`, orderID, order.status, order.orderDate)
+		if err != nil {
+			log.Printf("Durum geçmişi ekleme hatası: %v", err)
+		}
+
+		// Sipariş numarası sayacı örnek numaraların gerisinde kalmasın
+		_, err = tx.Exec(`
+			INSERT INTO order_sequences (user_id, year, last_number) VALUES (1, ?, ?)
+			ON CONFLICT (user_id, year) DO UPDATE SET last_number = MAX(last_number, excluded.last_number)
+		`, order.orderNumber[4:8], order.orderNumber[9:])
+		if err != nil {
+			log.Printf("Sipariş sayacı güncelleme hatası: %v", err)
+		}
+
+		if err := tx.Commit(); err != nil {
+			log.Printf("Sipariş kaydetme hatası: %v", err)
 		}
 	}
 
@@ -109,17 +214,17 @@ func main() {
 	transactions := []struct {
 		transactionType string
 		category        string
-		amount          float64
+		amount          money.Money
 		description     string
 		date            time.Time
 	}{
-		{"income", "Satış", 2500.00, "LED ampul satışları", time.Now().AddDate(0, 0, -10)},
-		{"expense", "Alım", 1200.00, "Yeni stok alımı", time.Now().AddDate(0, 0, -8)},
-		{"income", "Hizmet", 1500.00, "Tesisat kurulum işi", time.Now().AddDate(0, 0, -5)},
-		{"expense", "Kira", 3000.00, "Mağaza kirası", time.Now().AddDate(0, 0, -3)},
-		{"income", "Satış", 850.00, "Elektrik malzeme satışı", time.Now().AddDate(0, 0, -2)},
-		{"expense", "Yakıt", 200.00, "Araç yakıtı", time.Now().AddDate(0, 0, -1)},
-		{"income", "Satış", 450.00, "Bugünkü satışlar", time.Now()},
+		{"income", "Satış", money.TL(250000), "LED ampul satışları", time.Now().AddDate(0, 0, -10)},
+		{"expense", "Alım", money.TL(120000), "Yeni stok alımı", time.Now().AddDate(0, 0, -8)},
+		{"income", "Hizmet", money.TL(150000), "Tesisat kurulum işi", time.Now().AddDate(0, 0, -5)},
+		{"expense", "Kira", money.TL(300000), "Mağaza kirası", time.Now().AddDate(0, 0, -3)},
+		{"income", "Satış", money.TL(85000), "Elektrik malzeme satışı", time.Now().AddDate(0, 0, -2)},
+		{"expense", "Yakıt", money.TL(20000), "Araç yakıtı", time.Now().AddDate(0, 0, -1)},
+		{"income", "Satış", money.TL(45000), "Bugünkü satışlar", time.Now()},
 	}
 
 	for _, tx := range transactions {
@@ -132,6 +237,40 @@ func main() {
 		}
 	}
 
+	// Örnek randevular; tekrar çalıştırıldığında çoğaltılmaz
+	var appointmentCount int
+	if err := db.QueryRow("SELECT COUNT(*) FROM appointments WHERE user_id = 1").Scan(&appointmentCount); err != nil {
+		log.Printf("Randevu sayısı okunamadı: %v", err)
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	appointments := []struct {
+		customerID  int
+		title       string
+		description string
+		start       time.Time
+		duration    time.Duration
+		status      string
+	}{
+		{1, "Elektrik tesisatı kontrolü", "Daire içi sigorta kutusu kontrolü", today.Add(10 * time.Hour), time.Hour, "confirmed"},
+		{4, "Ofis aydınlatma keşfi", "LED dönüşüm için keşif", today.Add(14 * time.Hour), 90 * time.Minute, "new"},
+		{3, "Avize montajı", "", today.AddDate(0, 0, 2).Add(11 * time.Hour), time.Hour, "new"},
+	}
+
+	if appointmentCount == 0 {
+		for _, a := range appointments {
+			_, err = db.Exec(`
+				INSERT INTO appointments (user_id, customer_id, staff_id, title, description, start_time, end_time, status, reminder)
+				VALUES (1, ?, 1, ?, ?, ?, ?, ?, 60)
+			`, a.customerID, a.title, a.description, a.start, a.start.Add(a.duration), a.status)
+			if err != nil {
+				log.Printf("Randevu ekleme hatası: %v", err)
+			}
+		}
+	}
+
 	log.Println("Örnek veriler başarıyla eklendi!")
-	log.Println("5 müşteri, 8 ürün, 5 sipariş ve 7 finansal işlem eklendi.")
+	log.Println("5 müşteri, 8 ürün, 5 sipariş, 7 finansal işlem ve 3 randevu eklendi.")
+	log.Printf("Giriş bilgileri: ahmet@example.com / %s", demoPassword)
 }
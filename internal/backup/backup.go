@@ -0,0 +1,412 @@
+// Package backup SQLite veritabanının yedeklerini alır, saklar ve geri yükler.
+//
+// Yedekler VACUUM INTO ile çalışan veritabanından tutarlı bir anlık görüntü olarak alınır; yazma
+// işlemleri durdurulmaz. Yedek dosyaları isteğe bağlı olarak gzip ile sıkıştırılır ve AES-256-GCM
+// ile şifrelenir. Geri yükleme dosyayı doğruladıktan sonra SQLite online backup API'siyle çalışan
+// veritabanının üzerine kopyalar; böylece açık bağlantılar kapatılmadan yeni içeriği görür.
+//
+// Veritabanı tek dosya olduğundan yedekler işletme başına değil, kurulumun tamamı için alınır.
+package backup
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/umutaraz/tradesman-app/internal/database"
+)
+
+// Kind yedeğin alınma nedeni
+type Kind string
+
+const (
+	KindAuto       Kind = "auto"        // Zamanlayıcının aldığı yedek; saklama sınırına göre silinir
+	KindManual     Kind = "manual"      // Kullanıcının aldığı yedek
+	KindPreRestore Kind = "pre-restore" // Geri yüklemeden hemen önce alınan güvenlik yedeği
+)
+
+var (
+	// ErrNotFound yedek dosyası yoksa ya da adı geçersizse döner
+	ErrNotFound = errors.New("yedek bulunamadı")
+	// ErrInvalid geri yüklenecek dosya bu uygulamanın geçerli bir yedeği değilse döner
+	ErrInvalid = errors.New("geçersiz yedek dosyası")
+)
+
+// nameLayout yedek adındaki zaman damgasının biçimi
+const nameLayout = "20060102-150405"
+
+// namePattern yedek dosya adları: tradesman-20241017-030000-auto.db.gz.enc
+var namePattern = regexp.MustCompile(`^tradesman-(\d{8}-\d{6})-(auto|manual|pre-restore)\.db(\.gz)?(\.enc)?$`)
+
+// Info bir yedek dosyasının bilgileri
+type Info struct {
+	Name       string    `json:"name"`
+	Kind       Kind      `json:"kind"`
+	Size       int64     `json:"size"`
+	Compressed bool      `json:"compressed"`
+	Encrypted  bool      `json:"encrypted"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Config yedekleme yapılandırması
+type Config struct {
+	Dir      string // Yedeklerin saklandığı dizin
+	Compress bool   // Yedekler gzip ile sıkıştırılır
+	Key      string // Boş değilse yedekler bu parolanın SHA-256 özetiyle AES-256-GCM ile şifrelenir
+}
+
+// Manager yedek alma, listeleme ve geri yükleme işlemlerini yürütür. Aynı anda yalnızca bir
+// yedekleme ya da geri yükleme çalışır.
+type Manager struct {
+	db       *database.DB
+	dir      string
+	compress bool
+	key      []byte
+	mu       sync.Mutex
+}
+
+// New yedek dizinini oluşturur ve yeni bir Manager döndürür
+func New(db *database.DB, cfg Config) (*Manager, error) {
+	if err := os.MkdirAll(cfg.Dir, 0o700); err != nil {
+		return nil, fmt.Errorf("yedek dizini oluşturulamadı: %w", err)
+	}
+
+	m := &Manager{db: db, dir: cfg.Dir, compress: cfg.Compress}
+	if cfg.Key != "" {
+		sum := sha256.Sum256([]byte(cfg.Key))
+		m.key = sum[:]
+	}
+	return m, nil
+}
+
+// AdminID kurulumun ilk sistem yöneticisini döndürür; otomatik yedekleme ayarları bu hesabın
+// ayarlarından okunur. Yönetici yoksa sql.ErrNoRows döner.
+func (m *Manager) AdminID() (int, error) {
+	var id int
+	err := m.db.QueryRow("SELECT id FROM users WHERE system_admin = 1 ORDER BY id LIMIT 1").Scan(&id)
+	return id, err
+}
+
+// Create çalışan veritabanının yedeğini alır
+func (m *Manager) Create(kind Kind) (Info, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.create(kind, time.Now())
+}
+
+func (m *Manager) create(kind Kind, now time.Time) (Info, error) {
+	ext := ".db"
+	if m.compress {
+		ext += ".gz"
+	}
+	if m.key != nil {
+		ext += ".enc"
+	}
+
+	// Aynı saniyede alınan yedekler birbirinin üzerine yazmasın
+	var name string
+	for {
+		name = fmt.Sprintf("tradesman-%s-%s%s", now.Format(nameLayout), kind, ext)
+		if _, err := os.Stat(filepath.Join(m.dir, name)); os.IsNotExist(err) {
+			break
+		}
+		now = now.Add(time.Second)
+	}
+
+	snapshot := filepath.Join(m.dir, "."+name+".snapshot")
+	os.Remove(snapshot)
+	defer os.Remove(snapshot)
+	if _, err := m.db.Exec("VACUUM INTO ?", snapshot); err != nil {
+		return Info{}, fmt.Errorf("yedek alınamadı: %w", err)
+	}
+
+	// Dosya tamamlanmadan listede görünmesin diye önce geçici adla yazılır
+	path := filepath.Join(m.dir, name)
+	partial := filepath.Join(m.dir, "."+name+".part")
+	defer os.Remove(partial)
+	if err := m.encodeFile(snapshot, partial); err != nil {
+		return Info{}, fmt.Errorf("yedek yazılamadı: %w", err)
+	}
+	if err := os.Rename(partial, path); err != nil {
+		return Info{}, err
+	}
+
+	return m.stat(name)
+}
+
+// List yedekleri yeniden eskiye döndürür
+func (m *Manager) List() ([]Info, error) {
+	entries, err := os.ReadDir(m.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	backups := []Info{}
+	for _, entry := range entries {
+		if entry.IsDir() || !namePattern.MatchString(entry.Name()) {
+			continue
+		}
+		info, err := m.stat(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		backups = append(backups, info)
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		if !backups[i].CreatedAt.Equal(backups[j].CreatedAt) {
+			return backups[i].CreatedAt.After(backups[j].CreatedAt)
+		}
+		return backups[i].Name > backups[j].Name
+	})
+	return backups, nil
+}
+
+// Path yedek dosyasının yolunu döndürür; ad geçersizse ya da dosya yoksa ErrNotFound döner
+func (m *Manager) Path(name string) (string, error) {
+	if !namePattern.MatchString(name) {
+		return "", ErrNotFound
+	}
+	path := filepath.Join(m.dir, name)
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+	return path, nil
+}
+
+// Delete yedeği siler
+func (m *Manager) Delete(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	path, err := m.Path(name)
+	if err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// Prune en yeni keep tanesi dışındaki otomatik yedekleri siler ve silinen sayısını döndürür. Elle
+// alınan yedekler ve geri yükleme öncesi güvenlik yedekleri silinmez.
+func (m *Manager) Prune(keep int) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	backups, err := m.List()
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	kept := 0
+	for _, b := range backups {
+		if b.Kind != KindAuto {
+			continue
+		}
+		if kept < keep {
+			kept++
+			continue
+		}
+		if err := os.Remove(filepath.Join(m.dir, b.Name)); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// RestoreFile kayıtlı bir yedeği geri yükler
+func (m *Manager) RestoreFile(name string) (Info, error) {
+	path, err := m.Path(name)
+	if err != nil {
+		return Info{}, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return Info{}, err
+	}
+	defer f.Close()
+
+	return m.Restore(f)
+}
+
+// Restore yedeği doğrular ve çalışan veritabanının yerine koyar. Önce mevcut veritabanının güvenlik
+// yedeği alınır ve bilgileri döndürülür; eski sürümden alınmış yedeğin şeması güncellenir.
+func (m *Manager) Restore(r io.Reader) (Info, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	restore := filepath.Join(m.dir, fmt.Sprintf(".restore-%d.db", time.Now().UnixNano()))
+	defer os.Remove(restore)
+
+	f, err := os.OpenFile(restore, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return Info{}, err
+	}
+	err = m.decode(r, f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return Info{}, err
+	}
+
+	if err := validate(restore); err != nil {
+		return Info{}, err
+	}
+
+	safety, err := m.create(KindPreRestore, time.Now())
+	if err != nil {
+		return Info{}, err
+	}
+
+	if err := m.copyInto(restore); err != nil {
+		return safety, fmt.Errorf("yedek geri yüklenemedi: %w", err)
+	}
+	if _, err := m.db.MigrateUp(); err != nil {
+		return safety, fmt.Errorf("geri yüklenen veritabanının şeması güncellenemedi: %w", err)
+	}
+	return safety, nil
+}
+
+// validate dosyanın bozulmamış ve bu uygulamanın, en fazla çalışan sürüm kadar yeni bir
+// veritabanı olduğunu doğrular
+func validate(path string) error {
+	db, err := database.Open(path)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	defer db.Close()
+
+	var result string
+	if err := db.QueryRow("PRAGMA integrity_check").Scan(&result); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	if result != "ok" {
+		return fmt.Errorf("%w: veritabanı bozuk (%s)", ErrInvalid, result)
+	}
+
+	version, err := db.SchemaVersion()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	if version == 0 {
+		return fmt.Errorf("%w: dosya bu uygulamanın veritabanı değil", ErrInvalid)
+	}
+	latest, err := database.LatestSchemaVersion()
+	if err != nil {
+		return err
+	}
+	if version > latest {
+		return fmt.Errorf("%w: yedek uygulamanın daha yeni bir sürümünden alınmış (şema %d, desteklenen %d)",
+			ErrInvalid, version, latest)
+	}
+	return nil
+}
+
+// copyInto dosyadaki veritabanını SQLite online backup API'siyle çalışan veritabanının üzerine
+// kopyalar. Kopyalama tek adımda yapılır; bu sırada diğer bağlantılar bekler.
+func (m *Manager) copyInto(path string) error {
+	ctx := context.Background()
+
+	src, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	srcConn, err := src.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer srcConn.Close()
+
+	destConn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer destConn.Close()
+
+	return destConn.Raw(func(destDriver interface{}) error {
+		return srcConn.Raw(func(srcDriver interface{}) error {
+			dest, ok := destDriver.(*sqlite3.SQLiteConn)
+			if !ok {
+				return errors.New("geri yükleme yalnızca SQLite veritabanında desteklenir")
+			}
+			b, err := dest.Backup("main", srcDriver.(*sqlite3.SQLiteConn), "main")
+			if err != nil {
+				return err
+			}
+			if _, err := b.Step(-1); err != nil {
+				b.Finish()
+				return err
+			}
+			return b.Finish()
+		})
+	})
+}
+
+func (m *Manager) stat(name string) (Info, error) {
+	match := namePattern.FindStringSubmatch(name)
+	if match == nil {
+		return Info{}, ErrNotFound
+	}
+	fi, err := os.Stat(filepath.Join(m.dir, name))
+	if err != nil {
+		return Info{}, err
+	}
+
+	createdAt, err := time.ParseInLocation(nameLayout, match[1], time.Local)
+	if err != nil {
+		return Info{}, err
+	}
+	return Info{
+		Name:       name,
+		Kind:       Kind(match[2]),
+		Size:       fi.Size(),
+		Compressed: match[3] != "",
+		Encrypted:  match[4] != "",
+		CreatedAt:  createdAt,
+	}, nil
+}
+
+// Label yedeğin türünün Türkçe karşılığı
+func (i Info) Label() string {
+	switch i.Kind {
+	case KindAuto:
+		return "Otomatik"
+	case KindManual:
+		return "Elle"
+	case KindPreRestore:
+		return "Geri yükleme öncesi"
+	default:
+		return string(i.Kind)
+	}
+}
+
+// SizeLabel dosya boyutunu okunabilir biçimde döndürür
+func (i Info) SizeLabel() string {
+	if i.Size < 1024 {
+		return fmt.Sprintf("%d B", i.Size)
+	}
+	size := float64(i.Size) / 1024
+	for _, unit := range []string{"KB", "MB"} {
+		if size < 1024 {
+			return fmt.Sprintf("%.1f %s", size, unit)
+		}
+		size /= 1024
+	}
+	return fmt.Sprintf("%.1f GB", size)
+}
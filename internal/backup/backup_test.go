@@ -0,0 +1,320 @@
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/database/dbtest"
+	"github.com/umutaraz/tradesman-app/internal/settings"
+)
+
+func testManager(t *testing.T, db *database.DB, cfg Config) *Manager {
+	t.Helper()
+	cfg.Dir = filepath.Join(t.TempDir(), "backups")
+	m, err := New(db, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func keyed(key string) *Manager {
+	m := &Manager{}
+	if key != "" {
+		sum := sha256.Sum256([]byte(key))
+		m.key = sum[:]
+	}
+	return m
+}
+
+func TestCodecRoundTrip(t *testing.T) {
+	content := append(append([]byte{}, sqliteMagic...), bytes.Repeat([]byte("esnaf"), 1000)...)
+	tests := []struct {
+		name     string
+		compress bool
+		key      string
+		magic    []byte
+	}{
+		{"plain", false, "", sqliteMagic},
+		{"compressed", true, "", gzipMagic},
+		{"encrypted", false, "gizli", encryptedMagic},
+		{"compressed and encrypted", true, "gizli", encryptedMagic},
+	}
+	for _, tt := range tests {
+		m := keyed(tt.key)
+		m.compress = tt.compress
+
+		var encoded bytes.Buffer
+		if err := m.encode(bytes.NewReader(content), &encoded); err != nil {
+			t.Fatalf("%s: encode: %v", tt.name, err)
+		}
+		if !bytes.HasPrefix(encoded.Bytes(), tt.magic) {
+			t.Errorf("%s: encoded header = %q", tt.name, encoded.Bytes()[:len(tt.magic)])
+		}
+		if tt.key != "" && bytes.Contains(encoded.Bytes(), []byte("esnafesnaf")) {
+			t.Errorf("%s: encrypted backup contains plain text", tt.name)
+		}
+
+		var decoded bytes.Buffer
+		if err := m.decode(bytes.NewReader(encoded.Bytes()), &decoded); err != nil {
+			t.Fatalf("%s: decode: %v", tt.name, err)
+		}
+		if !bytes.Equal(decoded.Bytes(), content) {
+			t.Errorf("%s: decoded content differs", tt.name)
+		}
+	}
+}
+
+func TestDecodeRejectsInvalidFiles(t *testing.T) {
+	content := append(append([]byte{}, sqliteMagic...), "veri"...)
+	encrypt := func(key string) []byte {
+		var buf bytes.Buffer
+		if err := keyed(key).encode(bytes.NewReader(content), &buf); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+	tampered := encrypt("gizli")
+	tampered[len(tampered)-1] ^= 0xff
+	var foreignGzip bytes.Buffer
+	gz := gzip.NewWriter(&foreignGzip)
+	gz.Write([]byte("bu bir veritabanı değil"))
+	gz.Close()
+
+	tests := []struct {
+		name string
+		key  string
+		data []byte
+	}{
+		{"wrong key", "başka", encrypt("gizli")},
+		{"encrypted without key", "", encrypt("gizli")},
+		{"tampered", "gizli", tampered},
+		{"truncated", "gizli", encrypt("gizli")[:len(encryptedMagic)+4]},
+		{"foreign file", "", []byte("tarih;para_birimi;kur\n")},
+		{"foreign gzip", "", foreignGzip.Bytes()},
+		{"empty", "", nil},
+	}
+	for _, tt := range tests {
+		err := keyed(tt.key).decode(bytes.NewReader(tt.data), &bytes.Buffer{})
+		if !errors.Is(err, ErrInvalid) {
+			t.Errorf("%s: err = %v, want ErrInvalid", tt.name, err)
+		}
+	}
+}
+
+func customerCount(t *testing.T, db *database.DB) int {
+	t.Helper()
+	var n int
+	if err := db.QueryRow("SELECT COUNT(*) FROM customers").Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestCreateAndRestore(t *testing.T) {
+	db := dbtest.New(t)
+	userID := dbtest.User(t, db, "sahip@example.com")
+	m := testManager(t, db, Config{Compress: true, Key: "gizli"})
+
+	if _, err := db.Exec("INSERT INTO customers (user_id, name) VALUES (?, 'Ayşe Demir')", userID); err != nil {
+		t.Fatal(err)
+	}
+	info, err := m.Create(KindManual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Kind != KindManual || !info.Compressed || !info.Encrypted {
+		t.Errorf("backup = %+v", info)
+	}
+
+	if _, err := db.Exec("INSERT INTO customers (user_id, name) VALUES (?, 'Mehmet Kaya')", userID); err != nil {
+		t.Fatal(err)
+	}
+	safety, err := m.RestoreFile(info.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Geri yükleme açık bağlantılarda görünür; öncesinde güvenlik yedeği alınır
+	if n := customerCount(t, db); n != 1 {
+		t.Errorf("customers after restore = %d, want 1", n)
+	}
+	if safety.Kind != KindPreRestore {
+		t.Errorf("safety backup = %+v", safety)
+	}
+	if _, err := m.RestoreFile(safety.Name); err != nil {
+		t.Fatal(err)
+	}
+	if n := customerCount(t, db); n != 2 {
+		t.Errorf("customers after restoring the safety backup = %d, want 2", n)
+	}
+
+	if _, err := m.RestoreFile("../test.db"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("restore outside the backup directory: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestRestoreRejectsTamperedAndForeignFiles(t *testing.T) {
+	db := dbtest.New(t)
+	userID := dbtest.User(t, db, "sahip@example.com")
+	if _, err := db.Exec("INSERT INTO customers (user_id, name) VALUES (?, 'Ayşe Demir')", userID); err != nil {
+		t.Fatal(err)
+	}
+	m := testManager(t, db, Config{Key: "gizli"})
+	info, err := m.Create(KindManual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, err := m.Path(info.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := append([]byte{}, encrypted...)
+	tampered[len(tampered)/2] ^= 0x01
+
+	// Başka bir uygulamanın geçerli SQLite veritabanı
+	foreignPath := filepath.Join(t.TempDir(), "foreign.db")
+	foreign, err := sql.Open("sqlite3", foreignPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := foreign.Exec("CREATE TABLE notes (body TEXT)"); err != nil {
+		t.Fatal(err)
+	}
+	foreign.Close()
+	foreignDB, err := os.ReadFile(foreignPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		m    *Manager
+		data []byte
+	}{
+		{"tampered", m, tampered},
+		{"wrong key", testManager(t, db, Config{Key: "başka"}), encrypted},
+		{"foreign database", m, foreignDB},
+		{"not a database", m, []byte("merhaba")},
+	}
+	for _, tt := range tests {
+		if _, err := tt.m.Restore(bytes.NewReader(tt.data)); !errors.Is(err, ErrInvalid) {
+			t.Errorf("%s: err = %v, want ErrInvalid", tt.name, err)
+		}
+	}
+
+	// Reddedilen dosyalar veritabanına dokunmaz ve güvenlik yedeği bırakmaz
+	if n := customerCount(t, db); n != 1 {
+		t.Errorf("customers = %d, want 1", n)
+	}
+	backups, err := m.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 1 {
+		t.Errorf("backups = %+v, want only the manual backup", backups)
+	}
+}
+
+func TestPruneKeepsManualBackups(t *testing.T) {
+	db := dbtest.New(t)
+	m := testManager(t, db, Config{})
+
+	start := time.Date(2026, 5, 1, 3, 0, 0, 0, time.Local)
+	for i := 0; i < 4; i++ {
+		if _, err := m.create(KindAuto, start.AddDate(0, 0, i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := m.create(KindManual, start); err != nil {
+		t.Fatal(err)
+	}
+	// Aynı saniyede alınan yedek öncekinin üzerine yazmaz
+	if _, err := m.create(KindManual, start); err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := m.Prune(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 2 {
+		t.Errorf("removed = %d, want 2", removed)
+	}
+	backups, err := m.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var auto, manual int
+	for _, b := range backups {
+		switch b.Kind {
+		case KindAuto:
+			auto++
+			if b.CreatedAt.Before(start.AddDate(0, 0, 2)) {
+				t.Errorf("kept old auto backup %s", b.Name)
+			}
+		case KindManual:
+			manual++
+		}
+	}
+	if auto != 2 || manual != 2 {
+		t.Errorf("backups = %d auto, %d manual; want 2 and 2", auto, manual)
+	}
+}
+
+func TestSchedulerFollowsAdminSettings(t *testing.T) {
+	db := dbtest.New(t)
+	m := testManager(t, db, Config{})
+	store := settings.NewStore(db)
+	s := NewScheduler(m, store, time.Hour)
+
+	// Kayıt olan işletme sahibi yönetici olmaz; yönetici atanana kadar yedek alınmaz
+	ownerID := dbtest.User(t, db, "sahip@example.com")
+	if taken, err := s.Process(time.Now()); err != nil || taken {
+		t.Fatalf("without admin: taken = %v, err = %v", taken, err)
+	}
+	if _, err := db.SetSystemAdmin("Sahip@Example.com", true); err != nil {
+		t.Fatal(err)
+	}
+	if id, err := m.AdminID(); err != nil || id != ownerID {
+		t.Fatalf("AdminID = %d, %v; want %d", id, err, ownerID)
+	}
+	tests := []struct {
+		name  string
+		now   time.Time
+		taken bool
+	}{
+		{"first backup", time.Now(), true},
+		{"within the week", time.Now().AddDate(0, 0, 6), false},
+		{"after a week", time.Now().AddDate(0, 0, 8), true},
+	}
+	for _, tt := range tests {
+		taken, err := s.Process(tt.now)
+		if err != nil || taken != tt.taken {
+			t.Errorf("%s: taken = %v, err = %v; want %v", tt.name, taken, err, tt.taken)
+		}
+	}
+
+	prefs, err := store.Get(ownerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prefs.BackupEnabled = false
+	if err := store.Save(ownerID, prefs); err != nil {
+		t.Fatal(err)
+	}
+	if taken, err := s.Process(time.Now().AddDate(1, 0, 0)); err != nil || taken {
+		t.Errorf("disabled: taken = %v, err = %v", taken, err)
+	}
+}
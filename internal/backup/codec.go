@@ -0,0 +1,153 @@
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Dosya biçimlerinin başlıkları
+var (
+	sqliteMagic    = []byte("SQLite format 3\x00")
+	gzipMagic      = []byte{0x1f, 0x8b}
+	encryptedMagic = []byte("ESNAFBAK1") // Ardından 12 baytlık nonce ve AES-256-GCM şifreli içerik
+)
+
+// maxEncryptedSize şifreli yedeğin en büyük boyutu; GCM içeriği bir bütün olarak doğruladığından
+// şifreli yedekler bellekte çözülür
+const maxEncryptedSize = 1 << 30
+
+// encodeFile veritabanı dosyasını yapılandırmaya göre sıkıştırıp şifreleyerek dst'ye yazar
+func (m *Manager) encodeFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+
+	err = m.encode(in, out)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func (m *Manager) encode(r io.Reader, w io.Writer) error {
+	if m.key == nil {
+		return compress(r, w, m.compress)
+	}
+
+	var plain bytes.Buffer
+	if err := compress(r, &plain, m.compress); err != nil {
+		return err
+	}
+
+	gcm, err := newGCM(m.key)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	if _, err := w.Write(encryptedMagic); err != nil {
+		return err
+	}
+	if _, err := w.Write(nonce); err != nil {
+		return err
+	}
+	_, err = w.Write(gcm.Seal(nil, nonce, plain.Bytes(), encryptedMagic))
+	return err
+}
+
+func compress(r io.Reader, w io.Writer, enabled bool) error {
+	if !enabled {
+		_, err := io.Copy(w, r)
+		return err
+	}
+
+	gz := gzip.NewWriter(w)
+	if _, err := io.Copy(gz, r); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// decode yedek dosyasını biçimini başlığından tanıyarak çözer ve SQLite veritabanını w'ye yazar.
+// Şifreli, sıkıştırılmış ve düz yedekler kabul edilir.
+func (m *Manager) decode(r io.Reader, w io.Writer) error {
+	br := bufio.NewReader(r)
+	header, _ := br.Peek(len(sqliteMagic))
+
+	switch {
+	case bytes.HasPrefix(header, encryptedMagic):
+		if m.key == nil {
+			return fmt.Errorf("%w: yedek şifreli, çözmek için BACKUP_KEY tanımlanmalı", ErrInvalid)
+		}
+		data, err := io.ReadAll(io.LimitReader(br, maxEncryptedSize+1))
+		if err != nil {
+			return err
+		}
+		if len(data) > maxEncryptedSize {
+			return fmt.Errorf("%w: dosya çok büyük", ErrInvalid)
+		}
+
+		gcm, err := newGCM(m.key)
+		if err != nil {
+			return err
+		}
+		data = data[len(encryptedMagic):]
+		if len(data) < gcm.NonceSize() {
+			return fmt.Errorf("%w: şifreli içerik eksik", ErrInvalid)
+		}
+		plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], encryptedMagic)
+		if err != nil {
+			return fmt.Errorf("%w: şifre çözülemedi, anahtar yanlış ya da dosya bozuk", ErrInvalid)
+		}
+		return m.decode(bytes.NewReader(plain), w)
+
+	case bytes.HasPrefix(header, gzipMagic):
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalid, err)
+		}
+		defer gz.Close()
+		return copyDatabase(gz, w)
+
+	default:
+		return copyDatabase(br, w)
+	}
+}
+
+// copyDatabase içeriğin bir SQLite veritabanı olduğunu doğrulayarak kopyalar
+func copyDatabase(r io.Reader, w io.Writer) error {
+	br := bufio.NewReader(r)
+	header, _ := br.Peek(len(sqliteMagic))
+	if !bytes.Equal(header, sqliteMagic) {
+		return fmt.Errorf("%w: dosya bir SQLite veritabanı değil", ErrInvalid)
+	}
+	if _, err := io.Copy(w, br); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	return nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
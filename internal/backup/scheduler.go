@@ -0,0 +1,111 @@
+package backup
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/settings"
+)
+
+// Scheduler ilk sistem yöneticisinin yedekleme ayarlarına göre otomatik yedek alır ve saklama
+// sınırını aşan eski otomatik yedekleri siler
+type Scheduler struct {
+	manager  *Manager
+	settings *settings.Store
+	interval time.Duration
+}
+
+// NewScheduler otomatik yedekleme zamanlayıcısı oluşturur; interval yedeğin zamanının gelip
+// gelmediğinin ne sıklıkla denetleneceğidir
+func NewScheduler(manager *Manager, store *settings.Store, interval time.Duration) *Scheduler {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	return &Scheduler{manager: manager, settings: store, interval: interval}
+}
+
+// Run bağlam iptal edilene kadar zamanı gelen yedekleri alır
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Process(time.Now()); err != nil {
+			log.Printf("Otomatik yedekleme hatası: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Process zamanı geldiyse otomatik yedek alıp eski yedekleri siler; yedek alındıysa true döner
+func (s *Scheduler) Process(now time.Time) (bool, error) {
+	adminID, err := s.manager.AdminID()
+	if err == sql.ErrNoRows {
+		// Henüz sistem yöneticisi atanmamış
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	prefs, err := s.settings.Get(adminID)
+	if err != nil {
+		return false, err
+	}
+	if !prefs.BackupEnabled {
+		return false, nil
+	}
+
+	last, err := s.lastAutoBackup()
+	if err != nil {
+		return false, err
+	}
+	if !last.IsZero() && now.Before(nextBackup(last, prefs.BackupFrequency)) {
+		return false, nil
+	}
+
+	info, err := s.manager.Create(KindAuto)
+	if err != nil {
+		return false, err
+	}
+	removed, err := s.manager.Prune(prefs.BackupKeep)
+	if err != nil {
+		return true, err
+	}
+
+	log.Printf("Otomatik yedek alındı: %s (%d eski yedek silindi)", info.Name, removed)
+	return true, nil
+}
+
+// lastAutoBackup en son otomatik yedeğin zamanını döndürür; hiç yoksa sıfır zaman döner
+func (s *Scheduler) lastAutoBackup() (time.Time, error) {
+	backups, err := s.manager.List()
+	if err != nil {
+		return time.Time{}, err
+	}
+	for _, b := range backups {
+		if b.Kind == KindAuto {
+			return b.CreatedAt, nil
+		}
+	}
+	return time.Time{}, nil
+}
+
+// nextBackup son yedekten sonra bir sonraki otomatik yedeğin zamanı
+func nextBackup(last time.Time, frequency string) time.Time {
+	switch frequency {
+	case settings.BackupDaily:
+		return last.AddDate(0, 0, 1)
+	case settings.BackupMonthly:
+		return last.AddDate(0, 1, 0)
+	default:
+		return last.AddDate(0, 0, 7)
+	}
+}
@@ -2,12 +2,44 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"time"
 )
 
 type Config struct {
 	Port         string
 	DatabasePath string
 	Environment  string
+	SessionTTL   time.Duration
+
+	// E-posta gönderimi; MailDriver smtp, file ya da memory olabilir
+	MailDriver     string
+	MailDir        string
+	MailFrom       string
+	SMTPHost       string
+	SMTPPort       string
+	SMTPUsername   string
+	SMTPPassword   string
+	OutboxInterval time.Duration
+
+	// Kısa mesaj gönderimi; SMSDriver http, file ya da memory olabilir
+	SMSDriver string
+	SMSDir    string
+	SMSURL    string
+	SMSToken  string
+	SMSFrom   string
+
+	// Veritabanı yedekleri; BackupKey boş değilse yedekler şifrelenir
+	BackupDir      string
+	BackupCompress bool
+	BackupKey      string
+
+	// Arka plan denetimlerinin çalışma aralıkları
+	StockCheckInterval   time.Duration
+	InvoiceCheckInterval time.Duration
+	ReminderInterval     time.Duration
+	DunningInterval      time.Duration
+	BackupInterval       time.Duration
 }
 
 func Load() *Config {
@@ -15,12 +47,61 @@ func Load() *Config {
 		Port:         getEnv("PORT", "8080"),
 		DatabasePath: getEnv("DATABASE_PATH", "./tradesman.db"),
 		Environment:  getEnv("ENVIRONMENT", "development"),
+		SessionTTL:   getEnvDuration("SESSION_TTL", 7*24*time.Hour),
+
+		MailDriver:     getEnv("MAIL_DRIVER", "file"),
+		MailDir:        getEnv("MAIL_DIR", "./mail"),
+		MailFrom:       getEnv("MAIL_FROM", "Esnaf Yönetim <noreply@localhost>"),
+		SMTPHost:       getEnv("SMTP_HOST", ""),
+		SMTPPort:       getEnv("SMTP_PORT", "587"),
+		SMTPUsername:   getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:   getEnv("SMTP_PASSWORD", ""),
+		OutboxInterval: getEnvDuration("OUTBOX_INTERVAL", 15*time.Second),
+
+		SMSDriver: getEnv("SMS_DRIVER", "file"),
+		SMSDir:    getEnv("SMS_DIR", "./sms"),
+		SMSURL:    getEnv("SMS_URL", ""),
+		SMSToken:  getEnv("SMS_TOKEN", ""),
+		SMSFrom:   getEnv("SMS_FROM", ""),
+
+		BackupDir:      getEnv("BACKUP_DIR", "./backups"),
+		BackupCompress: getEnvBool("BACKUP_COMPRESS", true),
+		BackupKey:      getEnv("BACKUP_KEY", ""),
+
+		StockCheckInterval:   getEnvDuration("STOCK_CHECK_INTERVAL", time.Minute),
+		InvoiceCheckInterval: getEnvDuration("INVOICE_CHECK_INTERVAL", 15*time.Minute),
+		ReminderInterval:     getEnvDuration("REMINDER_INTERVAL", time.Minute),
+		DunningInterval:      getEnvDuration("DUNNING_INTERVAL", time.Hour),
+		BackupInterval:       getEnvDuration("BACKUP_INTERVAL", time.Hour),
 	}
 }
 
+// IsProduction çerezlerin yalnızca HTTPS üzerinden gönderilip gönderilmeyeceğini belirler
+func (c *Config) IsProduction() bool {
+	return c.Environment == "production"
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return defaultValue
 }
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
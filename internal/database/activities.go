@@ -0,0 +1,26 @@
+package database
+
+import (
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/models"
+)
+
+// InsertCustomerActivity müşterinin etkinlik geçmişine kayıt ekler
+func InsertCustomerActivity(exec Execer, activity models.CustomerActivity) error {
+	_, err := exec.Exec(`
+		INSERT INTO customer_activities (user_id, customer_id, activity_type, description, invoice_id, outbox_id,
+		                                 created_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, activity.UserID, activity.CustomerID, activity.Type, activity.Description, activity.InvoiceID,
+		activity.OutboxID, nullableUserID(activity.CreatedBy), time.Now())
+	return err
+}
+
+// nullableUserID sistem tarafından yapılan işlemlerde (0) NULL yazar
+func nullableUserID(id int) interface{} {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
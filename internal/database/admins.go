@@ -0,0 +1,48 @@
+package database
+
+import (
+	"strings"
+
+	"github.com/umutaraz/tradesman-app/internal/models"
+)
+
+// IsSystemAdmin kullanıcı kurulumun yöneticisiyse true döner
+func (db *DB) IsSystemAdmin(userID int) (bool, error) {
+	var admin bool
+	err := db.QueryRow("SELECT system_admin FROM users WHERE id = ?", userID).Scan(&admin)
+	return admin, err
+}
+
+// SetSystemAdmin e-posta adresiyle bulunan kullanıcıya sistem yöneticiliği verir ya da geri alır;
+// adres kayıtlı değilse sql.ErrNoRows döner
+func (db *DB) SetSystemAdmin(email string, admin bool) (*models.User, error) {
+	var user models.User
+	err := db.QueryRow("SELECT id, name, email FROM users WHERE lower(email) = lower(?)", strings.TrimSpace(email)).
+		Scan(&user.ID, &user.Name, &user.Email)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec("UPDATE users SET system_admin = ? WHERE id = ?", admin, user.ID); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// SystemAdmins kurulumun yöneticilerini kayıt sırasıyla döndürür
+func (db *DB) SystemAdmins() ([]models.User, error) {
+	rows, err := db.Query("SELECT id, name, email FROM users WHERE system_admin = 1 ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var admins []models.User
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(&user.ID, &user.Name, &user.Email); err != nil {
+			return nil, err
+		}
+		admins = append(admins, user)
+	}
+	return admins, rows.Err()
+}
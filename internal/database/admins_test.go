@@ -0,0 +1,83 @@
+package database_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/database/dbtest"
+)
+
+// systemAdminVersion sistem yöneticisi sütununu ekleyen migration'ın sürümü
+func systemAdminVersion(t *testing.T, db *database.DB) int {
+	t.Helper()
+	statuses, err := db.MigrationStatuses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range statuses {
+		if s.Name == "system_admin" {
+			return s.Version
+		}
+	}
+	t.Fatal("system_admin migration not found")
+	return 0
+}
+
+func adminEmails(t *testing.T, db *database.DB) []string {
+	t.Helper()
+	admins, err := db.SystemAdmins()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var emails []string
+	for _, u := range admins {
+		emails = append(emails, u.Email)
+	}
+	return emails
+}
+
+func TestSystemAdmins(t *testing.T) {
+	db := dbtest.New(t)
+	version := systemAdminVersion(t, db)
+	if _, err := db.MigrateTo(version - 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// Mevcut kurulumda ilk işletme sahibi yönetici olur; daha önce eklenmiş çalışan olamaz
+	staff, err := db.Exec(`INSERT INTO users (name, email, password_hash, role) VALUES ('Kasiyer', 'kasiyer@example.com', '', 'cashier')`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ownerID := dbtest.User(t, db, "sahip@example.com")
+	staffID, _ := staff.LastInsertId()
+	if _, err := db.Exec("UPDATE users SET owner_id = ? WHERE id = ?", ownerID, staffID); err != nil {
+		t.Fatal(err)
+	}
+	dbtest.User(t, db, "diger@example.com")
+	if _, err := db.MigrateUp(); err != nil {
+		t.Fatal(err)
+	}
+	if got := adminEmails(t, db); len(got) != 1 || got[0] != "sahip@example.com" {
+		t.Errorf("admins after upgrade = %v, want the first owner", got)
+	}
+
+	// Sonradan kayıt olan hesaplar yönetici olmaz
+	newID := dbtest.User(t, db, "yeni@example.com")
+	if admin, err := db.IsSystemAdmin(newID); err != nil || admin {
+		t.Errorf("new account: admin = %v, %v", admin, err)
+	}
+
+	if _, err := db.SetSystemAdmin(" Yeni@Example.com ", true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.SetSystemAdmin("sahip@example.com", false); err != nil {
+		t.Fatal(err)
+	}
+	if got := adminEmails(t, db); len(got) != 1 || got[0] != "yeni@example.com" {
+		t.Errorf("admins = %v, want only yeni@example.com", got)
+	}
+	if _, err := db.SetSystemAdmin("yok@example.com", true); err != sql.ErrNoRows {
+		t.Errorf("unknown e-mail: err = %v, want sql.ErrNoRows", err)
+	}
+}
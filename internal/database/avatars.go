@@ -0,0 +1,47 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// ErrAvatarNotFound kullanıcının profil fotoğrafı yoksa döner
+var ErrAvatarNotFound = errors.New("profil fotoğrafı bulunamadı")
+
+// Avatar kullanıcının profil fotoğrafı
+type Avatar struct {
+	ContentType string
+	Data        []byte
+	UpdatedAt   time.Time
+}
+
+// UserAvatar kullanıcının profil fotoğrafını döndürür
+func (db *DB) UserAvatar(userID int) (*Avatar, error) {
+	var avatar Avatar
+	err := db.QueryRow("SELECT content_type, data, updated_at FROM user_avatars WHERE user_id = ?", userID).
+		Scan(&avatar.ContentType, &avatar.Data, &avatar.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrAvatarNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &avatar, nil
+}
+
+// SaveUserAvatar kullanıcının profil fotoğrafını kaydeder; varsa eskisinin yerine geçer
+func (db *DB) SaveUserAvatar(userID int, contentType string, data []byte) error {
+	_, err := db.Exec(`
+		INSERT INTO user_avatars (user_id, content_type, data, updated_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (user_id) DO UPDATE SET content_type = excluded.content_type, data = excluded.data,
+			updated_at = excluded.updated_at
+	`, userID, contentType, data, time.Now())
+	return err
+}
+
+// DeleteUserAvatar kullanıcının profil fotoğrafını siler
+func (db *DB) DeleteUserAvatar(userID int) error {
+	_, err := db.Exec("DELETE FROM user_avatars WHERE user_id = ?", userID)
+	return err
+}
@@ -2,17 +2,22 @@ package database
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/mattn/go-sqlite3"
 )
 
 type DB struct {
 	*sql.DB
 }
 
-func Initialize(dbPath string) (*DB, error) {
-	db, err := sql.Open("sqlite3", dbPath)
+// Open veritabanını şemaya dokunmadan açar; migrate komutu bunu kullanır
+func Open(dbPath string) (*DB, error) {
+	// Yazma işlemleri BEGIN IMMEDIATE ile başlar; böylece aynı anda açılan işlemler yazma kilidini
+	// sonradan yükseltmeye çalışıp "database is locked" hatası almak yerine sırayla bekler
+	db, err := sql.Open("sqlite3", withTxLock(dbPath))
 	if err != nil {
 		return nil, fmt.Errorf("veritabanı açma hatası: %w", err)
 	}
@@ -21,122 +26,58 @@ func Initialize(dbPath string) (*DB, error) {
 		return nil, fmt.Errorf("veritabanı bağlantı testi hatası: %w", err)
 	}
 
-	database := &DB{DB: db}
-
-	// Tabloları oluştur
-	if err := database.createTables(); err != nil {
-		return nil, fmt.Errorf("tablo oluşturma hatası: %w", err)
-	}
-
-	return database, nil
+	return &DB{DB: db}, nil
 }
 
-func (db *DB) createTables() error {
-	// Kullanıcılar tablosu
-	usersTable := `
-	CREATE TABLE IF NOT EXISTS users (
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		name TEXT NOT NULL,
-		email TEXT UNIQUE NOT NULL,
-		password_hash TEXT NOT NULL,
-		role TEXT DEFAULT 'user',
-		business_name TEXT,
-		phone TEXT,
-		address TEXT,
-		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
-	);`
-
-	// Müşteriler tablosu
-	customersTable := `
-	CREATE TABLE IF NOT EXISTS customers (
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		user_id INTEGER NOT NULL,
-		name TEXT NOT NULL,
-		email TEXT,
-		phone TEXT,
-		address TEXT,
-		notes TEXT,
-		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		FOREIGN KEY (user_id) REFERENCES users(id)
-	);`
+// OpenReadOnly mevcut veritabanını yalnızca okumak için açar; dosya yoksa oluşturmaz, hata döner
+func OpenReadOnly(dbPath string) (*DB, error) {
+	db, err := sql.Open("sqlite3", "file:"+dbPath+"?mode=ro")
+	if err != nil {
+		return nil, fmt.Errorf("veritabanı açma hatası: %w", err)
+	}
 
-	// Ürünler/Hizmetler tablosu
-	productsTable := `
-	CREATE TABLE IF NOT EXISTS products (
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		user_id INTEGER NOT NULL,
-		name TEXT NOT NULL,
-		description TEXT,
-		price DECIMAL(10,2) NOT NULL,
-		category TEXT,
-		stock_quantity INTEGER DEFAULT 0,
-		unit TEXT DEFAULT 'adet',
-		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		FOREIGN KEY (user_id) REFERENCES users(id)
-	);`
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("veritabanı bağlantı testi hatası: %w", err)
+	}
 
-	// Siparişler tablosu
-	ordersTable := `
-	CREATE TABLE IF NOT EXISTS orders (
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		user_id INTEGER NOT NULL,
-		customer_id INTEGER NOT NULL,
-		order_number TEXT UNIQUE NOT NULL,
-		status TEXT DEFAULT 'pending',
-		total_amount DECIMAL(10,2) NOT NULL,
-		notes TEXT,
-		order_date DATETIME DEFAULT CURRENT_TIMESTAMP,
-		delivery_date DATETIME,
-		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		FOREIGN KEY (user_id) REFERENCES users(id),
-		FOREIGN KEY (customer_id) REFERENCES customers(id)
-	);`
+	return &DB{DB: db}, nil
+}
 
-	// Sipariş detayları tablosu
-	orderItemsTable := `
-	CREATE TABLE IF NOT EXISTS order_items (
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		order_id INTEGER NOT NULL,
-		product_id INTEGER NOT NULL,
-		quantity INTEGER NOT NULL,
-		unit_price DECIMAL(10,2) NOT NULL,
-		total_price DECIMAL(10,2) NOT NULL,
-		FOREIGN KEY (order_id) REFERENCES orders(id),
-		FOREIGN KEY (product_id) REFERENCES products(id)
-	);`
+// IsUniqueViolation hata bir UNIQUE kısıtının ihlalinden kaynaklanıyorsa true döner; ön kontrolden
+// sonra aynı anda eklenen kayıtlar bu hatayla reddedilir
+func IsUniqueViolation(err error) bool {
+	var sqliteErr sqlite3.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique
+}
 
-	// Gelir/Gider takibi tablosu
-	transactionsTable := `
-	CREATE TABLE IF NOT EXISTS transactions (
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		user_id INTEGER NOT NULL,
-		type TEXT NOT NULL CHECK (type IN ('income', 'expense')),
-		category TEXT NOT NULL,
-		amount DECIMAL(10,2) NOT NULL,
-		description TEXT,
-		transaction_date DATETIME DEFAULT CURRENT_TIMESTAMP,
-		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		FOREIGN KEY (user_id) REFERENCES users(id)
-	);`
+// withTxLock bağlantı adresine _txlock=immediate parametresini ekler
+func withTxLock(dbPath string) string {
+	if strings.Contains(dbPath, "_txlock=") {
+		return dbPath
+	}
+	if strings.Contains(dbPath, "?") {
+		return dbPath + "&_txlock=immediate"
+	}
+	return dbPath + "?_txlock=immediate"
+}
 
-	tables := []string{
-		usersTable,
-		customersTable,
-		productsTable,
-		ordersTable,
-		orderItemsTable,
-		transactionsTable,
+// Initialize veritabanını açar ve bekleyen migration'ları uygular
+func Initialize(dbPath string) (*DB, error) {
+	database, err := Open(dbPath)
+	if err != nil {
+		return nil, err
 	}
 
-	for _, table := range tables {
-		if _, err := db.Exec(table); err != nil {
-			return fmt.Errorf("tablo oluşturma hatası: %w", err)
-		}
+	if _, err := database.MigrateUp(); err != nil {
+		database.Close()
+		return nil, fmt.Errorf("şema güncelleme hatası: %w", err)
 	}
 
-	return nil
+	return database, nil
+}
+
+// tableExists tablonun veritabanında bulunup bulunmadığını kontrol eder
+func (db *DB) tableExists(table string) (bool, error) {
+	return tableExists(db, table)
 }
@@ -0,0 +1,40 @@
+// Package dbtest testler için geçici dizinde, şeması oluşturulmuş bir SQLite veritabanı açar.
+// Şema FTS5 gerektirdiğinden testler -tags sqlite_fts5 ile çalıştırılmalıdır; etiket yoksa New
+// testi atlamaz, hatayla durdurur.
+package dbtest
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/umutaraz/tradesman-app/internal/database"
+)
+
+// New test bitince kapatılan yeni bir veritabanı döndürür
+func New(t testing.TB) *database.DB {
+	t.Helper()
+
+	db, err := database.Initialize(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// User işletme sahibi bir kullanıcı ekler ve ID'sini döndürür
+func User(t testing.TB, db *database.DB, email string) int {
+	t.Helper()
+
+	result, err := db.Exec(`
+		INSERT INTO users (name, email, password_hash, role, business_name) VALUES (?, ?, '', 'owner', ?)
+	`, email, email, email)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return int(id)
+}
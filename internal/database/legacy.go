@@ -0,0 +1,117 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// Migration sisteminden önceki sürüm şemayı her açılışta CREATE TABLE IF NOT EXISTS ifadeleriyle
+// kuruyordu ve yalnızca kullanıcı, müşteri, ürün, sipariş ve gelir/gider tablolarını içeriyordu.
+// Böyle bir veritabanı ilk kez açıldığında legacyVersion'a kadarki migration'ların karşılığına
+// getirilir ve bu sürümler uygulanmış sayılır.
+
+// legacyVersion eski şemanın karşılığı olan son migration sürümü
+const legacyVersion = 2
+
+// querier *sql.DB ve *sql.Tx'in ortak sorgu yöntemleri
+type querier interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// legacyColumns eski şemadaki tablolarda bulunmayan, başlangıç şemasıyla gelen kolonlar
+var legacyColumns = []struct {
+	table      string
+	column     string
+	definition string
+}{
+	{"users", "owner_id", "INTEGER REFERENCES users(id)"},
+	{"users", "last_login_at", "DATETIME"},
+	{"products", "is_service", "INTEGER NOT NULL DEFAULT 0"},
+	{"products", "reorder_level", "INTEGER"},
+	{"products", "reorder_quantity", "INTEGER NOT NULL DEFAULT 0"},
+	{"transactions", "order_id", "INTEGER REFERENCES orders(id)"},
+	{"customers", "archived_at", "DATETIME"},
+	{"orders", "on_credit", "INTEGER NOT NULL DEFAULT 0"},
+}
+
+// adoptLegacySchema eski şemayla oluşturulmuş veritabanını legacyVersion'a getirir ve bu sürüme kadarki
+// migration'ları uygulanmış olarak kaydeder. Boş veritabanında hiçbir şey yapmaz.
+func adoptLegacySchema(tx *sql.Tx, migrations []Migration) error {
+	legacy, err := tableExists(tx, "users")
+	if err != nil || !legacy {
+		return err
+	}
+
+	initial, ok := findMigration(migrations, 1)
+	if !ok {
+		return fmt.Errorf("başlangıç migration'ı bulunamadı")
+	}
+	if _, err := tx.Exec(initial.Up); err != nil {
+		return migrationError(initial, err)
+	}
+
+	for _, col := range legacyColumns {
+		if err := ensureColumn(tx, col.table, col.column, col.definition); err != nil {
+			return fmt.Errorf("kolon ekleme hatası: %w", err)
+		}
+	}
+
+	for _, m := range migrations {
+		if m.Version > legacyVersion {
+			break
+		}
+		if m.Version > initial.Version {
+			if _, err := tx.Exec(m.Up); err != nil {
+				return migrationError(m, err)
+			}
+		}
+		if _, err := tx.Exec("INSERT INTO schema_migrations (version, name, checksum) VALUES (?, ?, ?)",
+			m.Version, m.Name, m.Checksum); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// tableExists tablonun veritabanında bulunup bulunmadığını kontrol eder
+func tableExists(q querier, table string) (bool, error) {
+	var count int
+	err := q.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", table).Scan(&count)
+	return count > 0, err
+}
+
+// ensureColumn tabloda kolon yoksa ALTER TABLE ile ekler
+func ensureColumn(q querier, table, column, definition string) error {
+	rows, err := q.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid        int
+			name       string
+			colType    string
+			notNull    int
+			defaultVal sql.NullString
+			pk         int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultVal, &pk); err != nil {
+			return err
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+
+	_, err = q.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+	return err
+}
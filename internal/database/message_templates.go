@@ -0,0 +1,31 @@
+package database
+
+import "database/sql"
+
+// GetMessageTemplate işletmenin şablon metnini döndürür; değiştirilmemişse defaultBody ve false döner
+func (db *DB) GetMessageTemplate(userID int, key, defaultBody string) (string, bool, error) {
+	var body string
+	err := db.QueryRow("SELECT body FROM message_templates WHERE user_id = ? AND key = ?", userID, key).Scan(&body)
+	if err == sql.ErrNoRows {
+		return defaultBody, false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return body, true, nil
+}
+
+// SetMessageTemplate şablon metnini kaydeder
+func (db *DB) SetMessageTemplate(userID int, key, body string) error {
+	_, err := db.Exec(`
+		INSERT INTO message_templates (user_id, key, body, updated_at) VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT (user_id, key) DO UPDATE SET body = excluded.body, updated_at = excluded.updated_at
+	`, userID, key, body)
+	return err
+}
+
+// ResetMessageTemplate işletmenin şablon değişikliğini siler; varsayılan metne dönülür
+func (db *DB) ResetMessageTemplate(userID int, key string) error {
+	_, err := db.Exec("DELETE FROM message_templates WHERE user_id = ? AND key = ?", userID, key)
+	return err
+}
@@ -0,0 +1,428 @@
+package database
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Şema değişiklikleri migrations dizinindeki sıralı SQL dosyalarıyla yapılır:
+//
+//	0003_appointment_location.up.sql    sürümü uygular
+//	0003_appointment_location.down.sql  sürümü geri alır (isteğe bağlı)
+//
+// Uygulanan sürümler schema_migrations tablosunda dosya içeriklerinin özetiyle birlikte tutulur.
+// Uygulanmış bir dosya sonradan değiştirilirse özet tutmaz ve migration çalıştırılmaz; değişiklik
+// için her zaman yeni bir sürüm eklenmelidir. down dosyası olmayan sürümler geri alınamaz.
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migration tek bir şema sürümü
+type Migration struct {
+	Version  int
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+// Reversible sürümün geri alınıp alınamayacağını belirtir
+func (m Migration) Reversible() bool { return m.Down != "" }
+
+// MigrationStatus sürümün veritabanındaki durumu
+type MigrationStatus struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+	Modified  bool // Uygulandıktan sonra dosya değiştirilmiş
+	Missing   bool // Veritabanında uygulanmış ama bu sürümde dosyası yok
+	Legacy    bool // Veritabanı eski şemada; sürüm ilk açılışta uyarlanıp uygulanmış sayılacak
+}
+
+// appliedMigration schema_migrations kaydı
+type appliedMigration struct {
+	version   int
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+// loadMigrations gömülü migration dosyalarını sürüm sırasıyla döndürür
+func loadMigrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		file := entry.Name()
+		base, direction := strings.TrimSuffix(file, ".sql"), ""
+		switch {
+		case strings.HasSuffix(base, ".up"):
+			base, direction = strings.TrimSuffix(base, ".up"), "up"
+		case strings.HasSuffix(base, ".down"):
+			base, direction = strings.TrimSuffix(base, ".down"), "down"
+		default:
+			return nil, fmt.Errorf("migration dosya adı geçersiz: %s", file)
+		}
+
+		number, name, ok := strings.Cut(base, "_")
+		version, err := strconv.Atoi(number)
+		if !ok || err != nil || version <= 0 || name == "" {
+			return nil, fmt.Errorf("migration dosya adı geçersiz: %s", file)
+		}
+
+		content, err := migrationFiles.ReadFile(path.Join("migrations", file))
+		if err != nil {
+			return nil, err
+		}
+
+		m := byVersion[version]
+		if m == nil {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		} else if m.Name != name {
+			return nil, fmt.Errorf("%d sürümü için birden fazla migration var: %s, %s", version, m.Name, name)
+		}
+		if direction == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("%04d_%s migration'ının up dosyası yok", m.Version, m.Name)
+		}
+		sum := sha256.Sum256([]byte(m.Up + "\x00" + m.Down))
+		m.Checksum = hex.EncodeToString(sum[:])
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// migrationState schema_migrations tablosunu hazırlar, migration dosyalarını ve uygulanmış
+// sürümleri yükler
+func (db *DB) migrationState() ([]Migration, map[int]appliedMigration, error) {
+	exists, err := db.tableExists("schema_migrations")
+	if err != nil {
+		return nil, nil, err
+	}
+	if !exists {
+		migrations, err := loadMigrations()
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := db.initMigrations(migrations); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return db.readMigrationState()
+}
+
+// readMigrationState migration dosyalarını ve uygulanmış sürümleri veritabanına yazmadan yükler;
+// schema_migrations tablosu yoksa hiçbir sürüm uygulanmamış sayılır
+func (db *DB) readMigrationState() ([]Migration, map[int]appliedMigration, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	exists, err := db.tableExists("schema_migrations")
+	if err != nil || !exists {
+		return migrations, map[int]appliedMigration{}, err
+	}
+
+	applied, err := db.appliedMigrations()
+	if err != nil {
+		return nil, nil, err
+	}
+	return migrations, applied, nil
+}
+
+// isLegacySchema veritabanı migration sisteminden önceki şemayla oluşturulmuş ve henüz
+// uyarlanmamışsa true döner
+func (db *DB) isLegacySchema() (bool, error) {
+	exists, err := db.tableExists("schema_migrations")
+	if err != nil || exists {
+		return false, err
+	}
+	return db.tableExists("users")
+}
+
+// initMigrations schema_migrations tablosunu oluşturur; veritabanı migration sisteminden önce
+// oluşturulmuşsa aynı işlem içinde başlangıç sürümüne uyarlanır
+func (db *DB) initMigrations(migrations []Migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		CREATE TABLE schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			checksum TEXT NOT NULL,
+			applied_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`); err != nil {
+		return err
+	}
+	if err := adoptLegacySchema(tx, migrations); err != nil {
+		return fmt.Errorf("mevcut veritabanı uyarlanamadı: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// prepareMigrations migrationState'i yükler ve uygulanmış sürümlerin dosyalarla uyuştuğunu denetler
+func (db *DB) prepareMigrations() ([]Migration, map[int]appliedMigration, error) {
+	migrations, applied, err := db.migrationState()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, m := range migrations {
+		if a, ok := applied[m.Version]; ok && a.checksum != m.Checksum {
+			return nil, nil, fmt.Errorf("uygulanmış %04d_%s migration'ı sonradan değiştirilmiş; "+
+				"değişiklikler yeni bir migration olarak eklenmelidir", m.Version, m.Name)
+		}
+	}
+	for version, a := range applied {
+		if _, ok := findMigration(migrations, version); !ok {
+			return nil, nil, fmt.Errorf("veritabanında bu sürümde bulunmayan %04d_%s migration'ı uygulanmış; "+
+				"uygulamanın daha yeni bir sürümü kullanılmalıdır", version, a.name)
+		}
+	}
+
+	return migrations, applied, nil
+}
+
+// appliedMigrations schema_migrations kayıtlarını döndürür
+func (db *DB) appliedMigrations() (map[int]appliedMigration, error) {
+	rows, err := db.Query("SELECT version, name, checksum, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]appliedMigration{}
+	for rows.Next() {
+		var a appliedMigration
+		if err := rows.Scan(&a.version, &a.name, &a.checksum, &a.appliedAt); err != nil {
+			return nil, err
+		}
+		applied[a.version] = a
+	}
+
+	return applied, rows.Err()
+}
+
+// MigrationStatuses tüm migration'ları veritabanındaki durumlarıyla döndürür. Veritabanına yazmaz;
+// eski şemadaki veritabanı uyarlanmaz, yalnızca durumu raporlanır. Değiştirilmiş ya da dosyası
+// bulunmayan sürümler hata yerine durum olarak raporlanır.
+func (db *DB) MigrationStatuses() ([]MigrationStatus, error) {
+	migrations, applied, err := db.readMigrationState()
+	if err != nil {
+		return nil, err
+	}
+	legacy, err := db.isLegacySchema()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		status := MigrationStatus{Migration: m, Legacy: legacy && m.Version <= legacyVersion}
+		if a, ok := applied[m.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = a.appliedAt
+			status.Modified = a.checksum != m.Checksum
+			delete(applied, m.Version)
+		}
+		statuses = append(statuses, status)
+	}
+	for _, a := range applied {
+		statuses = append(statuses, MigrationStatus{
+			Migration: Migration{Version: a.version, Name: a.name, Checksum: a.checksum},
+			Applied:   true,
+			AppliedAt: a.appliedAt,
+			Missing:   true,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+
+	return statuses, nil
+}
+
+// LatestSchemaVersion uygulamayla gelen en yüksek migration sürümünü döndürür
+func LatestSchemaVersion() (int, error) {
+	migrations, err := loadMigrations()
+	if err != nil || len(migrations) == 0 {
+		return 0, err
+	}
+	return migrations[len(migrations)-1].Version, nil
+}
+
+// SchemaVersion veritabanına uygulanmış en yüksek sürümü döndürür; hiçbiri uygulanmamışsa 0
+func (db *DB) SchemaVersion() (int, error) {
+	exists, err := db.tableExists("schema_migrations")
+	if err != nil || !exists {
+		return 0, err
+	}
+
+	var version int
+	err = db.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version)
+	return version, err
+}
+
+// MigrateUp bekleyen tüm migration'ları uygular ve uygulananları döndürür
+func (db *DB) MigrateUp() ([]Migration, error) {
+	return db.MigrateTo(-1)
+}
+
+// MigrateDown son uygulanan steps sürümü geri alır ve geri alınanları döndürür
+func (db *DB) MigrateDown(steps int) ([]Migration, error) {
+	if steps <= 0 {
+		return nil, fmt.Errorf("geri alınacak sürüm sayısı pozitif olmalıdır")
+	}
+	migrations, applied, err := db.prepareMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	target := 0
+	for i := len(migrations) - 1; i >= 0; i-- {
+		if _, ok := applied[migrations[i].Version]; !ok {
+			continue
+		}
+		if steps == 0 {
+			target = migrations[i].Version
+			break
+		}
+		steps--
+	}
+
+	return db.migrate(migrations, applied, target, false)
+}
+
+// MigrateTo şemayı verilen sürüme getirir: daha yüksek sürümler geri alınır, verilen sürüme kadar
+// bekleyenler uygulanır. version -1 ise en son sürüme çıkılır. Uygulanan ya da geri alınan
+// migration'ları işlem sırasıyla döndürür.
+func (db *DB) MigrateTo(version int) ([]Migration, error) {
+	migrations, applied, err := db.prepareMigrations()
+	if err != nil {
+		return nil, err
+	}
+	return db.migrate(migrations, applied, version, true)
+}
+
+// migrate version'dan yüksek uygulanmış sürümleri geri alır; apply ise version'a kadar bekleyenleri uygular
+func (db *DB) migrate(migrations []Migration, applied map[int]appliedMigration, version int, apply bool) ([]Migration, error) {
+	if version >= 0 {
+		found := version == 0
+		for _, m := range migrations {
+			found = found || m.Version == version
+		}
+		if !found {
+			return nil, fmt.Errorf("%d sürümünde migration yok", version)
+		}
+	}
+
+	// Geri alınacaklar en yeniden başlayarak, önce hepsinin geri alınabilir olduğu denetlenir
+	var down []Migration
+	for i := len(migrations) - 1; i >= 0; i-- {
+		m := migrations[i]
+		if _, ok := applied[m.Version]; ok && version >= 0 && m.Version > version {
+			if m.Version == 1 {
+				return nil, fmt.Errorf("%04d_%s başlangıç şemasıdır ve geri alınamaz; en fazla 1 sürümüne inilebilir",
+					m.Version, m.Name)
+			}
+			if !m.Reversible() {
+				return nil, fmt.Errorf("%04d_%s migration'ı geri alınamaz", m.Version, m.Name)
+			}
+			down = append(down, m)
+		}
+	}
+
+	done := []Migration{}
+	for _, m := range down {
+		if err := db.runMigration(m, false); err != nil {
+			return done, err
+		}
+		done = append(done, m)
+	}
+	if !apply {
+		return done, nil
+	}
+
+	for _, m := range migrations {
+		if _, ok := applied[m.Version]; ok || (version >= 0 && m.Version > version) {
+			continue
+		}
+		if err := db.runMigration(m, true); err != nil {
+			return done, err
+		}
+		done = append(done, m)
+	}
+
+	return done, nil
+}
+
+// runMigration migration'ı tek bir işlem içinde uygular ya da geri alır ve kaydını günceller
+func (db *DB) runMigration(m Migration, up bool) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	script, record, args := m.Down, "DELETE FROM schema_migrations WHERE version = ?", []interface{}{m.Version}
+	if up {
+		script = m.Up
+		record = "INSERT INTO schema_migrations (version, name, checksum) VALUES (?, ?, ?)"
+		args = append(args, m.Name, m.Checksum)
+	}
+
+	if _, err := tx.Exec(script); err != nil {
+		return migrationError(m, err)
+	}
+	if _, err := tx.Exec(record, args...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// migrationError SQL hatasını hangi migration'da oluştuğunu belirterek döndürür
+func migrationError(m Migration, err error) error {
+	if strings.Contains(err.Error(), "no such module: fts5") {
+		return fmt.Errorf("%04d_%s: SQLite FTS5 desteği bulunamadı, uygulama -tags sqlite_fts5 ile derlenmelidir: %w",
+			m.Version, m.Name, err)
+	}
+	return fmt.Errorf("%04d_%s: %w", m.Version, m.Name, err)
+}
+
+// findMigration verilen sürümdeki migration'ı döndürür
+func findMigration(migrations []Migration, version int) (Migration, bool) {
+	for _, m := range migrations {
+		if m.Version == version {
+			return m, true
+		}
+	}
+	return Migration{}, false
+}
@@ -0,0 +1,194 @@
+package database_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/database/dbtest"
+	"github.com/umutaraz/tradesman-app/internal/models"
+)
+
+func TestMigrationsRollBackAndReapply(t *testing.T) {
+	db := dbtest.New(t)
+
+	statuses, err := db.MigrationStatuses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	latest := statuses[len(statuses)-1].Version
+	for i, s := range statuses {
+		if s.Version != i+1 || !s.Applied || s.Modified || s.Missing {
+			t.Errorf("status %d = %+v", i, s)
+		}
+	}
+	if version, err := db.SchemaVersion(); err != nil || version != latest {
+		t.Fatalf("SchemaVersion = %d, %v; want %d", version, err, latest)
+	}
+
+	if _, err := db.MigrateDown(0); err == nil {
+		t.Error("MigrateDown(0) succeeded")
+	}
+	if _, err := db.MigrateTo(latest + 1); err == nil {
+		t.Error("MigrateTo an unknown version succeeded")
+	}
+
+	done, err := db.MigrateDown(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(done) != 1 || done[0].Version != latest {
+		t.Errorf("MigrateDown(1) = %+v", done)
+	}
+	if version, _ := db.SchemaVersion(); version != latest-1 {
+		t.Errorf("version after rollback = %d, want %d", version, latest-1)
+	}
+
+	done, err = db.MigrateUp()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(done) != 1 || done[0].Version != latest {
+		t.Errorf("MigrateUp = %+v", done)
+	}
+	// Yeniden uygulanan sürüm bekleyen bir şey bırakmaz
+	if done, err := db.MigrateUp(); err != nil || len(done) != 0 {
+		t.Errorf("second MigrateUp = %+v, %v", done, err)
+	}
+}
+
+func TestInitializeAdoptsLegacyDatabase(t *testing.T) {
+	// Depodaki örnek veritabanı migration sisteminden önceki şemayla oluşturulmuştur
+	path := filepath.Join(t.TempDir(), "legacy.db")
+	copyFile(t, filepath.Join("..", "..", "tradesman.db"), path)
+
+	db, err := database.Initialize(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	statuses, err := db.MigrationStatuses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range statuses {
+		if !s.Applied {
+			t.Errorf("%04d_%s not applied", s.Version, s.Name)
+		}
+	}
+
+	var customers int
+	if err := db.QueryRow("SELECT COUNT(*) FROM customers").Scan(&customers); err != nil {
+		t.Fatal(err)
+	}
+	if customers != 5 {
+		t.Errorf("customers = %d, want the 5 legacy customers", customers)
+	}
+	// Mevcut kayıtlar arama dizinine aktarılır
+	results, err := db.Search(1, "ozkan", []string{models.SearchCustomer}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Title != "Fatma Özkan" {
+		t.Errorf("search = %+v, want the legacy customer", results)
+	}
+
+	// İkinci açılış şemayı yeniden uyarlamaz
+	db.Close()
+	db, err = database.Initialize(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+}
+
+func copyFile(t *testing.T, from, to string) {
+	t.Helper()
+	src, err := os.Open(from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	dst, err := os.Create(to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		t.Fatal(err)
+	}
+	if err := dst.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMigrationStatusesIsReadOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "legacy.db")
+	db, err := database.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// Migration sisteminden önceki sürümün şeması gibi yalnızca temel tablolar
+	_, err = db.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NOT NULL,
+		email TEXT UNIQUE NOT NULL, password_hash TEXT NOT NULL)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ro, err := database.OpenReadOnly(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ro.Close()
+
+	statuses, err := ro.MigrationStatuses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range statuses {
+		if s.Applied {
+			t.Errorf("%04d_%s reported as applied", s.Version, s.Name)
+		}
+		if want := s.Version <= 2; s.Legacy != want {
+			t.Errorf("%04d_%s: Legacy = %v, want %v", s.Version, s.Name, s.Legacy, want)
+		}
+	}
+
+	var tables int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'").Scan(&tables); err != nil {
+		t.Fatal(err)
+	}
+	if tables != 1 {
+		t.Errorf("status changed the schema: %d tables, want 1", tables)
+	}
+}
+
+func TestMigrateDownStopsAtInitialSchema(t *testing.T) {
+	db := dbtest.New(t)
+
+	latest, err := database.LatestSchemaVersion()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = db.MigrateDown(latest)
+	if err == nil || !strings.Contains(err.Error(), "başlangıç şemasıdır") {
+		t.Fatalf("MigrateDown(%d) error = %v, want initial schema refusal", latest, err)
+	}
+	if version, err := db.SchemaVersion(); err != nil || version != latest {
+		t.Errorf("schema version after refusal = %d, %v; want %d", version, err, latest)
+	}
+
+	done, err := db.MigrateTo(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(done) != latest-1 {
+		t.Errorf("MigrateTo(1) rolled back %d migrations, want %d", len(done), latest-1)
+	}
+}
@@ -0,0 +1,400 @@
+-- Başlangıç şeması. Migration sisteminden önce oluşturulmuş veritabanları bu sürüme uyarlanırken
+-- dosya mevcut tabloların üzerinde yeniden çalıştırılır; bu yüzden tüm nesneler IF NOT EXISTS ile
+-- oluşturulur. Sonraki migration'larda bu gerekmez.
+
+-- Kullanıcılar tablosu
+CREATE TABLE IF NOT EXISTS users (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL,
+	email TEXT UNIQUE NOT NULL,
+	password_hash TEXT NOT NULL,
+	role TEXT DEFAULT 'user',
+	business_name TEXT,
+	phone TEXT,
+	address TEXT,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	owner_id INTEGER REFERENCES users(id),
+	last_login_at DATETIME
+);
+
+-- Müşteriler tablosu
+CREATE TABLE IF NOT EXISTS customers (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL,
+	name TEXT NOT NULL,
+	email TEXT,
+	phone TEXT,
+	address TEXT,
+	notes TEXT,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	archived_at DATETIME,
+	FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+-- Ürünler/Hizmetler tablosu
+CREATE TABLE IF NOT EXISTS products (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL,
+	name TEXT NOT NULL,
+	description TEXT,
+	price DECIMAL(10,2) NOT NULL,
+	category TEXT,
+	stock_quantity INTEGER DEFAULT 0,
+	unit TEXT DEFAULT 'adet',
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	is_service INTEGER NOT NULL DEFAULT 0,
+	reorder_level INTEGER,
+	reorder_quantity INTEGER NOT NULL DEFAULT 0,
+	FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+-- Siparişler tablosu
+CREATE TABLE IF NOT EXISTS orders (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL,
+	customer_id INTEGER NOT NULL,
+	order_number TEXT UNIQUE NOT NULL,
+	status TEXT DEFAULT 'pending',
+	total_amount DECIMAL(10,2) NOT NULL,
+	notes TEXT,
+	order_date DATETIME DEFAULT CURRENT_TIMESTAMP,
+	delivery_date DATETIME,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	on_credit INTEGER NOT NULL DEFAULT 0,
+	FOREIGN KEY (user_id) REFERENCES users(id),
+	FOREIGN KEY (customer_id) REFERENCES customers(id)
+);
+
+-- Sipariş detayları tablosu
+CREATE TABLE IF NOT EXISTS order_items (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	order_id INTEGER NOT NULL,
+	product_id INTEGER NOT NULL,
+	quantity INTEGER NOT NULL,
+	unit_price DECIMAL(10,2) NOT NULL,
+	total_price DECIMAL(10,2) NOT NULL,
+	FOREIGN KEY (order_id) REFERENCES orders(id),
+	FOREIGN KEY (product_id) REFERENCES products(id)
+);
+
+-- Gelir/Gider takibi tablosu
+CREATE TABLE IF NOT EXISTS transactions (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL,
+	type TEXT NOT NULL CHECK (type IN ('income', 'expense')),
+	category TEXT NOT NULL,
+	amount DECIMAL(10,2) NOT NULL,
+	description TEXT,
+	transaction_date DATETIME DEFAULT CURRENT_TIMESTAMP,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	order_id INTEGER REFERENCES orders(id),
+	FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+-- Oturumlar tablosu
+CREATE TABLE IF NOT EXISTS sessions (
+	token_hash TEXT PRIMARY KEY,
+	user_id INTEGER NOT NULL,
+	expires_at DATETIME NOT NULL,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+-- Sipariş durum geçmişi tablosu
+CREATE TABLE IF NOT EXISTS order_status_history (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	order_id INTEGER NOT NULL,
+	from_status TEXT,
+	to_status TEXT NOT NULL,
+	changed_by INTEGER,
+	note TEXT,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (order_id) REFERENCES orders(id),
+	FOREIGN KEY (changed_by) REFERENCES users(id)
+);
+
+-- Randevular tablosu
+CREATE TABLE IF NOT EXISTS appointments (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL,
+	customer_id INTEGER NOT NULL,
+	staff_id INTEGER NOT NULL,
+	title TEXT NOT NULL,
+	description TEXT,
+	start_time DATETIME NOT NULL,
+	end_time DATETIME NOT NULL,
+	status TEXT DEFAULT 'new',
+	reminder INTEGER DEFAULT 0,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (user_id) REFERENCES users(id),
+	FOREIGN KEY (customer_id) REFERENCES customers(id),
+	FOREIGN KEY (staff_id) REFERENCES users(id)
+);
+CREATE INDEX IF NOT EXISTS idx_appointments_staff_time ON appointments(user_id, staff_id, start_time);
+
+-- Faturalar; numara kesilme anında atanır, taslak dışındaki faturalar değiştirilemez
+CREATE TABLE IF NOT EXISTS invoices (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL,
+	customer_id INTEGER NOT NULL,
+	order_id INTEGER,
+	invoice_number TEXT UNIQUE,
+	invoice_type TEXT NOT NULL DEFAULT 'sales',
+	credit_for_id INTEGER,
+	status TEXT NOT NULL DEFAULT 'draft',
+	invoice_date DATETIME NOT NULL,
+	due_date DATETIME,
+	subtotal REAL NOT NULL DEFAULT 0,
+	tax_amount REAL NOT NULL DEFAULT 0,
+	total_amount REAL NOT NULL DEFAULT 0,
+	notes TEXT,
+	issued_at DATETIME,
+	paid_at DATETIME,
+	voided_at DATETIME,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (user_id) REFERENCES users(id),
+	FOREIGN KEY (customer_id) REFERENCES customers(id),
+	FOREIGN KEY (order_id) REFERENCES orders(id),
+	FOREIGN KEY (credit_for_id) REFERENCES invoices(id)
+);
+
+CREATE TRIGGER IF NOT EXISTS invoices_immutable_update
+BEFORE UPDATE ON invoices
+WHEN OLD.status != 'draft' AND (
+	NEW.customer_id != OLD.customer_id OR NEW.invoice_date != OLD.invoice_date OR
+	NEW.subtotal != OLD.subtotal OR NEW.tax_amount != OLD.tax_amount OR
+	NEW.total_amount != OLD.total_amount OR NEW.invoice_number IS NOT OLD.invoice_number OR
+	NEW.invoice_type != OLD.invoice_type OR NEW.status = 'draft'
+)
+BEGIN
+	SELECT RAISE(ABORT, 'kesilmiş fatura değiştirilemez');
+END;
+
+CREATE TRIGGER IF NOT EXISTS invoices_immutable_delete
+BEFORE DELETE ON invoices
+WHEN OLD.status != 'draft'
+BEGIN
+	SELECT RAISE(ABORT, 'kesilmiş fatura silinemez');
+END;
+
+-- Fatura satırları; her satırın kendi KDV oranı vardır
+CREATE TABLE IF NOT EXISTS invoice_lines (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	invoice_id INTEGER NOT NULL,
+	product_id INTEGER,
+	description TEXT NOT NULL,
+	quantity REAL NOT NULL,
+	unit TEXT DEFAULT 'adet',
+	unit_price REAL NOT NULL,
+	tax_rate INTEGER NOT NULL,
+	subtotal REAL NOT NULL,
+	tax_amount REAL NOT NULL,
+	total REAL NOT NULL,
+	FOREIGN KEY (invoice_id) REFERENCES invoices(id),
+	FOREIGN KEY (product_id) REFERENCES products(id)
+);
+
+CREATE TRIGGER IF NOT EXISTS invoice_lines_immutable_insert
+BEFORE INSERT ON invoice_lines
+WHEN (SELECT status FROM invoices WHERE id = NEW.invoice_id) != 'draft'
+BEGIN
+	SELECT RAISE(ABORT, 'kesilmiş fatura değiştirilemez');
+END;
+
+CREATE TRIGGER IF NOT EXISTS invoice_lines_immutable_update
+BEFORE UPDATE ON invoice_lines
+WHEN (SELECT status FROM invoices WHERE id = OLD.invoice_id) != 'draft'
+BEGIN
+	SELECT RAISE(ABORT, 'kesilmiş fatura değiştirilemez');
+END;
+
+CREATE TRIGGER IF NOT EXISTS invoice_lines_immutable_delete
+BEFORE DELETE ON invoice_lines
+WHEN (SELECT status FROM invoices WHERE id = OLD.invoice_id) != 'draft'
+BEGIN
+	SELECT RAISE(ABORT, 'kesilmiş fatura değiştirilemez');
+END;
+
+-- Fatura numarası sayaçları; işletme, seri ve yıl bazında boşluksuz artar
+CREATE TABLE IF NOT EXISTS invoice_sequences (
+	user_id INTEGER NOT NULL,
+	series TEXT NOT NULL,
+	year INTEGER NOT NULL,
+	last_number INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (user_id, series, year)
+);
+
+-- Giden ileti kuyruğu; arka plandaki işçi gönderir ve başarısızları yeniden dener
+CREATE TABLE IF NOT EXISTS outbox (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL,
+	channel TEXT NOT NULL,
+	recipient TEXT NOT NULL,
+	subject TEXT,
+	payload BLOB NOT NULL,
+	status TEXT NOT NULL DEFAULT 'pending',
+	attempts INTEGER NOT NULL DEFAULT 0,
+	max_attempts INTEGER NOT NULL DEFAULT 6,
+	next_attempt_at DATETIME NOT NULL,
+	last_error TEXT,
+	sent_at DATETIME,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_due ON outbox(status, next_attempt_at);
+
+-- Müşteri etkinlik geçmişi (gönderilen faturalar vb.)
+CREATE TABLE IF NOT EXISTS customer_activities (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL,
+	customer_id INTEGER NOT NULL,
+	activity_type TEXT NOT NULL,
+	description TEXT NOT NULL,
+	invoice_id INTEGER,
+	outbox_id INTEGER,
+	created_by INTEGER,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (user_id) REFERENCES users(id),
+	FOREIGN KEY (customer_id) REFERENCES customers(id),
+	FOREIGN KEY (invoice_id) REFERENCES invoices(id),
+	FOREIGN KEY (outbox_id) REFERENCES outbox(id),
+	FOREIGN KEY (created_by) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_customer_activities_customer ON customer_activities(customer_id, created_at);
+
+-- İşletme ayarları (anahtar/değer)
+CREATE TABLE IF NOT EXISTS settings (
+	user_id INTEGER NOT NULL,
+	key TEXT NOT NULL,
+	value TEXT NOT NULL,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (user_id, key),
+	FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+-- Bildirimler; her kayıt tek bir kullanıcıya aittir
+CREATE TABLE IF NOT EXISTS notifications (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL,
+	type TEXT NOT NULL,
+	title TEXT NOT NULL,
+	message TEXT NOT NULL,
+	link TEXT,
+	read_at DATETIME,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	severity TEXT NOT NULL DEFAULT 'info',
+	FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user ON notifications(user_id, read_at, created_at);
+
+-- Açık düşük stok uyarıları; stok eşiğin üzerine çıkana kadar aynı ürün için yeni uyarı üretilmez
+CREATE TABLE IF NOT EXISTS stock_alerts (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL,
+	product_id INTEGER NOT NULL,
+	stock_quantity INTEGER NOT NULL,
+	reorder_level INTEGER NOT NULL,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	resolved_at DATETIME,
+	FOREIGN KEY (user_id) REFERENCES users(id),
+	FOREIGN KEY (product_id) REFERENCES products(id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stock_alerts_open ON stock_alerts(product_id) WHERE resolved_at IS NULL;
+
+-- Gönderilmiş randevu hatırlatmaları; aynı randevu saati için her kanaldan tek hatırlatma
+-- gönderilmesini sağlar (randevu başka saate alınırsa yeniden hatırlatılır)
+CREATE TABLE IF NOT EXISTS appointment_reminders (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL,
+	appointment_id INTEGER NOT NULL,
+	channel TEXT NOT NULL,
+	start_time DATETIME NOT NULL,
+	notification_id INTEGER,
+	outbox_id INTEGER,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (user_id) REFERENCES users(id),
+	FOREIGN KEY (appointment_id) REFERENCES appointments(id),
+	FOREIGN KEY (notification_id) REFERENCES notifications(id),
+	FOREIGN KEY (outbox_id) REFERENCES outbox(id),
+	UNIQUE (appointment_id, channel, start_time)
+);
+
+-- İşletmenin değiştirdiği mesaj şablonları; kayıt yoksa varsayılan metin kullanılır
+CREATE TABLE IF NOT EXISTS message_templates (
+	user_id INTEGER NOT NULL,
+	key TEXT NOT NULL,
+	body TEXT NOT NULL,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (user_id, key),
+	FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+-- Müşteri cari hesap hareketleri; bakiye borçlar eksi alacaklardır
+CREATE TABLE IF NOT EXISTS ledger_entries (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL,
+	customer_id INTEGER NOT NULL,
+	entry_type TEXT NOT NULL CHECK (entry_type IN ('debit', 'credit')),
+	source TEXT NOT NULL,
+	amount REAL NOT NULL CHECK (amount > 0),
+	description TEXT NOT NULL,
+	payment_method TEXT,
+	entry_date DATETIME NOT NULL,
+	invoice_id INTEGER,
+	order_id INTEGER,
+	created_by INTEGER,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (user_id) REFERENCES users(id),
+	FOREIGN KEY (customer_id) REFERENCES customers(id),
+	FOREIGN KEY (invoice_id) REFERENCES invoices(id),
+	FOREIGN KEY (order_id) REFERENCES orders(id),
+	FOREIGN KEY (created_by) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_customer ON ledger_entries(customer_id, entry_date);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ledger_entries_document ON ledger_entries(source, invoice_id)
+	WHERE source IN ('invoice', 'credit_note', 'invoice_void');
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ledger_entries_order ON ledger_entries(order_id) WHERE source = 'order';
+
+-- Tahsilatların faturalara dağıtımı; bir faturanın dağıtımları toplamı kalan tutarını aşamaz
+CREATE TABLE IF NOT EXISTS payment_allocations (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	entry_id INTEGER NOT NULL,
+	invoice_id INTEGER NOT NULL,
+	amount REAL NOT NULL CHECK (amount > 0),
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (entry_id) REFERENCES ledger_entries(id),
+	FOREIGN KEY (invoice_id) REFERENCES invoices(id),
+	UNIQUE (entry_id, invoice_id)
+);
+
+-- Vadesi geçen faturalar için gönderilen ödeme hatırlatmaları; her kademe fatura başına bir kez gönderilir
+CREATE TABLE IF NOT EXISTS dunning_notices (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL,
+	customer_id INTEGER NOT NULL,
+	invoice_id INTEGER NOT NULL,
+	stage INTEGER NOT NULL,
+	days_overdue INTEGER NOT NULL,
+	outstanding REAL NOT NULL,
+	outbox_id INTEGER,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (user_id) REFERENCES users(id),
+	FOREIGN KEY (customer_id) REFERENCES customers(id),
+	FOREIGN KEY (invoice_id) REFERENCES invoices(id),
+	FOREIGN KEY (outbox_id) REFERENCES outbox(id),
+	UNIQUE (invoice_id, stage)
+);
+
+CREATE INDEX IF NOT EXISTS idx_dunning_notices_user ON dunning_notices(user_id, created_at);
@@ -0,0 +1,14 @@
+DROP TRIGGER IF EXISTS search_customers_rename;
+DROP TRIGGER IF EXISTS search_customers_insert;
+DROP TRIGGER IF EXISTS search_customers_update;
+DROP TRIGGER IF EXISTS search_customers_delete;
+DROP TRIGGER IF EXISTS search_products_insert;
+DROP TRIGGER IF EXISTS search_products_update;
+DROP TRIGGER IF EXISTS search_products_delete;
+DROP TRIGGER IF EXISTS search_orders_insert;
+DROP TRIGGER IF EXISTS search_orders_update;
+DROP TRIGGER IF EXISTS search_orders_delete;
+DROP TRIGGER IF EXISTS search_invoices_insert;
+DROP TRIGGER IF EXISTS search_invoices_update;
+DROP TRIGGER IF EXISTS search_invoices_delete;
+DROP TABLE IF EXISTS search_index;
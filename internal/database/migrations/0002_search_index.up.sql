@@ -0,0 +1,178 @@
+-- Müşteri, ürün, sipariş ve faturalar için genel arama dizini (FTS5). Kayıtlar kaynak tablolardaki
+-- tetikleyicilerle güncel tutulur. unicode61 ayrıştırıcısı büyük/küçük harf ve aksan farklarını yok sayar;
+-- Türkçe noktalı ve noktasız i ayrıştırıcıya gelmeden "i"ye çevrilir.
+--
+-- Dizin satırının rowid'i kaynak kaydın id'si ve türünden türetilir: id * 4 + tür
+-- (0 müşteri, 1 ürün, 2 sipariş, 3 fatura). Güncelleme ve silme tetikleyicileri satırı doğrudan bulur.
+
+CREATE VIRTUAL TABLE search_index USING fts5(
+	kind UNINDEXED,
+	ref_id UNINDEXED,
+	user_id UNINDEXED,
+	label UNINDEXED,
+	detail UNINDEXED,
+	title,
+	body,
+	tokenize = 'unicode61 remove_diacritics 2'
+);
+
+CREATE TRIGGER search_customers_insert AFTER INSERT ON customers
+BEGIN
+	INSERT INTO search_index (rowid, kind, ref_id, user_id, label, detail, title, body)
+	SELECT NEW.id * 4 + 0, 'customer', NEW.id, NEW.user_id,
+		NEW.name,
+		COALESCE(NEW.phone, ''),
+		replace(replace(COALESCE(NEW.name, ''), 'ı', 'i'), 'İ', 'i'),
+		replace(replace(COALESCE(NEW.email, '') || ' ' || COALESCE(NEW.phone, '') || ' ' ||
+			COALESCE(NEW.address, '') || ' ' || COALESCE(NEW.notes, ''), 'ı', 'i'), 'İ', 'i');
+END;
+
+CREATE TRIGGER search_customers_update AFTER UPDATE OF name, email, phone, address, notes ON customers
+BEGIN
+	DELETE FROM search_index WHERE rowid = OLD.id * 4 + 0;
+	INSERT INTO search_index (rowid, kind, ref_id, user_id, label, detail, title, body)
+	SELECT NEW.id * 4 + 0, 'customer', NEW.id, NEW.user_id,
+		NEW.name,
+		COALESCE(NEW.phone, ''),
+		replace(replace(COALESCE(NEW.name, ''), 'ı', 'i'), 'İ', 'i'),
+		replace(replace(COALESCE(NEW.email, '') || ' ' || COALESCE(NEW.phone, '') || ' ' ||
+			COALESCE(NEW.address, '') || ' ' || COALESCE(NEW.notes, ''), 'ı', 'i'), 'İ', 'i');
+END;
+
+CREATE TRIGGER search_customers_delete AFTER DELETE ON customers
+BEGIN
+	DELETE FROM search_index WHERE rowid = OLD.id * 4 + 0;
+END;
+
+CREATE TRIGGER search_products_insert AFTER INSERT ON products
+BEGIN
+	INSERT INTO search_index (rowid, kind, ref_id, user_id, label, detail, title, body)
+	SELECT NEW.id * 4 + 1, 'product', NEW.id, NEW.user_id,
+		NEW.name,
+		COALESCE(NEW.category, ''),
+		replace(replace(COALESCE(NEW.name, ''), 'ı', 'i'), 'İ', 'i'),
+		replace(replace(COALESCE(NEW.category, '') || ' ' || COALESCE(NEW.description, ''), 'ı', 'i'), 'İ', 'i');
+END;
+
+CREATE TRIGGER search_products_update AFTER UPDATE OF name, description, category ON products
+BEGIN
+	DELETE FROM search_index WHERE rowid = OLD.id * 4 + 1;
+	INSERT INTO search_index (rowid, kind, ref_id, user_id, label, detail, title, body)
+	SELECT NEW.id * 4 + 1, 'product', NEW.id, NEW.user_id,
+		NEW.name,
+		COALESCE(NEW.category, ''),
+		replace(replace(COALESCE(NEW.name, ''), 'ı', 'i'), 'İ', 'i'),
+		replace(replace(COALESCE(NEW.category, '') || ' ' || COALESCE(NEW.description, ''), 'ı', 'i'), 'İ', 'i');
+END;
+
+CREATE TRIGGER search_products_delete AFTER DELETE ON products
+BEGIN
+	DELETE FROM search_index WHERE rowid = OLD.id * 4 + 1;
+END;
+
+CREATE TRIGGER search_orders_insert AFTER INSERT ON orders
+BEGIN
+	INSERT INTO search_index (rowid, kind, ref_id, user_id, label, detail, title, body)
+	SELECT NEW.id * 4 + 2, 'order', NEW.id, NEW.user_id,
+		NEW.order_number,
+		(SELECT name FROM customers WHERE customers.id = NEW.customer_id),
+		replace(replace(COALESCE(NEW.order_number, ''), 'ı', 'i'), 'İ', 'i'),
+		replace(replace(COALESCE((SELECT name FROM customers WHERE customers.id = NEW.customer_id), '') || ' ' || COALESCE(NEW.notes, ''), 'ı', 'i'), 'İ', 'i');
+END;
+
+CREATE TRIGGER search_orders_update AFTER UPDATE OF order_number, customer_id, notes ON orders
+BEGIN
+	DELETE FROM search_index WHERE rowid = OLD.id * 4 + 2;
+	INSERT INTO search_index (rowid, kind, ref_id, user_id, label, detail, title, body)
+	SELECT NEW.id * 4 + 2, 'order', NEW.id, NEW.user_id,
+		NEW.order_number,
+		(SELECT name FROM customers WHERE customers.id = NEW.customer_id),
+		replace(replace(COALESCE(NEW.order_number, ''), 'ı', 'i'), 'İ', 'i'),
+		replace(replace(COALESCE((SELECT name FROM customers WHERE customers.id = NEW.customer_id), '') || ' ' || COALESCE(NEW.notes, ''), 'ı', 'i'), 'İ', 'i');
+END;
+
+CREATE TRIGGER search_orders_delete AFTER DELETE ON orders
+BEGIN
+	DELETE FROM search_index WHERE rowid = OLD.id * 4 + 2;
+END;
+
+CREATE TRIGGER search_invoices_insert AFTER INSERT ON invoices
+BEGIN
+	INSERT INTO search_index (rowid, kind, ref_id, user_id, label, detail, title, body)
+	SELECT NEW.id * 4 + 3, 'invoice', NEW.id, NEW.user_id,
+		COALESCE(NEW.invoice_number, 'Taslak fatura'),
+		(SELECT name FROM customers WHERE customers.id = NEW.customer_id),
+		replace(replace(COALESCE(NEW.invoice_number, ''), 'ı', 'i'), 'İ', 'i'),
+		replace(replace(COALESCE((SELECT name FROM customers WHERE customers.id = NEW.customer_id), '') || ' ' || COALESCE(NEW.notes, ''), 'ı', 'i'), 'İ', 'i');
+END;
+
+CREATE TRIGGER search_invoices_update AFTER UPDATE OF invoice_number, customer_id, notes ON invoices
+BEGIN
+	DELETE FROM search_index WHERE rowid = OLD.id * 4 + 3;
+	INSERT INTO search_index (rowid, kind, ref_id, user_id, label, detail, title, body)
+	SELECT NEW.id * 4 + 3, 'invoice', NEW.id, NEW.user_id,
+		COALESCE(NEW.invoice_number, 'Taslak fatura'),
+		(SELECT name FROM customers WHERE customers.id = NEW.customer_id),
+		replace(replace(COALESCE(NEW.invoice_number, ''), 'ı', 'i'), 'İ', 'i'),
+		replace(replace(COALESCE((SELECT name FROM customers WHERE customers.id = NEW.customer_id), '') || ' ' || COALESCE(NEW.notes, ''), 'ı', 'i'), 'İ', 'i');
+END;
+
+CREATE TRIGGER search_invoices_delete AFTER DELETE ON invoices
+BEGIN
+	DELETE FROM search_index WHERE rowid = OLD.id * 4 + 3;
+END;
+
+-- Müşteri adı değişince sipariş ve fatura satırlarındaki ad da yenilenir
+CREATE TRIGGER search_customers_rename AFTER UPDATE OF name ON customers
+BEGIN
+	DELETE FROM search_index WHERE rowid IN (SELECT id * 4 + 2 FROM orders WHERE customer_id = NEW.id);
+	INSERT INTO search_index (rowid, kind, ref_id, user_id, label, detail, title, body)
+	SELECT orders.id * 4 + 2, 'order', orders.id, orders.user_id,
+		orders.order_number,
+		(SELECT name FROM customers WHERE customers.id = orders.customer_id),
+		replace(replace(COALESCE(orders.order_number, ''), 'ı', 'i'), 'İ', 'i'),
+		replace(replace(COALESCE((SELECT name FROM customers WHERE customers.id = orders.customer_id), '') || ' ' || COALESCE(orders.notes, ''), 'ı', 'i'), 'İ', 'i')
+	FROM orders WHERE customer_id = NEW.id;
+	DELETE FROM search_index WHERE rowid IN (SELECT id * 4 + 3 FROM invoices WHERE customer_id = NEW.id);
+	INSERT INTO search_index (rowid, kind, ref_id, user_id, label, detail, title, body)
+	SELECT invoices.id * 4 + 3, 'invoice', invoices.id, invoices.user_id,
+		COALESCE(invoices.invoice_number, 'Taslak fatura'),
+		(SELECT name FROM customers WHERE customers.id = invoices.customer_id),
+		replace(replace(COALESCE(invoices.invoice_number, ''), 'ı', 'i'), 'İ', 'i'),
+		replace(replace(COALESCE((SELECT name FROM customers WHERE customers.id = invoices.customer_id), '') || ' ' || COALESCE(invoices.notes, ''), 'ı', 'i'), 'İ', 'i')
+	FROM invoices WHERE customer_id = NEW.id;
+END;
+
+-- Mevcut kayıtların aktarımı
+INSERT INTO search_index (rowid, kind, ref_id, user_id, label, detail, title, body)
+SELECT customers.id * 4 + 0, 'customer', customers.id, customers.user_id,
+	customers.name,
+	COALESCE(customers.phone, ''),
+	replace(replace(COALESCE(customers.name, ''), 'ı', 'i'), 'İ', 'i'),
+	replace(replace(COALESCE(customers.email, '') || ' ' || COALESCE(customers.phone, '') || ' ' ||
+		COALESCE(customers.address, '') || ' ' || COALESCE(customers.notes, ''), 'ı', 'i'), 'İ', 'i')
+FROM customers;
+
+INSERT INTO search_index (rowid, kind, ref_id, user_id, label, detail, title, body)
+SELECT products.id * 4 + 1, 'product', products.id, products.user_id,
+	products.name,
+	COALESCE(products.category, ''),
+	replace(replace(COALESCE(products.name, ''), 'ı', 'i'), 'İ', 'i'),
+	replace(replace(COALESCE(products.category, '') || ' ' || COALESCE(products.description, ''), 'ı', 'i'), 'İ', 'i')
+FROM products;
+
+INSERT INTO search_index (rowid, kind, ref_id, user_id, label, detail, title, body)
+SELECT orders.id * 4 + 2, 'order', orders.id, orders.user_id,
+	orders.order_number,
+	(SELECT name FROM customers WHERE customers.id = orders.customer_id),
+	replace(replace(COALESCE(orders.order_number, ''), 'ı', 'i'), 'İ', 'i'),
+	replace(replace(COALESCE((SELECT name FROM customers WHERE customers.id = orders.customer_id), '') || ' ' || COALESCE(orders.notes, ''), 'ı', 'i'), 'İ', 'i')
+FROM orders;
+
+INSERT INTO search_index (rowid, kind, ref_id, user_id, label, detail, title, body)
+SELECT invoices.id * 4 + 3, 'invoice', invoices.id, invoices.user_id,
+	COALESCE(invoices.invoice_number, 'Taslak fatura'),
+	(SELECT name FROM customers WHERE customers.id = invoices.customer_id),
+	replace(replace(COALESCE(invoices.invoice_number, ''), 'ı', 'i'), 'İ', 'i'),
+	replace(replace(COALESCE((SELECT name FROM customers WHERE customers.id = invoices.customer_id), '') || ' ' || COALESCE(invoices.notes, ''), 'ı', 'i'), 'İ', 'i')
+FROM invoices;
@@ -0,0 +1,42 @@
+-- Tutarlar yeniden lira cinsinden ondalık olarak saklanır
+
+-- Kesilmiş faturaların değişmezlik tetikleyicileri dönüşüm süresince kaldırılır
+DROP TRIGGER IF EXISTS invoices_immutable_update;
+DROP TRIGGER IF EXISTS invoice_lines_immutable_update;
+
+UPDATE products SET price = price / 100.0;
+UPDATE orders SET total_amount = total_amount / 100.0;
+UPDATE order_items SET unit_price = unit_price / 100.0,
+	total_price = total_price / 100.0;
+UPDATE transactions SET amount = amount / 100.0;
+UPDATE invoices SET subtotal = subtotal / 100.0,
+	tax_amount = tax_amount / 100.0,
+	total_amount = total_amount / 100.0;
+UPDATE invoice_lines SET unit_price = unit_price / 100.0,
+	subtotal = subtotal / 100.0,
+	tax_amount = tax_amount / 100.0,
+	total = total / 100.0;
+UPDATE ledger_entries SET amount = amount / 100.0;
+UPDATE payment_allocations SET amount = amount / 100.0;
+UPDATE dunning_notices SET outstanding = outstanding / 100.0;
+
+CREATE TRIGGER invoices_immutable_update
+BEFORE UPDATE ON invoices
+WHEN OLD.status != 'draft' AND (
+	NEW.customer_id != OLD.customer_id OR NEW.invoice_date != OLD.invoice_date OR
+	NEW.subtotal != OLD.subtotal OR NEW.tax_amount != OLD.tax_amount OR
+	NEW.total_amount != OLD.total_amount OR NEW.invoice_number IS NOT OLD.invoice_number OR
+	NEW.invoice_type != OLD.invoice_type OR NEW.status = 'draft'
+)
+BEGIN
+	SELECT RAISE(ABORT,
+	'kesilmiş fatura değiştirilemez');
+END;
+
+CREATE TRIGGER invoice_lines_immutable_update
+BEFORE UPDATE ON invoice_lines
+WHEN (SELECT status FROM invoices WHERE id = OLD.invoice_id) != 'draft'
+BEGIN
+	SELECT RAISE(ABORT,
+	'kesilmiş fatura değiştirilemez');
+END;
@@ -0,0 +1,44 @@
+-- Tutarlar kayan nokta yerine kuruş cinsinden tam sayı olarak saklanır (internal/money).
+-- Kolon tipleri değişmez: DECIMAL kolonlar tam sayıyı olduğu gibi, REAL kolonlar tam değerli
+-- ondalık olarak tutar; toplamlar 2^53 kuruşa kadar kesindir.
+
+-- Kesilmiş faturaların değişmezlik tetikleyicileri dönüşüm süresince kaldırılır
+DROP TRIGGER IF EXISTS invoices_immutable_update;
+DROP TRIGGER IF EXISTS invoice_lines_immutable_update;
+
+UPDATE products SET price = CAST(ROUND(price * 100) AS INTEGER);
+UPDATE orders SET total_amount = CAST(ROUND(total_amount * 100) AS INTEGER);
+UPDATE order_items SET unit_price = CAST(ROUND(unit_price * 100) AS INTEGER),
+	total_price = CAST(ROUND(total_price * 100) AS INTEGER);
+UPDATE transactions SET amount = CAST(ROUND(amount * 100) AS INTEGER);
+UPDATE invoices SET subtotal = CAST(ROUND(subtotal * 100) AS INTEGER),
+	tax_amount = CAST(ROUND(tax_amount * 100) AS INTEGER),
+	total_amount = CAST(ROUND(total_amount * 100) AS INTEGER);
+UPDATE invoice_lines SET unit_price = CAST(ROUND(unit_price * 100) AS INTEGER),
+	subtotal = CAST(ROUND(subtotal * 100) AS INTEGER),
+	tax_amount = CAST(ROUND(tax_amount * 100) AS INTEGER),
+	total = CAST(ROUND(total * 100) AS INTEGER);
+UPDATE ledger_entries SET amount = CAST(ROUND(amount * 100) AS INTEGER);
+UPDATE payment_allocations SET amount = CAST(ROUND(amount * 100) AS INTEGER);
+UPDATE dunning_notices SET outstanding = CAST(ROUND(outstanding * 100) AS INTEGER);
+
+CREATE TRIGGER invoices_immutable_update
+BEFORE UPDATE ON invoices
+WHEN OLD.status != 'draft' AND (
+	NEW.customer_id != OLD.customer_id OR NEW.invoice_date != OLD.invoice_date OR
+	NEW.subtotal != OLD.subtotal OR NEW.tax_amount != OLD.tax_amount OR
+	NEW.total_amount != OLD.total_amount OR NEW.invoice_number IS NOT OLD.invoice_number OR
+	NEW.invoice_type != OLD.invoice_type OR NEW.status = 'draft'
+)
+BEGIN
+	SELECT RAISE(ABORT,
+	'kesilmiş fatura değiştirilemez');
+END;
+
+CREATE TRIGGER invoice_lines_immutable_update
+BEFORE UPDATE ON invoice_lines
+WHEN (SELECT status FROM invoices WHERE id = OLD.invoice_id) != 'draft'
+BEGIN
+	SELECT RAISE(ABORT,
+	'kesilmiş fatura değiştirilemez');
+END;
@@ -0,0 +1,22 @@
+DROP TABLE IF EXISTS exchange_rates;
+
+DROP TRIGGER IF EXISTS invoices_immutable_update;
+CREATE TRIGGER invoices_immutable_update
+BEFORE UPDATE ON invoices
+WHEN OLD.status != 'draft' AND (
+	NEW.customer_id != OLD.customer_id OR NEW.invoice_date != OLD.invoice_date OR
+	NEW.subtotal != OLD.subtotal OR NEW.tax_amount != OLD.tax_amount OR
+	NEW.total_amount != OLD.total_amount OR NEW.invoice_number IS NOT OLD.invoice_number OR
+	NEW.invoice_type != OLD.invoice_type OR NEW.status = 'draft'
+)
+BEGIN
+	SELECT RAISE(ABORT, 'kesilmiş fatura değiştirilemez');
+END;
+
+ALTER TABLE invoices DROP COLUMN base_total_amount;
+ALTER TABLE invoices DROP COLUMN exchange_rate;
+ALTER TABLE invoices DROP COLUMN base_currency;
+ALTER TABLE invoices DROP COLUMN currency;
+ALTER TABLE transactions DROP COLUMN currency;
+ALTER TABLE orders DROP COLUMN currency;
+ALTER TABLE products DROP COLUMN currency;
@@ -0,0 +1,46 @@
+-- Ürün, sipariş, fatura ve gelir/gider kayıtlarının para birimi. Mevcut kayıtlar Türk lirasıdır.
+ALTER TABLE products ADD COLUMN currency TEXT NOT NULL DEFAULT 'TRY';
+ALTER TABLE orders ADD COLUMN currency TEXT NOT NULL DEFAULT 'TRY';
+ALTER TABLE transactions ADD COLUMN currency TEXT NOT NULL DEFAULT 'TRY';
+ALTER TABLE invoices ADD COLUMN currency TEXT NOT NULL DEFAULT 'TRY';
+
+-- Kesildiği gün işletmenin ana para birimi, geçerli kur (1 birim fatura para birimi kaç birim ana
+-- para birimidir) ve bu kurla çevrilmiş genel toplam. Cari hesap, tahsilat dağıtımı ve vade raporları
+-- ana para biriminde çalışır; döviz faturaları bu tutarla yazılır.
+ALTER TABLE invoices ADD COLUMN base_currency TEXT NOT NULL DEFAULT 'TRY';
+ALTER TABLE invoices ADD COLUMN exchange_rate REAL NOT NULL DEFAULT 1;
+ALTER TABLE invoices ADD COLUMN base_total_amount INTEGER NOT NULL DEFAULT 0;
+
+-- Kesilmiş faturanın para birimi ve kuru da değiştirilemez
+DROP TRIGGER IF EXISTS invoices_immutable_update;
+
+UPDATE invoices SET base_total_amount = total_amount;
+
+CREATE TRIGGER invoices_immutable_update
+BEFORE UPDATE ON invoices
+WHEN OLD.status != 'draft' AND (
+	NEW.customer_id != OLD.customer_id OR NEW.invoice_date != OLD.invoice_date OR
+	NEW.subtotal != OLD.subtotal OR NEW.tax_amount != OLD.tax_amount OR
+	NEW.total_amount != OLD.total_amount OR NEW.invoice_number IS NOT OLD.invoice_number OR
+	NEW.invoice_type != OLD.invoice_type OR NEW.status = 'draft' OR
+	NEW.currency != OLD.currency OR NEW.base_currency != OLD.base_currency OR
+	NEW.exchange_rate != OLD.exchange_rate OR NEW.base_total_amount != OLD.base_total_amount
+)
+BEGIN
+	SELECT RAISE(ABORT, 'kesilmiş fatura değiştirilemez');
+END;
+
+-- Döviz kurları; TCMB gibi 1 birim dövizin Türk lirası karşılığıdır. Ana para birimi farklıysa
+-- çapraz kur iki kurun oranıdır. Bir güne ait kur yoksa önceki en yakın günün kuru kullanılır.
+CREATE TABLE exchange_rates (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL,
+	currency TEXT NOT NULL,
+	rate_date TEXT NOT NULL, -- YYYY-AA-GG
+	rate REAL NOT NULL CHECK (rate > 0),
+	source TEXT NOT NULL DEFAULT 'manual', -- manual, tcmb, csv
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (user_id) REFERENCES users(id),
+	UNIQUE (user_id, currency, rate_date)
+);
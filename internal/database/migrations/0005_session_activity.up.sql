@@ -0,0 +1,3 @@
+-- Oturumun son kullanıldığı an; hareketsiz oturumlar işletmenin otomatik çıkış süresi dolunca kapanır.
+-- Boşsa oturumun açıldığı an esas alınır.
+ALTER TABLE sessions ADD COLUMN last_seen_at DATETIME;
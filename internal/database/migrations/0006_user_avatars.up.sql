@@ -0,0 +1,7 @@
+-- Kullanıcıların profil fotoğrafları; veritabanında tutulduğundan yedeklere de girer
+CREATE TABLE user_avatars (
+	user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+	content_type TEXT NOT NULL,
+	data BLOB NOT NULL,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
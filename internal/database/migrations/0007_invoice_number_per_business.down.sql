@@ -0,0 +1,110 @@
+-- Fatura numarası yeniden tüm veritabanında tekil olur; farklı işletmelerde aynı numara varsa geri
+-- alma başarısız olur
+CREATE TABLE invoices_rebuild AS SELECT * FROM invoices;
+CREATE TABLE invoices_rebuild_sequence AS SELECT seq FROM sqlite_sequence WHERE name = 'invoices';
+
+DROP TRIGGER invoices_immutable_delete;
+DROP TRIGGER invoices_immutable_update;
+DROP TRIGGER search_invoices_insert;
+DROP TRIGGER search_invoices_update;
+DROP TRIGGER search_invoices_delete;
+DROP TABLE invoices;
+
+CREATE TABLE invoices (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL,
+	customer_id INTEGER NOT NULL,
+	order_id INTEGER,
+	invoice_number TEXT UNIQUE,
+	invoice_type TEXT NOT NULL DEFAULT 'sales',
+	credit_for_id INTEGER,
+	status TEXT NOT NULL DEFAULT 'draft',
+	invoice_date DATETIME NOT NULL,
+	due_date DATETIME,
+	subtotal REAL NOT NULL DEFAULT 0,
+	tax_amount REAL NOT NULL DEFAULT 0,
+	total_amount REAL NOT NULL DEFAULT 0,
+	notes TEXT,
+	issued_at DATETIME,
+	paid_at DATETIME,
+	voided_at DATETIME,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	currency TEXT NOT NULL DEFAULT 'TRY',
+	base_currency TEXT NOT NULL DEFAULT 'TRY',
+	exchange_rate REAL NOT NULL DEFAULT 1,
+	base_total_amount INTEGER NOT NULL DEFAULT 0,
+	FOREIGN KEY (user_id) REFERENCES users(id),
+	FOREIGN KEY (customer_id) REFERENCES customers(id),
+	FOREIGN KEY (order_id) REFERENCES orders(id),
+	FOREIGN KEY (credit_for_id) REFERENCES invoices(id)
+);
+
+INSERT INTO invoices (
+	id, user_id, customer_id, order_id, invoice_number, invoice_type, credit_for_id, status, invoice_date,
+	due_date, subtotal, tax_amount, total_amount, notes, issued_at, paid_at, voided_at, created_at, updated_at,
+	currency, base_currency, exchange_rate, base_total_amount
+)
+SELECT
+	id, user_id, customer_id, order_id, invoice_number, invoice_type, credit_for_id, status, invoice_date,
+	due_date, subtotal, tax_amount, total_amount, notes, issued_at, paid_at, voided_at, created_at, updated_at,
+	currency, base_currency, exchange_rate, base_total_amount
+FROM invoices_rebuild;
+
+-- Silinmiş taslakların ID'leri yeniden kullanılmasın
+UPDATE sqlite_sequence SET seq = (SELECT seq FROM invoices_rebuild_sequence)
+WHERE name = 'invoices' AND seq < (SELECT seq FROM invoices_rebuild_sequence);
+INSERT INTO sqlite_sequence (name, seq)
+SELECT 'invoices', seq FROM invoices_rebuild_sequence
+WHERE NOT EXISTS (SELECT 1 FROM sqlite_sequence WHERE name = 'invoices');
+
+DROP TABLE invoices_rebuild;
+DROP TABLE invoices_rebuild_sequence;
+
+-- Tabloyla birlikte silinen tetikleyiciler aynen yeniden oluşturulur
+CREATE TRIGGER invoices_immutable_delete
+BEFORE DELETE ON invoices
+WHEN OLD.status != 'draft'
+BEGIN
+	SELECT RAISE(ABORT, 'kesilmiş fatura silinemez');
+END;
+
+CREATE TRIGGER invoices_immutable_update
+BEFORE UPDATE ON invoices
+WHEN OLD.status != 'draft' AND (
+	NEW.customer_id != OLD.customer_id OR NEW.invoice_date != OLD.invoice_date OR
+	NEW.subtotal != OLD.subtotal OR NEW.tax_amount != OLD.tax_amount OR
+	NEW.total_amount != OLD.total_amount OR NEW.invoice_number IS NOT OLD.invoice_number OR
+	NEW.invoice_type != OLD.invoice_type OR NEW.status = 'draft' OR
+	NEW.currency != OLD.currency OR NEW.base_currency != OLD.base_currency OR
+	NEW.exchange_rate != OLD.exchange_rate OR NEW.base_total_amount != OLD.base_total_amount
+)
+BEGIN
+	SELECT RAISE(ABORT, 'kesilmiş fatura değiştirilemez');
+END;
+
+CREATE TRIGGER search_invoices_delete AFTER DELETE ON invoices
+BEGIN
+	DELETE FROM search_index WHERE rowid = OLD.id * 4 + 3;
+END;
+
+CREATE TRIGGER search_invoices_insert AFTER INSERT ON invoices
+BEGIN
+	INSERT INTO search_index (rowid, kind, ref_id, user_id, label, detail, title, body)
+	SELECT NEW.id * 4 + 3, 'invoice', NEW.id, NEW.user_id,
+		COALESCE(NEW.invoice_number, 'Taslak fatura'),
+		(SELECT name FROM customers WHERE customers.id = NEW.customer_id),
+		replace(replace(COALESCE(NEW.invoice_number, ''), 'ı', 'i'), 'İ', 'i'),
+		replace(replace(COALESCE((SELECT name FROM customers WHERE customers.id = NEW.customer_id), '') || ' ' || COALESCE(NEW.notes, ''), 'ı', 'i'), 'İ', 'i');
+END;
+
+CREATE TRIGGER search_invoices_update AFTER UPDATE OF invoice_number, customer_id, notes ON invoices
+BEGIN
+	DELETE FROM search_index WHERE rowid = OLD.id * 4 + 3;
+	INSERT INTO search_index (rowid, kind, ref_id, user_id, label, detail, title, body)
+	SELECT NEW.id * 4 + 3, 'invoice', NEW.id, NEW.user_id,
+		COALESCE(NEW.invoice_number, 'Taslak fatura'),
+		(SELECT name FROM customers WHERE customers.id = NEW.customer_id),
+		replace(replace(COALESCE(NEW.invoice_number, ''), 'ı', 'i'), 'İ', 'i'),
+		replace(replace(COALESCE((SELECT name FROM customers WHERE customers.id = NEW.customer_id), '') || ' ' || COALESCE(NEW.notes, ''), 'ı', 'i'), 'İ', 'i');
+END;
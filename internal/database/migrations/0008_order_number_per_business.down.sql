@@ -0,0 +1,75 @@
+-- Sipariş numarası yeniden tüm veritabanında tekil olur; farklı işletmelerde aynı numara varsa geri
+-- alma başarısız olur
+DROP TABLE order_sequences;
+
+CREATE TABLE orders_rebuild AS SELECT * FROM orders;
+CREATE TABLE orders_rebuild_sequence AS SELECT seq FROM sqlite_sequence WHERE name = 'orders';
+
+DROP TRIGGER search_orders_insert;
+DROP TRIGGER search_orders_update;
+DROP TRIGGER search_orders_delete;
+DROP TABLE orders;
+
+CREATE TABLE orders (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL,
+	customer_id INTEGER NOT NULL,
+	order_number TEXT UNIQUE NOT NULL,
+	status TEXT DEFAULT 'pending',
+	total_amount DECIMAL(10,2) NOT NULL,
+	notes TEXT,
+	order_date DATETIME DEFAULT CURRENT_TIMESTAMP,
+	delivery_date DATETIME,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	on_credit INTEGER NOT NULL DEFAULT 0,
+	currency TEXT NOT NULL DEFAULT 'TRY',
+	FOREIGN KEY (user_id) REFERENCES users(id),
+	FOREIGN KEY (customer_id) REFERENCES customers(id)
+);
+
+INSERT INTO orders (
+	id, user_id, customer_id, order_number, status, total_amount, notes, order_date, delivery_date, created_at,
+	updated_at, on_credit, currency
+)
+SELECT
+	id, user_id, customer_id, order_number, status, total_amount, notes, order_date, delivery_date, created_at,
+	updated_at, on_credit, currency
+FROM orders_rebuild;
+
+-- Silinmiş siparişlerin ID'leri yeniden kullanılmasın
+UPDATE sqlite_sequence SET seq = (SELECT seq FROM orders_rebuild_sequence)
+WHERE name = 'orders' AND seq < (SELECT seq FROM orders_rebuild_sequence);
+INSERT INTO sqlite_sequence (name, seq)
+SELECT 'orders', seq FROM orders_rebuild_sequence
+WHERE NOT EXISTS (SELECT 1 FROM sqlite_sequence WHERE name = 'orders');
+
+DROP TABLE orders_rebuild;
+DROP TABLE orders_rebuild_sequence;
+
+-- Tabloyla birlikte silinen tetikleyiciler aynen yeniden oluşturulur
+CREATE TRIGGER search_orders_delete AFTER DELETE ON orders
+BEGIN
+	DELETE FROM search_index WHERE rowid = OLD.id * 4 + 2;
+END;
+
+CREATE TRIGGER search_orders_insert AFTER INSERT ON orders
+BEGIN
+	INSERT INTO search_index (rowid, kind, ref_id, user_id, label, detail, title, body)
+	SELECT NEW.id * 4 + 2, 'order', NEW.id, NEW.user_id,
+		NEW.order_number,
+		(SELECT name FROM customers WHERE customers.id = NEW.customer_id),
+		replace(replace(COALESCE(NEW.order_number, ''), 'ı', 'i'), 'İ', 'i'),
+		replace(replace(COALESCE((SELECT name FROM customers WHERE customers.id = NEW.customer_id), '') || ' ' || COALESCE(NEW.notes, ''), 'ı', 'i'), 'İ', 'i');
+END;
+
+CREATE TRIGGER search_orders_update AFTER UPDATE OF order_number, customer_id, notes ON orders
+BEGIN
+	DELETE FROM search_index WHERE rowid = OLD.id * 4 + 2;
+	INSERT INTO search_index (rowid, kind, ref_id, user_id, label, detail, title, body)
+	SELECT NEW.id * 4 + 2, 'order', NEW.id, NEW.user_id,
+		NEW.order_number,
+		(SELECT name FROM customers WHERE customers.id = NEW.customer_id),
+		replace(replace(COALESCE(NEW.order_number, ''), 'ı', 'i'), 'İ', 'i'),
+		replace(replace(COALESCE((SELECT name FROM customers WHERE customers.id = NEW.customer_id), '') || ' ' || COALESCE(NEW.notes, ''), 'ı', 'i'), 'İ', 'i');
+END;
@@ -0,0 +1,8 @@
+-- Kurulumun yöneticileri. Veritabanı yedeği bütün işletmeleri içerdiğinden yedekleri yalnızca sistem
+-- yöneticileri görebilir, alabilir ve geri yükleyebilir. Bu yetki işletme rollerinden ayrıdır; kayıt
+-- olan hesaplara verilmez, sunucuda "tradesman-app admin grant <e-posta>" komutuyla verilir.
+ALTER TABLE users ADD COLUMN system_admin BOOLEAN NOT NULL DEFAULT 0;
+
+-- Mevcut kurulumlarda yedekleri şimdiye kadar yöneten ilk işletme sahibi yönetici olarak kalır
+UPDATE users SET system_admin = 1
+WHERE id = (SELECT id FROM users WHERE owner_id IS NULL ORDER BY id LIMIT 1);
@@ -0,0 +1,35 @@
+package database
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/models"
+)
+
+// Execer *sql.DB ve *sql.Tx tarafından karşılanır
+type Execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// InsertNotification bildirimi kaydeder ve ID'sini n.ID alanına yazar
+func InsertNotification(exec Execer, n *models.Notification) error {
+	if n.CreatedAt.IsZero() {
+		n.CreatedAt = time.Now()
+	}
+
+	result, err := exec.Exec(`
+		INSERT INTO notifications (user_id, type, severity, title, message, link, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, n.UserID, n.Type, n.Severity, n.Title, n.Message, n.Link, n.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	n.ID = int(id)
+	return nil
+}
@@ -0,0 +1,28 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/money"
+)
+
+// InvoiceOutstandingExpr faturanın dağıtılan tahsilatlar ve kesilen iade faturaları düşüldükten
+// sonra kalan tutarını ana para biriminde veren SQL ifadesi. invoices tablosu takma adsız
+// kullanılmalıdır.
+var InvoiceOutstandingExpr = fmt.Sprintf(`(invoices.base_total_amount
+	- (SELECT COALESCE(SUM(pa.amount), 0) FROM payment_allocations pa WHERE pa.invoice_id = invoices.id)
+	- (SELECT COALESCE(SUM(cn.base_total_amount), 0) FROM invoices cn
+	   WHERE cn.credit_for_id = invoices.id AND cn.status IN ('%s', '%s', '%s')))`,
+	models.InvoiceIssued, models.InvoiceOverdue, models.InvoicePaid)
+
+// CustomerBalanceExpr müşterinin cari hesap bakiyesini (borçlar eksi alacaklar) veren SQL ifadesi.
+// customers tablosu takma adsız kullanılmalıdır.
+var CustomerBalanceExpr = fmt.Sprintf(`(SELECT COALESCE(SUM(CASE WHEN l.entry_type = '%s'
+	THEN l.amount ELSE -l.amount END), 0)
+	FROM ledger_entries l WHERE l.customer_id = customers.id)`, models.LedgerDebit)
+
+// CustomerCurrencyExpr cari bakiyenin para birimini, yani müşterinin işletmesinin ana para birimini
+// veren SQL ifadesi. customers tablosu takma adsız kullanılmalıdır.
+var CustomerCurrencyExpr = fmt.Sprintf(`COALESCE((SELECT s.value FROM settings s
+	WHERE s.user_id = customers.user_id AND s.key = '%s'), '%s')`, SettingCurrency, money.DefaultCurrency)
@@ -0,0 +1,74 @@
+package database
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/umutaraz/tradesman-app/internal/models"
+)
+
+// Genel arama dizini müşteri, ürün, sipariş ve faturaları tek bir FTS5 tablosunda tutar
+// (migrations/0002_search_index.up.sql). Kayıtlar kaynak tablolardaki tetikleyicilerle güncel tutulur;
+// büyük/küçük harf, aksan ve Türkçe noktalı/noktasız i farkları yok sayılır.
+
+// SearchMatch kullanıcının yazdığı metni FTS5 sorgusuna çevirir: her kelime ön ek olarak aranır
+// ve tüm kelimelerin eşleşmesi gerekir. Aranacak kelime yoksa boş döner.
+func SearchMatch(query string) string {
+	words := strings.FieldsFunc(query, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		word = strings.NewReplacer("ı", "i", "İ", "i").Replace(word)
+		terms = append(terms, `"`+word+`"*`)
+	}
+	return strings.Join(terms, " ")
+}
+
+// Search işletmenin kayıtlarında arama yapar ve en iyi eşleşmeden başlayarak döndürür.
+// kinds boşsa tüm türlerde aranır; URL alanını çağıran doldurur.
+func (db *DB) Search(userID int, query string, kinds []string, limit int) ([]models.SearchResult, error) {
+	results := []models.SearchResult{}
+	match := SearchMatch(query)
+	if match == "" {
+		return results, nil
+	}
+
+	where := "search_index MATCH ? AND user_id = ?"
+	args := []interface{}{match, userID}
+	if len(kinds) > 0 {
+		where += " AND kind IN (?" + strings.Repeat(", ?", len(kinds)-1) + ")"
+		for _, kind := range kinds {
+			args = append(args, kind)
+		}
+	}
+	args = append(args, limit)
+
+	// Başlıktaki eşleşme diğer alanlardan daha değerlidir; bm25 daha iyi eşleşmede daha küçüktür
+	rows, err := db.Query(`
+		SELECT kind, ref_id, label, detail, -bm25(search_index, 0, 0, 0, 0, 0, 10.0, 1.0) AS score
+		FROM search_index
+		WHERE `+where+`
+		ORDER BY score DESC
+		LIMIT ?
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r models.SearchResult
+		if err := rows.Scan(&r.Type, &r.ID, &r.Title, &r.Subtitle, &r.Score); err != nil {
+			return nil, err
+		}
+		results = append(results, r)
+	}
+
+	return results, rows.Err()
+}
+
+// SearchIDs aramayla eşleşen belirli türdeki kayıtların ID'lerini veren alt sorgu;
+// "id IN (" + SearchIDs + ")" biçiminde kullanılır ve SearchMatch sonucunu parametre alır
+const SearchIDs = "SELECT ref_id FROM search_index WHERE search_index MATCH ? AND kind = ?"
@@ -0,0 +1,182 @@
+package database_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/database/dbtest"
+	"github.com/umutaraz/tradesman-app/internal/models"
+)
+
+func TestSearchMatch(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"kab", `"kab"*`},
+		{"  Işık  İzmir ", `"Işik"* "izmir"*`},
+		{"SIP-2026-001", `"SIP"* "2026"* "001"*`},
+		{`kablo" OR x`, `"kablo"* "OR"* "x"*`},
+		{"*-?", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := database.SearchMatch(tt.query); got != tt.want {
+			t.Errorf("SearchMatch(%q) = %s, want %s", tt.query, got, tt.want)
+		}
+	}
+}
+
+// searchFixture aramada kullanılan kayıtları ekler
+type searchFixture struct {
+	db         *database.DB
+	userID     int
+	customerID int
+	orderID    int
+	invoiceID  int
+	productID  int
+}
+
+func newSearchFixture(t *testing.T) searchFixture {
+	t.Helper()
+	db := dbtest.New(t)
+	f := searchFixture{db: db, userID: dbtest.User(t, db, "sahip@example.com")}
+	f.customerID = f.insert(t, "INSERT INTO customers (user_id, name, phone, address) VALUES (?, 'Işıl Çelik', '0533 111 22 33', 'Gökçe Sokak, İzmir')", f.userID)
+	f.productID = f.insert(t, "INSERT INTO products (user_id, name, category, price) VALUES (?, 'Kablo Kanalı', 'Elektrik', 25)", f.userID)
+	f.orderID = f.insert(t, "INSERT INTO orders (user_id, customer_id, order_number, total_amount) VALUES (?, ?, 'SIP-2026-001', 100)", f.userID, f.customerID)
+	f.invoiceID = f.insert(t, `INSERT INTO invoices (user_id, customer_id, invoice_number, status, invoice_date)
+		VALUES (?, ?, 'FTR2026000000001', 'issued', CURRENT_TIMESTAMP)`, f.userID, f.customerID)
+
+	// Başka işletmenin aynı adlı kayıtları
+	otherID := dbtest.User(t, db, "diger@example.com")
+	other := f.insert(t, "INSERT INTO customers (user_id, name) VALUES (?, 'Işıl Çelik')", otherID)
+	f.insert(t, "INSERT INTO products (user_id, name, price) VALUES (?, 'Kablo Kanalı', 30)", otherID)
+	f.insert(t, "INSERT INTO orders (user_id, customer_id, order_number, total_amount) VALUES (?, ?, 'SIP-2026-002', 50)", otherID, other)
+	return f
+}
+
+func (f searchFixture) insert(t *testing.T, query string, args ...interface{}) int {
+	t.Helper()
+	result, err := f.db.Exec(query, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, _ := result.LastInsertId()
+	return int(id)
+}
+
+// search eşleşen kayıtları "tür:id" biçiminde döndürür
+func (f searchFixture) search(t *testing.T, query string, kinds ...string) map[string]models.SearchResult {
+	t.Helper()
+	results, err := f.db.Search(f.userID, query, kinds, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := make(map[string]models.SearchResult)
+	for _, r := range results {
+		found[r.Type+":"+strconv.Itoa(r.ID)] = r
+	}
+	return found
+}
+
+func TestSearchFoldsTurkishAndMatchesPrefixes(t *testing.T) {
+	f := newSearchFixture(t)
+	customer := models.SearchCustomer + ":" + strconv.Itoa(f.customerID)
+	product := models.SearchProduct + ":" + strconv.Itoa(f.productID)
+	order := models.SearchOrder + ":" + strconv.Itoa(f.orderID)
+	invoice := models.SearchInvoice + ":" + strconv.Itoa(f.invoiceID)
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		// Noktalı/noktasız i ve aksanlar yok sayılır
+		{"ISIL", []string{customer, order, invoice}},
+		{"isil celik", []string{customer, order, invoice}},
+		{"ışıl", []string{customer, order, invoice}},
+		{"izmir", []string{customer}},
+		{"gokce", []string{customer}},
+		// Kelimeler ön ek olarak aranır ve hepsi eşleşmelidir
+		{"kab", []string{product}},
+		{"kab kan", []string{product}},
+		{"kablo celik", nil},
+		{"SIP-2026", []string{order}},
+		{"FTR2026", []string{invoice}},
+		{"0533", []string{customer}},
+	}
+	for _, tt := range tests {
+		found := f.search(t, tt.query)
+		if len(found) != len(tt.want) {
+			t.Errorf("search %q = %v, want %v", tt.query, found, tt.want)
+			continue
+		}
+		for _, key := range tt.want {
+			if _, ok := found[key]; !ok {
+				t.Errorf("search %q = %v, missing %s", tt.query, found, key)
+			}
+		}
+	}
+
+	// Sonuç başlıkları orijinal yazımı korur ve tür filtresi uygulanır
+	found := f.search(t, "isil", models.SearchCustomer)
+	if len(found) != 1 || found[customer].Title != "Işıl Çelik" || found[customer].Subtitle != "0533 111 22 33" {
+		t.Errorf("customer search = %+v", found)
+	}
+}
+
+func TestSearchIndexFollowsChanges(t *testing.T) {
+	f := newSearchFixture(t)
+	order := models.SearchOrder + ":" + strconv.Itoa(f.orderID)
+	invoice := models.SearchInvoice + ":" + strconv.Itoa(f.invoiceID)
+
+	// Müşteri adı değişince sipariş ve fatura da yeni adla bulunur
+	if _, err := f.db.Exec("UPDATE customers SET name = 'Ayşe Şahin' WHERE id = ?", f.customerID); err != nil {
+		t.Fatal(err)
+	}
+	found := f.search(t, "sahin", models.SearchOrder, models.SearchInvoice)
+	if _, ok := found[order]; !ok || len(found) != 2 || found[invoice].Subtitle != "Ayşe Şahin" {
+		t.Errorf("after rename = %+v", found)
+	}
+	if found := f.search(t, "isil"); len(found) != 0 {
+		t.Errorf("old name still matches: %+v", found)
+	}
+
+	if _, err := f.db.Exec("UPDATE products SET name = 'Priz' WHERE id = ?", f.productID); err != nil {
+		t.Fatal(err)
+	}
+	if found := f.search(t, "kablo"); len(found) != 0 {
+		t.Errorf("renamed product still matches: %+v", found)
+	}
+
+	if _, err := f.db.Exec("DELETE FROM orders WHERE id = ?", f.orderID); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := f.search(t, "SIP")[order]; ok {
+		t.Error("deleted order still matches")
+	}
+}
+
+func TestSearchIsScopedToBusiness(t *testing.T) {
+	f := newSearchFixture(t)
+	for _, query := range []string{"isil", "kablo", "SIP-2026"} {
+		results, err := f.db.Search(f.userID, query, nil, 20)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) == 0 {
+			t.Errorf("search %q found nothing", query)
+		}
+		for _, r := range results {
+			var userID int
+			table := map[string]string{models.SearchCustomer: "customers", models.SearchProduct: "products",
+				models.SearchOrder: "orders", models.SearchInvoice: "invoices"}[r.Type]
+			if err := f.db.QueryRow("SELECT user_id FROM "+table+" WHERE id = ?", r.ID).Scan(&userID); err != nil {
+				t.Fatal(err)
+			}
+			if userID != f.userID {
+				t.Errorf("search %q returned %s %d of business %d", query, r.Type, r.ID, userID)
+			}
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package database
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/models"
+)
+
+// ErrSessionNotFound oturum bulunamadığında ya da süresi dolduğunda döner
+var ErrSessionNotFound = errors.New("oturum bulunamadı")
+
+// sessionTouchInterval oturumun son kullanım zamanının en sık güncellenme aralığı; her istekte
+// veritabanına yazılmasın diye
+const sessionTouchInterval = time.Minute
+
+// CreateSession kullanıcı için yeni bir oturum açar ve çereze yazılacak
+// ham token'ı döndürür. Veritabanında yalnızca token'ın özeti saklanır.
+func (db *DB) CreateSession(userID int, ttl time.Duration) (string, time.Time, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", time.Time{}, fmt.Errorf("oturum anahtarı üretilemedi: %w", err)
+	}
+	token := hex.EncodeToString(buf)
+	expiresAt := time.Now().Add(ttl)
+
+	_, err := db.Exec(`
+		INSERT INTO sessions (token_hash, user_id, expires_at)
+		VALUES (?, ?, ?)
+	`, hashToken(token), userID, expiresAt)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return token, expiresAt, nil
+}
+
+// SessionUser token'a ait oturumun kullanıcısını döndürür
+func (db *DB) SessionUser(token string) (*models.User, error) {
+	var user models.User
+	var ownerID sql.NullInt64
+	var businessName, phone, address sql.NullString
+	var lastLoginAt sql.NullTime
+	err := db.QueryRow(`
+		SELECT u.id, u.owner_id, u.name, u.email, u.role, u.business_name, u.phone, u.address,
+		       u.last_login_at, u.created_at, u.updated_at
+		FROM sessions s
+		JOIN users u ON s.user_id = u.id
+		WHERE s.token_hash = ? AND s.expires_at > ?
+	`, hashToken(token), time.Now()).Scan(&user.ID, &ownerID, &user.Name, &user.Email, &user.Role,
+		&businessName, &phone, &address, &lastLoginAt, &user.CreatedAt, &user.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if ownerID.Valid {
+		id := int(ownerID.Int64)
+		user.OwnerID = &id
+	}
+	if lastLoginAt.Valid {
+		user.LastLoginAt = &lastLoginAt.Time
+	}
+	user.BusinessName = businessName.String
+	user.Phone = phone.String
+	user.Address = address.String
+
+	return &user, nil
+}
+
+// TouchSession oturumun son kullanım zamanını günceller. idle sıfırdan büyükse ve oturum bu süreden
+// uzun süredir kullanılmıyorsa oturum silinir ve ErrSessionNotFound döner.
+func (db *DB) TouchSession(token string, idle time.Duration) error {
+	hash := hashToken(token)
+	var lastSeenAt, createdAt sql.NullTime
+	err := db.QueryRow("SELECT last_seen_at, created_at FROM sessions WHERE token_hash = ?", hash).
+		Scan(&lastSeenAt, &createdAt)
+	if err == sql.ErrNoRows {
+		return ErrSessionNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	lastSeen := createdAt.Time
+	if lastSeenAt.Valid {
+		lastSeen = lastSeenAt.Time
+	}
+	if idle > 0 && now.Sub(lastSeen) > idle {
+		if _, err := db.Exec("DELETE FROM sessions WHERE token_hash = ?", hash); err != nil {
+			return err
+		}
+		return ErrSessionNotFound
+	}
+	if now.Sub(lastSeen) < sessionTouchInterval {
+		return nil
+	}
+
+	_, err = db.Exec("UPDATE sessions SET last_seen_at = ? WHERE token_hash = ?", now, hash)
+	return err
+}
+
+// DeleteSession oturumu sonlandırır
+func (db *DB) DeleteSession(token string) error {
+	_, err := db.Exec("DELETE FROM sessions WHERE token_hash = ?", hashToken(token))
+	return err
+}
+
+// DeleteUserSessions kullanıcının exceptToken dışındaki tüm oturumlarını sonlandırır
+func (db *DB) DeleteUserSessions(userID int, exceptToken string) error {
+	_, err := db.Exec("DELETE FROM sessions WHERE user_id = ? AND token_hash <> ?", userID, hashToken(exceptToken))
+	return err
+}
+
+// DeleteExpiredSessions süresi dolmuş oturumları temizler
+func (db *DB) DeleteExpiredSessions() error {
+	_, err := db.Exec("DELETE FROM sessions WHERE expires_at <= ?", time.Now())
+	return err
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,82 @@
+package database_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/database/dbtest"
+)
+
+func TestSessionLifecycle(t *testing.T) {
+	db := dbtest.New(t)
+	userID := dbtest.User(t, db, "sahip@example.com")
+
+	token, expiresAt, err := db.CreateSession(userID, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(token) != 64 || time.Until(expiresAt) <= 0 {
+		t.Fatalf("token = %q, expires at %v", token, expiresAt)
+	}
+
+	user, err := db.SessionUser(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.ID != userID || user.Email != "sahip@example.com" {
+		t.Errorf("session user = %d %s", user.ID, user.Email)
+	}
+
+	// Veritabanında ham token değil özeti saklanır
+	var stored int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sessions WHERE token_hash = ?", token).Scan(&stored); err != nil {
+		t.Fatal(err)
+	}
+	if stored != 0 {
+		t.Error("raw session token stored in the database")
+	}
+
+	if err := db.DeleteSession(token); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.SessionUser(token); !errors.Is(err, database.ErrSessionNotFound) {
+		t.Errorf("after logout: err = %v, want ErrSessionNotFound", err)
+	}
+	if _, err := db.SessionUser("bilinmeyen"); !errors.Is(err, database.ErrSessionNotFound) {
+		t.Errorf("unknown token: err = %v, want ErrSessionNotFound", err)
+	}
+}
+
+func TestExpiredSessions(t *testing.T) {
+	db := dbtest.New(t)
+	userID := dbtest.User(t, db, "sahip@example.com")
+
+	expired, _, err := db.CreateSession(userID, -time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	active, _, err := db.CreateSession(userID, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := db.SessionUser(expired); !errors.Is(err, database.ErrSessionNotFound) {
+		t.Errorf("expired session: err = %v, want ErrSessionNotFound", err)
+	}
+
+	if err := db.DeleteExpiredSessions(); err != nil {
+		t.Fatal(err)
+	}
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sessions").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("sessions after cleanup = %d, want 1", count)
+	}
+	if _, err := db.SessionUser(active); err != nil {
+		t.Errorf("active session removed: %v", err)
+	}
+}
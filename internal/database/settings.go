@@ -0,0 +1,104 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+)
+
+// Ayar anahtarları
+const (
+	// SettingLowStockLevel ürün bazında eşik tanımlanmamışsa kullanılan düşük stok seviyesi
+	SettingLowStockLevel = "low_stock_level"
+	// SettingReminderEmail randevu hatırlatmalarının müşteriye e-postayla da gönderilip gönderilmeyeceği
+	SettingReminderEmail = "reminder_email"
+	// SettingReminderSMS randevu hatırlatmalarının müşteriye SMS ile de gönderilip gönderilmeyeceği
+	SettingReminderSMS = "reminder_sms"
+	// SettingDunningStages ödeme hatırlatmalarının gönderileceği gecikme günleri (virgülle ayrılmış);
+	// boşsa hatırlatma gönderilmez
+	SettingDunningStages = "dunning_stages"
+	// SettingDunningSMS ödeme hatırlatmalarının müşteriye SMS ile de gönderilip gönderilmeyeceği
+	SettingDunningSMS = "dunning_sms"
+	// SettingPageSize liste sayfalarında sayfa başına gösterilen kayıt sayısı
+	SettingPageSize = "page_size"
+	// SettingCurrency işletmenin ana para birimi; cari hesap ve raporlar bu birimde tutulur
+	SettingCurrency = "currency"
+	// SettingDateFormat sayfalarda gösterilen tarih biçimi (dd.mm.yyyy, dd/mm/yyyy, mm/dd/yyyy, yyyy-mm-dd)
+	SettingDateFormat = "date_format"
+	// SettingTaxRate oranı belirtilmemiş fatura satırlarına uygulanan KDV oranı
+	SettingTaxRate = "tax_rate"
+	// SettingAutoLogout hareketsiz oturumun kapanacağı süre (dakika); 0 ise kapalı
+	SettingAutoLogout = "auto_logout_minutes"
+	// SettingThemeMode arayüz teması (light, dark, system)
+	SettingThemeMode = "theme_mode"
+	// SettingThemeColor arayüzün ana rengi
+	SettingThemeColor = "theme_color"
+	// SettingDashboardCards dashboard'da gösterilen kartlar (virgülle ayrılmış)
+	SettingDashboardCards = "dashboard_cards"
+	// SettingBackupEnabled otomatik veritabanı yedeklemesinin açık olup olmadığı
+	SettingBackupEnabled = "backup_enabled"
+	// SettingBackupFrequency otomatik yedekleme sıklığı (daily, weekly, monthly)
+	SettingBackupFrequency = "backup_frequency"
+	// SettingBackupKeep saklanacak en fazla otomatik yedek sayısı
+	SettingBackupKeep = "backup_keep"
+)
+
+// DefaultLowStockLevel ayar kaydedilmemişse kullanılan düşük stok seviyesi
+const DefaultLowStockLevel = 10
+
+// DefaultDunningStages ayar kaydedilmemişse kullanılan ödeme hatırlatma kademeleri
+const DefaultDunningStages = "7,30,60"
+
+// LowStockLevelExpr ürünün geçerli düşük stok eşiğini veren SQL ifadesi: ürüne özel eşik,
+// yoksa işletme ayarı, o da yoksa varsayılan. products tablosu takma adsız kullanılmalıdır.
+var LowStockLevelExpr = fmt.Sprintf(`COALESCE(products.reorder_level,
+	(SELECT CAST(value AS INTEGER) FROM settings WHERE settings.user_id = products.user_id AND settings.key = '%s'),
+	%d)`, SettingLowStockLevel, DefaultLowStockLevel)
+
+// GetSetting işletmenin ayar değerini döndürür; kayıt yoksa defaultValue döner
+func (db *DB) GetSetting(userID int, key, defaultValue string) (string, error) {
+	var value string
+	err := db.QueryRow("SELECT value FROM settings WHERE user_id = ? AND key = ?", userID, key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return defaultValue, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+// GetIntSetting sayısal ayar değerini döndürür; kayıt yoksa ya da sayı değilse defaultValue döner
+func (db *DB) GetIntSetting(userID int, key string, defaultValue int) (int, error) {
+	value, err := db.GetSetting(userID, key, "")
+	if err != nil || value == "" {
+		return defaultValue, err
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return defaultValue, nil
+	}
+	return n, nil
+}
+
+// GetBoolSetting açık/kapalı ayar değerini döndürür; kayıt yoksa ya da geçersizse defaultValue döner
+func (db *DB) GetBoolSetting(userID int, key string, defaultValue bool) (bool, error) {
+	value, err := db.GetSetting(userID, key, "")
+	if err != nil || value == "" {
+		return defaultValue, err
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return defaultValue, nil
+	}
+	return b, nil
+}
+
+// SetSetting ayar değerini kaydeder
+func (db *DB) SetSetting(userID int, key, value string) error {
+	_, err := db.Exec(`
+		INSERT INTO settings (user_id, key, value, updated_at) VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT (user_id, key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at
+	`, userID, key, value)
+	return err
+}
@@ -0,0 +1,134 @@
+// Package exchange döviz kurlarını saklar ve tutarları işletmenin ana para birimine çevirir.
+//
+// Kurlar TCMB'nin yayımladığı gibi 1 birim dövizin Türk lirası karşılığıdır; Türk lirasının kuru her
+// zaman 1'dir. Ana para birimi Türk lirası değilse çapraz kur iki kurun oranıyla bulunur. Bir güne
+// ait kur yoksa o günden önceki en yakın günün kuru kullanılır (hafta sonu ve tatillerde TCMB kur
+// yayımlamaz).
+package exchange
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/money"
+)
+
+// DateLayout kur tarihlerinin saklandığı biçim
+const DateLayout = "2006-01-02"
+
+// Rounding çevrilen tutarların kuruşa yuvarlanma kuralı; çok sayıda çevrimde hata birikmesin diye
+// banker yuvarlaması kullanılır
+const Rounding = money.HalfEven
+
+// ErrNoRate istenen gün ya da öncesi için kur girilmemişse döner
+var ErrNoRate = errors.New("kur bulunamadı")
+
+// Queryer *sql.DB ve *sql.Tx için ortak sorgu arayüzü; kurlar açık bir işlem içinden de okunabilir
+type Queryer interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// Rate currency'nin date günündeki (yoksa önceki en yakın gündeki) Türk lirası karşılığını döndürür
+func Rate(q Queryer, userID int, currency money.Currency, date time.Time) (*big.Rat, error) {
+	if currency == money.TRY {
+		return big.NewRat(1, 1), nil
+	}
+
+	var rate float64
+	err := q.QueryRow(`
+		SELECT rate FROM exchange_rates
+		WHERE user_id = ? AND currency = ? AND rate_date <= ?
+		ORDER BY rate_date DESC LIMIT 1
+	`, userID, currency, date.In(time.Local).Format(DateLayout)).Scan(&rate)
+	if err == sql.ErrNoRows {
+		// Paket işletmenin tarih biçimini bilmez; tarih kurların saklandığı biçimde yazılır
+		return nil, fmt.Errorf("%w: %s, %s", ErrNoRate, currency, date.In(time.Local).Format(DateLayout))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return RatFromFloat(rate), nil
+}
+
+// CrossRate 1 birim from'un date günündeki to karşılığını döndürür
+func CrossRate(q Queryer, userID int, from, to money.Currency, date time.Time) (*big.Rat, error) {
+	if from == to {
+		return big.NewRat(1, 1), nil
+	}
+
+	fromRate, err := Rate(q, userID, from, date)
+	if err != nil {
+		return nil, err
+	}
+	toRate, err := Rate(q, userID, to, date)
+	if err != nil {
+		return nil, err
+	}
+
+	return fromRate.Quo(fromRate, toRate), nil
+}
+
+// Converter tutarları işlem günündeki kurla ana para birimine çevirir. Aynı gün ve para birimi
+// için kur bir kez okunur; bir rapor hazırlanırken kullanılıp atılmalıdır.
+type Converter struct {
+	q      Queryer
+	userID int
+	base   money.Currency
+	rates  map[rateKey]*big.Rat
+}
+
+type rateKey struct {
+	currency money.Currency
+	date     string
+}
+
+// NewConverter işletmenin kurlarıyla base para birimine çeviren Converter oluşturur
+func NewConverter(q Queryer, userID int, base money.Currency) *Converter {
+	return &Converter{q: q, userID: userID, base: base, rates: map[rateKey]*big.Rat{}}
+}
+
+// Base çevrilen tutarların para birimi
+func (c *Converter) Base() money.Currency {
+	return c.base
+}
+
+// Convert tutarı date günündeki kurla ana para birimine çevirir; kur yoksa ErrNoRate döner
+func (c *Converter) Convert(m money.Money, date time.Time) (money.Money, error) {
+	if m.Currency() == c.base {
+		return m.WithCurrency(c.base), nil
+	}
+
+	key := rateKey{currency: m.Currency(), date: date.In(time.Local).Format(DateLayout)}
+	rate, ok := c.rates[key]
+	if !ok {
+		var err error
+		rate, err = CrossRate(c.q, c.userID, m.Currency(), c.base, date)
+		if err != nil {
+			return money.Money{}, err
+		}
+		c.rates[key] = rate
+	}
+
+	return m.Convert(c.base, rate, Rounding), nil
+}
+
+// RateFloat kuru altı ondalığa yuvarlayarak veritabanına yazılacak sayıya çevirir
+func RateFloat(rate *big.Rat) float64 {
+	f, _ := strconv.ParseFloat(rate.FloatString(6), 64)
+	return f
+}
+
+// RatFromFloat kayıtlı kuru en kısa ondalık gösterimiyle kesre çevirir; 34.2574 tam olarak
+// 342574/10000 sayılır
+func RatFromFloat(f float64) *big.Rat {
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(f, 'f', -1, 64))
+	if !ok {
+		return big.NewRat(1, 1)
+	}
+	return r
+}
@@ -0,0 +1,200 @@
+package exchange_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/database/dbtest"
+	"github.com/umutaraz/tradesman-app/internal/exchange"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/money"
+)
+
+func day(d int) time.Time {
+	return time.Date(2024, 10, d, 12, 0, 0, 0, time.Local)
+}
+
+func saveRate(t *testing.T, db *database.DB, userID int, currency money.Currency, date string, rate float64) {
+	t.Helper()
+	r := &models.ExchangeRate{UserID: userID, Currency: currency, Date: date, Rate: rate}
+	if err := exchange.Validate(r); err != nil {
+		t.Fatal(err)
+	}
+	if err := exchange.Save(db, r); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRateFallsBackToPreviousDay(t *testing.T) {
+	db := dbtest.New(t)
+	userID := dbtest.User(t, db, "sahip@example.com")
+	otherID := dbtest.User(t, db, "diger@example.com")
+	saveRate(t, db, userID, money.USD, "2024-10-17", 34.2574)
+	saveRate(t, db, userID, money.USD, "2024-10-18", 34.3)
+
+	tests := []struct {
+		name     string
+		userID   int
+		currency money.Currency
+		date     time.Time
+		want     float64
+		wantErr  error
+	}{
+		{"same day", userID, money.USD, day(17), 34.2574, nil},
+		// Hafta sonu TCMB kur yayımlamaz; cuma günün kuru kullanılır
+		{"weekend", userID, money.USD, day(20), 34.3, nil},
+		{"before first rate", userID, money.USD, day(16), 0, exchange.ErrNoRate},
+		{"other currency", userID, money.EUR, day(17), 0, exchange.ErrNoRate},
+		{"other business", otherID, money.USD, day(17), 0, exchange.ErrNoRate},
+		{"lira", userID, money.TRY, day(1), 1, nil},
+	}
+	for _, tt := range tests {
+		rate, err := exchange.Rate(db, tt.userID, tt.currency, tt.date)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err == nil && exchange.RateFloat(rate) != tt.want {
+			t.Errorf("%s: rate = %v, want %v", tt.name, exchange.RateFloat(rate), tt.want)
+		}
+	}
+
+	// Aynı gün yeniden girilen kur öncekinin üzerine yazılır
+	saveRate(t, db, userID, money.USD, "2024-10-17", 34.26)
+	rates, err := exchange.List(db, userID, money.USD, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rates) != 2 || rates[1].Rate != 34.26 {
+		t.Errorf("rates = %+v", rates)
+	}
+}
+
+func TestConverter(t *testing.T) {
+	db := dbtest.New(t)
+	userID := dbtest.User(t, db, "sahip@example.com")
+	saveRate(t, db, userID, money.USD, "2024-10-17", 34.2574)
+	saveRate(t, db, userID, money.EUR, "2024-10-17", 37.158)
+
+	tests := []struct {
+		name    string
+		base    money.Currency
+		m       money.Money
+		want    money.Money
+		wantErr error
+	}{
+		{"base currency", money.TRY, money.TL(12345), money.TL(12345), nil},
+		{"USD to TRY", money.TRY, money.New(10000, money.USD), money.TL(342574), nil},
+		// Ana para birimi TL değilse iki kurun oranı kullanılır: 3425,74 / 37,158 = 92,1938…
+		{"cross rate", money.EUR, money.New(10000, money.USD), money.New(9219, money.EUR), nil},
+		{"TRY to EUR", money.EUR, money.TL(371580), money.New(10000, money.EUR), nil},
+		{"missing rate", money.TRY, money.New(100, money.GBP), money.Money{}, exchange.ErrNoRate},
+	}
+	for _, tt := range tests {
+		c := exchange.NewConverter(db, userID, tt.base)
+		got, err := c.Convert(tt.m, day(18))
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: Convert = %v %s, want %v %s", tt.name, got, got.Currency(), tt.want, tt.want.Currency())
+		}
+	}
+}
+
+const tcmbBulletin = `<?xml version="1.0" encoding="UTF-8"?>
+<Tarih_Date Tarih="17.10.2024" Date="10/17/2024" Bulten_No="2024/195">
+	<Currency CrossOrder="0" Kod="USD" CurrencyCode="USD">
+		<Unit>1</Unit>
+		<Isim>ABD DOLARI</Isim>
+		<ForexBuying>34.2574</ForexBuying>
+	</Currency>
+	<Currency CrossOrder="9" Kod="EUR" CurrencyCode="EUR">
+		<Unit>1</Unit>
+		<ForexBuying>37.1580</ForexBuying>
+	</Currency>
+	<Currency CrossOrder="12" Kod="JPY" CurrencyCode="JPY">
+		<Unit>100</Unit>
+		<ForexBuying>22.8896</ForexBuying>
+	</Currency>
+	<Currency CrossOrder="11" Kod="GBP" CurrencyCode="GBP">
+		<Unit>10</Unit>
+		<ForexBuying>446.52</ForexBuying>
+	</Currency>
+	<Currency Kod="XDR" CurrencyCode="XDR">
+		<Unit>1</Unit>
+		<ForexBuying></ForexBuying>
+	</Currency>
+</Tarih_Date>`
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    []models.ExchangeRate
+		wantErr bool
+	}{
+		{"TCMB", "\xef\xbb\xbf" + tcmbBulletin, []models.ExchangeRate{
+			{Currency: money.USD, Date: "2024-10-17", Rate: 34.2574, Source: models.RateSourceTCMB},
+			{Currency: money.EUR, Date: "2024-10-17", Rate: 37.158, Source: models.RateSourceTCMB},
+			{Currency: money.GBP, Date: "2024-10-17", Rate: 44.652, Source: models.RateSourceTCMB},
+		}, false},
+		{"CSV with header", "tarih;para_birimi;kur\n2024-10-17;USD;34,2574\n17.10.2024;eur;37,1580\n\n", []models.ExchangeRate{
+			{Currency: money.USD, Date: "2024-10-17", Rate: 34.2574, Source: models.RateSourceCSV},
+			{Currency: money.EUR, Date: "2024-10-17", Rate: 37.158, Source: models.RateSourceCSV},
+		}, false},
+		{"CSV with commas", "2024-10-18,GBP,44.652\n", []models.ExchangeRate{
+			{Currency: money.GBP, Date: "2024-10-18", Rate: 44.652, Source: models.RateSourceCSV},
+		}, false},
+		{"TCMB without rates", `<Tarih_Date Tarih="17.10.2024"></Tarih_Date>`, nil, true},
+		{"TCMB without date", `<Tarih_Date><Currency Kod="USD"><ForexBuying>34</ForexBuying></Currency></Tarih_Date>`, nil, true},
+		{"CSV bad date", "2024-10-17;USD;34\n2024-13-01;USD;34\n", nil, true},
+		{"CSV unsupported currency", "2024-10-17;CHF;39\n", nil, true},
+		{"CSV negative rate", "2024-10-17;USD;-1\n", nil, true},
+		{"CSV missing column", "2024-10-17;USD\n", nil, true},
+		{"empty", "", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := exchange.Parse(strings.NewReader(tt.in))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: rates = %+v, want %+v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: rate %d = %+v, want %+v", tt.name, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rate    models.ExchangeRate
+		wantErr bool
+	}{
+		{"valid", models.ExchangeRate{Currency: "usd", Date: "2024-10-17", Rate: 34.2}, false},
+		{"lira", models.ExchangeRate{Currency: money.TRY, Date: "2024-10-17", Rate: 1}, true},
+		{"unsupported", models.ExchangeRate{Currency: "CHF", Date: "2024-10-17", Rate: 39}, true},
+		{"bad date", models.ExchangeRate{Currency: money.USD, Date: "17.10.2024", Rate: 34.2}, true},
+		{"zero rate", models.ExchangeRate{Currency: money.USD, Date: "2024-10-17"}, true},
+	}
+	for _, tt := range tests {
+		err := exchange.Validate(&tt.rate)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if err == nil && (tt.rate.Currency != money.USD || tt.rate.Source != models.RateSourceManual) {
+			t.Errorf("%s: rate = %+v, want normalized USD manual rate", tt.name, tt.rate)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package exchange
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/money"
+)
+
+// maxImportSize içe aktarılabilecek kur dosyasının en büyük boyutu
+const maxImportSize = 5 << 20
+
+// Parse kur dosyasını okur; içerik XML ise TCMB biçiminde, değilse CSV olarak çözümlenir
+func Parse(r io.Reader) ([]models.ExchangeRate, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxImportSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxImportSize {
+		return nil, errors.New("kur dosyası çok büyük")
+	}
+
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("<")) {
+		return ParseTCMB(bytes.NewReader(data))
+	}
+	return ParseCSV(bytes.NewReader(data))
+}
+
+// tcmbBulletin TCMB'nin günlük kur dosyası (https://www.tcmb.gov.tr/kurlar/today.xml)
+type tcmbBulletin struct {
+	Tarih      string `xml:"Tarih,attr"` // 17.10.2024
+	Date       string `xml:"Date,attr"`  // 10/17/2024
+	Currencies []struct {
+		Code        string `xml:"CurrencyCode,attr"`
+		Kod         string `xml:"Kod,attr"`
+		Unit        string `xml:"Unit"`
+		ForexBuying string `xml:"ForexBuying"`
+	} `xml:"Currency"`
+}
+
+// ParseTCMB TCMB kur dosyasındaki desteklenen para birimlerinin döviz alış kurlarını okur.
+// TCMB bazı kurları 1'den fazla birim için verir; kurlar birim başına çevrilir.
+func ParseTCMB(r io.Reader) ([]models.ExchangeRate, error) {
+	var bulletin tcmbBulletin
+	if err := xml.NewDecoder(r).Decode(&bulletin); err != nil {
+		return nil, fmt.Errorf("TCMB kur dosyası okunamadı: %w", err)
+	}
+
+	date, err := time.ParseInLocation("02.01.2006", bulletin.Tarih, time.Local)
+	if err != nil {
+		date, err = time.ParseInLocation("01/02/2006", bulletin.Date, time.Local)
+		if err != nil {
+			return nil, errors.New("TCMB kur dosyasında tarih bulunamadı")
+		}
+	}
+
+	var rates []models.ExchangeRate
+	for _, c := range bulletin.Currencies {
+		code := c.Code
+		if code == "" {
+			code = c.Kod
+		}
+		currency, err := money.ParseCurrency(code)
+		if err != nil || currency == money.TRY || strings.TrimSpace(c.ForexBuying) == "" {
+			continue
+		}
+
+		rate, err := parseRate(c.ForexBuying)
+		if err != nil {
+			return nil, fmt.Errorf("%s kuru okunamadı: %w", currency, err)
+		}
+		if unit, err := strconv.Atoi(strings.TrimSpace(c.Unit)); err == nil && unit > 1 {
+			rate /= float64(unit)
+		}
+
+		rates = append(rates, models.ExchangeRate{
+			Currency: currency,
+			Date:     date.Format(DateLayout),
+			Rate:     rate,
+			Source:   models.RateSourceTCMB,
+		})
+	}
+
+	if len(rates) == 0 {
+		return nil, errors.New("TCMB kur dosyasında desteklenen para birimi bulunamadı")
+	}
+	return rates, nil
+}
+
+// ParseCSV tarih, para birimi ve kur kolonlarından oluşan CSV dosyasını okur:
+//
+//	tarih;para_birimi;kur
+//	2024-10-17;USD;34,2574
+//	17.10.2024;EUR;37,1580
+//
+// Ayırıcı virgül ya da noktalı virgül olabilir; ilk satır başlıksa atlanır. Tarihler YYYY-AA-GG ya
+// da GG.AA.YYYY, kurlar noktalı ya da virgüllü ondalık olabilir.
+func ParseCSV(r io.Reader) ([]models.ExchangeRate, error) {
+	br := bufio.NewReader(r)
+	first, _ := br.Peek(1024)
+	delimiter := ','
+	if line, _, _ := bytes.Cut(first, []byte("\n")); bytes.Count(line, []byte(";")) > bytes.Count(line, []byte(",")) {
+		delimiter = ';'
+	}
+
+	reader := csv.NewReader(br)
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var rates []models.ExchangeRate
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("CSV okunamadı: %w", err)
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		if len(record) < 3 {
+			return nil, fmt.Errorf("%d. satır: tarih, para birimi ve kur kolonları gerekli", line)
+		}
+
+		date, dateErr := parseDate(record[0])
+		if dateErr != nil && line == 1 {
+			// Başlık satırı
+			continue
+		}
+		if dateErr != nil {
+			return nil, fmt.Errorf("%d. satır: %w", line, dateErr)
+		}
+		currency, err := money.ParseCurrency(record[1])
+		if err != nil {
+			return nil, fmt.Errorf("%d. satır: %w", line, err)
+		}
+		rate, err := parseRate(record[2])
+		if err != nil {
+			return nil, fmt.Errorf("%d. satır: %w", line, err)
+		}
+
+		rates = append(rates, models.ExchangeRate{
+			Currency: currency,
+			Date:     date.Format(DateLayout),
+			Rate:     rate,
+			Source:   models.RateSourceCSV,
+		})
+	}
+
+	if len(rates) == 0 {
+		return nil, errors.New("CSV dosyasında kur bulunamadı")
+	}
+	return rates, nil
+}
+
+// parseDate YYYY-AA-GG ya da GG.AA.YYYY biçimindeki tarihi okur
+func parseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{DateLayout, "02.01.2006"} {
+		if date, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("geçersiz tarih: %s", s)
+}
+
+// parseRate noktalı (34.2574) ya da virgüllü (34,2574) ondalık kuru okur
+func parseRate(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if strings.Contains(s, ",") {
+		s = strings.ReplaceAll(strings.ReplaceAll(s, ".", ""), ",", ".")
+	}
+	rate, err := strconv.ParseFloat(s, 64)
+	if err != nil || rate <= 0 {
+		return 0, fmt.Errorf("geçersiz kur: %s", s)
+	}
+	return rate, nil
+}
@@ -0,0 +1,135 @@
+package exchange
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/money"
+)
+
+// List işletmenin kurlarını yeniden eskiye döndürür; currency boş değilse yalnızca o para birimi,
+// limit 0 ise hepsi
+func List(db *database.DB, userID int, currency money.Currency, limit int) ([]models.ExchangeRate, error) {
+	query := `
+		SELECT id, user_id, currency, rate_date, rate, source, created_at, updated_at
+		FROM exchange_rates WHERE user_id = ?`
+	args := []interface{}{userID}
+	if currency != "" {
+		query += " AND currency = ?"
+		args = append(args, currency)
+	}
+	query += " ORDER BY rate_date DESC, currency"
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rates := []models.ExchangeRate{}
+	for rows.Next() {
+		var rate models.ExchangeRate
+		err := rows.Scan(&rate.ID, &rate.UserID, &rate.Currency, &rate.Date, &rate.Rate, &rate.Source,
+			&rate.CreatedAt, &rate.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+
+	return rates, rows.Err()
+}
+
+// Validate kuru doğrular ve para birimi ile tarihi saklanacak biçime getirir
+func Validate(rate *models.ExchangeRate) error {
+	currency, err := money.ParseCurrency(string(rate.Currency))
+	if err != nil {
+		return err
+	}
+	if currency == money.TRY {
+		return errors.New("Türk lirasının kuru her zaman 1'dir")
+	}
+	rate.Currency = currency
+
+	date, err := time.ParseInLocation(DateLayout, rate.Date, time.Local)
+	if err != nil {
+		return fmt.Errorf("geçersiz kur tarihi, YYYY-AA-GG biçiminde olmalıdır: %s", rate.Date)
+	}
+	rate.Date = date.Format(DateLayout)
+
+	if rate.Rate <= 0 {
+		return errors.New("kur sıfırdan büyük olmalıdır")
+	}
+	if rate.Source == "" {
+		rate.Source = models.RateSourceManual
+	}
+	return nil
+}
+
+// Save kuru kaydeder; aynı gün ve para birimi için kayıt varsa üzerine yazar. Kur Validate ile
+// doğrulanmış olmalıdır.
+func Save(db *database.DB, rate *models.ExchangeRate) error {
+	return save(db, rate)
+}
+
+// Import dosyadan okunan kurları tek işlemde kaydeder ve kaydedilen kur sayısını döndürür.
+// Kurlar Validate ile doğrulanmış olmalıdır.
+func Import(db *database.DB, userID int, rates []models.ExchangeRate) (int, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	for i := range rates {
+		rates[i].UserID = userID
+		if err := save(tx, &rates[i]); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(rates), tx.Commit()
+}
+
+// Delete işletmenin kurunu siler; kayıt yoksa sql.ErrNoRows döner
+func Delete(db *database.DB, userID, id int) error {
+	result, err := db.Exec("DELETE FROM exchange_rates WHERE id = ? AND user_id = ?", id, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// execer *sql.DB ve *sql.Tx için ortak yazma arayüzü
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+func save(e execer, rate *models.ExchangeRate) error {
+	now := time.Now()
+	_, err := e.Exec(`
+		INSERT INTO exchange_rates (user_id, currency, rate_date, rate, source, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (user_id, currency, rate_date) DO UPDATE
+		SET rate = excluded.rate, source = excluded.source, updated_at = excluded.updated_at
+	`, rate.UserID, rate.Currency, rate.Date, rate.Rate, rate.Source, now, now)
+	if err != nil {
+		return err
+	}
+
+	return e.QueryRow(`
+		SELECT id, created_at, updated_at FROM exchange_rates WHERE user_id = ? AND currency = ? AND rate_date = ?
+	`, rate.UserID, rate.Currency, rate.Date).Scan(&rate.ID, &rate.CreatedAt, &rate.UpdatedAt)
+}
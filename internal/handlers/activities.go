@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umutaraz/tradesman-app/internal/middleware"
+	"github.com/umutaraz/tradesman-app/internal/models"
+)
+
+// Müşterinin etkinlik geçmişi; e-posta gönderimlerinde kuyruktaki iletinin durumu da döner
+func (h *Handler) GetCustomerActivitiesAPI(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	businessID := middleware.BusinessID(c)
+	if _, err := h.customers.Get(businessID, id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	activities, err := h.getCustomerActivities(businessID, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, activities)
+}
+
+func (h *Handler) getCustomerActivities(userID, customerID int) ([]models.CustomerActivity, error) {
+	rows, err := h.db.Query(`
+		SELECT a.id, a.user_id, a.customer_id, a.activity_type, a.description, a.invoice_id, a.outbox_id,
+		       COALESCE(o.status, ''), COALESCE(o.last_error, ''), COALESCE(a.created_by, 0),
+		       COALESCE(u.name, ''), a.created_at
+		FROM customer_activities a
+		LEFT JOIN outbox o ON a.outbox_id = o.id
+		LEFT JOIN users u ON a.created_by = u.id
+		WHERE a.user_id = ? AND a.customer_id = ?
+		ORDER BY a.created_at DESC, a.id DESC
+	`, userID, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var activities []models.CustomerActivity
+	for rows.Next() {
+		var activity models.CustomerActivity
+		var invoiceID, outboxID sql.NullInt64
+		err := rows.Scan(&activity.ID, &activity.UserID, &activity.CustomerID, &activity.Type, &activity.Description,
+			&invoiceID, &outboxID, &activity.DeliveryStatus, &activity.DeliveryError, &activity.CreatedBy,
+			&activity.CreatedByName, &activity.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		activity.InvoiceID = nullIntPtr(invoiceID)
+		activity.OutboxID = nullIntPtr(outboxID)
+		activities = append(activities, activity)
+	}
+
+	return activities, rows.Err()
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umutaraz/tradesman-app/internal/backup"
+	"github.com/umutaraz/tradesman-app/internal/listquery"
+	"github.com/umutaraz/tradesman-app/internal/repository"
+	"github.com/umutaraz/tradesman-app/internal/settings"
+)
+
+// Servis katmanı hataları; API yanıtında uygun HTTP koduna çevrilir. Depolarla aynı hata
+// türleri kullanılır.
+var (
+	errNotFound   = repository.ErrNotFound
+	errValidation = repository.ErrValidation
+	errConflict   = repository.ErrConflict
+)
+
+func newValidationError(message string) error {
+	return repository.ValidationError(message)
+}
+
+func newConflictError(message string) error {
+	return repository.ConflictError(message)
+}
+
+// paramID URL'deki sayısal ID parametresini okur, geçersizse 400 döner
+func paramID(c *gin.Context, name string) (int, bool) {
+	id, err := strconv.Atoi(c.Param(name))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz ID"})
+		return 0, false
+	}
+	return id, true
+}
+
+// respondError hatayı tutarlı bir JSON gövdesiyle döndürür
+func respondError(c *gin.Context, err error) {
+	status, message := errorResponse(err)
+	c.JSON(status, gin.H{"error": message})
+}
+
+// errorResponse hatayı HTTP durum koduna ve kullanıcıya gösterilecek mesaja çevirir
+func errorResponse(err error) (int, string) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows), errors.Is(err, errNotFound):
+		return http.StatusNotFound, errNotFound.Error()
+	case errors.Is(err, backup.ErrNotFound):
+		return http.StatusNotFound, err.Error()
+	case errors.Is(err, backup.ErrInvalid):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, errValidation):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, errConflict), errors.Is(err, settings.ErrCurrencyLocked):
+		return http.StatusConflict, err.Error()
+	case listquery.IsError(err):
+		return http.StatusBadRequest, err.Error()
+	default:
+		return http.StatusInternalServerError, err.Error()
+	}
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umutaraz/tradesman-app/internal/config"
+	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/database/dbtest"
+	"github.com/umutaraz/tradesman-app/internal/middleware"
+	"github.com/umutaraz/tradesman-app/internal/repository"
+	"github.com/umutaraz/tradesman-app/internal/settings"
+)
+
+func TestRespondError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		err    error
+		status int
+		body   string
+	}{
+		{errNotFound, http.StatusNotFound, `{"error":"kayıt bulunamadı"}`},
+		{newValidationError("Ürün adı zorunludur"), http.StatusBadRequest, `{"error":"Ürün adı zorunludur"}`},
+		{errors.New("disk dolu"), http.StatusInternalServerError, `{"error":"disk dolu"}`},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		respondError(c, tt.err)
+		if w.Code != tt.status || w.Body.String() != tt.body {
+			t.Errorf("respondError(%v) = %d %s, want %d %s", tt.err, w.Code, w.Body, tt.status, tt.body)
+		}
+	}
+}
+
+// testHandler SQLite depolarıyla çalışan bir handler oluşturur
+func testHandler(db *database.DB) *Handler {
+	return New(db, repository.NewSQLite(db.DB), &config.Config{}, nil, settings.NewStore(db), nil)
+}
+
+// apiTestRouter müşteri uçlarını gerçek oturum doğrulamasıyla çalıştırır
+func apiTestRouter(db *database.DB) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := testHandler(db)
+
+	r := gin.New()
+	api := r.Group("/api/v1", middleware.Auth(db, func(int) time.Duration { return 0 }))
+	api.GET("/customers/:id", h.GetCustomerAPI)
+	api.PUT("/customers/:id", h.UpdateCustomer)
+	api.DELETE("/customers/:id", h.DeleteCustomer)
+	api.POST("/customers/:id/restore", h.RestoreCustomer)
+	return r
+}
+
+// apiRequest kullanıcı adına açılmış bir oturumla istek oluşturur
+func apiRequest(t *testing.T, db *database.DB, userID int, method, path, body string) *http.Request {
+	t.Helper()
+	token, _, err := db.CreateSession(userID, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.AddCookie(&http.Cookie{Name: middleware.SessionCookieName, Value: token})
+	return req
+}
+
+func TestCustomerAPIIsScopedToBusiness(t *testing.T) {
+	db := dbtest.New(t)
+	ownerID := dbtest.User(t, db, "sahip@example.com")
+	otherID := dbtest.User(t, db, "diger@example.com")
+
+	result, err := db.Exec("INSERT INTO customers (user_id, name, phone) VALUES (?, ?, ?)", ownerID, "Ayşe Demir", "0533 111 22 33")
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, _ := result.LastInsertId()
+	path := "/api/v1/customers/" + strconv.FormatInt(id, 10)
+	r := apiTestRouter(db)
+
+	tests := []struct {
+		name   string
+		userID int
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"invalid id", ownerID, http.MethodGet, "/api/v1/customers/abc", "", http.StatusBadRequest},
+		{"other business reads", otherID, http.MethodGet, path, "", http.StatusNotFound},
+		{"other business updates", otherID, http.MethodPut, path, `{"name":"Başkası"}`, http.StatusNotFound},
+		{"other business deletes", otherID, http.MethodDelete, path, "", http.StatusNotFound},
+		{"owner updates", ownerID, http.MethodPut, path, `{"name":"Ayşe Yılmaz","phone":"0533 111 22 33"}`, http.StatusOK},
+		{"owner reads", ownerID, http.MethodGet, path, "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, apiRequest(t, db, tt.userID, tt.method, tt.path, tt.body))
+		if w.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d (%s)", tt.name, w.Code, tt.status, w.Body)
+		}
+	}
+
+	var name string
+	if err := db.QueryRow("SELECT name FROM customers WHERE id = ?", id).Scan(&name); err != nil {
+		t.Fatal(err)
+	}
+	if name != "Ayşe Yılmaz" {
+		t.Errorf("name = %q, want the owner's update only", name)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, apiRequest(t, db, ownerID, http.MethodDelete, path, ""))
+	if w.Code != http.StatusOK {
+		t.Fatalf("owner delete: status = %d (%s)", w.Code, w.Body)
+	}
+}
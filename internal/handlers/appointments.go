@@ -0,0 +1,481 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umutaraz/tradesman-app/internal/middleware"
+	"github.com/umutaraz/tradesman-app/internal/models"
+)
+
+// appointmentForm randevu sayfasındaki ekleme/düzenleme formu
+type appointmentForm struct {
+	ID          int    `form:"appointment_id"`
+	Title       string `form:"title" binding:"required"`
+	CustomerID  int    `form:"customer_id" binding:"required"`
+	StaffID     int    `form:"staff_id"`
+	Date        string `form:"appointment_date" binding:"required"`
+	StartTime   string `form:"start_time" binding:"required"`
+	EndTime     string `form:"end_time" binding:"required"`
+	Description string `form:"description"`
+	Status      string `form:"status"`
+	Reminder    int    `form:"reminder"`
+}
+
+// appointment formdaki tarih ve saatleri yerel saate göre çözümler
+func (f appointmentForm) appointment() (*models.Appointment, error) {
+	start, err := time.ParseInLocation("2006-01-02 15:04", f.Date+" "+f.StartTime, time.Local)
+	if err != nil {
+		return nil, newValidationError("Geçersiz başlangıç saati")
+	}
+	end, err := time.ParseInLocation("2006-01-02 15:04", f.Date+" "+f.EndTime, time.Local)
+	if err != nil {
+		return nil, newValidationError("Geçersiz bitiş saati")
+	}
+
+	return &models.Appointment{
+		ID:          f.ID,
+		CustomerID:  f.CustomerID,
+		StaffID:     f.StaffID,
+		Title:       f.Title,
+		Description: f.Description,
+		StartTime:   start,
+		EndTime:     end,
+		Status:      f.Status,
+		Reminder:    f.Reminder,
+	}, nil
+}
+
+// Randevu listesi (API); view=day|week|month ve date ile tarih aralığı seçilir,
+// staff_id ve customer_id ile filtrelenebilir
+func (h *Handler) GetAppointmentsAPI(c *gin.Context) {
+	var conditions []string
+	var args []interface{}
+
+	if view := c.Query("view"); view != "" {
+		from, to, err := appointmentRange(view, c.Query("date"))
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		conditions = append(conditions, "a.start_time < ? AND a.end_time > ?")
+		args = append(args, to, from)
+	}
+
+	for _, filter := range []string{"staff_id", "customer_id"} {
+		value := c.Query(filter)
+		if value == "" {
+			continue
+		}
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			respondError(c, newValidationError("Geçersiz "+filter))
+			return
+		}
+		conditions = append(conditions, "a."+filter+" = ?")
+		args = append(args, id)
+	}
+
+	appointments, err := h.queryAppointments(middleware.BusinessID(c), strings.Join(conditions, " AND "), args...)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, appointments)
+}
+
+// Randevu detayı (API)
+func (h *Handler) GetAppointmentAPI(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	appointment, err := h.getAppointment(middleware.BusinessID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, appointment)
+}
+
+// Randevu oluştur
+func (h *Handler) CreateAppointment(c *gin.Context) {
+	var appointment models.Appointment
+	if err := c.ShouldBindJSON(&appointment); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	appointment.ID = 0
+
+	saved, err := h.saveAppointment(c, &appointment)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, saved)
+}
+
+// Randevu güncelle
+func (h *Handler) UpdateAppointment(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	var appointment models.Appointment
+	if err := c.ShouldBindJSON(&appointment); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	appointment.ID = id
+
+	saved, err := h.saveAppointment(c, &appointment)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, saved)
+}
+
+// Randevu sil
+func (h *Handler) DeleteAppointment(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	businessID := middleware.BusinessID(c)
+	appointment, err := h.getAppointment(businessID, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	err = h.withTx(func(tx *sql.Tx) error {
+		if _, err := tx.Exec("DELETE FROM appointment_reminders WHERE appointment_id = ?", id); err != nil {
+			return err
+		}
+		_, err := tx.Exec("DELETE FROM appointments WHERE id = ? AND user_id = ?", id, businessID)
+		return err
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if appointment.Status != models.AppointmentCanceled && appointment.EndTime.After(time.Now()) {
+		h.notifyAppointmentStaff(c, appointment, "Randevu silindi")
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// Randevu ekle (sayfa formu)
+func (h *Handler) AddAppointmentForm(c *gin.Context) {
+	h.submitAppointmentForm(c, false)
+}
+
+// Randevu güncelle (sayfa formu)
+func (h *Handler) UpdateAppointmentForm(c *gin.Context) {
+	h.submitAppointmentForm(c, true)
+}
+
+// Randevu bilgilerini düzenleme formu için getir
+func (h *Handler) GetAppointmentForm(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Geçersiz ID"})
+		return
+	}
+
+	appointment, err := h.getAppointment(middleware.BusinessID(c), id)
+	if err != nil {
+		status, message := errorResponse(err)
+		c.JSON(status, gin.H{"success": false, "message": message})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "appointment": appointment})
+}
+
+func (h *Handler) submitAppointmentForm(c *gin.Context, update bool) {
+	var form appointmentForm
+	if err := c.ShouldBind(&form); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Lütfen zorunlu alanları doldurun"})
+		return
+	}
+	if update && form.ID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Randevu seçilmedi"})
+		return
+	}
+	if !update {
+		form.ID = 0
+	}
+
+	appointment, err := form.appointment()
+	if err == nil {
+		appointment, err = h.saveAppointment(c, appointment)
+	}
+	if err != nil {
+		status, message := errorResponse(err)
+		c.JSON(status, gin.H{"success": false, "message": message})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "appointment": appointment})
+}
+
+// saveAppointment randevuyu doğrular, personelin çakışan randevusu yoksa ekler ya da günceller
+func (h *Handler) saveAppointment(c *gin.Context, appointment *models.Appointment) (*models.Appointment, error) {
+	businessID := middleware.BusinessID(c)
+
+	if appointment.StaffID == 0 {
+		appointment.StaffID = middleware.UserID(c)
+	}
+	if appointment.Status == "" {
+		appointment.Status = models.AppointmentNew
+	}
+	switch appointment.Status {
+	case models.AppointmentNew, models.AppointmentConfirmed, models.AppointmentCompleted, models.AppointmentCanceled:
+	default:
+		return nil, newValidationError("Geçersiz randevu durumu: " + appointment.Status)
+	}
+	if appointment.Reminder < 0 {
+		return nil, newValidationError("Hatırlatma süresi negatif olamaz")
+	}
+
+	appointment.StartTime = appointment.StartTime.In(time.Local).Truncate(time.Minute)
+	appointment.EndTime = appointment.EndTime.In(time.Local).Truncate(time.Minute)
+	if !appointment.EndTime.After(appointment.StartTime) {
+		return nil, newValidationError("Bitiş saati başlangıç saatinden sonra olmalıdır")
+	}
+
+	if _, err := h.customers.Get(businessID, appointment.CustomerID); err != nil {
+		return nil, newValidationError("Müşteri bulunamadı")
+	}
+
+	var staffCount int
+	err := h.db.QueryRow("SELECT COUNT(*) FROM users WHERE id = ? AND (id = ? OR owner_id = ?)",
+		appointment.StaffID, businessID, businessID).Scan(&staffCount)
+	if err != nil {
+		return nil, err
+	}
+	if staffCount == 0 {
+		return nil, newValidationError("Personel bulunamadı")
+	}
+
+	s, err := h.settings.Get(businessID)
+	if err != nil {
+		return nil, err
+	}
+
+	isNew := appointment.ID == 0
+	err = h.withTx(func(tx *sql.Tx) error {
+		if appointment.Status != models.AppointmentCanceled {
+			if err := checkAppointmentConflict(tx, businessID, appointment, s.DateTimeLayout()); err != nil {
+				return err
+			}
+		}
+
+		if appointment.ID == 0 {
+			result, err := tx.Exec(`
+				INSERT INTO appointments (user_id, customer_id, staff_id, title, description, start_time, end_time,
+				                          status, reminder)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+			`, businessID, appointment.CustomerID, appointment.StaffID, appointment.Title, appointment.Description,
+				appointment.StartTime, appointment.EndTime, appointment.Status, appointment.Reminder)
+			if err != nil {
+				return err
+			}
+			id, err := result.LastInsertId()
+			if err != nil {
+				return err
+			}
+			appointment.ID = int(id)
+			return nil
+		}
+
+		result, err := tx.Exec(`
+			UPDATE appointments SET customer_id = ?, staff_id = ?, title = ?, description = ?, start_time = ?,
+			       end_time = ?, status = ?, reminder = ?, updated_at = CURRENT_TIMESTAMP
+			WHERE id = ? AND user_id = ?
+		`, appointment.CustomerID, appointment.StaffID, appointment.Title, appointment.Description,
+			appointment.StartTime, appointment.EndTime, appointment.Status, appointment.Reminder,
+			appointment.ID, businessID)
+		if err != nil {
+			return err
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return errNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	saved, err := h.getAppointment(businessID, appointment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	title := "Randevu güncellendi"
+	switch {
+	case isNew:
+		title = "Yeni randevu"
+	case saved.Status == models.AppointmentCanceled:
+		title = "Randevu iptal edildi"
+	}
+	h.notifyAppointmentStaff(c, saved, title)
+
+	return saved, nil
+}
+
+// notifyAppointmentStaff randevuyu başkası oluşturduysa ya da değiştirdiyse sorumlu personele bildirir
+func (h *Handler) notifyAppointmentStaff(c *gin.Context, appointment *models.Appointment, title string) {
+	if appointment.StaffID == middleware.UserID(c) {
+		return
+	}
+
+	severity := models.SeverityInfo
+	if appointment.Status == models.AppointmentCanceled {
+		severity = models.SeverityWarning
+	}
+
+	s, err := h.settings.Get(middleware.BusinessID(c))
+	if err != nil {
+		log.Printf("Randevu bildirimi gönderilemedi: %v", err)
+		return
+	}
+
+	_, err = h.notifier.Send(appointment.StaffID, models.Notification{
+		Type:     models.NotificationAppointment,
+		Severity: severity,
+		Title:    title + ": " + appointment.Title,
+		Message: fmt.Sprintf("%s, %s - %s", appointment.Customer.Name,
+			appointment.StartTime.In(time.Local).Format(s.DateTimeLayout()),
+			appointment.EndTime.In(time.Local).Format("15:04")),
+		Link: "/appointments",
+	})
+	if err != nil {
+		// Bildirim hatası randevu kaydını geri almaz
+		log.Printf("Randevu bildirimi gönderilemedi: %v", err)
+	}
+}
+
+// checkAppointmentConflict personelin aynı saat aralığında iptal edilmemiş başka randevusu olup olmadığını kontrol eder;
+// çakışan randevunun zamanı işletmenin tarih biçimiyle (layout) yazılır
+func checkAppointmentConflict(tx *sql.Tx, businessID int, appointment *models.Appointment, layout string) error {
+	var title string
+	var start, end time.Time
+	err := tx.QueryRow(`
+		SELECT title, start_time, end_time FROM appointments
+		WHERE user_id = ? AND staff_id = ? AND id != ? AND status != ?
+		  AND start_time < ? AND end_time > ?
+		ORDER BY start_time LIMIT 1
+	`, businessID, appointment.StaffID, appointment.ID, models.AppointmentCanceled,
+		appointment.EndTime, appointment.StartTime).Scan(&title, &start, &end)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return newConflictError(fmt.Sprintf("Personelin bu saatte başka randevusu var: %s (%s - %s)",
+		title, start.In(time.Local).Format(layout), end.In(time.Local).Format("15:04")))
+}
+
+// appointmentRange gün, hafta (pazartesiden başlar) ya da ay görünümü için tarih aralığını döndürür
+func appointmentRange(view, date string) (time.Time, time.Time, error) {
+	day := time.Now()
+	if date != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", date, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, newValidationError("Geçersiz tarih, YYYY-AA-GG biçiminde olmalıdır")
+		}
+		day = parsed
+	}
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.Local)
+
+	switch view {
+	case "day":
+		return day, day.AddDate(0, 0, 1), nil
+	case "week":
+		offset := (int(day.Weekday()) + 6) % 7
+		start := day.AddDate(0, 0, -offset)
+		return start, start.AddDate(0, 0, 7), nil
+	case "month":
+		start := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.Local)
+		return start, start.AddDate(0, 1, 0), nil
+	default:
+		return time.Time{}, time.Time{}, newValidationError("Geçersiz görünüm: day, week ya da month olmalıdır")
+	}
+}
+
+const appointmentSelect = `
+	SELECT a.id, a.user_id, a.customer_id, a.staff_id, a.title, COALESCE(a.description, ''), a.start_time,
+	       a.end_time, a.status, a.reminder, a.created_at, a.updated_at,
+	       c.id, c.name, COALESCE(c.email, ''), COALESCE(c.phone, ''), COALESCE(u.name, '')
+	FROM appointments a
+	JOIN customers c ON a.customer_id = c.id
+	LEFT JOIN users u ON a.staff_id = u.id`
+
+func (h *Handler) getAppointment(userID, id int) (*models.Appointment, error) {
+	return scanAppointment(h.db.QueryRow(appointmentSelect+" WHERE a.id = ? AND a.user_id = ?", id, userID))
+}
+
+// queryAppointments işletmenin randevularını başlangıç saatine göre sıralı getirir;
+// where boş değilse ek koşul olarak eklenir
+func (h *Handler) queryAppointments(userID int, where string, args ...interface{}) ([]models.Appointment, error) {
+	query := appointmentSelect + " WHERE a.user_id = ?"
+	if where != "" {
+		query += " AND " + where
+	}
+	query += " ORDER BY a.start_time"
+
+	rows, err := h.db.Query(query, append([]interface{}{userID}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var appointments []models.Appointment
+	for rows.Next() {
+		appointment, err := scanAppointment(rows)
+		if err != nil {
+			return nil, err
+		}
+		appointments = append(appointments, *appointment)
+	}
+
+	return appointments, rows.Err()
+}
+
+func scanAppointment(rs rowScanner) (*models.Appointment, error) {
+	appointment := models.Appointment{Customer: &models.Customer{}}
+	err := rs.Scan(&appointment.ID, &appointment.UserID, &appointment.CustomerID, &appointment.StaffID,
+		&appointment.Title, &appointment.Description, &appointment.StartTime, &appointment.EndTime,
+		&appointment.Status, &appointment.Reminder, &appointment.CreatedAt, &appointment.UpdatedAt,
+		&appointment.Customer.ID, &appointment.Customer.Name, &appointment.Customer.Email,
+		&appointment.Customer.Phone, &appointment.StaffName)
+	if err != nil {
+		return nil, err
+	}
+
+	appointment.StartTime = appointment.StartTime.In(time.Local)
+	appointment.EndTime = appointment.EndTime.In(time.Local)
+
+	return &appointment, nil
+}
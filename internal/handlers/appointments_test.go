@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/database/dbtest"
+	"github.com/umutaraz/tradesman-app/internal/models"
+)
+
+func TestAppointmentRange(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.Local) }
+
+	tests := []struct {
+		view, date string
+		start, end time.Time
+		wantErr    bool
+	}{
+		{"day", "2026-10-18", day(2026, 10, 18), day(2026, 10, 19), false},
+		// 18 Ekim 2026 pazar; hafta pazartesi başlar
+		{"week", "2026-10-18", day(2026, 10, 12), day(2026, 10, 19), false},
+		{"week", "2026-10-12", day(2026, 10, 12), day(2026, 10, 19), false},
+		{"month", "2026-12-31", day(2026, 12, 1), day(2027, 1, 1), false},
+		{"year", "2026-10-18", time.Time{}, time.Time{}, true},
+		{"day", "18.10.2026", time.Time{}, time.Time{}, true},
+	}
+	for _, tt := range tests {
+		start, end, err := appointmentRange(tt.view, tt.date)
+		if tt.wantErr {
+			if !errors.Is(err, errValidation) {
+				t.Errorf("appointmentRange(%s, %s): err = %v, want validation error", tt.view, tt.date, err)
+			}
+			continue
+		}
+		if err != nil || !start.Equal(tt.start) || !end.Equal(tt.end) {
+			t.Errorf("appointmentRange(%s, %s) = %v, %v, %v; want %v, %v", tt.view, tt.date, start, end, err, tt.start, tt.end)
+		}
+	}
+}
+
+func TestAppointmentFormParsesLocalTimes(t *testing.T) {
+	f := appointmentForm{Title: "Kombi bakımı", CustomerID: 1, Date: "2026-10-18", StartTime: "09:30", EndTime: "10:15"}
+	a, err := f.appointment()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2026, 10, 18, 9, 30, 0, 0, time.Local); !a.StartTime.Equal(want) || a.Duration() != 45 {
+		t.Errorf("appointment = %v, %d min; want %v, 45 min", a.StartTime, a.Duration(), want)
+	}
+
+	f.EndTime = "25:00"
+	if _, err := f.appointment(); !errors.Is(err, errValidation) {
+		t.Errorf("invalid end time: err = %v, want validation error", err)
+	}
+}
+
+func TestCheckAppointmentConflict(t *testing.T) {
+	db := dbtest.New(t)
+	userID := dbtest.User(t, db, "sahip@example.com")
+	staffID := dbtest.User(t, db, "teknisyen@example.com")
+
+	result, err := db.Exec("INSERT INTO customers (user_id, name) VALUES (?, 'Ayşe Demir')", userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	customerID, _ := result.LastInsertId()
+
+	at := func(hour, minute int) time.Time { return time.Date(2026, 10, 19, hour, minute, 0, 0, time.Local) }
+	for _, a := range []struct {
+		start, end time.Time
+		status     string
+	}{
+		{at(10, 0), at(11, 0), models.AppointmentConfirmed},
+		{at(13, 0), at(14, 0), models.AppointmentCanceled},
+	} {
+		_, err := db.Exec(`
+			INSERT INTO appointments (user_id, customer_id, staff_id, title, start_time, end_time, status)
+			VALUES (?, ?, ?, 'Kombi bakımı', ?, ?, ?)
+		`, userID, customerID, staffID, a.start, a.end, a.status)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		staffID    int
+		start, end time.Time
+		conflict   bool
+	}{
+		{"overlapping", staffID, at(10, 30), at(11, 30), true},
+		{"containing", staffID, at(9, 0), at(12, 0), true},
+		{"back to back", staffID, at(11, 0), at(12, 0), false},
+		{"canceled slot", staffID, at(13, 0), at(14, 0), false},
+		{"other staff", userID, at(10, 0), at(11, 0), false},
+	}
+	for _, tt := range tests {
+		tx, err := db.Begin()
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = checkAppointmentConflict(tx, userID, &models.Appointment{StaffID: tt.staffID, StartTime: tt.start, EndTime: tt.end}, "02.01.2006 15:04")
+		tx.Rollback()
+		if errors.Is(err, errConflict) != tt.conflict || (err != nil && !tt.conflict) {
+			t.Errorf("%s: err = %v, want conflict %v", tt.name, err, tt.conflict)
+		}
+	}
+}
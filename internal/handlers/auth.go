@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umutaraz/tradesman-app/internal/middleware"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Kullanıcı bulunamadığında da bcrypt karşılaştırması yapılarak
+// yanıt süresinden e-posta adresinin varlığı anlaşılmasın diye kullanılır
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("esnaf-dummy-password"), bcrypt.DefaultCost)
+
+type loginRequest struct {
+	Email    string `form:"email" json:"email" binding:"required,email"`
+	Password string `form:"password" json:"password" binding:"required"`
+	Next     string `form:"next" json:"next"`
+}
+
+// Giriş Sayfası
+func (h *Handler) LoginPage(c *gin.Context) {
+	if token, err := c.Cookie(middleware.SessionCookieName); err == nil && token != "" {
+		if _, err := h.db.SessionUser(token); err == nil {
+			c.Redirect(http.StatusFound, "/")
+			return
+		}
+	}
+
+	c.HTML(http.StatusOK, "login.html", gin.H{
+		"title": "Giriş - Esnaf Yönetim Sistemi",
+		"next":  c.Query("next"),
+	})
+}
+
+// Giriş
+func (h *Handler) Login(c *gin.Context) {
+	var req loginRequest
+	if err := c.ShouldBind(&req); err != nil {
+		h.loginFailed(c, http.StatusBadRequest, "E-posta ve şifre gereklidir", req)
+		return
+	}
+
+	user, err := h.getUserByEmail(req.Email)
+	if err != nil && err != sql.ErrNoRows {
+		h.loginError(c, err, req)
+		return
+	}
+
+	hash := dummyPasswordHash
+	if user != nil {
+		hash = []byte(user.PasswordHash)
+	}
+	if bcrypt.CompareHashAndPassword(hash, []byte(req.Password)) != nil || user == nil {
+		h.loginFailed(c, http.StatusUnauthorized, "E-posta veya şifre hatalı", req)
+		return
+	}
+
+	// Süresi dolmuş oturumları temizle
+	_ = h.db.DeleteExpiredSessions()
+
+	if _, err := h.db.Exec("UPDATE users SET last_login_at = ? WHERE id = ?", time.Now(), user.ID); err != nil {
+		h.loginError(c, err, req)
+		return
+	}
+
+	token, expiresAt, err := h.db.CreateSession(user.ID, h.cfg.SessionTTL)
+	if err != nil {
+		h.loginError(c, err, req)
+		return
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(middleware.SessionCookieName, token, int(h.cfg.SessionTTL.Seconds()), "/", "", h.cfg.IsProduction(), true)
+
+	if c.ContentType() == gin.MIMEJSON {
+		c.JSON(http.StatusOK, gin.H{
+			"user":       user,
+			"expires_at": expiresAt,
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, safeRedirect(req.Next))
+}
+
+// Çıkış
+func (h *Handler) Logout(c *gin.Context) {
+	if token, err := c.Cookie(middleware.SessionCookieName); err == nil && token != "" {
+		_ = h.db.DeleteSession(token)
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(middleware.SessionCookieName, "", -1, "/", "", h.cfg.IsProduction(), true)
+
+	if c.ContentType() == gin.MIMEJSON {
+		c.JSON(http.StatusOK, gin.H{"success": true})
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/login")
+}
+
+func (h *Handler) loginFailed(c *gin.Context, status int, message string, req loginRequest) {
+	if c.ContentType() == gin.MIMEJSON {
+		c.JSON(status, gin.H{"error": message})
+		return
+	}
+
+	c.HTML(status, "login.html", gin.H{
+		"title": "Giriş - Esnaf Yönetim Sistemi",
+		"error": message,
+		"email": req.Email,
+		"next":  req.Next,
+	})
+}
+
+// loginError beklenmeyen hatayı günlüğe yazar; giriş sayfasına veritabanı ayrıntısı sızdırılmaz
+func (h *Handler) loginError(c *gin.Context, err error, req loginRequest) {
+	log.Printf("Giriş hatası: %v", err)
+	h.loginFailed(c, http.StatusInternalServerError, "Giriş yapılamadı, lütfen daha sonra tekrar deneyin", req)
+}
+
+// Yalnızca uygulama içi yollara yönlendirmeye izin ver
+func safeRedirect(next string) string {
+	if next == "" || !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		return "/"
+	}
+	return next
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umutaraz/tradesman-app/internal/database/dbtest"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestLogin(t *testing.T) {
+	db := dbtest.New(t)
+	userID := dbtest.User(t, db, "sahip@example.com")
+	hash, err := bcrypt.GenerateFromPassword([]byte("gizli-sifre"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("UPDATE users SET password_hash = ? WHERE id = ?", string(hash), userID); err != nil {
+		t.Fatal(err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/login", testHandler(db).Login)
+	login := func(email, password string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(gin.H{"email": email, "password": password})
+		req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(string(body)))
+		req.Header.Set("Content-Type", gin.MIMEJSON)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	tests := []struct {
+		name     string
+		email    string
+		password string
+		status   int
+	}{
+		{"missing password", "sahip@example.com", "", http.StatusBadRequest},
+		{"wrong password", "sahip@example.com", "yanlis", http.StatusUnauthorized},
+		{"unknown user", "yok@example.com", "gizli-sifre", http.StatusUnauthorized},
+		{"valid", "sahip@example.com", "gizli-sifre", http.StatusOK},
+	}
+	for _, tt := range tests {
+		if w := login(tt.email, tt.password); w.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d: %s", tt.name, w.Code, tt.status, w.Body)
+		}
+	}
+
+	// Veritabanı hatası kullanıcıya ayrıntısıyla gösterilmez
+	if _, err := db.Exec("DROP TABLE sessions"); err != nil {
+		t.Fatal(err)
+	}
+	w := login("sahip@example.com", "gizli-sifre")
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", w.Code)
+	}
+	if strings.Contains(w.Body.String(), "sessions") {
+		t.Errorf("response leaks the database error: %s", w.Body)
+	}
+}
+
+func TestSafeRedirect(t *testing.T) {
+	tests := []struct {
+		next string
+		want string
+	}{
+		{"", "/"},
+		{"/customers?page=2", "/customers?page=2"},
+		{"https://kotu.example", "/"},
+		{"//kotu.example", "/"},
+		{"/\\kotu.example", "/"},
+	}
+	for _, tt := range tests {
+		if got := safeRedirect(tt.next); got != tt.want {
+			t.Errorf("safeRedirect(%q) = %q, want %q", tt.next, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umutaraz/tradesman-app/internal/backup"
+	"github.com/umutaraz/tradesman-app/internal/middleware"
+)
+
+// isSystemAdmin isteği yapan kullanıcı kurulumun yöneticisiyse true döner. Veritabanı tüm işletmeleri
+// içerdiğinden yedekleri yalnızca sistem yöneticileri görebilir ve geri yükleyebilir; işletme rolleri
+// bu yetkiyi vermez.
+func (h *Handler) isSystemAdmin(c *gin.Context) (bool, error) {
+	return h.db.IsSystemAdmin(middleware.UserID(c))
+}
+
+// requireSystemAdmin sistem yöneticisi olmayan kullanıcıların isteğini 403 ile reddeder
+func (h *Handler) requireSystemAdmin(c *gin.Context) bool {
+	ok, err := h.isSystemAdmin(c)
+	if err != nil {
+		respondError(c, err)
+		return false
+	}
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Yedekleri yalnızca sistem yöneticisi yönetebilir"})
+		return false
+	}
+	return true
+}
+
+// Yedekler (API)
+func (h *Handler) GetBackupsAPI(c *gin.Context) {
+	if !h.requireSystemAdmin(c) {
+		return
+	}
+
+	backups, err := h.backups.List()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, backups)
+}
+
+// Yedek al
+func (h *Handler) CreateBackup(c *gin.Context) {
+	if !h.requireSystemAdmin(c) {
+		return
+	}
+
+	info, err := h.backups.Create(backup.KindManual)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, info)
+}
+
+// Yedeği indir
+func (h *Handler) DownloadBackup(c *gin.Context) {
+	if !h.requireSystemAdmin(c) {
+		return
+	}
+
+	path, err := h.backups.Path(c.Param("name"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.FileAttachment(path, c.Param("name"))
+}
+
+// Yedeği sil
+func (h *Handler) DeleteBackup(c *gin.Context) {
+	if !h.requireSystemAdmin(c) {
+		return
+	}
+
+	if err := h.backups.Delete(c.Param("name")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// Kayıtlı yedeği geri yükle
+func (h *Handler) RestoreBackup(c *gin.Context) {
+	if !h.requireSystemAdmin(c) {
+		return
+	}
+
+	safety, err := h.backups.RestoreFile(c.Param("name"))
+	h.respondRestore(c, safety, err)
+}
+
+// Yüklenen yedek dosyasını geri yükle
+func (h *Handler) UploadRestoreBackup(c *gin.Context) {
+	if !h.requireSystemAdmin(c) {
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		respondError(c, newValidationError("Yedek dosyası seçilmedi"))
+		return
+	}
+	f, err := file.Open()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	defer f.Close()
+
+	safety, err := h.backups.Restore(f)
+	h.respondRestore(c, safety, err)
+}
+
+// respondRestore geri yükleme sonucunu döndürür. Geri yüklenen veritabanında ayarlar farklı
+// olabileceğinden önbellek boşaltılır.
+func (h *Handler) respondRestore(c *gin.Context, safety backup.Info, err error) {
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	h.settings.InvalidateAll()
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Yedek geri yüklendi. Önceki veriler " + safety.Name + " adıyla yedeklendi.",
+		"safety":  safety,
+	})
+}
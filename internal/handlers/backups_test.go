@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umutaraz/tradesman-app/internal/backup"
+	"github.com/umutaraz/tradesman-app/internal/config"
+	"github.com/umutaraz/tradesman-app/internal/database/dbtest"
+	"github.com/umutaraz/tradesman-app/internal/middleware"
+	"github.com/umutaraz/tradesman-app/internal/repository"
+	"github.com/umutaraz/tradesman-app/internal/settings"
+)
+
+// İşletme sahibi olmak yedekleri yönetmeye yetmez; yalnızca sistem yöneticisi yönetebilir
+func TestBackupsRequireSystemAdmin(t *testing.T) {
+	db := dbtest.New(t)
+	adminID := dbtest.User(t, db, "yonetici@example.com")
+	ownerID := dbtest.User(t, db, "sahip@example.com")
+	if _, err := db.SetSystemAdmin("yonetici@example.com", true); err != nil {
+		t.Fatal(err)
+	}
+	backups, err := backup.New(db, backup.Config{Dir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := New(db, repository.NewSQLite(db.DB), &config.Config{}, nil, settings.NewStore(db), backups)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	auth := middleware.Auth(db, func(int) time.Duration { return 0 })
+	r.GET("/api/v1/backups", auth, h.GetBackupsAPI)
+	r.POST("/api/v1/backups", auth, h.CreateBackup)
+	r.POST("/settings/backup", auth, h.UpdateBackupSettings)
+
+	backupForm := url.Values{"backup_enabled": {"1"}, "backup_frequency": {"daily"}, "backup_keep": {"5"}}
+	tests := []struct {
+		name   string
+		userID int
+		method string
+		path   string
+		status int
+	}{
+		{"owner lists", ownerID, http.MethodGet, "/api/v1/backups", http.StatusForbidden},
+		{"owner creates", ownerID, http.MethodPost, "/api/v1/backups", http.StatusForbidden},
+		{"owner changes settings", ownerID, http.MethodPost, "/settings/backup", http.StatusForbidden},
+		{"admin creates", adminID, http.MethodPost, "/api/v1/backups", http.StatusCreated},
+		{"admin lists", adminID, http.MethodGet, "/api/v1/backups", http.StatusOK},
+		{"admin changes settings", adminID, http.MethodPost, "/settings/backup", http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := apiRequest(t, db, tt.userID, tt.method, tt.path, "")
+		if tt.path == "/settings/backup" {
+			req = formRequest(t, db, tt.userID, tt.path, backupForm)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d: %s", tt.name, w.Code, tt.status, w.Body)
+		}
+	}
+
+	if list, err := backups.List(); err != nil || len(list) != 1 {
+		t.Errorf("backups = %+v, %v; want only the admin's backup", list, err)
+	}
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/umutaraz/tradesman-app/internal/database/dbtest"
+	"github.com/umutaraz/tradesman-app/internal/listquery"
+	"github.com/umutaraz/tradesman-app/internal/repository"
+)
+
+func TestDeleteCustomerArchivesWhenHistoryExists(t *testing.T) {
+	db := dbtest.New(t)
+	userID := dbtest.User(t, db, "sahip@example.com")
+	h := testHandler(db)
+	r := apiTestRouter(db)
+
+	customer := func(name string) string {
+		result, err := db.Exec("INSERT INTO customers (user_id, name) VALUES (?, ?)", userID, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		id, _ := result.LastInsertId()
+		return strconv.FormatInt(id, 10)
+	}
+	withOrder := customer("Ayşe Demir")
+	withoutHistory := customer("Mehmet Kaya")
+	_, err := db.Exec(`INSERT INTO orders (user_id, customer_id, order_number, status, total_amount)
+		VALUES (?, ?, 'SIP-2026-001', 'completed', 100)`, userID, withOrder)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	do := func(method, path string) (int, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, apiRequest(t, db, userID, method, path, ""))
+		var body map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &body)
+		return w.Code, body
+	}
+
+	status, body := do(http.MethodDelete, "/api/v1/customers/"+withOrder)
+	if status != http.StatusOK || body["archived"] != true {
+		t.Fatalf("delete customer with orders = %d %v, want archived", status, body)
+	}
+	status, body = do(http.MethodDelete, "/api/v1/customers/"+withoutHistory)
+	if status != http.StatusOK || body["archived"] != false {
+		t.Fatalf("delete customer without history = %d %v, want deleted", status, body)
+	}
+	if status, _ := do(http.MethodGet, "/api/v1/customers/"+withoutHistory); status != http.StatusNotFound {
+		t.Errorf("deleted customer still readable: %d", status)
+	}
+
+	// Arşivdeki müşteri aktif listede görünmez ama kaydı korunur
+	q, err := listquery.Parse(url.Values{}, repository.CustomerList, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	active, _, err := h.customers.List(userID, false, q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archived, _, err := h.customers.List(userID, true, q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(active) != 0 || len(archived) != 1 || !archived[0].IsArchived() {
+		t.Fatalf("active = %d, archived = %d", len(active), len(archived))
+	}
+
+	status, body = do(http.MethodPost, "/api/v1/customers/"+withOrder+"/restore")
+	if status != http.StatusOK || body["archived_at"] != nil {
+		t.Fatalf("restore = %d %v", status, body)
+	}
+	if status, _ := do(http.MethodPost, "/api/v1/customers/"+withOrder+"/restore"); status != http.StatusConflict {
+		t.Errorf("restoring an active customer = %d, want 409", status)
+	}
+	if status, _ := do(http.MethodPost, "/api/v1/customers/"+withoutHistory+"/restore"); status != http.StatusNotFound {
+		t.Errorf("restoring a deleted customer = %d, want 404", status)
+	}
+}
@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umutaraz/tradesman-app/internal/exchange"
+	"github.com/umutaraz/tradesman-app/internal/middleware"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/money"
+)
+
+// customerForm müşteri ekleme ve düzenleme formu
+type customerForm struct {
+	Name    string `form:"name" binding:"required"`
+	Email   string `form:"email" binding:"omitempty,email"`
+	Phone   string `form:"phone"`
+	Address string `form:"address"`
+	Notes   string `form:"notes"`
+}
+
+func (f customerForm) customer() models.Customer {
+	return models.Customer{Name: f.Name, Email: f.Email, Phone: f.Phone, Address: f.Address, Notes: f.Notes}
+}
+
+// Müşteri detay sayfası
+func (h *Handler) CustomerDetail(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	businessID := middleware.BusinessID(c)
+
+	customer, err := h.customers.Get(businessID, id)
+	if err != nil {
+		c.HTML(http.StatusNotFound, "error.html", gin.H{"error": "Müşteri bulunamadı"})
+		return
+	}
+
+	orders, err := h.orders.ListByCustomer(businessID, id)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"error": err.Error()})
+		return
+	}
+
+	invoices, err := h.queryInvoices(businessID, "i.customer_id = ?", id)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"error": err.Error()})
+		return
+	}
+
+	appointments, err := h.queryAppointments(businessID, "a.customer_id = ?", id)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"error": err.Error()})
+		return
+	}
+
+	// Hesap ekstresi; tarih aralığı verilmezse tüm hareketler gösterilir
+	from, to, err := statementRange(c.Query("from"), c.Query("to"))
+	if err != nil {
+		from, to = nil, nil
+	}
+	statement, err := h.customerStatement(businessID, id, from, to)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"error": err.Error()})
+		return
+	}
+
+	open, err := openInvoices(h.db, businessID, id)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"error": err.Error()})
+		return
+	}
+
+	activities, err := h.getCustomerActivities(businessID, id)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"error": err.Error()})
+		return
+	}
+
+	base, err := h.baseCurrency(businessID)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"error": err.Error()})
+		return
+	}
+	// Açık faturalar tahsilat dağıtımıyla aynı yoldan okunur; gösterim için ana para birimiyle etiketlenir
+	for i := range open {
+		open[i].Total = open[i].Total.WithCurrency(base)
+		open[i].Outstanding = open[i].Outstanding.WithCurrency(base)
+	}
+	stats, err := customerStats(exchange.NewConverter(h.db, businessID, base), orders)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"error": err.Error()})
+		return
+	}
+
+	h.render(c, "customer_detail.html", gin.H{
+		"customer":             customer,
+		"customerStats":        stats,
+		"customerOrders":       orders,
+		"customerInvoices":     invoices,
+		"customerAppointments": appointments,
+		"statement":            statement,
+		"openInvoices":         open,
+		"statementFrom":        c.Query("from"),
+		"statementTo":          c.Query("to"),
+		"activities":           activities,
+		"today":                time.Now(),
+		"title":                "Müşteri Detayı - " + customer.Name,
+		"active":               "customers",
+	})
+}
+
+// customerStats müşterinin siparişlerini özetler. Döviz siparişleri sipariş günündeki kurla ana para
+// birimine çevrilir; kuru bulunamayanlar toplama katılmaz ve işaretlenir.
+func customerStats(converter *exchange.Converter, orders []models.Order) (models.CustomerStats, error) {
+	stats := models.CustomerStats{TotalSpent: money.New(0, converter.Base())}
+	for _, order := range orders {
+		if models.IsOrderReversed(order.Status) {
+			continue
+		}
+		stats.TotalOrders++
+
+		amount, err := converter.Convert(order.TotalAmount, order.OrderDate)
+		if errors.Is(err, exchange.ErrNoRate) {
+			stats.MissingRates = true
+			continue
+		}
+		if err != nil {
+			return stats, err
+		}
+		stats.TotalSpent = stats.TotalSpent.Add(amount)
+	}
+	return stats, nil
+}
+
+// Müşteri detayı (API)
+func (h *Handler) GetCustomerAPI(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	customer, err := h.customers.Get(middleware.BusinessID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, customer)
+}
+
+// Müşteri ekle (form)
+func (h *Handler) AddCustomerForm(c *gin.Context) {
+	var form customerForm
+	if err := c.ShouldBind(&form); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Lütfen müşteri adını ve e-posta adresini doğru girin"})
+		return
+	}
+
+	customer := form.customer()
+	customer.UserID = middleware.BusinessID(c)
+	id, err := h.customers.Create(&customer)
+	if err != nil {
+		status, message := errorResponse(err)
+		c.JSON(status, gin.H{"success": false, "message": message})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "id": id})
+}
+
+// Müşteri güncelle (form)
+func (h *Handler) UpdateCustomerForm(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+
+	var form customerForm
+	if err := c.ShouldBind(&form); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Lütfen müşteri adını ve e-posta adresini doğru girin"})
+		return
+	}
+
+	customer := form.customer()
+	if err := h.customers.Update(middleware.BusinessID(c), id, &customer); err != nil {
+		status, message := errorResponse(err)
+		c.JSON(status, gin.H{"success": false, "message": message})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// Müşteri güncelle
+func (h *Handler) UpdateCustomer(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	var customer models.Customer
+	if err := c.ShouldBindJSON(&customer); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	businessID := middleware.BusinessID(c)
+	if err := h.customers.Update(businessID, id, &customer); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	updated, err := h.customers.Get(businessID, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// Müşteri sil. Siparişi, randevusu, faturası ya da cari hesap hareketi bulunan müşteri silinmez, arşivlenir;
+// arşivdeki müşteriler listelerde görünmez ama geçmiş kayıtlarıyla birlikte saklanır.
+func (h *Handler) DeleteCustomer(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	businessID := middleware.BusinessID(c)
+	if _, err := h.customers.Get(businessID, id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	hasHistory, err := h.customers.HasHistory(businessID, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if hasHistory {
+		if err := h.customers.Archive(businessID, id); err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"success":  true,
+			"archived": true,
+			"message":  "Siparişi, randevusu, faturası ya da hesap hareketi bulunan müşteri silinmedi, arşivlendi",
+		})
+		return
+	}
+
+	if err := h.customers.Delete(businessID, id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "archived": false})
+}
+
+// Arşivlenmiş müşteriyi geri al
+func (h *Handler) RestoreCustomer(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	businessID := middleware.BusinessID(c)
+	if err := h.customers.Restore(businessID, id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	customer, err := h.customers.Get(businessID, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, customer)
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/database/dbtest"
+	"github.com/umutaraz/tradesman-app/internal/exchange"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/money"
+)
+
+func TestCustomerStatsMixedCurrencies(t *testing.T) {
+	db := dbtest.New(t)
+	userID := dbtest.User(t, db, "sahip@example.com")
+
+	day := time.Date(2024, 3, 15, 10, 0, 0, 0, time.Local)
+	err := exchange.Save(db, &models.ExchangeRate{UserID: userID, Currency: money.USD, Date: "2024-03-14", Rate: 32.5,
+		Source: models.RateSourceManual})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	orders := []models.Order{
+		{Status: models.OrderCompleted, TotalAmount: money.New(10000, money.TRY), OrderDate: day},
+		{Status: models.OrderDelivered, TotalAmount: money.New(1000, money.USD), OrderDate: day},
+		{Status: models.OrderCancelled, TotalAmount: money.New(5000, money.USD), OrderDate: day},
+		{Status: models.OrderPending, TotalAmount: money.New(2000, money.EUR), OrderDate: day},
+	}
+
+	stats, err := customerStats(exchange.NewConverter(db, userID, money.TRY), orders)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stats.TotalOrders != 3 {
+		t.Errorf("TotalOrders = %d, want 3", stats.TotalOrders)
+	}
+	// 100 TL + 10 USD × 32,50
+	if want := money.New(42500, money.TRY); stats.TotalSpent != want {
+		t.Errorf("TotalSpent = %v, want %v", stats.TotalSpent, want)
+	}
+	if !stats.MissingRates {
+		t.Error("EUR kuru olmadığı halde MissingRates işaretlenmedi")
+	}
+}
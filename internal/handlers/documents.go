@@ -0,0 +1,430 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umutaraz/tradesman-app/internal/middleware"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/money"
+	"github.com/umutaraz/tradesman-app/internal/pdf"
+)
+
+// Fatura PDF'i (A4)
+func (h *Handler) InvoicePDF(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.String(http.StatusBadRequest, "Geçersiz ID")
+		return
+	}
+
+	businessID := middleware.BusinessID(c)
+	invoice, err := h.getInvoice(businessID, id)
+	if err != nil {
+		status, message := errorResponse(err)
+		c.String(status, message)
+		return
+	}
+
+	data, err := h.invoicePDF(businessID, invoice)
+	if err != nil {
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	sendPDF(c, invoiceFileName(invoice), data)
+}
+
+// Sipariş fişi PDF'i; width=58 ya da 80 (mm) termal yazıcı genişliği
+func (h *Handler) OrderReceiptPDF(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.String(http.StatusBadRequest, "Geçersiz ID")
+		return
+	}
+
+	paperWidth := 80
+	if width := c.Query("width"); width != "" {
+		paperWidth, err = strconv.Atoi(width)
+		if err != nil || (paperWidth != 58 && paperWidth != 80) {
+			c.String(http.StatusBadRequest, "Fiş genişliği 58 ya da 80 olmalıdır")
+			return
+		}
+	}
+
+	businessID := middleware.BusinessID(c)
+	order, err := h.orders.Get(businessID, id)
+	if err != nil {
+		status, message := errorResponse(err)
+		c.String(status, message)
+		return
+	}
+
+	owner, err := h.getUserByID(businessID)
+	if err != nil {
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+	s, err := h.settings.Get(businessID)
+	if err != nil {
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	data, err := renderReceipt(owner, order, paperWidth, s.DateTimeLayout()).Bytes()
+	if err != nil {
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	sendPDF(c, "fis-"+order.OrderNumber+".pdf", data)
+}
+
+// sendPDF belgeyi tarayıcıda açılacak şekilde gönderir; download=1 ile indirilir
+func sendPDF(c *gin.Context, fileName string, data []byte) {
+	disposition := "inline"
+	if c.Query("download") == "1" {
+		disposition = "attachment"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("%s; filename=%q", disposition, fileName))
+	c.Data(http.StatusOK, "application/pdf", data)
+}
+
+func invoiceFileName(invoice *models.Invoice) string {
+	if invoice.InvoiceNumber == "" {
+		return fmt.Sprintf("taslak-fatura-%d.pdf", invoice.ID)
+	}
+	return invoice.InvoiceNumber + ".pdf"
+}
+
+// invoicePDF faturayı işletme bilgileriyle birlikte PDF'e dönüştürür
+func (h *Handler) invoicePDF(businessID int, invoice *models.Invoice) ([]byte, error) {
+	owner, err := h.getUserByID(businessID)
+	if err != nil {
+		return nil, err
+	}
+	s, err := h.settings.Get(businessID)
+	if err != nil {
+		return nil, err
+	}
+
+	// İade faturasında düzeltilen faturanın numarası gösterilir
+	var creditFor string
+	if invoice.CreditForID != nil {
+		original, err := h.getInvoice(businessID, *invoice.CreditForID)
+		if err != nil {
+			return nil, err
+		}
+		creditFor = original.InvoiceNumber
+	}
+
+	return renderInvoice(owner, invoice, creditFor, s.DateLayout()).Bytes()
+}
+
+// formatAmount tutarı Türkçe biçimde yazar (1.234,56 TL, dövizde 1.234,56 USD); PDF yazı tipinde ₺ karakteri yoktur
+func formatAmount(amount money.Money) string {
+	if amount.Currency() == money.TRY {
+		return amount.Number() + " TL"
+	}
+	return amount.Number() + " " + string(amount.Currency())
+}
+
+// formatQuantity miktarı gereksiz ondalıklar olmadan yazar
+func formatQuantity(quantity float64) string {
+	return strings.Replace(strconv.FormatFloat(quantity, 'f', -1, 64), ".", ",", 1)
+}
+
+// Fatura sayfa düzeni (punto)
+const (
+	invoiceMargin     = 40.0
+	invoiceBottom     = pdf.A4Height - 60
+	invoiceLineHeight = 13.0
+)
+
+// invoiceColumns fatura tablosu sütunları; sayısal sütunlar sağa yaslanır
+var invoiceColumns = []struct {
+	title string
+	right float64
+}{
+	{"Miktar", 340},
+	{"Birim Fiyat", 420},
+	{"KDV", 460},
+	{"Tutar", pdf.A4Width - invoiceMargin},
+}
+
+func renderInvoice(owner *models.User, invoice *models.Invoice, creditFor, layout string) *pdf.Document {
+	doc := pdf.New()
+	doc.Title = "Fatura " + invoice.InvoiceNumber
+	doc.Author = owner.BusinessName
+
+	right := pdf.A4Width - invoiceMargin
+	page := doc.AddPage(pdf.A4Width, pdf.A4Height)
+
+	// İşletme bilgileri
+	y := 60.0
+	page.Text(invoiceMargin, y, pdf.HelveticaBold, 15, businessName(owner))
+	y += 16
+	for _, line := range pdf.WrapText(pdf.Helvetica, 9, owner.Address, 260) {
+		page.Text(invoiceMargin, y, pdf.Helvetica, 9, line)
+		y += 12
+	}
+	if owner.Phone != "" {
+		page.Text(invoiceMargin, y, pdf.Helvetica, 9, "Tel: "+owner.Phone)
+		y += 12
+	}
+	page.Text(invoiceMargin, y, pdf.Helvetica, 9, "E-posta: "+owner.Email)
+
+	// Fatura başlığı
+	title := "FATURA"
+	if invoice.IsCreditNote() {
+		title = "İADE FATURASI"
+	}
+	page.TextRight(right, 62, pdf.HelveticaBold, 20, title)
+
+	number := invoice.InvoiceNumber
+	if number == "" {
+		number = "TASLAK"
+	}
+	meta := [][2]string{
+		{"Fatura No", number},
+		{"Fatura Tarihi", invoice.InvoiceDate.In(time.Local).Format(layout)},
+	}
+	if invoice.DueDate != nil {
+		meta = append(meta, [2]string{"Son Ödeme", invoice.DueDate.In(time.Local).Format(layout)})
+	}
+	if creditFor != "" {
+		meta = append(meta, [2]string{"İlgili Fatura", creditFor})
+	}
+	metaY := 82.0
+	for _, m := range meta {
+		page.TextRight(right-110, metaY, pdf.Helvetica, 9, m[0]+":")
+		page.TextRight(right, metaY, pdf.HelveticaBold, 9, m[1])
+		metaY += 13
+	}
+
+	// Müşteri bilgileri
+	y = math.Max(y, metaY) + 30
+	page.Text(invoiceMargin, y, pdf.HelveticaBold, 9, "SAYIN")
+	y += 14
+	page.Text(invoiceMargin, y, pdf.HelveticaBold, 11, invoice.Customer.Name)
+	y += 14
+	for _, line := range pdf.WrapText(pdf.Helvetica, 9, invoice.Customer.Address, 300) {
+		if line == "" {
+			continue
+		}
+		page.Text(invoiceMargin, y, pdf.Helvetica, 9, line)
+		y += 12
+	}
+	if invoice.Customer.Phone != "" {
+		page.Text(invoiceMargin, y, pdf.Helvetica, 9, "Tel: "+invoice.Customer.Phone)
+		y += 12
+	}
+	if invoice.Customer.Email != "" {
+		page.Text(invoiceMargin, y, pdf.Helvetica, 9, "E-posta: "+invoice.Customer.Email)
+		y += 12
+	}
+
+	// Satırlar
+	y += 20
+	y = invoiceTableHeader(page, y)
+	descWidth := invoiceColumns[0].right - 60 - invoiceMargin
+	for _, line := range invoice.Lines {
+		descLines := pdf.WrapText(pdf.Helvetica, 9, line.Description, descWidth)
+		if y+float64(len(descLines))*invoiceLineHeight > invoiceBottom {
+			page = doc.AddPage(pdf.A4Width, pdf.A4Height)
+			y = invoiceTableHeader(page, 60)
+		}
+
+		page.TextRight(invoiceColumns[0].right, y, pdf.Helvetica, 9, formatQuantity(line.Quantity)+" "+line.Unit)
+		page.TextRight(invoiceColumns[1].right, y, pdf.Helvetica, 9, formatAmount(line.UnitPrice))
+		page.TextRight(invoiceColumns[2].right, y, pdf.Helvetica, 9, fmt.Sprintf("%%%d", line.TaxRate))
+		page.TextRight(invoiceColumns[3].right, y, pdf.Helvetica, 9, formatAmount(line.Subtotal))
+		for _, d := range descLines {
+			page.Text(invoiceMargin+4, y, pdf.Helvetica, 9, d)
+			y += invoiceLineHeight
+		}
+		page.Line(invoiceMargin, y-8, right, y-8, 0.3)
+		y += 4
+	}
+
+	// Toplamlar
+	totals := [][2]string{{"Ara Toplam", formatAmount(invoice.Subtotal)}}
+	for _, tax := range invoice.TaxBreakdown() {
+		totals = append(totals, [2]string{
+			fmt.Sprintf("KDV %%%d (Matrah %s)", tax.Rate, formatAmount(tax.Base)),
+			formatAmount(tax.TaxAmount),
+		})
+	}
+	totals = append(totals, [2]string{"Toplam KDV", formatAmount(invoice.TaxAmount)})
+
+	if y+float64(len(totals)+3)*15 > invoiceBottom {
+		page = doc.AddPage(pdf.A4Width, pdf.A4Height)
+		y = 60
+	}
+	y += 10
+	for _, t := range totals {
+		page.TextRight(right-110, y, pdf.Helvetica, 9, t[0]+":")
+		page.TextRight(right, y, pdf.Helvetica, 9, t[1])
+		y += 15
+	}
+	page.FillRect(right-260, y-11, 260, 18, 0.9)
+	page.TextRight(right-110, y+2, pdf.HelveticaBold, 11, "GENEL TOPLAM:")
+	page.TextRight(right-4, y+2, pdf.HelveticaBold, 11, formatAmount(invoice.TotalAmount))
+	y += 30
+
+	// Döviz faturalarında kesim kuru ve ana para birimi karşılığı
+	if invoice.IsForeign() && invoice.Status != models.InvoiceDraft {
+		rate := strings.Replace(strconv.FormatFloat(invoice.ExchangeRate, 'f', -1, 64), ".", ",", 1)
+		page.TextRight(right, y-8, pdf.Helvetica, 8, fmt.Sprintf("Kur: 1 %s = %s %s, karşılığı %s",
+			invoice.Currency, rate, invoice.BaseCurrency, formatAmount(invoice.BaseTotal)))
+		y += 10
+	}
+
+	// Notlar
+	if invoice.Notes != "" {
+		page.Text(invoiceMargin, y, pdf.HelveticaBold, 9, "Notlar")
+		y += 13
+		for _, line := range pdf.WrapText(pdf.Helvetica, 9, invoice.Notes, right-invoiceMargin) {
+			if y > invoiceBottom {
+				page = doc.AddPage(pdf.A4Width, pdf.A4Height)
+				y = 60
+			}
+			page.Text(invoiceMargin, y, pdf.Helvetica, 9, line)
+			y += 12
+		}
+	}
+
+	// Durum uyarısı ve sayfa numaraları
+	stamp := ""
+	switch invoice.Status {
+	case models.InvoiceDraft:
+		stamp = "TASLAK - GEÇERLİ FATURA DEĞİLDİR"
+	case models.InvoiceVoid:
+		stamp = "İPTAL EDİLMİŞTİR"
+	}
+	for i, p := range doc.Pages() {
+		if stamp != "" {
+			p.TextCenter(pdf.A4Width/2, pdf.A4Height-50, pdf.HelveticaBold, 12, stamp)
+		}
+		p.TextRight(right, pdf.A4Height-30, pdf.Helvetica, 8, fmt.Sprintf("Sayfa %d/%d", i+1, len(doc.Pages())))
+	}
+
+	return doc
+}
+
+func invoiceTableHeader(page *pdf.Page, y float64) float64 {
+	right := pdf.A4Width - invoiceMargin
+	page.FillRect(invoiceMargin, y-12, right-invoiceMargin, 18, 0.9)
+	page.Text(invoiceMargin+4, y, pdf.HelveticaBold, 9, "Açıklama")
+	for i, col := range invoiceColumns {
+		x := col.right
+		if i == len(invoiceColumns)-1 {
+			x -= 4
+		}
+		page.TextRight(x, y, pdf.HelveticaBold, 9, col.title)
+	}
+	return y + 22
+}
+
+// renderReceipt siparişi termal yazıcı için dar bir fişe dönüştürür; tarihler layout biçimiyle yazılır
+func renderReceipt(owner *models.User, order *models.Order, paperWidthMM int, layout string) *pdf.Document {
+	doc := pdf.New()
+	doc.Title = "Fiş " + order.OrderNumber
+	doc.Author = owner.BusinessName
+
+	width := pdf.MM(float64(paperWidthMM))
+	margin := pdf.MM(3)
+	size := 8.5
+	if paperWidthMM == 58 {
+		size = 7
+	}
+	lineHeight := size * 1.4
+	content := width - 2*margin
+	center := width / 2
+	right := width - margin
+
+	page := doc.AddPage(width, 0)
+	y := margin + size
+
+	separator := func() {
+		y += lineHeight * 0.2
+		page.Line(margin, y, right, y, 0.5)
+		y += lineHeight
+	}
+
+	for _, line := range pdf.WrapText(pdf.HelveticaBold, size+2, businessName(owner), content) {
+		page.TextCenter(center, y, pdf.HelveticaBold, size+2, line)
+		y += lineHeight + 2
+	}
+	for _, line := range pdf.WrapText(pdf.Helvetica, size, owner.Address, content) {
+		if line == "" {
+			continue
+		}
+		page.TextCenter(center, y, pdf.Helvetica, size, line)
+		y += lineHeight
+	}
+	if owner.Phone != "" {
+		page.TextCenter(center, y, pdf.Helvetica, size, "Tel: "+owner.Phone)
+		y += lineHeight
+	}
+	separator()
+
+	page.TextCenter(center, y, pdf.HelveticaBold, size+1, "SATIŞ FİŞİ")
+	y += lineHeight + 1
+	for _, row := range [][2]string{
+		{"Fiş No", order.OrderNumber},
+		{"Tarih", order.OrderDate.In(time.Local).Format(layout)},
+		{"Müşteri", order.Customer.Name},
+	} {
+		page.Text(margin, y, pdf.Helvetica, size, row[0]+":")
+		page.TextRight(right, y, pdf.Helvetica, size, row[1])
+		y += lineHeight
+	}
+	separator()
+
+	for _, item := range order.Items {
+		name := fmt.Sprintf("Ürün #%d", item.ProductID)
+		unit := ""
+		if item.Product != nil {
+			name = item.Product.Name
+			unit = item.Product.Unit
+		}
+		for _, line := range pdf.WrapText(pdf.Helvetica, size, name, content) {
+			page.Text(margin, y, pdf.Helvetica, size, line)
+			y += lineHeight
+		}
+		detail := strings.TrimSpace(fmt.Sprintf("%d %s x %s", item.Quantity, unit, formatAmount(item.UnitPrice)))
+		page.Text(margin+4, y, pdf.Helvetica, size, detail)
+		page.TextRight(right, y, pdf.Helvetica, size, formatAmount(item.TotalPrice))
+		y += lineHeight
+	}
+	separator()
+
+	page.Text(margin, y+2, pdf.HelveticaBold, size+2, "TOPLAM")
+	page.TextRight(right, y+2, pdf.HelveticaBold, size+2, formatAmount(order.TotalAmount))
+	y += lineHeight + 4
+	if models.IsOrderReversed(order.Status) {
+		page.TextCenter(center, y, pdf.HelveticaBold, size, strings.ToUpper(models.OrderStatusLabel(order.Status)))
+		y += lineHeight
+	}
+	separator()
+
+	page.TextCenter(center, y, pdf.Helvetica, size, "Teşekkür ederiz")
+	y += lineHeight
+	page.TextCenter(center, y, pdf.Helvetica, size-1, time.Now().Format(layout))
+
+	return doc
+}
+
+// businessName belge başlığında kullanılacak işletme adını döndürür
+func businessName(owner *models.User) string {
+	if owner.BusinessName != "" {
+		return owner.BusinessName
+	}
+	return owner.Name
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umutaraz/tradesman-app/internal/exchange"
+	"github.com/umutaraz/tradesman-app/internal/middleware"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/money"
+)
+
+// exchangeRateLimit kur listesinde varsayılan olarak döndürülen kayıt sayısı
+const exchangeRateLimit = 100
+
+// baseCurrency işletmenin ana para birimini döndürür; ayar yoksa ya da geçersizse Türk lirasıdır
+func (h *Handler) baseCurrency(businessID int) (money.Currency, error) {
+	s, err := h.settings.Get(businessID)
+	if err != nil {
+		return "", err
+	}
+	return s.Currency, nil
+}
+
+// resolveCurrency istekteki para birimini doğrular; boşsa işletmenin ana para birimi kullanılır
+func (h *Handler) resolveCurrency(businessID int, currency money.Currency) (money.Currency, error) {
+	if currency == "" {
+		return h.baseCurrency(businessID)
+	}
+	parsed, err := money.ParseCurrency(string(currency))
+	if err != nil {
+		return "", newValidationError("Desteklenmeyen para birimi: " + string(currency))
+	}
+	return parsed, nil
+}
+
+// financialSummary [from, to) dönemindeki gelir ve giderleri toplar. Döviz kayıtları işlem
+// günündeki kurla ana para birimine çevrilir; kuru bulunamayan tutarlar toplama katılmaz ve
+// para birimi dökümünde işaretlenir.
+func (h *Handler) financialSummary(businessID int, from, to time.Time) (*models.FinancialSummary, error) {
+	base, err := h.baseCurrency(businessID)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := h.transactions.Totals(businessID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &models.FinancialSummary{
+		From:         from,
+		To:           to,
+		BaseCurrency: base,
+		Income:       money.New(0, base),
+		Expense:      money.New(0, base),
+		ByCurrency:   []models.CurrencyTotal{},
+	}
+
+	converter := exchange.NewConverter(h.db, businessID, base)
+	byCurrency := map[money.Currency]*models.CurrencyTotal{}
+	for _, total := range totals {
+		currency := total.Amount.Currency()
+		t, ok := byCurrency[currency]
+		if !ok {
+			t = &models.CurrencyTotal{
+				Currency:    currency,
+				Income:      money.New(0, currency),
+				Expense:     money.New(0, currency),
+				BaseIncome:  money.New(0, base),
+				BaseExpense: money.New(0, base),
+			}
+			byCurrency[currency] = t
+		}
+
+		converted, err := converter.Convert(total.Amount, total.Date)
+		if errors.Is(err, exchange.ErrNoRate) {
+			t.MissingRate = true
+		} else if err != nil {
+			return nil, err
+		}
+
+		if total.Type == "income" {
+			t.Income = t.Income.Add(total.Amount)
+			t.BaseIncome = t.BaseIncome.Add(converted)
+			summary.Income = summary.Income.Add(converted)
+		} else {
+			t.Expense = t.Expense.Add(total.Amount)
+			t.BaseExpense = t.BaseExpense.Add(converted)
+			summary.Expense = summary.Expense.Add(converted)
+		}
+	}
+
+	for _, t := range byCurrency {
+		summary.ByCurrency = append(summary.ByCurrency, *t)
+	}
+	sort.Slice(summary.ByCurrency, func(i, j int) bool {
+		return currencyOrder(summary.ByCurrency[i].Currency) < currencyOrder(summary.ByCurrency[j].Currency)
+	})
+	summary.Profit = summary.Income.Sub(summary.Expense)
+
+	return summary, nil
+}
+
+// currencyOrder para biriminin seçim listelerindeki sırası
+func currencyOrder(currency money.Currency) int {
+	for i, c := range money.Currencies {
+		if c == currency {
+			return i
+		}
+	}
+	return len(money.Currencies)
+}
+
+// Döviz kurları (API); currency ile süzülebilir
+func (h *Handler) GetExchangeRatesAPI(c *gin.Context) {
+	var currency money.Currency
+	if value := c.Query("currency"); value != "" {
+		parsed, err := money.ParseCurrency(value)
+		if err != nil {
+			respondError(c, newValidationError(err.Error()))
+			return
+		}
+		currency = parsed
+	}
+
+	limit := exchangeRateLimit
+	if value := c.Query("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			respondError(c, newValidationError("Geçersiz limit"))
+			return
+		}
+		limit = n
+	}
+
+	rates, err := exchange.List(h.db, middleware.BusinessID(c), currency, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rates)
+}
+
+// Kur ekle; aynı gün ve para birimi için girilmiş kurun üzerine yazar
+func (h *Handler) SaveExchangeRate(c *gin.Context) {
+	var rate models.ExchangeRate
+	if err := c.ShouldBindJSON(&rate); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rate.UserID = middleware.BusinessID(c)
+	rate.Source = models.RateSourceManual
+	if err := exchange.Validate(&rate); err != nil {
+		respondError(c, newValidationError(err.Error()))
+		return
+	}
+	if err := exchange.Save(h.db, &rate); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rate)
+}
+
+// Kuru sil
+func (h *Handler) DeleteExchangeRate(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := exchange.Delete(h.db, middleware.BusinessID(c), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// Kur dosyası içe aktar; TCMB XML (today.xml) ya da tarih, para birimi, kur kolonlu CSV kabul edilir
+func (h *Handler) ImportExchangeRates(c *gin.Context) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		respondError(c, newValidationError("Kur dosyası seçilmedi"))
+		return
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	defer f.Close()
+
+	rates, err := exchange.Parse(f)
+	if err != nil {
+		respondError(c, newValidationError(err.Error()))
+		return
+	}
+	for i := range rates {
+		if err := exchange.Validate(&rates[i]); err != nil {
+			respondError(c, newValidationError(err.Error()))
+			return
+		}
+	}
+
+	imported, err := exchange.Import(h.db, middleware.BusinessID(c), rates)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "imported": imported, "rates": rates})
+}
@@ -2,30 +2,70 @@ package handlers
 
 import (
 	"net/http"
+	"reflect"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/umutaraz/tradesman-app/internal/backup"
+	"github.com/umutaraz/tradesman-app/internal/config"
 	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/middleware"
 	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/money"
+	"github.com/umutaraz/tradesman-app/internal/notify"
+	"github.com/umutaraz/tradesman-app/internal/permissions"
+	"github.com/umutaraz/tradesman-app/internal/repository"
+	"github.com/umutaraz/tradesman-app/internal/settings"
 )
 
+func init() {
+	// Tutarlar istek doğrulamasında kuruş cinsinden sayı gibi karşılaştırılır (gt=0, gte=0)
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+			return field.Interface().(money.Money).Minor()
+		}, money.Money{})
+	}
+}
+
 type Handler struct {
-	db *database.DB
+	db           *database.DB
+	customers    repository.CustomerRepo
+	products     repository.ProductRepo
+	orders       repository.OrderRepo
+	transactions repository.TransactionRepo
+	cfg          *config.Config
+	notifier     *notify.Notifier
+	settings     *settings.Store
+	backups      *backup.Manager
 }
 
-func New(db *database.DB) *Handler {
-	return &Handler{db: db}
+func New(db *database.DB, store repository.Store, cfg *config.Config, notifier *notify.Notifier,
+	prefs *settings.Store, backups *backup.Manager) *Handler {
+	return &Handler{
+		db:           db,
+		customers:    store.Customers,
+		products:     store.Products,
+		orders:       store.Orders,
+		transactions: store.Transactions,
+		cfg:          cfg,
+		notifier:     notifier,
+		settings:     prefs,
+		backups:      backups,
+	}
 }
 
 // Dashboard
 func (h *Handler) Dashboard(c *gin.Context) {
-	stats, err := h.getDashboardStats()
+	stats, err := h.getDashboardStats(middleware.BusinessID(c))
 	if err != nil {
 		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"error": err.Error()})
 		return
 	}
 
-	c.HTML(http.StatusOK, "dashboard.html", gin.H{
+	h.render(c, "dashboard.html", gin.H{
 		"stats":  stats,
 		"title":  "Dashboard - Esnaf Yönetim Sistemi",
 		"active": "dashboard",
@@ -34,59 +74,110 @@ func (h *Handler) Dashboard(c *gin.Context) {
 
 // Müşteriler
 func (h *Handler) Customers(c *gin.Context) {
-	customers, err := h.getCustomers()
+	archived := c.Query("archived") == "1"
+	q, err := h.parseListQuery(c, repository.CustomerList)
+	if err != nil {
+		status, message := errorResponse(err)
+		c.HTML(status, "error.html", gin.H{"error": message})
+		return
+	}
+	customers, page, err := h.customers.List(middleware.BusinessID(c), archived, q)
 	if err != nil {
 		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"error": err.Error()})
 		return
 	}
 
-	c.HTML(http.StatusOK, "customers.html", gin.H{
-		"customers": customers,
-		"title":     "Müşteriler - Esnaf Yönetim Sistemi",
-		"active":    "customers",
+	h.render(c, "customers.html", gin.H{
+		"customers":  customers,
+		"pagination": page,
+		"filters":    c.Request.URL.Query(),
+		"archived":   archived,
+		"title":      "Müşteriler - Esnaf Yönetim Sistemi",
+		"active":     "customers",
 	})
 }
 
 // Ürünler
 func (h *Handler) Products(c *gin.Context) {
-	products, err := h.getProducts()
+	businessID := middleware.BusinessID(c)
+	q, err := h.parseListQuery(c, repository.ProductList)
+	if err != nil {
+		status, message := errorResponse(err)
+		c.HTML(status, "error.html", gin.H{"error": message})
+		return
+	}
+	products, page, err := h.products.List(businessID, q)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"error": err.Error()})
+		return
+	}
+
+	// Düşük stok uyarısı yalnızca görüntülenen sayfadaki değil tüm ürünleri kapsar
+	lowStock, err := h.products.LowStock(businessID, 0)
 	if err != nil {
 		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"error": err.Error()})
 		return
 	}
 
-	c.HTML(http.StatusOK, "products.html", gin.H{
-		"products": products,
-		"title":    "Ürünler - Esnaf Yönetim Sistemi",
-		"active":   "products",
+	s, err := h.settings.Get(businessID)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"error": err.Error()})
+		return
+	}
+
+	h.render(c, "products.html", gin.H{
+		"products":         products,
+		"pagination":       page,
+		"filters":          c.Request.URL.Query(),
+		"lowStockProducts": lowStock,
+		"lowStockLevel":    s.LowStockLevel,
+		"title":            "Ürünler - Esnaf Yönetim Sistemi",
+		"active":           "products",
 	})
 }
 
 // Siparişler
 func (h *Handler) Orders(c *gin.Context) {
-	orders, err := h.getOrders()
+	q, err := h.parseListQuery(c, repository.OrderList)
+	if err != nil {
+		status, message := errorResponse(err)
+		c.HTML(status, "error.html", gin.H{"error": message})
+		return
+	}
+	orders, page, err := h.orders.List(middleware.BusinessID(c), q)
 	if err != nil {
 		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"error": err.Error()})
 		return
 	}
 
-	c.HTML(http.StatusOK, "orders.html", gin.H{
-		"orders": orders,
-		"title":  "Siparişler - Esnaf Yönetim Sistemi",
-		"active": "orders",
+	h.render(c, "orders.html", gin.H{
+		"orders":     orders,
+		"pagination": page,
+		"filters":    c.Request.URL.Query(),
+		"title":      "Siparişler - Esnaf Yönetim Sistemi",
+		"active":     "orders",
 	})
 }
 
 // Muhasebe
 func (h *Handler) Accounting(c *gin.Context) {
-	transactions, err := h.getTransactions()
+	q, err := h.parseListQuery(c, repository.TransactionList)
+	if err != nil {
+		status, message := errorResponse(err)
+		c.HTML(status, "error.html", gin.H{"error": message})
+		return
+	}
+	transactions, page, err := h.transactions.List(middleware.BusinessID(c), q)
 	if err != nil {
 		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"error": err.Error()})
 		return
 	}
 
-	c.HTML(http.StatusOK, "accounting.html", gin.H{
+	h.render(c, "accounting.html", gin.H{
 		"transactions": transactions,
+		"pagination":   page,
+		"filters":      c.Request.URL.Query(),
+		"today":        time.Now(),
 		"title":        "Muhasebe - Esnaf Yönetim Sistemi",
 		"active":       "accounting",
 	})
@@ -94,310 +185,221 @@ func (h *Handler) Accounting(c *gin.Context) {
 
 // Randevular
 func (h *Handler) Appointments(c *gin.Context) {
-	// İleride randevu bilgileri için getAppointments() fonksiyonu oluşturulabilir
-	c.HTML(http.StatusOK, "appointments.html", gin.H{
-		"title":  "Randevular - Esnaf Yönetim Sistemi",
-		"active": "appointments",
-	})
-}
+	businessID := middleware.BusinessID(c)
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
 
-// Profil
-func (h *Handler) Profile(c *gin.Context) {
-	// Profil için kullanıcı bilgilerini al
-	// İleride gerçek kullanıcı bilgileri için getUserProfile() fonksiyonu oluşturulabilir
-	user := models.User{
-		ID:           1,
-		Name:         "Ahmet Yılmaz",
-		Email:        "ahmet@esnaf.com",
-		Role:         "Yönetici",
-		BusinessName: "Yılmaz Ticaret",
-		Phone:        "+90 (555) 123 4567",
-		Address:      "Atatürk Cad. No:123 İstanbul",
-		CreatedAt:    time.Now(),
-		UpdatedAt:    time.Now(),
-	}
-
-	c.HTML(http.StatusOK, "profile.html", gin.H{
-		"user":   user,
-		"title":  "Profil - Esnaf Yönetim Sistemi",
-		"active": "profile",
-	})
-}
-
-// Profil Güncelle
-func (h *Handler) UpdateProfile(c *gin.Context) {
-	// Form verilerini al
-	name := c.PostForm("name")
-	businessName := c.PostForm("business_name")
-	email := c.PostForm("email")
-	phone := c.PostForm("phone")
-	address := c.PostForm("address")
-
-	// Profil güncelleme işlemi burada yapılacak
-	// Şimdilik sadece başarı mesajı döndürelim
-
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "Profil başarıyla güncellendi",
-		"data": gin.H{
-			"name":          name,
-			"business_name": businessName,
-			"email":         email,
-			"phone":         phone,
-			"address":       address,
-		},
-	})
-}
-
-// Ayarlar
-func (h *Handler) Settings(c *gin.Context) {
-	c.HTML(http.StatusOK, "settings.html", gin.H{
-		"title":  "Ayarlar - Esnaf Yönetim Sistemi",
-		"active": "settings",
-	})
-}
-
-// Faturalar
-func (h *Handler) Invoices(c *gin.Context) {
-	// İleride fatura bilgileri için getInvoices() fonksiyonu oluşturulabilir
-	c.HTML(http.StatusOK, "invoices.html", gin.H{
-		"title":  "Faturalar - Esnaf Yönetim Sistemi",
-		"active": "invoices",
-	})
-}
-
-// Raporlar
-func (h *Handler) Reports(c *gin.Context) {
-	// İleride rapor bilgileri için getReports() fonksiyonu oluşturulabilir
-	c.HTML(http.StatusOK, "reports.html", gin.H{
-		"title":  "Raporlar - Esnaf Yönetim Sistemi",
-		"active": "reports",
-	})
-}
+	todayAppointments, err := h.queryAppointments(businessID, "a.start_time >= ? AND a.start_time < ?",
+		today, today.AddDate(0, 0, 1))
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"error": err.Error()})
+		return
+	}
 
-// Bildirimler
-func (h *Handler) Notifications(c *gin.Context) {
-	// İleride bildirim bilgileri için getNotifications() fonksiyonu oluşturulabilir
-	c.HTML(http.StatusOK, "notifications.html", gin.H{
-		"title":  "Bildirimler - Esnaf Yönetim Sistemi",
-		"active": "notifications",
-	})
-}
+	upcomingAppointments, err := h.queryAppointments(businessID, "a.start_time >= ? AND a.status != ?",
+		now, models.AppointmentCanceled)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"error": err.Error()})
+		return
+	}
+	if len(upcomingAppointments) > 20 {
+		upcomingAppointments = upcomingAppointments[:20]
+	}
 
-// API Endpoints
-func (h *Handler) GetCustomersAPI(c *gin.Context) {
-	customers, err := h.getCustomers()
+	// Takvimde son üç ay ve gelecek bir yıl gösterilir
+	appointments, err := h.queryAppointments(businessID, "a.start_time >= ? AND a.start_time < ?",
+		today.AddDate(0, -3, 0), today.AddDate(1, 0, 0))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, customers)
-}
 
-func (h *Handler) CreateCustomer(c *gin.Context) {
-	var customer models.Customer
-	if err := c.ShouldBindJSON(&customer); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	customers, err := h.customers.Active(businessID)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"error": err.Error()})
 		return
 	}
 
-	customer.UserID = 1 // Şimdilik sabit user ID
-	id, err := h.insertCustomer(&customer)
+	staff, err := h.getBusinessUsers(businessID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"error": err.Error()})
 		return
 	}
 
-	customer.ID = id
-	c.JSON(http.StatusCreated, customer)
+	h.render(c, "appointments.html", gin.H{
+		"todayAppointments":    todayAppointments,
+		"upcomingAppointments": upcomingAppointments,
+		"appointments":         appointments,
+		"customersList":        customers,
+		"staffList":            staff,
+		"currentUserID":        middleware.UserID(c),
+		"today":                today,
+		"title":                "Randevular - Esnaf Yönetim Sistemi",
+		"active":               "appointments",
+	})
 }
 
-// Database helper methods
-func (h *Handler) getDashboardStats() (*models.DashboardStats, error) {
-	stats := &models.DashboardStats{}
+// Faturalar
+func (h *Handler) Invoices(c *gin.Context) {
+	businessID := middleware.BusinessID(c)
+	if err := h.markOverdueInvoices(businessID); err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"error": err.Error()})
+		return
+	}
 
-	// Toplam müşteri sayısı
-	err := h.db.QueryRow("SELECT COUNT(*) FROM customers WHERE user_id = ?", 1).Scan(&stats.TotalCustomers)
+	invoices, err := h.queryInvoices(businessID, "")
 	if err != nil {
-		return nil, err
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"error": err.Error()})
+		return
 	}
 
-	// Toplam ürün sayısı
-	err = h.db.QueryRow("SELECT COUNT(*) FROM products WHERE user_id = ?", 1).Scan(&stats.TotalProducts)
+	customers, err := h.customers.Active(businessID)
 	if err != nil {
-		return nil, err
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"error": err.Error()})
+		return
 	}
 
-	// Toplam sipariş sayısı
-	err = h.db.QueryRow("SELECT COUNT(*) FROM orders WHERE user_id = ?", 1).Scan(&stats.TotalOrders)
+	products, err := h.products.All(businessID)
 	if err != nil {
-		return nil, err
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"error": err.Error()})
+		return
 	}
 
-	// Bekleyen sipariş sayısı
-	err = h.db.QueryRow("SELECT COUNT(*) FROM orders WHERE user_id = ? AND status = 'pending'", 1).Scan(&stats.PendingOrders)
-	if err != nil {
-		return nil, err
+	h.render(c, "invoices.html", gin.H{
+		"invoices":  invoices,
+		"customers": customers,
+		"products":  products,
+		"kdvRates":  models.KDVRates,
+		"title":     "Faturalar - Esnaf Yönetim Sistemi",
+		"active":    "invoices",
+	})
+}
+
+// Fatura detayı
+func (h *Handler) InvoiceDetail(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	businessID := middleware.BusinessID(c)
+
+	if err := h.markOverdueInvoices(businessID); err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"error": err.Error()})
+		return
 	}
 
-	// Bu ay gelir
-	err = h.db.QueryRow(`
-		SELECT COALESCE(SUM(amount), 0) 
-		FROM transactions 
-		WHERE user_id = ? AND type = 'income' 
-		AND strftime('%Y-%m', transaction_date) = strftime('%Y-%m', 'now')
-	`, 1).Scan(&stats.MonthlyRevenue)
+	invoice, err := h.getInvoice(businessID, id)
 	if err != nil {
-		return nil, err
+		c.HTML(http.StatusNotFound, "error.html", gin.H{"error": "Fatura bulunamadı"})
+		return
 	}
 
-	// Bu ay gider
-	err = h.db.QueryRow(`
-		SELECT COALESCE(SUM(amount), 0) 
-		FROM transactions 
-		WHERE user_id = ? AND type = 'expense' 
-		AND strftime('%Y-%m', transaction_date) = strftime('%Y-%m', 'now')
-	`, 1).Scan(&stats.MonthlyExpenses)
+	// Fatura üzerindeki işletme bilgileri hesap sahibinden alınır
+	owner, err := h.getUserByID(businessID)
 	if err != nil {
-		return nil, err
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"error": err.Error()})
+		return
 	}
 
-	stats.MonthlyProfit = stats.MonthlyRevenue - stats.MonthlyExpenses
-
-	return stats, nil
+	h.render(c, "invoices.html", gin.H{
+		"invoice": invoice,
+		"user":    owner,
+		"title":   "Fatura - " + invoice.InvoiceNumber,
+		"active":  "invoices",
+	})
 }
 
-func (h *Handler) getCustomers() ([]models.Customer, error) {
-	rows, err := h.db.Query("SELECT * FROM customers WHERE user_id = ? ORDER BY created_at DESC", 1)
+// API Endpoints
+func (h *Handler) GetCustomersAPI(c *gin.Context) {
+	q, err := h.parseListQuery(c, repository.CustomerList)
 	if err != nil {
-		return nil, err
+		respondError(c, err)
+		return
 	}
-	defer rows.Close()
-
-	var customers []models.Customer
-	for rows.Next() {
-		var customer models.Customer
-		err := rows.Scan(&customer.ID, &customer.UserID, &customer.Name, &customer.Email,
-			&customer.Phone, &customer.Address, &customer.Notes, &customer.CreatedAt, &customer.UpdatedAt)
-		if err != nil {
-			return nil, err
-		}
-		customers = append(customers, customer)
+	customers, page, err := h.customers.List(middleware.BusinessID(c), c.Query("archived") == "1", q)
+	if err != nil {
+		respondError(c, err)
+		return
 	}
-
-	return customers, nil
+	c.JSON(http.StatusOK, gin.H{"customers": customers, "pagination": page})
 }
 
-func (h *Handler) getProducts() ([]models.Product, error) {
-	rows, err := h.db.Query("SELECT * FROM products WHERE user_id = ? ORDER BY created_at DESC", 1)
-	if err != nil {
-		return nil, err
+func (h *Handler) CreateCustomer(c *gin.Context) {
+	var customer models.Customer
+	if err := c.ShouldBindJSON(&customer); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
-	defer rows.Close()
 
-	var products []models.Product
-	for rows.Next() {
-		var product models.Product
-		err := rows.Scan(&product.ID, &product.UserID, &product.Name, &product.Description,
-			&product.Price, &product.Category, &product.StockQuantity, &product.Unit,
-			&product.CreatedAt, &product.UpdatedAt)
-		if err != nil {
-			return nil, err
-		}
-		products = append(products, product)
+	customer.UserID = middleware.BusinessID(c)
+	id, err := h.customers.Create(&customer)
+	if err != nil {
+		respondError(c, err)
+		return
 	}
 
-	return products, nil
+	customer.ID = id
+	c.JSON(http.StatusCreated, customer)
 }
 
-func (h *Handler) getOrders() ([]models.Order, error) {
-	rows, err := h.db.Query(`
-		SELECT o.*, c.name as customer_name 
-		FROM orders o 
-		JOIN customers c ON o.customer_id = c.id 
-		WHERE o.user_id = ? 
-		ORDER BY o.created_at DESC
-	`, 1)
-	if err != nil {
+// getDashboardStats dashboard özet bilgilerini toplar
+func (h *Handler) getDashboardStats(userID int) (*models.DashboardStats, error) {
+	stats := &models.DashboardStats{}
+	var err error
+
+	// Toplam müşteri, ürün ve sipariş sayıları
+	if stats.TotalCustomers, err = h.customers.Count(userID); err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	var orders []models.Order
-	for rows.Next() {
-		var order models.Order
-		var customerName string
-		err := rows.Scan(&order.ID, &order.UserID, &order.CustomerID, &order.OrderNumber,
-			&order.Status, &order.TotalAmount, &order.Notes, &order.OrderDate,
-			&order.DeliveryDate, &order.CreatedAt, &order.UpdatedAt, &customerName)
-		if err != nil {
-			return nil, err
-		}
-		order.Customer = &models.Customer{Name: customerName}
-		orders = append(orders, order)
+	if stats.TotalProducts, err = h.products.Count(userID); err != nil {
+		return nil, err
+	}
+	if stats.TotalOrders, err = h.orders.Count(userID); err != nil {
+		return nil, err
 	}
 
-	return orders, nil
-}
-
-func (h *Handler) getTransactions() ([]models.Transaction, error) {
-	rows, err := h.db.Query("SELECT * FROM transactions WHERE user_id = ? ORDER BY transaction_date DESC", 1)
-	if err != nil {
+	// Bekleyen sipariş sayısı
+	if stats.PendingOrders, err = h.orders.CountByStatus(userID, models.OrderPending); err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
-	var transactions []models.Transaction
-	for rows.Next() {
-		var transaction models.Transaction
-		err := rows.Scan(&transaction.ID, &transaction.UserID, &transaction.Type,
-			&transaction.Category, &transaction.Amount, &transaction.Description,
-			&transaction.TransactionDate, &transaction.CreatedAt)
-		if err != nil {
-			return nil, err
-		}
-		transactions = append(transactions, transaction)
+	// Bu ayın geliri ve gideri; döviz kayıtları işlem günündeki kurla ana para birimine çevrilir
+	now := time.Now()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	summary, err := h.financialSummary(userID, monthStart, monthStart.AddDate(0, 1, 0))
+	if err != nil {
+		return nil, err
 	}
 
-	return transactions, nil
-}
+	stats.MonthlyRevenue = summary.Income
+	stats.MonthlyExpenses = summary.Expense
+	stats.MonthlyProfit = summary.Profit
+	stats.MissingRates = summary.HasMissingRates()
 
-func (h *Handler) insertCustomer(customer *models.Customer) (int, error) {
-	result, err := h.db.Exec(`
-		INSERT INTO customers (user_id, name, email, phone, address, notes)
-		VALUES (?, ?, ?, ?, ?, ?)
-	`, customer.UserID, customer.Name, customer.Email, customer.Phone, customer.Address, customer.Notes)
+	// Stoğu eşiğe inmiş ürünler, en kritik olanlar önce
+	stats.LowStockProducts, err = h.products.LowStock(userID, 10)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	id, err := result.LastInsertId()
+	// Son 30 günde en çok satan ürünler (iptal ve iade edilen siparişler hariç)
+	stats.TopProducts, err = h.products.TopSelling(userID, now.AddDate(0, 0, -30), 5)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	return int(id), nil
+	return stats, nil
 }
 
 // Ürün Detayı
 func (h *Handler) ProductDetail(c *gin.Context) {
-	id := c.Param("id")
+	id, _ := strconv.Atoi(c.Param("id"))
 
 	// Ürün detayını veritabanından al
-	var product models.Product
-	err := h.db.QueryRow(`
-		SELECT id, user_id, name, description, price, category, stock_quantity, unit, created_at, updated_at
-		FROM products WHERE id = ? AND user_id = ?
-	`, id, 1).Scan(&product.ID, &product.UserID, &product.Name, &product.Description,
-		&product.Price, &product.Category, &product.StockQuantity, &product.Unit,
-		&product.CreatedAt, &product.UpdatedAt)
-
+	product, err := h.products.Get(middleware.BusinessID(c), id)
 	if err != nil {
 		c.HTML(http.StatusNotFound, "error.html", gin.H{"error": "Ürün bulunamadı"})
 		return
 	}
 
-	c.HTML(http.StatusOK, "product_detail.html", gin.H{
+	h.render(c, "product_detail.html", gin.H{
 		"product": product,
 		"title":   "Ürün Detayı - " + product.Name,
 		"active":  "products",
@@ -406,68 +408,20 @@ func (h *Handler) ProductDetail(c *gin.Context) {
 
 // Sipariş Detayı
 func (h *Handler) OrderDetail(c *gin.Context) {
-	id := c.Param("id")
-
-	// Sipariş detayını veritabanından al
-	var order models.Order
-	err := h.db.QueryRow(`
-		SELECT o.id, o.user_id, o.customer_id, o.order_number, o.status, o.total_amount, 
-		       o.notes, o.order_date, o.delivery_date, o.created_at, o.updated_at,
-		       c.name as customer_name, c.email as customer_email, c.phone as customer_phone
-		FROM orders o 
-		JOIN customers c ON o.customer_id = c.id 
-		WHERE o.id = ? AND o.user_id = ?
-	`, id, 1).Scan(&order.ID, &order.UserID, &order.CustomerID, &order.OrderNumber,
-		&order.Status, &order.TotalAmount, &order.Notes, &order.OrderDate,
-		&order.DeliveryDate, &order.CreatedAt, &order.UpdatedAt,
-		&order.Customer.Name, &order.Customer.Email, &order.Customer.Phone)
+	id, _ := strconv.Atoi(c.Param("id"))
 
+	// Sipariş detayını ve kalemlerini veritabanından al
+	order, err := h.orders.Get(middleware.BusinessID(c), id)
 	if err != nil {
 		c.HTML(http.StatusNotFound, "error.html", gin.H{"error": "Sipariş bulunamadı"})
 		return
 	}
 
-	// Sipariş kalemlerini al
-	items, err := h.getOrderItems(order.ID)
-	if err != nil {
-		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"error": err.Error()})
-		return
-	}
-	order.Items = items
-
-	c.HTML(http.StatusOK, "order_detail.html", gin.H{
-		"order":  order,
-		"title":  "Sipariş Detayı - " + order.OrderNumber,
-		"active": "orders",
+	h.render(c, "order_detail.html", gin.H{
+		"order":        order,
+		"nextStatuses": models.NextOrderStatuses(order.Status),
+		"canManage":    middleware.Can(c, permissions.ManageOrders),
+		"title":        "Sipariş Detayı - " + order.OrderNumber,
+		"active":       "orders",
 	})
 }
-
-// Sipariş kalemlerini getir
-func (h *Handler) getOrderItems(orderID int) ([]models.OrderItem, error) {
-	rows, err := h.db.Query(`
-		SELECT oi.id, oi.order_id, oi.product_id, oi.quantity, oi.unit_price, oi.total_price,
-		       p.name as product_name, p.unit as product_unit
-		FROM order_items oi
-		JOIN products p ON oi.product_id = p.id
-		WHERE oi.order_id = ?
-	`, orderID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var items []models.OrderItem
-	for rows.Next() {
-		var item models.OrderItem
-		var productName, productUnit string
-		err := rows.Scan(&item.ID, &item.OrderID, &item.ProductID, &item.Quantity,
-			&item.UnitPrice, &item.TotalPrice, &productName, &productUnit)
-		if err != nil {
-			return nil, err
-		}
-		item.Product = &models.Product{Name: productName, Unit: productUnit}
-		items = append(items, item)
-	}
-
-	return items, nil
-}
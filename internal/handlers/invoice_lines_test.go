@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/database/dbtest"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/money"
+	"github.com/umutaraz/tradesman-app/internal/settings"
+)
+
+func TestBuildInvoiceLine(t *testing.T) {
+	db := dbtest.New(t)
+	userID := dbtest.User(t, db, "sahip@example.com")
+	result, err := db.Exec("INSERT INTO products (user_id, name, price, unit) VALUES (?, 'Kablo', 1200, 'metre')", userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, _ := result.LastInsertId()
+	productID := int(id)
+	missingID := productID + 100
+
+	tests := []struct {
+		name    string
+		req     invoiceLineRequest
+		want    models.InvoiceLine
+		wantErr bool
+	}{
+		{"default rate", invoiceLineRequest{Description: "Montaj", Quantity: 2, UnitPrice: money.TL(15000)},
+			models.InvoiceLine{Description: "Montaj", Quantity: 2, Unit: "adet", UnitPrice: money.TL(15000), TaxRate: 20,
+				Subtotal: money.TL(30000), TaxAmount: money.TL(6000), Total: money.TL(36000)}, false},
+		{"product fills description and unit", invoiceLineRequest{ProductID: &productID, Quantity: 2.5,
+			UnitPrice: money.TL(1234), TaxRate: 10}, models.InvoiceLine{ProductID: &productID, Description: "Kablo",
+			Quantity: 2.5, Unit: "metre", UnitPrice: money.TL(1234), TaxRate: 10, Subtotal: money.TL(3085),
+			TaxAmount: money.TL(309), Total: money.TL(3394)}, false},
+		{"invalid rate", invoiceLineRequest{Description: "Montaj", Quantity: 1, UnitPrice: money.TL(1000), TaxRate: 18},
+			models.InvoiceLine{}, true},
+		{"zero quantity", invoiceLineRequest{Description: "Montaj", UnitPrice: money.TL(1000)}, models.InvoiceLine{}, true},
+		{"negative price", invoiceLineRequest{Description: "Montaj", Quantity: 1, UnitPrice: money.TL(-100)},
+			models.InvoiceLine{}, true},
+		{"missing description", invoiceLineRequest{Quantity: 1, UnitPrice: money.TL(1000)}, models.InvoiceLine{}, true},
+		{"unknown product", invoiceLineRequest{ProductID: &missingID, Quantity: 1, UnitPrice: money.TL(1000)},
+			models.InvoiceLine{}, true},
+	}
+	for _, tt := range tests {
+		tx, err := db.Begin()
+		if err != nil {
+			t.Fatal(err)
+		}
+		line, err := buildInvoiceLine(tx, userID, tt.req, 20)
+		tx.Rollback()
+
+		if tt.wantErr {
+			if !errors.Is(err, errValidation) {
+				t.Errorf("%s: err = %v, want validation error", tt.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if line.Description != tt.want.Description || line.Unit != tt.want.Unit || line.TaxRate != tt.want.TaxRate ||
+			line.Subtotal.Minor() != tt.want.Subtotal.Minor() || line.TaxAmount.Minor() != tt.want.TaxAmount.Minor() ||
+			line.Total.Minor() != tt.want.Total.Minor() {
+			t.Errorf("%s: line = %+v, want %+v", tt.name, line, tt.want)
+		}
+	}
+
+	// Oran verilmeyen satıra işletmenin varsayılan KDV oranı uygulanır
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+	line, err := buildInvoiceLine(tx, userID, invoiceLineRequest{Description: "Montaj", Quantity: 1, UnitPrice: money.TL(10000)}, 10)
+	if err != nil || line.TaxRate != 10 || line.TaxAmount.Minor() != 1000 {
+		t.Errorf("business default rate: line = %+v, err = %v; want %%10 KDV", line, err)
+	}
+}
+
+// newDraftInvoice tek satırlı bir taslak fatura oluşturur
+func newDraftInvoice(t *testing.T, db *database.DB, userID, customerID int, invoiceType string, date time.Time) int {
+	t.Helper()
+	var id int
+	err := inTx(db, func(tx *sql.Tx) (err error) {
+		id, err = insertInvoice(tx, &models.Invoice{UserID: userID, CustomerID: customerID, Type: invoiceType, InvoiceDate: date,
+			Currency: money.TRY})
+		if err != nil {
+			return err
+		}
+		return replaceInvoiceLines(tx, userID, id, []invoiceLineRequest{{Description: "Montaj", Quantity: 1, UnitPrice: money.TL(10000)}}, 20)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func inTx(db *database.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func TestIssueInvoiceNumbering(t *testing.T) {
+	db := dbtest.New(t)
+	userID := dbtest.User(t, db, "sahip@example.com")
+	result, err := db.Exec("INSERT INTO customers (user_id, name) VALUES (?, 'Ayşe Demir')", userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, _ := result.LastInsertId()
+	customerID := int(id)
+
+	day := func(d int) time.Time { return time.Date(2026, 5, d, 0, 0, 0, 0, time.Local) }
+	first := newDraftInvoice(t, db, userID, customerID, models.InvoiceTypeSales, day(2))
+	second := newDraftInvoice(t, db, userID, customerID, models.InvoiceTypeSales, day(3))
+	early := newDraftInvoice(t, db, userID, customerID, models.InvoiceTypeSales, day(1))
+	credit := newDraftInvoice(t, db, userID, customerID, models.InvoiceTypeCreditNote, day(3))
+
+	issue := func(invoiceID int) error {
+		return inTx(db, func(tx *sql.Tx) error { return issueInvoice(tx, userID, invoiceID, settings.Defaults()) })
+	}
+	number := func(invoiceID int) string {
+		var n sql.NullString
+		if err := db.QueryRow("SELECT invoice_number FROM invoices WHERE id = ?", invoiceID).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		return n.String
+	}
+
+	if err := issue(first); err != nil {
+		t.Fatal(err)
+	}
+	// Geri alınan işlem numara tüketmez
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := issueInvoice(tx, userID, second, settings.Defaults()); err != nil {
+		t.Fatal(err)
+	}
+	tx.Rollback()
+	if err := issue(second); err != nil {
+		t.Fatal(err)
+	}
+	if err := issue(credit); err != nil {
+		t.Fatal(err)
+	}
+
+	for invoiceID, want := range map[int]string{first: "FTR2026000000001", second: "FTR2026000000002",
+		credit: creditInvoiceSeries + "2026000000001"} {
+		if got := number(invoiceID); got != want {
+			t.Errorf("invoice %d number = %s, want %s", invoiceID, got, want)
+		}
+	}
+
+	if err := issue(early); !errors.Is(err, errValidation) {
+		t.Errorf("issuing before the last invoice date: err = %v, want validation error", err)
+	}
+	if err := issue(first); !errors.Is(err, errConflict) {
+		t.Errorf("reissuing: err = %v, want conflict", err)
+	}
+}
+
+func TestIssueForeignCurrencyInvoice(t *testing.T) {
+	db := dbtest.New(t)
+	userID := dbtest.User(t, db, "sahip@example.com")
+	result, err := db.Exec("INSERT INTO customers (user_id, name) VALUES (?, 'Ayşe Demir')", userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, _ := result.LastInsertId()
+	customerID := int(id)
+
+	date := time.Date(2026, 5, 4, 0, 0, 0, 0, time.Local)
+	invoiceID := newDraftInvoice(t, db, userID, customerID, models.InvoiceTypeSales, date)
+	creditID := newDraftInvoice(t, db, userID, customerID, models.InvoiceTypeCreditNote, date.AddDate(0, 0, 1))
+	if _, err := db.Exec("UPDATE invoices SET currency = ? WHERE id IN (?, ?)", money.USD, invoiceID, creditID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("UPDATE invoices SET credit_for_id = ? WHERE id = ?", invoiceID, creditID); err != nil {
+		t.Fatal(err)
+	}
+	issue := func(invoiceID int) error {
+		return inTx(db, func(tx *sql.Tx) error { return issueInvoice(tx, userID, invoiceID, settings.Defaults()) })
+	}
+	type issued struct {
+		base      money.Currency
+		rate      float64
+		total     money.Money
+		baseTotal money.Money
+	}
+	read := func(invoiceID int) issued {
+		var s issued
+		err := db.QueryRow("SELECT base_currency, exchange_rate, total_amount, base_total_amount FROM invoices WHERE id = ?",
+			invoiceID).Scan(&s.base, &s.rate, &s.total, &s.baseTotal)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	// Fatura tarihinde ya da öncesinde kur yoksa döviz faturası kesilemez
+	if err := issue(invoiceID); !errors.Is(err, errValidation) {
+		t.Fatalf("issue without rate: err = %v, want validation error", err)
+	}
+
+	if _, err := db.Exec("INSERT INTO exchange_rates (user_id, currency, rate_date, rate) VALUES (?, 'USD', '2026-05-01', 32.5)",
+		userID); err != nil {
+		t.Fatal(err)
+	}
+	if err := issue(invoiceID); err != nil {
+		t.Fatal(err)
+	}
+	inv := read(invoiceID)
+	if want := inv.total.Minor() * 325 / 10; inv.base != money.TRY || inv.rate != 32.5 || inv.baseTotal.Minor() != want {
+		t.Errorf("issued invoice = %+v, want base total %d at 32.5", inv, want)
+	}
+
+	// İade faturası sonradan girilen kura değil asıl faturanın kuruna bağlıdır
+	if _, err := db.Exec("INSERT INTO exchange_rates (user_id, currency, rate_date, rate) VALUES (?, 'USD', '2026-05-05', 35)",
+		userID); err != nil {
+		t.Fatal(err)
+	}
+	if err := issue(creditID); err != nil {
+		t.Fatal(err)
+	}
+	if credit := read(creditID); credit.rate != 32.5 || credit.baseTotal != inv.baseTotal {
+		t.Errorf("credit note = %+v, want the original invoice's rate and base total", credit)
+	}
+	var outstanding money.Money
+	if err := db.QueryRow("SELECT "+database.InvoiceOutstandingExpr+" FROM invoices WHERE id = ?", invoiceID).
+		Scan(&outstanding); err != nil {
+		t.Fatal(err)
+	}
+	if !outstanding.IsZero() {
+		t.Errorf("outstanding after full credit note = %v, want 0", outstanding)
+	}
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/mailer"
+	"github.com/umutaraz/tradesman-app/internal/middleware"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/outbox"
+)
+
+// invoiceSendRequest faturayı e-postayla gönderme isteği; adres boşsa müşterinin adresi kullanılır
+type invoiceSendRequest struct {
+	Email   string `json:"email" binding:"omitempty,email"`
+	Message string `json:"message"`
+}
+
+// Faturayı PDF ekiyle müşteriye e-postala. İleti kuyruğa alınır ve arka planda gönderilir;
+// gönderim müşterinin etkinlik geçmişine kaydedilir.
+func (h *Handler) SendInvoice(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req invoiceSendRequest
+	// Gövde isteğe bağlıdır
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	businessID := middleware.BusinessID(c)
+	invoice, err := h.getInvoice(businessID, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if invoice.Status == models.InvoiceDraft {
+		respondError(c, newConflictError("Taslak faturalar gönderilemez; önce faturayı kesin"))
+		return
+	}
+
+	recipient := strings.TrimSpace(req.Email)
+	if recipient == "" {
+		recipient = invoice.Customer.Email
+	}
+	if recipient == "" {
+		respondError(c, newValidationError("Müşterinin e-posta adresi kayıtlı değil"))
+		return
+	}
+
+	owner, err := h.getUserByID(businessID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	s, err := h.settings.Get(businessID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	data, err := h.invoicePDF(businessID, invoice)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	msg := mailer.Message{
+		ReplyTo: owner.Email,
+		To:      []string{recipient},
+		Subject: fmt.Sprintf("%s - %s numaralı fatura", businessName(owner), invoice.InvoiceNumber),
+		Body:    invoiceMailBody(owner, invoice, req.Message, s.DateLayout()),
+		Attachments: []mailer.Attachment{{
+			Filename:    invoiceFileName(invoice),
+			ContentType: "application/pdf",
+			Data:        data,
+		}},
+	}
+
+	var outboxID int
+	err = h.withTx(func(tx *sql.Tx) error {
+		var err error
+		outboxID, err = outbox.EnqueueEmail(tx, businessID, msg)
+		if err != nil {
+			return newValidationError(err.Error())
+		}
+
+		return database.InsertCustomerActivity(tx, models.CustomerActivity{
+			UserID:      businessID,
+			CustomerID:  invoice.CustomerID,
+			Type:        models.ActivityInvoiceEmailed,
+			Description: fmt.Sprintf("%s numaralı fatura %s adresine gönderildi", invoice.InvoiceNumber, recipient),
+			InvoiceID:   &invoice.ID,
+			OutboxID:    &outboxID,
+			CreatedBy:   middleware.UserID(c),
+		})
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":   "Fatura gönderim kuyruğuna alındı",
+		"outbox_id": outboxID,
+		"email":     recipient,
+	})
+}
+
+// invoiceMailBody fatura e-postasının metnini tarihleri işletmenin biçimiyle (layout) yazarak oluşturur
+func invoiceMailBody(owner *models.User, invoice *models.Invoice, note, layout string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Sayın %s,\n\n", invoice.Customer.Name)
+	if invoice.IsCreditNote() {
+		fmt.Fprintf(&b, "%s numaralı iade faturanız ekte yer almaktadır.\n\n", invoice.InvoiceNumber)
+	} else {
+		fmt.Fprintf(&b, "%s numaralı faturanız ekte yer almaktadır.\n\n", invoice.InvoiceNumber)
+	}
+
+	fmt.Fprintf(&b, "Fatura tarihi: %s\n", invoice.InvoiceDate.In(time.Local).Format(layout))
+	fmt.Fprintf(&b, "Tutar: %s\n", formatAmount(invoice.TotalAmount))
+	if invoice.DueDate != nil && invoice.Status != models.InvoicePaid {
+		fmt.Fprintf(&b, "Son ödeme tarihi: %s\n", invoice.DueDate.In(time.Local).Format(layout))
+	}
+
+	if note = strings.TrimSpace(note); note != "" {
+		fmt.Fprintf(&b, "\n%s\n", note)
+	}
+
+	fmt.Fprintf(&b, "\nSaygılarımızla,\n%s\n", businessName(owner))
+	if owner.Phone != "" {
+		fmt.Fprintf(&b, "Tel: %s\n", owner.Phone)
+	}
+	return b.String()
+}
@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/exchange"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/money"
+	"github.com/umutaraz/tradesman-app/internal/settings"
+)
+
+// Fatura satırları, toplamları ve numaralandırması için yardımcılar.
+// Hepsi çağıranın açtığı veritabanı işlemi (tx) içinde çalışır.
+
+// Fatura serileri; numara biçimi SERİ + YIL + 9 haneli sıra (FTR2024000000001)
+const (
+	salesInvoiceSeries  = "FTR"
+	creditInvoiceSeries = "IAD"
+)
+
+// invoiceRounding kısmi miktarlı satır tutarlarının ve KDV'nin kuruşa yuvarlanma kuralı;
+// faturalarda ticari yuvarlama (yarım yukarı) uygulanır
+const invoiceRounding = money.HalfUp
+
+// invoiceLineRequest fatura satırı isteği
+type invoiceLineRequest struct {
+	ProductID   *int        `json:"product_id"`
+	Description string      `json:"description"`
+	Quantity    float64     `json:"quantity" binding:"gt=0"`
+	Unit        string      `json:"unit"`
+	UnitPrice   money.Money `json:"unit_price" binding:"gte=0"`
+	TaxRate     int         `json:"tax_rate"`
+}
+
+// buildInvoiceLine satırı doğrular, ürün bilgilerini tamamlar ve KDV'yi hesaplar; oran verilmemişse
+// işletmenin varsayılan KDV oranı kullanılır
+func buildInvoiceLine(tx *sql.Tx, userID int, req invoiceLineRequest, defaultTaxRate int) (models.InvoiceLine, error) {
+	line := models.InvoiceLine{
+		ProductID:   req.ProductID,
+		Description: req.Description,
+		Quantity:    req.Quantity,
+		Unit:        req.Unit,
+		UnitPrice:   req.UnitPrice,
+		TaxRate:     req.TaxRate,
+	}
+
+	if line.TaxRate == 0 {
+		line.TaxRate = defaultTaxRate
+	}
+	if !models.IsValidKDVRate(line.TaxRate) {
+		return line, newValidationError(fmt.Sprintf("Geçersiz KDV oranı: %%%d (geçerli oranlar: %%1, %%10, %%20)", line.TaxRate))
+	}
+	if line.Quantity <= 0 {
+		return line, newValidationError("Miktar sıfırdan büyük olmalıdır")
+	}
+	if line.UnitPrice.IsNegative() {
+		return line, newValidationError("Birim fiyat negatif olamaz")
+	}
+
+	if line.ProductID != nil && *line.ProductID > 0 {
+		var name, unit string
+		err := tx.QueryRow("SELECT name, unit FROM products WHERE id = ? AND user_id = ?", *line.ProductID, userID).
+			Scan(&name, &unit)
+		if err == sql.ErrNoRows {
+			return line, newValidationError(fmt.Sprintf("Ürün bulunamadı: %d", *line.ProductID))
+		}
+		if err != nil {
+			return line, err
+		}
+		if line.Description == "" {
+			line.Description = name
+		}
+		if line.Unit == "" {
+			line.Unit = unit
+		}
+	} else {
+		line.ProductID = nil
+	}
+
+	if line.Description == "" {
+		return line, newValidationError("Ürün seçilmeyen satırlar için açıklama zorunludur")
+	}
+	if line.Unit == "" {
+		line.Unit = "adet"
+	}
+
+	line.Subtotal = line.UnitPrice.Mul(line.Quantity, invoiceRounding)
+	line.TaxAmount = line.Subtotal.Percent(line.TaxRate, invoiceRounding)
+	line.Total = line.Subtotal.Add(line.TaxAmount)
+
+	return line, nil
+}
+
+// replaceInvoiceLines taslak faturanın satırlarını yenileriyle değiştirir ve toplamları günceller
+func replaceInvoiceLines(tx *sql.Tx, userID, invoiceID int, requests []invoiceLineRequest, defaultTaxRate int) error {
+	if len(requests) == 0 {
+		return newValidationError("Fatura en az bir satır içermelidir")
+	}
+
+	if _, err := tx.Exec("DELETE FROM invoice_lines WHERE invoice_id = ?", invoiceID); err != nil {
+		return err
+	}
+
+	for _, req := range requests {
+		line, err := buildInvoiceLine(tx, userID, req, defaultTaxRate)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO invoice_lines (invoice_id, product_id, description, quantity, unit, unit_price, tax_rate,
+			                           subtotal, tax_amount, total)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, invoiceID, line.ProductID, line.Description, line.Quantity, line.Unit, line.UnitPrice, line.TaxRate,
+			line.Subtotal, line.TaxAmount, line.Total)
+		if err != nil {
+			return err
+		}
+	}
+
+	return recalculateInvoiceTotals(tx, invoiceID)
+}
+
+// recalculateInvoiceTotals fatura toplamlarını satırlardan yeniden hesaplar
+func recalculateInvoiceTotals(tx *sql.Tx, invoiceID int) error {
+	_, err := tx.Exec(`
+		UPDATE invoices SET
+			subtotal = (SELECT COALESCE(SUM(subtotal), 0) FROM invoice_lines WHERE invoice_id = ?),
+			tax_amount = (SELECT COALESCE(SUM(tax_amount), 0) FROM invoice_lines WHERE invoice_id = ?),
+			total_amount = (SELECT COALESCE(SUM(total), 0) FROM invoice_lines WHERE invoice_id = ?),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`, invoiceID, invoiceID, invoiceID, invoiceID)
+	return err
+}
+
+// issueInvoice taslak faturaya sıradaki numarayı verip keser ve müşterinin cari hesabına yazar;
+// bundan sonra fatura değiştirilemez. Döviz faturalarının tutarı fatura tarihindeki kurla ana para
+// birimine çevrilip saklanır; cari hesap ve alacaklar bu tutarla izlenir. Ana para birimi ve hata
+// mesajlarındaki tarih biçimi işletmenin ayarlarından (prefs) alınır.
+func issueInvoice(tx *sql.Tx, userID, invoiceID int, prefs settings.Settings) error {
+	base := prefs.Currency
+	var status, invoiceType string
+	var currency money.Currency
+	var invoiceDate time.Time
+	var total money.Money
+	var creditForID sql.NullInt64
+	var lineCount int
+	err := tx.QueryRow(`
+		SELECT status, invoice_type, currency, invoice_date, total_amount, credit_for_id,
+		       (SELECT COUNT(*) FROM invoice_lines WHERE invoice_id = invoices.id)
+		FROM invoices WHERE id = ? AND user_id = ?
+	`, invoiceID, userID).Scan(&status, &invoiceType, &currency, &invoiceDate, &total, &creditForID, &lineCount)
+	if err != nil {
+		return err
+	}
+
+	if status != models.InvoiceDraft {
+		return newConflictError("Yalnızca taslak faturalar kesilebilir")
+	}
+	if lineCount == 0 {
+		return newValidationError("Satırı olmayan fatura kesilemez")
+	}
+
+	series := salesInvoiceSeries
+	if invoiceType == models.InvoiceTypeCreditNote {
+		series = creditInvoiceSeries
+	}
+	year := invoiceDate.Year()
+	prefix := fmt.Sprintf("%s%d", series, year)
+
+	// Faturalar tarih sırasıyla numaralandırılmalıdır
+	var lastDate time.Time
+	err = tx.QueryRow(`
+		SELECT invoice_date FROM invoices
+		WHERE user_id = ? AND invoice_number LIKE ?
+		ORDER BY invoice_number DESC LIMIT 1
+	`, userID, prefix+"%").Scan(&lastDate)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if err == nil && dateOnly(invoiceDate).Before(dateOnly(lastDate)) {
+		return newValidationError(fmt.Sprintf("Fatura tarihi son kesilen faturanın tarihinden (%s) önce olamaz",
+			lastDate.In(time.Local).Format(prefs.DateLayout())))
+	}
+
+	rate, err := invoiceRate(tx, userID, currency, &base, invoiceDate, creditForID)
+	if err != nil {
+		return err
+	}
+	baseTotal := total.WithCurrency(currency).Convert(base, rate, exchange.Rounding)
+
+	number, err := nextInvoiceNumber(tx, userID, series, year)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE invoices SET invoice_number = ?, status = ?, issued_at = ?, base_currency = ?, exchange_rate = ?,
+		       base_total_amount = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`, number, models.InvoiceIssued, time.Now(), base, exchange.RateFloat(rate), baseTotal, invoiceID)
+	if err != nil {
+		return err
+	}
+
+	return postInvoiceEntry(tx, invoiceID)
+}
+
+// invoiceRate faturanın kesilirken kullanılacak kurunu döndürür. İade faturası asıl faturanın kuru ve
+// ana para birimiyle kesilir ki iki fatura cari hesapta birbirini tam kapatsın; bu durumda base asıl
+// faturanınkiyle değiştirilir. Kur saklandığı hassasiyete yuvarlanır, böylece faturada görünen kurla
+// hesaplanan tutar birebir tutar.
+func invoiceRate(tx *sql.Tx, userID int, currency money.Currency, base *money.Currency, invoiceDate time.Time,
+	creditForID sql.NullInt64) (*big.Rat, error) {
+	if creditForID.Valid {
+		var rate float64
+		err := tx.QueryRow("SELECT base_currency, exchange_rate FROM invoices WHERE id = ? AND user_id = ?",
+			creditForID.Int64, userID).Scan(base, &rate)
+		if err != nil {
+			return nil, err
+		}
+		return exchange.RatFromFloat(rate), nil
+	}
+
+	rate, err := exchange.CrossRate(tx, userID, currency, *base, invoiceDate)
+	if errors.Is(err, exchange.ErrNoRate) {
+		return nil, newValidationError(fmt.Sprintf("Fatura tarihi için %s kuru girilmemiş; önce kur ekleyin", currency))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return exchange.RatFromFloat(exchange.RateFloat(rate)), nil
+}
+
+// nextInvoiceNumber seri ve yıl için sayacı artırıp sıradaki numarayı döndürür.
+// Sayaç fatura ile aynı işlemde güncellendiğinden geri alınan işlemler numara boşluğu bırakmaz.
+func nextInvoiceNumber(tx *sql.Tx, userID int, series string, year int) (string, error) {
+	_, err := tx.Exec(`
+		INSERT INTO invoice_sequences (user_id, series, year, last_number) VALUES (?, ?, ?, 0)
+		ON CONFLICT (user_id, series, year) DO NOTHING
+	`, userID, series, year)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE invoice_sequences SET last_number = last_number + 1
+		WHERE user_id = ? AND series = ? AND year = ?
+	`, userID, series, year)
+	if err != nil {
+		return "", err
+	}
+
+	var next int
+	err = tx.QueryRow("SELECT last_number FROM invoice_sequences WHERE user_id = ? AND series = ? AND year = ?",
+		userID, series, year).Scan(&next)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s%d%09d", series, year, next), nil
+}
+
+// dateOnly zamanın yerel saate göre gün başlangıcını döndürür
+func dateOnly(t time.Time) time.Time {
+	t = t.In(time.Local)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
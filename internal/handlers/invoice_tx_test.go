@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/database/dbtest"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/money"
+	"github.com/umutaraz/tradesman-app/internal/settings"
+)
+
+// issueTestInvoice işletme için tek satırlı bir fatura oluşturup keser ve numarasını döndürür
+func issueTestInvoice(t *testing.T, db *database.DB, userID, customerID int, date time.Time) string {
+	t.Helper()
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	id, err := insertInvoice(tx, &models.Invoice{UserID: userID, CustomerID: customerID, Type: models.InvoiceTypeSales,
+		Currency: money.TRY, BaseCurrency: money.TRY, InvoiceDate: date})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := []invoiceLineRequest{{Description: "Montaj", Quantity: 1, Unit: "adet", UnitPrice: money.TL(10000), TaxRate: 20}}
+	if err := replaceInvoiceLines(tx, userID, id, lines, 20); err != nil {
+		t.Fatal(err)
+	}
+	if err := issueInvoice(tx, userID, id, settings.Defaults()); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	var number string
+	if err := db.QueryRow("SELECT invoice_number FROM invoices WHERE id = ?", id).Scan(&number); err != nil {
+		t.Fatal(err)
+	}
+	return number
+}
+
+func testCustomer(t *testing.T, db *database.DB, userID int, name string) int {
+	t.Helper()
+
+	result, err := db.Exec("INSERT INTO customers (user_id, name) VALUES (?, ?)", userID, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return int(id)
+}
+
+func TestInvoiceNumbersArePerBusiness(t *testing.T) {
+	db := dbtest.New(t)
+	date := time.Date(2024, 5, 2, 0, 0, 0, 0, time.Local)
+
+	first := dbtest.User(t, db, "birinci@example.com")
+	second := dbtest.User(t, db, "ikinci@example.com")
+	firstCustomer := testCustomer(t, db, first, "Ayşe Demir")
+	secondCustomer := testCustomer(t, db, second, "Mehmet Kaya")
+
+	numbers := []string{
+		issueTestInvoice(t, db, first, firstCustomer, date),
+		issueTestInvoice(t, db, second, secondCustomer, date),
+		issueTestInvoice(t, db, first, firstCustomer, date),
+		issueTestInvoice(t, db, second, secondCustomer, date),
+	}
+
+	want := []string{"FTR2024000000001", "FTR2024000000001", "FTR2024000000002", "FTR2024000000002"}
+	for i := range want {
+		if numbers[i] != want[i] {
+			t.Errorf("fatura %d numarası = %s, want %s", i+1, numbers[i], want[i])
+		}
+	}
+
+	// Numara işletme içinde tekil kalmalıdır
+	_, err := db.Exec(`INSERT INTO invoices (user_id, customer_id, invoice_number, invoice_date) VALUES (?, ?, ?, ?)`,
+		first, firstCustomer, "FTR2024000000001", date)
+	if err == nil {
+		t.Error("aynı işletmede tekrarlanan fatura numarası kabul edildi")
+	}
+}
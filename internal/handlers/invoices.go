@@ -0,0 +1,887 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umutaraz/tradesman-app/internal/middleware"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/money"
+	"github.com/umutaraz/tradesman-app/internal/permissions"
+)
+
+// invoiceRequest taslak fatura oluşturma/güncelleme isteği; tarihler YYYY-AA-GG biçimindedir
+type invoiceRequest struct {
+	CustomerID  int                  `json:"customer_id" binding:"required"`
+	Currency    money.Currency       `json:"currency"` // Boşsa işletmenin ana para birimi
+	InvoiceDate string               `json:"invoice_date"`
+	DueDate     string               `json:"due_date"`
+	Notes       string               `json:"notes"`
+	Lines       []invoiceLineRequest `json:"lines" binding:"required,min=1,dive"`
+}
+
+// dates fatura ve vade tarihlerini çözümler; fatura tarihi boşsa bugün kullanılır
+func (r invoiceRequest) dates() (time.Time, *time.Time, error) {
+	invoiceDate := dateOnly(time.Now())
+	if r.InvoiceDate != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", r.InvoiceDate, time.Local)
+		if err != nil {
+			return time.Time{}, nil, newValidationError("Geçersiz fatura tarihi, YYYY-AA-GG biçiminde olmalıdır")
+		}
+		invoiceDate = parsed
+	}
+
+	if r.DueDate == "" {
+		return invoiceDate, nil, nil
+	}
+	dueDate, err := time.ParseInLocation("2006-01-02", r.DueDate, time.Local)
+	if err != nil {
+		return time.Time{}, nil, newValidationError("Geçersiz son ödeme tarihi, YYYY-AA-GG biçiminde olmalıdır")
+	}
+	if dueDate.Before(invoiceDate) {
+		return time.Time{}, nil, newValidationError("Son ödeme tarihi fatura tarihinden önce olamaz")
+	}
+
+	return invoiceDate, &dueDate, nil
+}
+
+// Fatura listesi (API); status ve customer_id ile filtrelenebilir
+func (h *Handler) GetInvoicesAPI(c *gin.Context) {
+	businessID := middleware.BusinessID(c)
+	if err := h.markOverdueInvoices(businessID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var conditions []string
+	var args []interface{}
+	if status := c.Query("status"); status != "" {
+		conditions = append(conditions, "i.status = ?")
+		args = append(args, status)
+	}
+	if customerID := c.Query("customer_id"); customerID != "" {
+		id, err := strconv.Atoi(customerID)
+		if err != nil {
+			respondError(c, newValidationError("Geçersiz customer_id"))
+			return
+		}
+		conditions = append(conditions, "i.customer_id = ?")
+		args = append(args, id)
+	}
+
+	invoices, err := h.queryInvoices(businessID, strings.Join(conditions, " AND "), args...)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, invoices)
+}
+
+// Fatura detayı (API)
+func (h *Handler) GetInvoiceAPI(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	businessID := middleware.BusinessID(c)
+	if err := h.markOverdueInvoices(businessID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	invoice, err := h.getInvoice(businessID, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, invoice)
+}
+
+// Taslak fatura oluştur
+func (h *Handler) CreateInvoice(c *gin.Context) {
+	var req invoiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	invoiceDate, dueDate, err := req.dates()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	businessID := middleware.BusinessID(c)
+	if _, err := h.customers.Get(businessID, req.CustomerID); err != nil {
+		respondError(c, newValidationError("Müşteri bulunamadı"))
+		return
+	}
+	base, err := h.baseCurrency(businessID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	currency, err := h.resolveCurrency(businessID, req.Currency)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	taxRate, err := h.defaultTaxRate(businessID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var invoiceID int
+	err = h.withTx(func(tx *sql.Tx) error {
+		id, err := insertInvoice(tx, &models.Invoice{
+			UserID:       businessID,
+			CustomerID:   req.CustomerID,
+			Currency:     currency,
+			BaseCurrency: base,
+			Type:         models.InvoiceTypeSales,
+			InvoiceDate:  invoiceDate,
+			DueDate:      dueDate,
+			Notes:        req.Notes,
+		})
+		if err != nil {
+			return err
+		}
+		invoiceID = id
+		return replaceInvoiceLines(tx, businessID, id, req.Lines, taxRate)
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	h.respondInvoice(c, http.StatusCreated, businessID, invoiceID)
+}
+
+// Taslak faturayı güncelle; kesilmiş faturalar değiştirilemez
+func (h *Handler) UpdateInvoice(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req invoiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	invoiceDate, dueDate, err := req.dates()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	businessID := middleware.BusinessID(c)
+	draft, ok := h.loadDraftInvoice(c, businessID, id)
+	if !ok {
+		return
+	}
+	if _, err := h.customers.Get(businessID, req.CustomerID); err != nil {
+		respondError(c, newValidationError("Müşteri bulunamadı"))
+		return
+	}
+
+	// Para birimi verilmezse değişmez
+	currency := draft.Currency
+	if req.Currency != "" {
+		if currency, err = h.resolveCurrency(businessID, req.Currency); err != nil {
+			respondError(c, err)
+			return
+		}
+	}
+	taxRate, err := h.defaultTaxRate(businessID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	err = h.withTx(func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+			UPDATE invoices SET customer_id = ?, currency = ?, invoice_date = ?, due_date = ?, notes = ?,
+			       updated_at = CURRENT_TIMESTAMP
+			WHERE id = ? AND user_id = ?
+		`, req.CustomerID, currency, invoiceDate, dueDate, req.Notes, id, businessID)
+		if err != nil {
+			return err
+		}
+		return replaceInvoiceLines(tx, businessID, id, req.Lines, taxRate)
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	h.respondInvoice(c, http.StatusOK, businessID, id)
+}
+
+// Taslak faturayı sil
+func (h *Handler) DeleteInvoice(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	businessID := middleware.BusinessID(c)
+	if _, ok := h.loadDraftInvoice(c, businessID, id); !ok {
+		return
+	}
+
+	err := h.withTx(func(tx *sql.Tx) error {
+		if _, err := tx.Exec("DELETE FROM invoice_lines WHERE invoice_id = ?", id); err != nil {
+			return err
+		}
+		_, err := tx.Exec("DELETE FROM invoices WHERE id = ? AND user_id = ?", id, businessID)
+		return err
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// Faturayı kes; sıradaki numara atanır ve fatura kilitlenir
+func (h *Handler) IssueInvoice(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	businessID := middleware.BusinessID(c)
+	s, err := h.settings.Get(businessID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if err := h.withTx(func(tx *sql.Tx) error { return issueInvoice(tx, businessID, id, s) }); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	h.respondInvoice(c, http.StatusOK, businessID, id)
+}
+
+// Faturayı ödendi olarak işaretle; kalan tutar için müşterinin cari hesabına tahsilat yazılır
+// ve faturaya dağıtılır. İade faturalarında müşteriye yapılan geri ödeme borç olarak yazılır.
+func (h *Handler) PayInvoice(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		PaidAt        *time.Time `json:"paid_at"`
+		PaymentMethod string     `json:"payment_method"`
+	}
+	// Gövde isteğe bağlıdır
+	_ = c.ShouldBindJSON(&req)
+
+	paidAt := time.Now()
+	if req.PaidAt != nil {
+		paidAt = *req.PaidAt
+	}
+	if req.PaymentMethod == "" {
+		req.PaymentMethod = models.PaymentCash
+	}
+	if !models.IsValidPaymentMethod(req.PaymentMethod) {
+		respondError(c, newValidationError("Geçersiz ödeme yöntemi: "+req.PaymentMethod))
+		return
+	}
+
+	businessID := middleware.BusinessID(c)
+	invoice, err := h.getInvoice(businessID, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if invoice.Status != models.InvoiceIssued && invoice.Status != models.InvoiceOverdue {
+		respondError(c, newConflictError("Yalnızca kesilmiş ve ödenmemiş faturalar ödendi olarak işaretlenebilir"))
+		return
+	}
+
+	err = h.withTx(func(tx *sql.Tx) error {
+		if invoice.IsCreditNote() {
+			_, err := insertLedgerEntry(tx, &models.LedgerEntry{
+				UserID:        businessID,
+				CustomerID:    invoice.CustomerID,
+				Type:          models.LedgerDebit,
+				Source:        models.LedgerSourceRefund,
+				Amount:        invoice.BaseTotal,
+				Description:   "İade ödemesi " + invoice.InvoiceNumber,
+				PaymentMethod: req.PaymentMethod,
+				EntryDate:     paidAt,
+				InvoiceID:     &id,
+				CreatedBy:     middleware.UserID(c),
+			})
+			if err != nil {
+				return err
+			}
+			_, err = tx.Exec("UPDATE invoices SET status = ?, paid_at = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?",
+				models.InvoicePaid, paidAt, id)
+			return err
+		}
+
+		outstanding, err := invoiceOutstanding(tx, id)
+		if err != nil {
+			return err
+		}
+		if !outstanding.IsPositive() {
+			return refreshInvoicePayment(tx, id, paidAt)
+		}
+
+		entryID, err := insertLedgerEntry(tx, &models.LedgerEntry{
+			UserID:        businessID,
+			CustomerID:    invoice.CustomerID,
+			Type:          models.LedgerCredit,
+			Source:        models.LedgerSourcePayment,
+			Amount:        outstanding,
+			Description:   "Fatura ödemesi " + invoice.InvoiceNumber,
+			PaymentMethod: req.PaymentMethod,
+			EntryDate:     paidAt,
+			InvoiceID:     &id,
+			CreatedBy:     middleware.UserID(c),
+		})
+		if err != nil {
+			return err
+		}
+		return allocatePayment(tx, businessID, entryID, id, outstanding)
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	h.respondInvoice(c, http.StatusOK, businessID, id)
+}
+
+// Faturayı iptal et; numara korunur, ödenmiş faturalar için iade faturası kullanılmalıdır
+func (h *Handler) VoidInvoice(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	businessID := middleware.BusinessID(c)
+	invoice, err := h.getInvoice(businessID, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	switch invoice.Status {
+	case models.InvoiceIssued, models.InvoiceOverdue:
+	case models.InvoiceDraft:
+		respondError(c, newConflictError("Taslak faturalar iptal edilmez, silinebilir"))
+		return
+	case models.InvoicePaid:
+		respondError(c, newConflictError("Ödenmiş fatura iptal edilemez; iade faturası oluşturun"))
+		return
+	default:
+		respondError(c, newConflictError("Fatura zaten iptal edilmiş"))
+		return
+	}
+
+	// Faturaya dağıtılmış tahsilatlar serbest kalır ve cari hesaba ters kayıt yazılır
+	voidedAt := time.Now()
+	err = h.withTx(func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+			UPDATE invoices SET status = ?, voided_at = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND user_id = ?
+		`, models.InvoiceVoid, voidedAt, id, businessID)
+		if err != nil {
+			return err
+		}
+		return postInvoiceVoid(tx, id, voidedAt)
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	h.respondInvoice(c, http.StatusOK, businessID, id)
+}
+
+// Kesilmiş fatura için taslak iade faturası oluştur; satır verilmezse faturanın tamamı iade edilir
+func (h *Handler) CreateCreditNote(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Notes string               `json:"notes"`
+		Lines []invoiceLineRequest `json:"lines" binding:"dive"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	businessID := middleware.BusinessID(c)
+	original, err := h.getInvoice(businessID, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if original.IsCreditNote() {
+		respondError(c, newConflictError("İade faturası için iade faturası oluşturulamaz"))
+		return
+	}
+	switch original.Status {
+	case models.InvoiceIssued, models.InvoiceOverdue, models.InvoicePaid:
+	default:
+		respondError(c, newConflictError("Yalnızca kesilmiş faturalar için iade faturası oluşturulabilir"))
+		return
+	}
+
+	lines := req.Lines
+	if len(lines) == 0 {
+		for _, line := range original.Lines {
+			lines = append(lines, invoiceLineRequest{
+				ProductID:   line.ProductID,
+				Description: line.Description,
+				Quantity:    line.Quantity,
+				Unit:        line.Unit,
+				UnitPrice:   line.UnitPrice,
+				TaxRate:     line.TaxRate,
+			})
+		}
+	}
+
+	notes := req.Notes
+	if notes == "" {
+		notes = "İade faturası: " + original.InvoiceNumber
+	}
+	taxRate, err := h.defaultTaxRate(businessID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var creditID int
+	err = h.withTx(func(tx *sql.Tx) error {
+		creditFor := original.ID
+		id, err := insertInvoice(tx, &models.Invoice{
+			UserID:       businessID,
+			CustomerID:   original.CustomerID,
+			OrderID:      original.OrderID,
+			Currency:     original.Currency,
+			BaseCurrency: original.BaseCurrency,
+			Type:         models.InvoiceTypeCreditNote,
+			CreditForID:  &creditFor,
+			InvoiceDate:  dateOnly(time.Now()),
+			Notes:        notes,
+		})
+		if err != nil {
+			return err
+		}
+		creditID = id
+
+		if err := replaceInvoiceLines(tx, businessID, id, lines, taxRate); err != nil {
+			return err
+		}
+
+		// İade edilen toplam, faturanın tutarını aşamaz
+		var credited money.Money
+		err = tx.QueryRow(`
+			SELECT COALESCE(SUM(total_amount), 0) FROM invoices
+			WHERE credit_for_id = ? AND status != ?
+		`, original.ID, models.InvoiceVoid).Scan(&credited)
+		if err != nil {
+			return err
+		}
+		credited = credited.WithCurrency(original.Currency)
+		if credited.Cmp(original.TotalAmount) > 0 {
+			return newValidationError(fmt.Sprintf("İade toplamı (%s) fatura tutarını (%s) aşamaz",
+				credited, original.TotalAmount))
+		}
+		return nil
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	h.respondInvoice(c, http.StatusCreated, businessID, creditID)
+}
+
+// Siparişten taslak fatura oluştur; sipariş kalemleri fatura satırlarına aktarılır
+func (h *Handler) CreateInvoiceFromOrder(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		TaxRate int    `json:"tax_rate"`
+		DueDate string `json:"due_date"`
+		Notes   string `json:"notes"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	businessID := middleware.BusinessID(c)
+	order, err := h.orders.Get(businessID, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if models.IsOrderReversed(order.Status) {
+		respondError(c, newConflictError("İptal ya da iade edilen sipariş faturalanamaz"))
+		return
+	}
+
+	var existing int
+	err = h.db.QueryRow(`
+		SELECT COUNT(*) FROM invoices WHERE order_id = ? AND invoice_type = ? AND status != ?
+	`, id, models.InvoiceTypeSales, models.InvoiceVoid).Scan(&existing)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if existing > 0 {
+		respondError(c, newConflictError("Bu sipariş için zaten fatura oluşturulmuş"))
+		return
+	}
+
+	invoiceReq := invoiceRequest{DueDate: req.DueDate}
+	invoiceDate, dueDate, err := invoiceReq.dates()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	base, err := h.baseCurrency(businessID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	taxRate, err := h.defaultTaxRate(businessID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var lines []invoiceLineRequest
+	for _, item := range order.Items {
+		productID := item.ProductID
+		lines = append(lines, invoiceLineRequest{
+			ProductID: &productID,
+			Quantity:  float64(item.Quantity),
+			UnitPrice: item.UnitPrice,
+			TaxRate:   req.TaxRate,
+		})
+	}
+
+	notes := req.Notes
+	if notes == "" {
+		notes = "Sipariş " + order.OrderNumber
+	}
+
+	var invoiceID int
+	err = h.withTx(func(tx *sql.Tx) error {
+		orderID := order.ID
+		id, err := insertInvoice(tx, &models.Invoice{
+			UserID:       businessID,
+			CustomerID:   order.CustomerID,
+			OrderID:      &orderID,
+			Currency:     order.Currency,
+			BaseCurrency: base,
+			Type:         models.InvoiceTypeSales,
+			InvoiceDate:  invoiceDate,
+			DueDate:      dueDate,
+			Notes:        notes,
+		})
+		if err != nil {
+			return err
+		}
+		invoiceID = id
+		return replaceInvoiceLines(tx, businessID, id, lines, taxRate)
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	h.respondInvoice(c, http.StatusCreated, businessID, invoiceID)
+}
+
+// respondInvoice güncel faturayı verilen durum koduyla döndürür
+func (h *Handler) respondInvoice(c *gin.Context, status, userID, id int) {
+	invoice, err := h.getInvoice(userID, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(status, invoice)
+}
+
+// loadDraftInvoice düzenlenebilecek (taslak) faturayı getirir
+func (h *Handler) loadDraftInvoice(c *gin.Context, userID, id int) (*models.Invoice, bool) {
+	invoice, err := h.getInvoice(userID, id)
+	if err != nil {
+		respondError(c, err)
+		return nil, false
+	}
+	if !invoice.IsEditable() {
+		respondError(c, newConflictError("Kesilmiş fatura değiştirilemez; düzeltme için iade faturası oluşturun"))
+		return nil, false
+	}
+	return invoice, true
+}
+
+// insertInvoice taslak fatura kaydı oluşturur; kur ve ana para birimi tutarı kesilirken yazılır
+func insertInvoice(tx *sql.Tx, invoice *models.Invoice) (int, error) {
+	result, err := tx.Exec(`
+		INSERT INTO invoices (user_id, customer_id, order_id, invoice_type, credit_for_id, status, currency,
+		                      base_currency, invoice_date, due_date, notes)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, invoice.UserID, invoice.CustomerID, invoice.OrderID, invoice.Type, invoice.CreditForID, models.InvoiceDraft,
+		invoice.Currency, invoice.BaseCurrency, invoice.InvoiceDate, invoice.DueDate, invoice.Notes)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := result.LastInsertId()
+	return int(id), err
+}
+
+// markOverdueInvoices vadesi geçen kesilmiş faturaları gecikmiş olarak işaretler ve
+// faturaları yönetebilen kullanıcılara bildirir
+func (h *Handler) markOverdueInvoices(userID int) error {
+	overdue, err := h.queryInvoices(userID, "i.status = ? AND i.due_date IS NOT NULL AND i.due_date < ?",
+		models.InvoiceIssued, dateOnly(time.Now()))
+	if err != nil || len(overdue) == 0 {
+		return err
+	}
+	s, err := h.settings.Get(userID)
+	if err != nil {
+		return err
+	}
+
+	for _, invoice := range overdue {
+		result, err := h.db.Exec(`
+			UPDATE invoices SET status = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND status = ?
+		`, models.InvoiceOverdue, invoice.ID, models.InvoiceIssued)
+		if err != nil {
+			return err
+		}
+		// Başka bir istek aynı anda işaretlediyse bildirim tekrarlanmaz
+		if n, _ := result.RowsAffected(); n == 0 {
+			continue
+		}
+
+		err = h.notifier.SendToBusiness(userID, permissions.ManageInvoices, models.Notification{
+			Type:     models.NotificationInvoice,
+			Severity: models.SeverityDanger,
+			Title:    "Vadesi geçen fatura: " + invoice.InvoiceNumber,
+			Message: fmt.Sprintf("%s için kesilen %s tutarındaki faturanın son ödeme tarihi %s idi.",
+				invoice.Customer.Name, formatAmount(invoice.TotalAmount), invoice.DueDate.Format(s.DateLayout())),
+			Link: fmt.Sprintf("/invoices/%d", invoice.ID),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WatchOverdueInvoices vadesi geçen faturaları sayfa açılmasını beklemeden düzenli aralıklarla işaretler
+func (h *Handler) WatchOverdueInvoices(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := h.markAllOverdueInvoices(); err != nil {
+			log.Printf("Vadesi geçen fatura denetimi hatası: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *Handler) markAllOverdueInvoices() error {
+	rows, err := h.db.Query(`
+		SELECT DISTINCT user_id FROM invoices WHERE status = ? AND due_date IS NOT NULL AND due_date < ?
+	`, models.InvoiceIssued, dateOnly(time.Now()))
+	if err != nil {
+		return err
+	}
+
+	var businessIDs []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		businessIDs = append(businessIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, id := range businessIDs {
+		if err := h.markOverdueInvoices(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const invoiceSelect = `
+	SELECT i.id, i.user_id, i.customer_id, i.order_id, COALESCE(i.invoice_number, ''), i.invoice_type,
+	       i.credit_for_id, i.status, i.invoice_date, i.due_date, i.subtotal, i.tax_amount, i.total_amount,
+	       i.currency, i.base_currency, i.exchange_rate, i.base_total_amount, COALESCE(i.notes, ''),
+	       i.issued_at, i.paid_at, i.voided_at, i.created_at, i.updated_at,
+	       c.id, c.name, COALESCE(c.email, ''), COALESCE(c.phone, ''), COALESCE(c.address, '')
+	FROM invoices i
+	JOIN customers c ON i.customer_id = c.id`
+
+// Fatura ve satırlarını getir
+func (h *Handler) getInvoice(userID, id int) (*models.Invoice, error) {
+	invoice, err := scanInvoice(h.db.QueryRow(invoiceSelect+" WHERE i.id = ? AND i.user_id = ?", id, userID))
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := h.getInvoiceLines(invoice.ID, invoice.Currency)
+	if err != nil {
+		return nil, err
+	}
+	invoice.Lines = lines
+
+	return invoice, nil
+}
+
+// queryInvoices işletmenin faturalarını yeniden eskiye getirir; where boş değilse ek koşul olarak eklenir
+func (h *Handler) queryInvoices(userID int, where string, args ...interface{}) ([]models.Invoice, error) {
+	query := invoiceSelect + " WHERE i.user_id = ?"
+	if where != "" {
+		query += " AND " + where
+	}
+	query += " ORDER BY i.invoice_date DESC, i.id DESC"
+
+	rows, err := h.db.Query(query, append([]interface{}{userID}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var invoices []models.Invoice
+	for rows.Next() {
+		invoice, err := scanInvoice(rows)
+		if err != nil {
+			return nil, err
+		}
+		invoices = append(invoices, *invoice)
+	}
+
+	return invoices, rows.Err()
+}
+
+// Fatura satırlarını getir; tutarlar faturanın para birimindedir
+func (h *Handler) getInvoiceLines(invoiceID int, currency money.Currency) ([]models.InvoiceLine, error) {
+	rows, err := h.db.Query(`
+		SELECT id, invoice_id, product_id, description, quantity, COALESCE(unit, ''), unit_price, tax_rate,
+		       subtotal, tax_amount, total
+		FROM invoice_lines WHERE invoice_id = ? ORDER BY id
+	`, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lines []models.InvoiceLine
+	for rows.Next() {
+		var line models.InvoiceLine
+		var productID sql.NullInt64
+		err := rows.Scan(&line.ID, &line.InvoiceID, &productID, &line.Description, &line.Quantity, &line.Unit,
+			&line.UnitPrice, &line.TaxRate, &line.Subtotal, &line.TaxAmount, &line.Total)
+		if err != nil {
+			return nil, err
+		}
+		line.ProductID = nullIntPtr(productID)
+		line.UnitPrice = line.UnitPrice.WithCurrency(currency)
+		line.Subtotal = line.Subtotal.WithCurrency(currency)
+		line.TaxAmount = line.TaxAmount.WithCurrency(currency)
+		line.Total = line.Total.WithCurrency(currency)
+		lines = append(lines, line)
+	}
+
+	return lines, rows.Err()
+}
+
+func scanInvoice(rs rowScanner) (*models.Invoice, error) {
+	invoice := models.Invoice{Customer: &models.Customer{}}
+	var orderID, creditForID sql.NullInt64
+	var dueDate, issuedAt, paidAt, voidedAt sql.NullTime
+	err := rs.Scan(&invoice.ID, &invoice.UserID, &invoice.CustomerID, &orderID, &invoice.InvoiceNumber,
+		&invoice.Type, &creditForID, &invoice.Status, &invoice.InvoiceDate, &dueDate, &invoice.Subtotal,
+		&invoice.TaxAmount, &invoice.TotalAmount, &invoice.Currency, &invoice.BaseCurrency, &invoice.ExchangeRate,
+		&invoice.BaseTotal, &invoice.Notes, &issuedAt, &paidAt, &voidedAt,
+		&invoice.CreatedAt, &invoice.UpdatedAt,
+		&invoice.Customer.ID, &invoice.Customer.Name, &invoice.Customer.Email, &invoice.Customer.Phone,
+		&invoice.Customer.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice.Subtotal = invoice.Subtotal.WithCurrency(invoice.Currency)
+	invoice.TaxAmount = invoice.TaxAmount.WithCurrency(invoice.Currency)
+	invoice.TotalAmount = invoice.TotalAmount.WithCurrency(invoice.Currency)
+	invoice.BaseTotal = invoice.BaseTotal.WithCurrency(invoice.BaseCurrency)
+	invoice.OrderID = nullIntPtr(orderID)
+	invoice.CreditForID = nullIntPtr(creditForID)
+	invoice.DueDate = nullTimePtr(dueDate)
+	invoice.IssuedAt = nullTimePtr(issuedAt)
+	invoice.PaidAt = nullTimePtr(paidAt)
+	invoice.VoidedAt = nullTimePtr(voidedAt)
+
+	return &invoice, nil
+}
+
+// nullTimePtr geçerli sql.NullTime değerini yerel saatte işaretçiye çevirir
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	local := t.Time.In(time.Local)
+	return &local
+}
+
+// nullIntPtr geçerli sql.NullInt64 değerini işaretçiye çevirir
+func nullIntPtr(i sql.NullInt64) *int {
+	if !i.Valid {
+		return nil
+	}
+	id := int(i.Int64)
+	return &id
+}
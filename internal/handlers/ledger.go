@@ -0,0 +1,502 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umutaraz/tradesman-app/internal/middleware"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/money"
+)
+
+// customerPaymentRequest müşteriden tahsilat ya da müşteriye ödeme isteği; tarih YYYY-AA-GG biçimindedir
+type customerPaymentRequest struct {
+	Type          string              `json:"type"` // payment (varsayılan) ya da refund
+	Amount        money.Money         `json:"amount" binding:"gt=0"`
+	Date          string              `json:"date"`
+	Description   string              `json:"description"`
+	PaymentMethod string              `json:"payment_method"`
+	AutoAllocate  bool                `json:"auto_allocate"` // Açık faturalara vadesi en yakın olandan başlayarak dağıt
+	Allocations   []allocationRequest `json:"allocations" binding:"dive"`
+}
+
+// allocationRequest tahsilatın bir faturaya dağıtılacak kısmı
+type allocationRequest struct {
+	InvoiceID int         `json:"invoice_id" binding:"required"`
+	Amount    money.Money `json:"amount" binding:"gt=0"`
+}
+
+// customerPaymentForm müşteri detay sayfasındaki ödeme formu
+type customerPaymentForm struct {
+	PaymentType   string      `form:"payment_type"` // income: tahsilat, expense: müşteriye ödeme
+	Amount        money.Money `form:"amount" binding:"gt=0"`
+	Date          string      `form:"date"`
+	Description   string      `form:"description"`
+	PaymentMethod string      `form:"payment_method"`
+	AutoAllocate  bool        `form:"auto_allocate"`
+}
+
+// Müşterinin güncel bakiyesi, dağıtılmamış tahsilatları ve açık faturaları (API)
+func (h *Handler) GetCustomerBalanceAPI(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	businessID := middleware.BusinessID(c)
+	customer, err := h.customers.Get(businessID, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var unallocated money.Money
+	err = h.db.QueryRow(`
+		SELECT COALESCE(SUM(amount - (SELECT COALESCE(SUM(amount), 0) FROM payment_allocations WHERE entry_id = ledger_entries.id)), 0)
+		FROM ledger_entries WHERE user_id = ? AND customer_id = ? AND source = ?
+	`, businessID, id, models.LedgerSourcePayment).Scan(&unallocated)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	invoices, err := openInvoices(h.db, businessID, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"customer_id":   customer.ID,
+		"balance":       customer.Balance,
+		"unallocated":   unallocated,
+		"open_invoices": invoices,
+	})
+}
+
+// Müşterinin hesap ekstresi (API); from ve to (YYYY-AA-GG) ile tarih aralığı verilebilir
+func (h *Handler) GetCustomerStatementAPI(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	from, to, err := statementRange(c.Query("from"), c.Query("to"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	businessID := middleware.BusinessID(c)
+	if _, err := h.customers.Get(businessID, id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	statement, err := h.customerStatement(businessID, id, from, to)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, statement)
+}
+
+// Cari hesaba tahsilat ya da müşteriye ödeme yaz (API)
+func (h *Handler) CreateCustomerPayment(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req customerPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entry, err := h.recordCustomerPayment(c, id, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+// Cari hesaba tahsilat ya da müşteriye ödeme yaz (form)
+func (h *Handler) AddCustomerPaymentForm(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+
+	var form customerPaymentForm
+	if err := c.ShouldBind(&form); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Lütfen tutarı doğru girin"})
+		return
+	}
+
+	req := customerPaymentRequest{
+		Type:          models.LedgerSourcePayment,
+		Amount:        form.Amount,
+		Date:          form.Date,
+		Description:   form.Description,
+		PaymentMethod: form.PaymentMethod,
+		AutoAllocate:  form.AutoAllocate,
+	}
+	if form.PaymentType == "expense" {
+		req.Type = models.LedgerSourceRefund
+		req.AutoAllocate = false
+	}
+
+	entry, err := h.recordCustomerPayment(c, id, req)
+	if err != nil {
+		status, message := errorResponse(err)
+		c.JSON(status, gin.H{"success": false, "message": message})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "id": entry.ID})
+}
+
+// Tahsilatı faturalara dağıt (API); allocations boşsa açık faturalara otomatik dağıtılır
+func (h *Handler) AllocateCustomerPayment(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	entryID, ok := paramID(c, "entryId")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Allocations []allocationRequest `json:"allocations" binding:"dive"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	businessID := middleware.BusinessID(c)
+	if _, err := h.getLedgerEntry(businessID, id, entryID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	err := h.withTx(func(tx *sql.Tx) error {
+		if len(req.Allocations) == 0 {
+			return autoAllocatePayment(tx, businessID, id, entryID)
+		}
+		for _, allocation := range req.Allocations {
+			if err := allocatePayment(tx, businessID, entryID, allocation.InvoiceID, allocation.Amount); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	entry, err := h.getLedgerEntry(businessID, id, entryID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// Elle girilen tahsilat ya da ödemeyi sil; dağıtıldığı faturalar yeniden açılır
+func (h *Handler) DeleteCustomerPayment(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	entryID, ok := paramID(c, "entryId")
+	if !ok {
+		return
+	}
+
+	businessID := middleware.BusinessID(c)
+	entry, err := h.getLedgerEntry(businessID, id, entryID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if !entry.IsManual() {
+		respondError(c, newConflictError("Fatura ve siparişlerden oluşan hareketler silinemez; kaynak belgeyi iptal edin"))
+		return
+	}
+
+	err = h.withTx(func(tx *sql.Tx) error {
+		if err := releaseAllocations(tx, entryID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec("DELETE FROM ledger_entries WHERE id = ?", entryID); err != nil {
+			return err
+		}
+
+		// İade faturasının geri ödemesi silinirse iade faturası yeniden ödeme bekler
+		if entry.Source == models.LedgerSourceRefund && entry.InvoiceID != nil {
+			_, err := tx.Exec(`
+				UPDATE invoices SET status = ?, paid_at = NULL, updated_at = CURRENT_TIMESTAMP
+				WHERE id = ? AND invoice_type = ? AND status = ?
+			`, models.InvoiceIssued, *entry.InvoiceID, models.InvoiceTypeCreditNote, models.InvoicePaid)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// recordCustomerPayment isteği doğrulayıp hareketi yazar ve tahsilatı faturalara dağıtır
+func (h *Handler) recordCustomerPayment(c *gin.Context, customerID int, req customerPaymentRequest) (*models.LedgerEntry, error) {
+	businessID := middleware.BusinessID(c)
+	if _, err := h.customers.Get(businessID, customerID); err != nil {
+		return nil, err
+	}
+
+	if !req.Amount.IsPositive() {
+		return nil, newValidationError("Tutar sıfırdan büyük olmalıdır")
+	}
+	if req.PaymentMethod == "" {
+		req.PaymentMethod = models.PaymentCash
+	}
+	if !models.IsValidPaymentMethod(req.PaymentMethod) {
+		return nil, newValidationError("Geçersiz ödeme yöntemi: " + req.PaymentMethod)
+	}
+
+	entry := models.LedgerEntry{
+		UserID:        businessID,
+		CustomerID:    customerID,
+		Type:          models.LedgerCredit,
+		Source:        models.LedgerSourcePayment,
+		Amount:        req.Amount,
+		Description:   req.Description,
+		PaymentMethod: req.PaymentMethod,
+		EntryDate:     time.Now(),
+		CreatedBy:     middleware.UserID(c),
+	}
+	switch req.Type {
+	case "", models.LedgerSourcePayment:
+		if entry.Description == "" {
+			entry.Description = "Tahsilat (" + models.PaymentMethodLabel(req.PaymentMethod) + ")"
+		}
+	case models.LedgerSourceRefund:
+		if req.AutoAllocate || len(req.Allocations) > 0 {
+			return nil, newValidationError("Müşteriye yapılan ödemeler faturalara dağıtılamaz")
+		}
+		entry.Type = models.LedgerDebit
+		entry.Source = models.LedgerSourceRefund
+		if entry.Description == "" {
+			entry.Description = "Müşteriye ödeme (" + models.PaymentMethodLabel(req.PaymentMethod) + ")"
+		}
+	default:
+		return nil, newValidationError("Geçersiz işlem tipi: " + req.Type)
+	}
+
+	if req.Date != "" {
+		date, err := time.ParseInLocation("2006-01-02", req.Date, time.Local)
+		if err != nil {
+			return nil, newValidationError("Geçersiz tarih, YYYY-AA-GG biçiminde olmalıdır")
+		}
+		// Bugün girilen hareketler saatiyle birlikte kaydedilir
+		if !dateOnly(time.Now()).Equal(date) {
+			entry.EntryDate = date
+		}
+	}
+	if entry.EntryDate.After(time.Now()) {
+		return nil, newValidationError("İleri tarihli ödeme girilemez")
+	}
+
+	err := h.withTx(func(tx *sql.Tx) error {
+		entryID, err := insertLedgerEntry(tx, &entry)
+		if err != nil {
+			return err
+		}
+
+		for _, allocation := range req.Allocations {
+			if err := allocatePayment(tx, businessID, entryID, allocation.InvoiceID, allocation.Amount); err != nil {
+				return err
+			}
+		}
+		if req.AutoAllocate {
+			return autoAllocatePayment(tx, businessID, customerID, entryID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return h.getLedgerEntry(businessID, customerID, entry.ID)
+}
+
+// customerStatement müşterinin tarih aralığındaki hareketlerini yürüyen bakiyeyle döndürür;
+// aralık sınırları boşsa başlangıç ya da bitiş sınırı uygulanmaz
+func (h *Handler) customerStatement(userID, customerID int, from, to *time.Time) (*models.CustomerStatement, error) {
+	statement := &models.CustomerStatement{CustomerID: customerID, From: from, To: to, Entries: []models.LedgerEntry{}}
+	base, err := h.baseCurrency(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	where := "user_id = ? AND customer_id = ?"
+	args := []interface{}{userID, customerID}
+
+	if from != nil {
+		openingArgs := append([]interface{}{models.LedgerDebit}, args...)
+		openingArgs = append(openingArgs, *from)
+		err := h.db.QueryRow(`
+			SELECT COALESCE(SUM(CASE WHEN entry_type = ? THEN amount ELSE -amount END), 0)
+			FROM ledger_entries WHERE `+where+` AND entry_date < ?
+		`, openingArgs...).Scan(&statement.OpeningBalance)
+		if err != nil {
+			return nil, err
+		}
+		statement.OpeningBalance = statement.OpeningBalance.WithCurrency(base)
+		where += " AND entry_date >= ?"
+		args = append(args, *from)
+	}
+	if to != nil {
+		where += " AND entry_date < ?"
+		args = append(args, to.AddDate(0, 0, 1))
+	}
+
+	entries, err := h.queryLedgerEntries(base, where, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	balance := statement.OpeningBalance
+	for i := range entries {
+		if entries[i].IsDebit() {
+			balance = balance.Add(entries[i].Amount)
+			statement.TotalDebit = statement.TotalDebit.Add(entries[i].Amount)
+		} else {
+			balance = balance.Sub(entries[i].Amount)
+			statement.TotalCredit = statement.TotalCredit.Add(entries[i].Amount)
+		}
+		entries[i].Balance = balance
+	}
+
+	statement.Entries = entries
+	statement.ClosingBalance = balance
+	return statement, nil
+}
+
+// statementRange ekstre tarih aralığını çözümler; to günü dahildir
+func statementRange(fromValue, toValue string) (*time.Time, *time.Time, error) {
+	var from, to *time.Time
+	if fromValue != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", fromValue, time.Local)
+		if err != nil {
+			return nil, nil, newValidationError("Geçersiz başlangıç tarihi, YYYY-AA-GG biçiminde olmalıdır")
+		}
+		from = &parsed
+	}
+	if toValue != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", toValue, time.Local)
+		if err != nil {
+			return nil, nil, newValidationError("Geçersiz bitiş tarihi, YYYY-AA-GG biçiminde olmalıdır")
+		}
+		to = &parsed
+	}
+	if from != nil && to != nil && to.Before(*from) {
+		return nil, nil, newValidationError("Bitiş tarihi başlangıç tarihinden önce olamaz")
+	}
+
+	return from, to, nil
+}
+
+// ledgerColumns scanLedgerEntry sırasıyla hareket kolonları
+const ledgerColumns = `id, user_id, customer_id, entry_type, source, amount, description, COALESCE(payment_method, ''),
+	entry_date, invoice_id, order_id, COALESCE(created_by, 0), created_at,
+	(SELECT COALESCE(SUM(amount), 0) FROM payment_allocations WHERE entry_id = ledger_entries.id)`
+
+// getLedgerEntry müşterinin tek hareketini dağıtımlarıyla birlikte döndürür
+func (h *Handler) getLedgerEntry(userID, customerID, id int) (*models.LedgerEntry, error) {
+	base, err := h.baseCurrency(userID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := h.queryLedgerEntries(base, "user_id = ? AND customer_id = ? AND id = ?", userID, customerID, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, errNotFound
+	}
+	return &entries[0], nil
+}
+
+// queryLedgerEntries hareketleri tarih sırasıyla döndürür; tahsilatların dağıtımları da yüklenir.
+// Hareketler işletmenin ana para birimindedir ve tutarlar base ile etiketlenir.
+func (h *Handler) queryLedgerEntries(base money.Currency, where string, args ...interface{}) ([]models.LedgerEntry, error) {
+	rows, err := h.db.Query("SELECT "+ledgerColumns+" FROM ledger_entries WHERE "+where+" ORDER BY entry_date, id", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.LedgerEntry{}
+	index := map[int]int{}
+	for rows.Next() {
+		var entry models.LedgerEntry
+		var invoiceID, orderID sql.NullInt64
+		err := rows.Scan(&entry.ID, &entry.UserID, &entry.CustomerID, &entry.Type, &entry.Source, &entry.Amount,
+			&entry.Description, &entry.PaymentMethod, &entry.EntryDate, &invoiceID, &orderID, &entry.CreatedBy,
+			&entry.CreatedAt, &entry.Allocated)
+		if err != nil {
+			return nil, err
+		}
+		entry.Amount = entry.Amount.WithCurrency(base)
+		entry.Allocated = entry.Allocated.WithCurrency(base)
+		entry.InvoiceID = nullIntPtr(invoiceID)
+		entry.OrderID = nullIntPtr(orderID)
+		index[entry.ID] = len(entries)
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	allocations, err := h.db.Query(`
+		SELECT a.id, a.entry_id, a.invoice_id, COALESCE(i.invoice_number, ''), a.amount, a.created_at
+		FROM payment_allocations a
+		JOIN invoices i ON i.id = a.invoice_id
+		WHERE a.entry_id IN (SELECT id FROM ledger_entries WHERE `+where+`)
+		ORDER BY a.id
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer allocations.Close()
+
+	for allocations.Next() {
+		var allocation models.PaymentAllocation
+		err := allocations.Scan(&allocation.ID, &allocation.EntryID, &allocation.InvoiceID, &allocation.InvoiceNumber,
+			&allocation.Amount, &allocation.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		allocation.Amount = allocation.Amount.WithCurrency(base)
+		if i, ok := index[allocation.EntryID]; ok {
+			entries[i].Allocations = append(entries[i].Allocations, allocation)
+		}
+	}
+
+	return entries, allocations.Err()
+}
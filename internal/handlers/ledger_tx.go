@@ -0,0 +1,373 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/money"
+)
+
+// Müşteri cari hesap hareketleri ve tahsilat dağıtımları için yardımcılar.
+// Yazan yardımcılar çağıranın açtığı veritabanı işlemi (tx) içinde çalışır.
+//
+// Faturalar kesildiğinde, veresiye siparişler teslim edildiğinde cari hesaba borç yazılır;
+// tahsilatlar alacak olarak yazılır ve isteğe bağlı olarak açık faturalara dağıtılır.
+// Kalan tutarı sıfırlanan fatura ödendi olarak işaretlenir.
+
+// queryer *sql.DB ve *sql.Tx için ortak sorgu arayüzü
+type queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// insertLedgerEntry hareketi kaydeder ve ID'sini döndürür
+func insertLedgerEntry(tx *sql.Tx, entry *models.LedgerEntry) (int, error) {
+	var paymentMethod interface{}
+	if entry.PaymentMethod != "" {
+		paymentMethod = entry.PaymentMethod
+	}
+	var createdBy interface{}
+	if entry.CreatedBy != 0 {
+		createdBy = entry.CreatedBy
+	}
+
+	result, err := tx.Exec(`
+		INSERT INTO ledger_entries (user_id, customer_id, entry_type, source, amount, description, payment_method,
+		                            entry_date, invoice_id, order_id, created_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, entry.UserID, entry.CustomerID, entry.Type, entry.Source, entry.Amount, entry.Description,
+		paymentMethod, entry.EntryDate, entry.InvoiceID, entry.OrderID, createdBy, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	entry.ID = int(id)
+
+	return entry.ID, nil
+}
+
+// postInvoiceEntry kesilen faturayı cari hesaba yazar. Veresiye siparişten oluşturulan satış
+// faturaları siparişin borç kaydıyla zaten hesapta olduğundan ikinci kez yazılmaz.
+func postInvoiceEntry(tx *sql.Tx, invoiceID int) error {
+	var userID, customerID int
+	var invoiceType, number string
+	var invoiceDate time.Time
+	var total money.Money
+	var creditForID sql.NullInt64
+	var onCredit bool
+	err := tx.QueryRow(`
+		SELECT i.user_id, i.customer_id, i.invoice_type, i.invoice_number, i.invoice_date, i.base_total_amount,
+		       i.credit_for_id, COALESCE(o.on_credit, 0)
+		FROM invoices i LEFT JOIN orders o ON o.id = i.order_id
+		WHERE i.id = ?
+	`, invoiceID).Scan(&userID, &customerID, &invoiceType, &number, &invoiceDate, &total, &creditForID, &onCredit)
+	if err != nil {
+		return err
+	}
+	if !total.IsPositive() {
+		return nil
+	}
+
+	entry := models.LedgerEntry{
+		UserID:      userID,
+		CustomerID:  customerID,
+		Type:        models.LedgerDebit,
+		Source:      models.LedgerSourceInvoice,
+		Amount:      total,
+		Description: "Fatura " + number,
+		EntryDate:   invoiceDate,
+		InvoiceID:   &invoiceID,
+	}
+	if invoiceType == models.InvoiceTypeCreditNote {
+		entry.Type = models.LedgerCredit
+		entry.Source = models.LedgerSourceCreditNote
+		entry.Description = "İade faturası " + number
+	} else if onCredit {
+		return nil
+	}
+
+	if _, err := insertLedgerEntry(tx, &entry); err != nil {
+		return err
+	}
+
+	// İade faturası düzelttiği faturanın kalan tutarını azaltır
+	if creditForID.Valid {
+		return refreshInvoicePayment(tx, int(creditForID.Int64), invoiceDate)
+	}
+	return nil
+}
+
+// postInvoiceVoid iptal edilen faturanın cari hesap kaydını ters kayıtla kapatır ve faturaya
+// dağıtılmış tahsilatları serbest bırakır; serbest kalan tutar başka faturalara dağıtılabilir
+func postInvoiceVoid(tx *sql.Tx, invoiceID int, voidedAt time.Time) error {
+	var entry models.LedgerEntry
+	var number string
+	var creditForID sql.NullInt64
+	err := tx.QueryRow(`
+		SELECT l.user_id, l.customer_id, l.entry_type, l.amount, i.invoice_number, i.credit_for_id
+		FROM ledger_entries l JOIN invoices i ON i.id = l.invoice_id
+		WHERE l.invoice_id = ? AND l.source IN (?, ?)
+	`, invoiceID, models.LedgerSourceInvoice, models.LedgerSourceCreditNote).
+		Scan(&entry.UserID, &entry.CustomerID, &entry.Type, &entry.Amount, &number, &creditForID)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	if err == nil {
+		entry.Type = oppositeLedgerType(entry.Type)
+		entry.Source = models.LedgerSourceInvoiceVoid
+		entry.Description = "Fatura iptali " + number
+		entry.EntryDate = voidedAt
+		entry.InvoiceID = &invoiceID
+		if _, err := insertLedgerEntry(tx, &entry); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec("DELETE FROM payment_allocations WHERE invoice_id = ?", invoiceID); err != nil {
+		return err
+	}
+
+	// İptal edilen iade faturası, düzelttiği faturanın kalan tutarını geri getirir
+	if creditForID.Valid {
+		return refreshInvoicePayment(tx, int(creditForID.Int64), voidedAt)
+	}
+	return nil
+}
+
+// allocatePayment tahsilatın bir kısmını faturaya dağıtır; fatura kapanırsa ödendi olarak işaretlenir
+func allocatePayment(tx *sql.Tx, userID, entryID, invoiceID int, amount money.Money) error {
+	if !amount.IsPositive() {
+		return newValidationError("Dağıtılan tutar sıfırdan büyük olmalıdır")
+	}
+
+	var customerID int
+	var source string
+	var entryAmount, allocated money.Money
+	var paidAt time.Time
+	err := tx.QueryRow(`
+		SELECT customer_id, source, amount, entry_date,
+		       (SELECT COALESCE(SUM(amount), 0) FROM payment_allocations WHERE entry_id = ledger_entries.id)
+		FROM ledger_entries WHERE id = ? AND user_id = ?
+	`, entryID, userID).Scan(&customerID, &source, &entryAmount, &paidAt, &allocated)
+	if err == sql.ErrNoRows {
+		return errNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if source != models.LedgerSourcePayment {
+		return newValidationError("Yalnızca tahsilatlar faturalara dağıtılabilir")
+	}
+	if remaining := entryAmount.Sub(allocated); amount.Cmp(remaining) > 0 {
+		return newValidationError(fmt.Sprintf("Tahsilatın dağıtılmamış tutarı (%s) yetersiz", remaining))
+	}
+
+	var invoiceCustomerID int
+	var invoiceType, status, number string
+	var invoiceDate time.Time
+	err = tx.QueryRow(`
+		SELECT customer_id, invoice_type, status, COALESCE(invoice_number, ''), invoice_date
+		FROM invoices WHERE id = ? AND user_id = ?
+	`, invoiceID, userID).Scan(&invoiceCustomerID, &invoiceType, &status, &number, &invoiceDate)
+	if err == sql.ErrNoRows {
+		return newValidationError(fmt.Sprintf("Fatura bulunamadı: %d", invoiceID))
+	}
+	if err != nil {
+		return err
+	}
+	if invoiceCustomerID != customerID {
+		return newValidationError(fmt.Sprintf("%s numaralı fatura bu müşteriye ait değil", number))
+	}
+	if invoiceType == models.InvoiceTypeCreditNote || (status != models.InvoiceIssued && status != models.InvoiceOverdue) {
+		return newValidationError(fmt.Sprintf("%s numaralı fatura ödeme beklemiyor", number))
+	}
+
+	outstanding, err := invoiceOutstanding(tx, invoiceID)
+	if err != nil {
+		return err
+	}
+	if amount.Cmp(outstanding) > 0 {
+		return newValidationError(fmt.Sprintf("%s numaralı faturanın kalan tutarı %s", number, outstanding))
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO payment_allocations (entry_id, invoice_id, amount, created_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (entry_id, invoice_id) DO UPDATE SET amount = amount + excluded.amount
+	`, entryID, invoiceID, amount, time.Now())
+	if err != nil {
+		return err
+	}
+
+	// Faturadan önce alınan avans, fatura tarihinde ödenmiş sayılır
+	if paidAt.Before(invoiceDate) {
+		paidAt = invoiceDate
+	}
+	return refreshInvoicePayment(tx, invoiceID, paidAt)
+}
+
+// autoAllocatePayment tahsilatın dağıtılmamış kısmını müşterinin açık faturalarına vadesi en
+// yakın olandan başlayarak dağıtır
+func autoAllocatePayment(tx *sql.Tx, userID, customerID, entryID int) error {
+	var remaining money.Money
+	err := tx.QueryRow(`
+		SELECT amount - (SELECT COALESCE(SUM(amount), 0) FROM payment_allocations WHERE entry_id = ledger_entries.id)
+		FROM ledger_entries WHERE id = ?
+	`, entryID).Scan(&remaining)
+	if err != nil {
+		return err
+	}
+
+	invoices, err := openInvoices(tx, userID, customerID)
+	if err != nil {
+		return err
+	}
+
+	for _, invoice := range invoices {
+		if !remaining.IsPositive() {
+			break
+		}
+		amount := money.Min(invoice.Outstanding, remaining)
+		if err := allocatePayment(tx, userID, entryID, invoice.InvoiceID, amount); err != nil {
+			return err
+		}
+		remaining = remaining.Sub(amount)
+	}
+
+	return nil
+}
+
+// releaseAllocations hareketin dağıtımlarını siler ve etkilenen faturaların ödeme durumunu yeniler
+func releaseAllocations(tx *sql.Tx, entryID int) error {
+	rows, err := tx.Query("SELECT invoice_id FROM payment_allocations WHERE entry_id = ?", entryID)
+	if err != nil {
+		return err
+	}
+	var invoiceIDs []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		invoiceIDs = append(invoiceIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM payment_allocations WHERE entry_id = ?", entryID); err != nil {
+		return err
+	}
+
+	for _, id := range invoiceIDs {
+		if err := refreshInvoicePayment(tx, id, time.Now()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// invoiceOutstanding faturanın dağıtılan tahsilatlar ve kesilen iade faturaları düşüldükten sonra
+// kalan tutarını döndürür
+func invoiceOutstanding(q queryer, invoiceID int) (money.Money, error) {
+	var outstanding money.Money
+	err := q.QueryRow("SELECT "+database.InvoiceOutstandingExpr+" FROM invoices WHERE id = ?", invoiceID).Scan(&outstanding)
+	return outstanding, err
+}
+
+// refreshInvoicePayment kalan tutarı kapanan faturayı ödendi olarak işaretler; tahsilatı geri
+// alınan ödenmiş faturayı yeniden açar (vadesi geçmişse gecikmiş olarak)
+func refreshInvoicePayment(tx *sql.Tx, invoiceID int, paidAt time.Time) error {
+	var invoiceType, status string
+	var dueDate sql.NullTime
+	err := tx.QueryRow("SELECT invoice_type, status, due_date FROM invoices WHERE id = ?", invoiceID).
+		Scan(&invoiceType, &status, &dueDate)
+	if err != nil {
+		return err
+	}
+	if invoiceType == models.InvoiceTypeCreditNote {
+		return nil
+	}
+
+	outstanding, err := invoiceOutstanding(tx, invoiceID)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case (status == models.InvoiceIssued || status == models.InvoiceOverdue) && !outstanding.IsPositive():
+		_, err = tx.Exec("UPDATE invoices SET status = ?, paid_at = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?",
+			models.InvoicePaid, paidAt, invoiceID)
+	case status == models.InvoicePaid && outstanding.IsPositive():
+		reopened := models.InvoiceIssued
+		if dueDate.Valid && dueDate.Time.Before(dateOnly(time.Now())) {
+			reopened = models.InvoiceOverdue
+		}
+		_, err = tx.Exec("UPDATE invoices SET status = ?, paid_at = NULL, updated_at = CURRENT_TIMESTAMP WHERE id = ?",
+			reopened, invoiceID)
+	}
+	return err
+}
+
+// openInvoices müşterinin ödeme bekleyen faturalarını vadesi en yakın olandan başlayarak döndürür
+func openInvoices(q queryer, userID, customerID int) ([]models.OpenInvoice, error) {
+	rows, err := q.Query(`
+		SELECT id, invoice_number, invoice_date, due_date, status, base_total_amount
+		FROM invoices
+		WHERE user_id = ? AND customer_id = ? AND invoice_type != ? AND status IN (?, ?)
+		ORDER BY COALESCE(due_date, invoice_date), id
+	`, userID, customerID, models.InvoiceTypeCreditNote, models.InvoiceIssued, models.InvoiceOverdue)
+	if err != nil {
+		return nil, err
+	}
+
+	invoices := []models.OpenInvoice{}
+	for rows.Next() {
+		var invoice models.OpenInvoice
+		var dueDate sql.NullTime
+		err := rows.Scan(&invoice.InvoiceID, &invoice.InvoiceNumber, &invoice.InvoiceDate, &dueDate,
+			&invoice.Status, &invoice.Total)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		invoice.DueDate = nullTimePtr(dueDate)
+		invoices = append(invoices, invoice)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	open := invoices[:0]
+	for _, invoice := range invoices {
+		outstanding, err := invoiceOutstanding(q, invoice.InvoiceID)
+		if err != nil {
+			return nil, err
+		}
+		if !outstanding.IsPositive() {
+			continue
+		}
+		invoice.Outstanding = outstanding
+		open = append(open, invoice)
+	}
+
+	return open, nil
+}
+
+// oppositeLedgerType hareket yönünün tersini döndürür
+func oppositeLedgerType(entryType string) string {
+	if entryType == models.LedgerDebit {
+		return models.LedgerCredit
+	}
+	return models.LedgerDebit
+}
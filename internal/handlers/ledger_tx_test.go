@@ -0,0 +1,291 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/database/dbtest"
+	"github.com/umutaraz/tradesman-app/internal/middleware"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/money"
+	"github.com/umutaraz/tradesman-app/internal/settings"
+)
+
+// ledgerFixture iki kesilmiş satış faturası olan bir müşteri
+type ledgerFixture struct {
+	db             *database.DB
+	h              *Handler
+	userID         int
+	customerID     int
+	first, second  int
+	total1, total2 int64 // kuruş
+}
+
+func newLedgerFixture(t *testing.T) ledgerFixture {
+	t.Helper()
+	db := dbtest.New(t)
+	f := ledgerFixture{db: db, h: testHandler(db), userID: dbtest.User(t, db, "sahip@example.com")}
+	result, err := db.Exec("INSERT INTO customers (user_id, name) VALUES (?, 'Ayşe Demir')", f.userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, _ := result.LastInsertId()
+	f.customerID = int(id)
+
+	day := func(d int) time.Time { return time.Date(2026, 5, d, 0, 0, 0, 0, time.Local) }
+	f.first = newDraftInvoice(t, db, f.userID, f.customerID, models.InvoiceTypeSales, day(2))
+	f.second = newDraftInvoice(t, db, f.userID, f.customerID, models.InvoiceTypeSales, day(3))
+	// İkinci faturanın vadesi daha yakındır
+	for _, inv := range []struct {
+		id  int
+		due time.Time
+	}{{f.first, day(20)}, {f.second, day(10)}} {
+		if _, err := db.Exec("UPDATE invoices SET due_date = ? WHERE id = ?", inv.due, inv.id); err != nil {
+			t.Fatal(err)
+		}
+		if err := inTx(db, func(tx *sql.Tx) error { return issueInvoice(tx, f.userID, inv.id, settings.Defaults()) }); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f.total1 = f.invoice(t, f.first).total
+	f.total2 = f.invoice(t, f.second).total
+	return f
+}
+
+type invoiceState struct {
+	status string
+	total  int64 // kuruş
+}
+
+func (f ledgerFixture) invoice(t *testing.T, invoiceID int) invoiceState {
+	t.Helper()
+	var s invoiceState
+	var total money.Money
+	if err := f.db.QueryRow("SELECT status, total_amount FROM invoices WHERE id = ?", invoiceID).Scan(&s.status, &total); err != nil {
+		t.Fatal(err)
+	}
+	s.total = total.Minor()
+	return s
+}
+
+func (f ledgerFixture) outstanding(t *testing.T, invoiceID int) int64 {
+	t.Helper()
+	amount, err := invoiceOutstanding(f.db, invoiceID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return amount.Minor()
+}
+
+func (f ledgerFixture) balance(t *testing.T) int64 {
+	t.Helper()
+	customer, err := f.h.customers.Get(f.userID, f.customerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return customer.Balance.Minor()
+}
+
+// payment müşteriden tahsilat kaydeder
+func (f ledgerFixture) payment(t *testing.T, kurus int64) int {
+	t.Helper()
+	var id int
+	err := inTx(f.db, func(tx *sql.Tx) (err error) {
+		id, err = insertLedgerEntry(tx, &models.LedgerEntry{UserID: f.userID, CustomerID: f.customerID,
+			Type: models.LedgerCredit, Source: models.LedgerSourcePayment, Amount: money.TL(kurus),
+			PaymentMethod: models.PaymentCash, Description: "Tahsilat", EntryDate: time.Now()})
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func TestAutoAllocatePaymentByDueDate(t *testing.T) {
+	f := newLedgerFixture(t)
+	if got := f.balance(t); got != f.total1+f.total2 {
+		t.Fatalf("balance = %v, want %v", got, f.total1+f.total2)
+	}
+
+	entryID := f.payment(t, f.total2+5000)
+	err := inTx(f.db, func(tx *sql.Tx) error { return autoAllocatePayment(tx, f.userID, f.customerID, entryID) })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Vadesi yakın olan fatura kapanır, kalan tutar diğerine düşer
+	if s := f.invoice(t, f.second); s.status != models.InvoicePaid {
+		t.Errorf("second invoice status = %s, want paid", s.status)
+	}
+	if got, want := f.outstanding(t, f.first), f.total1-5000; got != want {
+		t.Errorf("first invoice outstanding = %v, want %v", got, want)
+	}
+	if got, want := f.balance(t), f.total1-5000; got != want {
+		t.Errorf("balance = %v, want %v", got, want)
+	}
+
+	// Tahsilat geri alınınca fatura vadesi geçtiği için gecikmiş olarak yeniden açılır
+	if err := inTx(f.db, func(tx *sql.Tx) error { return releaseAllocations(tx, entryID) }); err != nil {
+		t.Fatal(err)
+	}
+	if s := f.invoice(t, f.second); s.status != models.InvoiceOverdue {
+		t.Errorf("second invoice status after release = %s, want overdue", s.status)
+	}
+	if got := f.outstanding(t, f.first); got != f.total1 {
+		t.Errorf("first invoice outstanding after release = %v, want %v", got, f.total1)
+	}
+}
+
+func TestAllocatePaymentValidation(t *testing.T) {
+	f := newLedgerFixture(t)
+	entryID := f.payment(t, 3000)
+
+	credit := newDraftInvoice(t, f.db, f.userID, f.customerID, models.InvoiceTypeCreditNote, time.Date(2026, 5, 4, 0, 0, 0, 0, time.Local))
+	if _, err := f.db.Exec("UPDATE invoices SET credit_for_id = ? WHERE id = ?", f.second, credit); err != nil {
+		t.Fatal(err)
+	}
+	if err := inTx(f.db, func(tx *sql.Tx) error { return issueInvoice(tx, f.userID, credit, settings.Defaults()) }); err != nil {
+		t.Fatal(err)
+	}
+	creditTotal := f.invoice(t, credit).total
+
+	// Tam tutarlı iade faturası düzelttiği faturayı kapatır ve cari bakiyeyi azaltır
+	if got := f.outstanding(t, f.second); got != f.total2-creditTotal {
+		t.Errorf("outstanding after credit note = %v, want %v", got, f.total2-creditTotal)
+	}
+	if s := f.invoice(t, f.second); s.status != models.InvoicePaid {
+		t.Errorf("credited invoice status = %s, want paid", s.status)
+	}
+	if got, want := f.balance(t), f.total1+f.total2-creditTotal-3000; got != want {
+		t.Errorf("balance = %v, want %v", got, want)
+	}
+
+	otherCustomer, err := f.db.Exec("INSERT INTO customers (user_id, name) VALUES (?, 'Mehmet Kaya')", f.userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherID, _ := otherCustomer.LastInsertId()
+	foreign := newDraftInvoice(t, f.db, f.userID, int(otherID), models.InvoiceTypeSales, time.Date(2026, 5, 5, 0, 0, 0, 0, time.Local))
+	if err := inTx(f.db, func(tx *sql.Tx) error { return issueInvoice(tx, f.userID, foreign, settings.Defaults()) }); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		entryID   int
+		invoiceID int
+		amount    int64 // kuruş
+		want      error
+	}{
+		{"zero amount", entryID, f.first, 0, errValidation},
+		{"more than the payment", entryID, f.first, 3100, errValidation},
+		{"credit note", entryID, credit, 1000, errValidation},
+		{"paid invoice", entryID, f.second, 1000, errValidation},
+		{"other customer's invoice", entryID, foreign, 1000, errValidation},
+		{"unknown payment", entryID + 1000, f.first, 1000, errNotFound},
+		{"partial", entryID, f.first, 2000, nil},
+		{"rest of the payment", entryID, f.first, 1000, nil},
+		{"fully allocated", entryID, f.first, 1, errValidation},
+	}
+	for _, tt := range tests {
+		err := inTx(f.db, func(tx *sql.Tx) error {
+			return allocatePayment(tx, f.userID, tt.entryID, tt.invoiceID, money.TL(tt.amount))
+		})
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestVoidInvoiceReleasesAllocations(t *testing.T) {
+	f := newLedgerFixture(t)
+	entryID := f.payment(t, 4000)
+	if err := inTx(f.db, func(tx *sql.Tx) error { return allocatePayment(tx, f.userID, entryID, f.first, money.TL(4000)) }); err != nil {
+		t.Fatal(err)
+	}
+
+	err := inTx(f.db, func(tx *sql.Tx) error {
+		if _, err := tx.Exec("UPDATE invoices SET status = ? WHERE id = ?", models.InvoiceVoid, f.first); err != nil {
+			return err
+		}
+		return postInvoiceVoid(tx, f.first, time.Now())
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var allocations int
+	if err := f.db.QueryRow("SELECT COUNT(*) FROM payment_allocations WHERE entry_id = ?", entryID).Scan(&allocations); err != nil {
+		t.Fatal(err)
+	}
+	if allocations != 0 {
+		t.Errorf("allocations = %d, want 0", allocations)
+	}
+	// İptal ters kayıtla kapanır; serbest kalan tahsilat diğer faturaya dağıtılabilir
+	if got, want := f.balance(t), f.total2-4000; got != want {
+		t.Errorf("balance = %v, want %v", got, want)
+	}
+	if err := inTx(f.db, func(tx *sql.Tx) error { return allocatePayment(tx, f.userID, entryID, f.second, money.TL(4000)) }); err != nil {
+		t.Errorf("allocating the released payment: %v", err)
+	}
+}
+
+// Cari hareket varken ana para birimi değiştirilemez; ekstre tutarları ana para birimiyle etiketlenir
+func TestBaseCurrencyFollowsLedger(t *testing.T) {
+	f := newLedgerFixture(t)
+	entryID := f.payment(t, 4000)
+	if err := inTx(f.db, func(tx *sql.Tx) error { return allocatePayment(tx, f.userID, entryID, f.first, money.TL(4000)) }); err != nil {
+		t.Fatal(err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.PUT("/api/v1/settings", middleware.Auth(f.db, func(int) time.Duration { return 0 }), f.h.UpdateSettingsAPI)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, apiRequest(t, f.db, f.userID, http.MethodPut, "/api/v1/settings", `{"currency": "USD"}`))
+	if w.Code != http.StatusConflict {
+		t.Errorf("changing the base currency: status = %d, want 409: %s", w.Code, w.Body)
+	}
+
+	// Kayıtlar öncesinden kalan ana para birimi veritabanından okunur
+	if err := f.db.SetSetting(f.userID, database.SettingCurrency, string(money.EUR)); err != nil {
+		t.Fatal(err)
+	}
+	f.h.settings.InvalidateAll()
+	from := time.Date(2026, 5, 3, 0, 0, 0, 0, time.Local)
+	statement, err := f.h.customerStatement(f.userID, f.customerID, &from, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	amounts := map[string]money.Money{
+		"opening": statement.OpeningBalance,
+		"closing": statement.ClosingBalance,
+		"debit":   statement.TotalDebit,
+		"credit":  statement.TotalCredit,
+	}
+	for _, entry := range statement.Entries {
+		amounts["entry"] = entry.Amount
+		amounts["running balance"] = entry.Balance
+		for _, allocation := range entry.Allocations {
+			amounts["allocation"] = allocation.Amount
+		}
+	}
+	if len(amounts) != 7 {
+		t.Fatalf("statement = %+v", statement)
+	}
+	for name, m := range amounts {
+		if m.Currency() != money.EUR {
+			t.Errorf("%s = %v %s, want EUR", name, m, m.Currency())
+		}
+	}
+	if customer, err := f.h.customers.Get(f.userID, f.customerID); err != nil || customer.Balance.Currency() != money.EUR {
+		t.Errorf("customer balance = %v, %v; want EUR", customer, err)
+	}
+}
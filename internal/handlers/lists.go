@@ -0,0 +1,17 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umutaraz/tradesman-app/internal/listquery"
+	"github.com/umutaraz/tradesman-app/internal/middleware"
+)
+
+// parseListQuery istekteki liste parametrelerini çözümler; sayfa boyutu verilmemişse işletmenin
+// "sayfa başına öğe sayısı" ayarı kullanılır
+func (h *Handler) parseListQuery(c *gin.Context, spec listquery.Spec) (*listquery.Query, error) {
+	s, err := h.settings.Get(middleware.BusinessID(c))
+	if err != nil {
+		return nil, err
+	}
+	return listquery.Parse(c.Request.URL.Query(), spec, s.PageSize)
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/database/dbtest"
+	"github.com/umutaraz/tradesman-app/internal/middleware"
+)
+
+// listPage müşteri listesinin bir sayfasındaki adları ve sonraki sayfanın imlecini döndürür
+func listPage(t *testing.T, r *gin.Engine, db *database.DB, userID int, query string) (names []string, next string) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, apiRequest(t, db, userID, http.MethodGet, "/api/v1/customers?"+query, ""))
+	if w.Code != http.StatusOK {
+		t.Fatalf("%s: status = %d: %s", query, w.Code, w.Body)
+	}
+	var resp struct {
+		Customers []struct {
+			Name string `json:"name"`
+		} `json:"customers"`
+		Pagination struct {
+			NextCursor string `json:"next_cursor"`
+		} `json:"pagination"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range resp.Customers {
+		names = append(names, c.Name)
+	}
+	return names, resp.Pagination.NextCursor
+}
+
+func TestCustomerListCursor(t *testing.T) {
+	db := dbtest.New(t)
+	userID := dbtest.User(t, db, "sahip@example.com")
+	addCustomer := func(name string, createdAt time.Time) {
+		t.Helper()
+		if _, err := db.Exec("INSERT INTO customers (user_id, name, created_at) VALUES (?, ?, ?)", userID, name, createdAt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 10, 0, 0, 0, time.Local) }
+	// Aynı zamanda eklenen kayıtlar ID'ye göre sıralanır
+	addCustomer("Ayşe", day(5))
+	addCustomer("Burak", day(4))
+	addCustomer("Cem", day(4))
+	addCustomer("Deniz", day(2))
+	addCustomer("Elif", day(1))
+	addCustomer("Fatma", day(1))
+
+	gin.SetMode(gin.TestMode)
+	h := testHandler(db)
+	r := gin.New()
+	r.GET("/api/v1/customers", middleware.Auth(db, func(int) time.Duration { return 0 }), h.GetCustomersAPI)
+
+	walk := func(query string, insertAfterFirst func()) []string {
+		var all []string
+		cursor := ""
+		for i := 0; i < 10; i++ {
+			q := query
+			if cursor != "" {
+				q += "&cursor=" + url.QueryEscape(cursor)
+			}
+			names, next := listPage(t, r, db, userID, q)
+			all = append(all, names...)
+			if i == 0 && insertAfterFirst != nil {
+				insertAfterFirst()
+			}
+			if next == "" {
+				return all
+			}
+			cursor = next
+		}
+		t.Fatalf("%s: cursor did not reach the last page", query)
+		return nil
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"newest first", "limit=2", []string{"Ayşe", "Cem", "Burak", "Deniz", "Fatma", "Elif"}},
+		{"oldest first", "limit=4&sort=created_at", []string{"Elif", "Fatma", "Deniz", "Burak", "Cem", "Ayşe"}},
+		{"by name descending", "limit=5&sort=-name", []string{"Fatma", "Elif", "Deniz", "Cem", "Burak", "Ayşe"}},
+		{"by balance", "limit=3&sort=balance", []string{"Ayşe", "Burak", "Cem", "Deniz", "Elif", "Fatma"}},
+	}
+	for _, tt := range tests {
+		if got := walk(tt.query, nil); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: names = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// Sayfalar arasında en başa eklenen kayıt sonraki sayfaları kaydırmaz
+	got := walk("limit=2", func() { addCustomer("Gül", day(6)) })
+	if want := []string{"Ayşe", "Cem", "Burak", "Deniz", "Fatma", "Elif"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("names after insert = %v, want %v", got, want)
+	}
+
+	// İmleç başka bir sıralamayla kullanılamaz
+	_, cursor := listPage(t, r, db, userID, "limit=2")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, apiRequest(t, db, userID, http.MethodGet, "/api/v1/customers?limit=2&sort=name&cursor="+url.QueryEscape(cursor), ""))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("cursor with another sort: status = %d, want 400", w.Code)
+	}
+}
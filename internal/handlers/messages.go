@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/messaging"
+	"github.com/umutaraz/tradesman-app/internal/middleware"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/outbox"
+)
+
+// customerMessageRequest müşteriye kısa mesaj gönderme isteği. Metin verilmezse şablon
+// kullanılır; şablonun gerektirdiği kayıt (sipariş, fatura ya da randevu) da gönderilmelidir.
+type customerMessageRequest struct {
+	Template      string `json:"template"`
+	Text          string `json:"text"`
+	Channel       string `json:"channel" binding:"omitempty,oneof=sms whatsapp"`
+	OrderID       int    `json:"order_id"`
+	InvoiceID     int    `json:"invoice_id"`
+	AppointmentID int    `json:"appointment_id"`
+}
+
+// templateUpdateRequest şablon metni; boş metin şablonu varsayılana döndürür
+type templateUpdateRequest struct {
+	Body string `json:"body"`
+}
+
+// Müşteriye kısa mesaj gönder. İleti kuyruğa alınır ve arka planda gönderilir;
+// gönderim müşterinin etkinlik geçmişine kaydedilir.
+func (h *Handler) SendCustomerMessage(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req customerMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	businessID := middleware.BusinessID(c)
+	customer, err := h.customers.Get(businessID, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if strings.TrimSpace(customer.Phone) == "" {
+		respondError(c, newValidationError("Müşterinin telefon numarası kayıtlı değil"))
+		return
+	}
+
+	data, err := h.messageData(businessID, customer, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	text, err := h.messageText(businessID, req, data)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	msg := messaging.Message{Channel: req.Channel, To: customer.Phone, Body: text}
+	if msg.Channel == "" {
+		msg.Channel = messaging.ChannelSMS
+	}
+	if err := msg.Validate(); err != nil {
+		respondError(c, newValidationError(err.Error()))
+		return
+	}
+
+	var outboxID int
+	err = h.withTx(func(tx *sql.Tx) error {
+		var err error
+		outboxID, err = outbox.EnqueueMessage(tx, businessID, msg)
+		if err != nil {
+			return err
+		}
+
+		activity := models.CustomerActivity{
+			UserID:      businessID,
+			CustomerID:  customer.ID,
+			Type:        models.ActivityMessageSent,
+			Description: fmt.Sprintf("%s numarasına %s gönderildi: %s", customer.Phone, channelLabel(msg.Channel), text),
+			OutboxID:    &outboxID,
+			CreatedBy:   middleware.UserID(c),
+		}
+		if data.Invoice != nil {
+			activity.InvoiceID = &data.Invoice.ID
+		}
+		return database.InsertCustomerActivity(tx, activity)
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":   "Mesaj gönderim kuyruğuna alındı",
+		"outbox_id": outboxID,
+		"phone":     customer.Phone,
+		"text":      text,
+	})
+}
+
+// messageData şablonda kullanılacak kayıtları yükler; kayıtlar müşteriye ait olmalıdır
+func (h *Handler) messageData(businessID int, customer *models.Customer, req customerMessageRequest) (messaging.Data, error) {
+	owner, err := h.getUserByID(businessID)
+	if err != nil {
+		return messaging.Data{}, err
+	}
+
+	s, err := h.settings.Get(businessID)
+	if err != nil {
+		return messaging.Data{}, err
+	}
+
+	data := messaging.Data{Customer: customer, BusinessName: businessName(owner), BusinessPhone: owner.Phone,
+		DateLayout: s.DateLayout()}
+
+	if req.OrderID > 0 {
+		if data.Order, err = h.orders.Get(businessID, req.OrderID); err != nil {
+			return data, err
+		}
+		if data.Order.CustomerID != customer.ID {
+			return data, newValidationError("Sipariş bu müşteriye ait değil")
+		}
+	}
+	if req.InvoiceID > 0 {
+		if data.Invoice, err = h.getInvoice(businessID, req.InvoiceID); err != nil {
+			return data, err
+		}
+		if data.Invoice.CustomerID != customer.ID {
+			return data, newValidationError("Fatura bu müşteriye ait değil")
+		}
+	}
+	if req.AppointmentID > 0 {
+		if data.Appointment, err = h.getAppointment(businessID, req.AppointmentID); err != nil {
+			return data, err
+		}
+		if data.Appointment.CustomerID != customer.ID {
+			return data, newValidationError("Randevu bu müşteriye ait değil")
+		}
+	}
+
+	return data, nil
+}
+
+// messageText serbest metni ya da işletmenin şablonunu doldurur
+func (h *Handler) messageText(businessID int, req customerMessageRequest, data messaging.Data) (string, error) {
+	if strings.TrimSpace(req.Text) != "" {
+		return messaging.Render(req.Text, data), nil
+	}
+
+	tmpl, ok := messaging.FindTemplate(req.Template)
+	if !ok {
+		return "", newValidationError("Mesaj metni ya da geçerli bir şablon seçilmelidir")
+	}
+
+	switch {
+	case tmpl.Key == messaging.TemplateOrderReady && data.Order == nil:
+		return "", newValidationError("Bu şablon için sipariş seçilmelidir")
+	case tmpl.Key == messaging.TemplatePaymentDue && data.Invoice == nil:
+		return "", newValidationError("Bu şablon için fatura seçilmelidir")
+	case tmpl.Key == messaging.TemplateAppointmentReminder && data.Appointment == nil:
+		return "", newValidationError("Bu şablon için randevu seçilmelidir")
+	}
+
+	body, _, err := h.db.GetMessageTemplate(businessID, tmpl.Key, tmpl.Default)
+	if err != nil {
+		return "", err
+	}
+	return messaging.Render(body, data), nil
+}
+
+// channelLabel etkinlik geçmişinde gösterilen kanal adı
+func channelLabel(channel string) string {
+	if channel == messaging.ChannelWhatsApp {
+		return "WhatsApp mesajı"
+	}
+	return "SMS"
+}
+
+// Mesaj şablonları ve kullanılabilecek yer tutucular
+func (h *Handler) GetMessageTemplatesAPI(c *gin.Context) {
+	templates, err := h.getMessageTemplates(middleware.BusinessID(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"templates":    templates,
+		"placeholders": messaging.Placeholders,
+	})
+}
+
+// Mesaj şablonunu güncelle
+func (h *Handler) UpdateMessageTemplate(c *gin.Context) {
+	tmpl, ok := messaging.FindTemplate(c.Param("key"))
+	if !ok {
+		respondError(c, errNotFound)
+		return
+	}
+
+	var req templateUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	businessID := middleware.BusinessID(c)
+	body := strings.TrimSpace(req.Body)
+	if err := messaging.ValidateTemplate(body); err != nil {
+		respondError(c, newValidationError(err.Error()))
+		return
+	}
+
+	if body == "" || body == tmpl.Default {
+		err := h.db.ResetMessageTemplate(businessID, tmpl.Key)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		body = tmpl.Default
+	} else if err := h.db.SetMessageTemplate(businessID, tmpl.Key, body); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	tmpl.Body = body
+	tmpl.Customized = body != tmpl.Default
+	c.JSON(http.StatusOK, tmpl)
+}
+
+// getMessageTemplates tanımlı şablonları işletmenin metinleriyle döndürür
+func (h *Handler) getMessageTemplates(businessID int) ([]messaging.Template, error) {
+	templates := make([]messaging.Template, 0, len(messaging.Templates))
+	for _, tmpl := range messaging.Templates {
+		body, customized, err := h.db.GetMessageTemplate(businessID, tmpl.Key, tmpl.Default)
+		if err != nil {
+			return nil, err
+		}
+		tmpl.Body = body
+		tmpl.Customized = customized
+		templates = append(templates, tmpl)
+	}
+	return templates, nil
+}
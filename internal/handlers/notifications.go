@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"database/sql"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umutaraz/tradesman-app/internal/middleware"
+	"github.com/umutaraz/tradesman-app/internal/models"
+)
+
+// sseHeartbeat proxy'lerin boşta kalan bağlantıyı kapatmaması için gönderilen yorum satırı aralığı
+const sseHeartbeat = 25 * time.Second
+
+// Bildirimler
+func (h *Handler) Notifications(c *gin.Context) {
+	notifications, err := h.queryNotifications(middleware.UserID(c), false, 200)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"error": err.Error()})
+		return
+	}
+
+	h.render(c, "notifications.html", gin.H{
+		"notifications": notifications,
+		"title":         "Bildirimler - Esnaf Yönetim Sistemi",
+		"active":        "notifications",
+	})
+}
+
+// Bildirim listesi (API); unread=1 yalnızca okunmamışları döndürür
+func (h *Handler) GetNotificationsAPI(c *gin.Context) {
+	limit := 50
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 && l <= 200 {
+		limit = l
+	}
+
+	userID := middleware.UserID(c)
+	notifications, err := h.queryNotifications(userID, c.Query("unread") == "1", limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	unread, err := h.unreadNotificationCount(userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"notifications": notifications, "unread_count": unread})
+}
+
+// Bildirimi okundu olarak işaretle
+func (h *Handler) MarkNotificationRead(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	userID := middleware.UserID(c)
+	result, err := h.db.Exec(`
+		UPDATE notifications SET read_at = COALESCE(read_at, ?) WHERE id = ? AND user_id = ?
+	`, time.Now(), id, userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		respondError(c, errNotFound)
+		return
+	}
+
+	h.respondUnreadCount(c, userID)
+}
+
+// Tüm bildirimleri okundu olarak işaretle
+func (h *Handler) MarkAllNotificationsRead(c *gin.Context) {
+	userID := middleware.UserID(c)
+	_, err := h.db.Exec("UPDATE notifications SET read_at = ? WHERE user_id = ? AND read_at IS NULL", time.Now(), userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	h.respondUnreadCount(c, userID)
+}
+
+// Bildirimi sil
+func (h *Handler) DeleteNotification(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	userID := middleware.UserID(c)
+	result, err := h.db.Exec("DELETE FROM notifications WHERE id = ? AND user_id = ?", id, userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		respondError(c, errNotFound)
+		return
+	}
+
+	h.respondUnreadCount(c, userID)
+}
+
+// Yeni bildirimleri Server-Sent Events ile anlık gönderir. Bağlantı açıldığında okunmamış
+// bildirim sayısı "unread" olayıyla, ardından her yeni bildirim "notification" olayıyla iletilir.
+func (h *Handler) NotificationStream(c *gin.Context) {
+	userID := middleware.UserID(c)
+	unread, err := h.unreadNotificationCount(userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	events, unsubscribe := h.notifier.Hub().Subscribe(userID)
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	c.SSEvent("unread", gin.H{"unread_count": unread})
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case n, ok := <-events:
+			if !ok {
+				return false
+			}
+			c.SSEvent("notification", n)
+			return true
+		case <-heartbeat.C:
+			_, err := io.WriteString(w, ": ping\n\n")
+			return err == nil
+		}
+	})
+}
+
+func (h *Handler) respondUnreadCount(c *gin.Context, userID int) {
+	unread, err := h.unreadNotificationCount(userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "unread_count": unread})
+}
+
+func (h *Handler) unreadNotificationCount(userID int) (int, error) {
+	var count int
+	err := h.db.QueryRow("SELECT COUNT(*) FROM notifications WHERE user_id = ? AND read_at IS NULL", userID).Scan(&count)
+	return count, err
+}
+
+func (h *Handler) queryNotifications(userID int, unreadOnly bool, limit int) ([]models.Notification, error) {
+	query := `
+		SELECT id, user_id, type, severity, title, message, COALESCE(link, ''), read_at, created_at
+		FROM notifications WHERE user_id = ?`
+	if unreadOnly {
+		query += " AND read_at IS NULL"
+	}
+	query += " ORDER BY created_at DESC, id DESC LIMIT ?"
+
+	rows, err := h.db.Query(query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := []models.Notification{}
+	for rows.Next() {
+		var n models.Notification
+		var readAt sql.NullTime
+		if err := rows.Scan(&n.ID, &n.UserID, &n.Type, &n.Severity, &n.Title, &n.Message, &n.Link, &readAt,
+			&n.CreatedAt); err != nil {
+			return nil, err
+		}
+		n.ReadAt = nullTimePtr(readAt)
+		notifications = append(notifications, n)
+	}
+
+	return notifications, rows.Err()
+}
@@ -0,0 +1,309 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umutaraz/tradesman-app/internal/middleware"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/repository"
+)
+
+// Sipariş listesi (API)
+func (h *Handler) GetOrdersAPI(c *gin.Context) {
+	q, err := h.parseListQuery(c, repository.OrderList)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	orders, page, err := h.orders.List(middleware.BusinessID(c), q)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"orders": orders, "pagination": page})
+}
+
+// Sipariş detayı (API)
+func (h *Handler) GetOrderAPI(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	order, err := h.orders.Get(middleware.BusinessID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// Sipariş oluştur
+func (h *Handler) CreateOrder(c *gin.Context) {
+	var order models.Order
+	if err := c.ShouldBindJSON(&order); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if len(order.Items) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Sipariş en az bir kalem içermelidir"})
+		return
+	}
+
+	// Yeni sipariş yalnızca açık ya da doğrudan tamamlanmış olarak oluşturulabilir
+	switch order.Status {
+	case "":
+		order.Status = models.OrderPending
+	case models.OrderPending, models.OrderProcessing, models.OrderCompleted:
+	default:
+		respondError(c, newValidationError("Yeni sipariş bu durumla oluşturulamaz: "+order.Status))
+		return
+	}
+	if order.OrderDate.IsZero() {
+		order.OrderDate = time.Now()
+	}
+
+	businessID := middleware.BusinessID(c)
+	base, err := h.baseCurrency(businessID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if order.Currency, err = h.resolveCurrency(businessID, order.Currency); err != nil {
+		respondError(c, err)
+		return
+	}
+	// Cari hesap ana para biriminde tutulduğundan veresiye satış dövizle yapılamaz
+	if order.OnCredit && order.Currency != base {
+		respondError(c, newValidationError("Veresiye siparişler yalnızca ana para biriminde ("+string(base)+") oluşturulabilir"))
+		return
+	}
+
+	order.UserID = businessID
+	orderID, err := h.orders.Create(&order, middleware.UserID(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	created, err := h.orders.Get(businessID, orderID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// Sipariş güncelle
+func (h *Handler) UpdateOrder(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	var order models.Order
+	if err := c.ShouldBindJSON(&order); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	businessID := middleware.BusinessID(c)
+	existing, err := h.orders.Get(businessID, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if order.Status == "" {
+		order.Status = existing.Status
+	}
+	if order.OrderDate.IsZero() {
+		order.OrderDate = existing.OrderDate
+	}
+
+	if err := h.orders.Update(businessID, id, &order, middleware.UserID(c)); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	updated, err := h.orders.Get(businessID, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// orderStatusRequest durum değişikliği isteği
+type orderStatusRequest struct {
+	Status string `json:"status" binding:"required"`
+	Note   string `json:"note"`
+}
+
+// Sipariş durumunu değiştir; yalnızca izin verilen geçişler kabul edilir
+func (h *Handler) UpdateOrderStatus(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req orderStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.applyOrderStatus(c, id, req.Status, req.Note)
+}
+
+// Siparişi iptal et; stoklar iade edilir ve gelir kaydı geri alınır
+func (h *Handler) CancelOrder(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Note string `json:"note"`
+	}
+	// Gövde isteğe bağlıdır
+	_ = c.ShouldBindJSON(&req)
+
+	h.applyOrderStatus(c, id, models.OrderCancelled, req.Note)
+}
+
+// applyOrderStatus durum geçişini uygular ve güncel siparişi döndürür
+func (h *Handler) applyOrderStatus(c *gin.Context, id int, status, note string) {
+	businessID := middleware.BusinessID(c)
+	if err := h.orders.ChangeStatus(businessID, id, status, middleware.UserID(c), note); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	updated, err := h.orders.Get(businessID, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// Sipariş sil
+func (h *Handler) DeleteOrder(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.orders.Delete(middleware.BusinessID(c), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// Sipariş kalemleri (API)
+func (h *Handler) GetOrderItemsAPI(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	order, err := h.orders.Get(middleware.BusinessID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order.Items)
+}
+
+// Siparişe kalem ekle
+func (h *Handler) CreateOrderItem(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	var item models.OrderItem
+	if err := c.ShouldBindJSON(&item); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.orders.AddItem(middleware.BusinessID(c), id, &item); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, item)
+}
+
+// Sipariş kalemini güncelle
+func (h *Handler) UpdateOrderItem(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	itemID, ok := paramID(c, "itemId")
+	if !ok {
+		return
+	}
+
+	var item models.OrderItem
+	if err := c.ShouldBindJSON(&item); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item.ID = itemID
+	if err := h.orders.UpdateItem(middleware.BusinessID(c), id, &item); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// Sipariş kalemini sil
+func (h *Handler) DeleteOrderItem(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	itemID, ok := paramID(c, "itemId")
+	if !ok {
+		return
+	}
+
+	if err := h.orders.DeleteItem(middleware.BusinessID(c), id, itemID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// withTx fonksiyonu tek bir veritabanı işlemi içinde çalıştırır
+func (h *Handler) withTx(fn func(tx *sql.Tx) error) error {
+	tx, err := h.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
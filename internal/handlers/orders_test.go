@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umutaraz/tradesman-app/internal/config"
+	"github.com/umutaraz/tradesman-app/internal/listquery"
+	"github.com/umutaraz/tradesman-app/internal/middleware"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/money"
+	"github.com/umutaraz/tradesman-app/internal/repository"
+)
+
+// orderTestRouter sipariş durum uçlarını bellek içi depolarla, verilen işletme sahibi adına çalıştırır
+func orderTestRouter(store repository.Store, userID int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := New(nil, store, &config.Config{}, nil, nil, nil)
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		middleware.SetCurrentUser(c, &models.User{ID: userID, Role: models.RoleOwner})
+	})
+	r.GET("/api/v1/orders/:id", h.GetOrderAPI)
+	r.PATCH("/api/v1/orders/:id/status", h.UpdateOrderStatus)
+	r.POST("/api/v1/orders/:id/cancel", h.CancelOrder)
+	return r
+}
+
+func statusRequest(path, status string) *http.Request {
+	req := httptest.NewRequest(http.MethodPatch, path+"/status", strings.NewReader(`{"status":"`+status+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func TestReturnOrderRestoresStockAndIncome(t *testing.T) {
+	store := repository.NewMemory()
+	const userID, otherID = 1, 2
+
+	customerID, err := store.Customers.Create(&models.Customer{UserID: userID, Name: "Ayşe Demir"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	productID, err := store.Products.Create(&models.Product{UserID: userID, Name: "LED Ampul", Price: money.TL(4550),
+		Currency: money.TRY, StockQuantity: 10, Unit: "adet"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	orderID, err := store.Orders.Create(&models.Order{UserID: userID, CustomerID: customerID, Status: models.OrderCompleted,
+		Currency: money.TRY, OrderDate: time.Now(), Items: []models.OrderItem{{ProductID: productID, Quantity: 4}}}, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	product, err := store.Products.Get(userID, productID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if product.StockQuantity != 6 {
+		t.Fatalf("stock after order = %d, want 6", product.StockQuantity)
+	}
+
+	path := "/api/v1/orders/" + strconv.Itoa(orderID)
+	router := orderTestRouter(store, userID)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, statusRequest(path, models.OrderReturned))
+	if w.Code != http.StatusOK {
+		t.Fatalf("return: status = %d, body = %s", w.Code, w.Body)
+	}
+	var order models.Order
+	if err := json.Unmarshal(w.Body.Bytes(), &order); err != nil {
+		t.Fatal(err)
+	}
+	if order.Status != models.OrderReturned || order.TotalAmount.Minor() != 18200 || len(order.History) != 2 {
+		t.Errorf("returned order = %s %s, %d history entries", order.Status, order.TotalAmount, len(order.History))
+	}
+
+	// İadede stok geri eklenir ve siparişin gelir kaydı silinir
+	product, err = store.Products.Get(userID, productID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if product.StockQuantity != 10 {
+		t.Errorf("stock after return = %d, want 10", product.StockQuantity)
+	}
+	q, err := listquery.Parse(url.Values{}, repository.TransactionList, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transactions, _, err := store.Transactions.List(userID, q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(transactions) != 0 {
+		t.Errorf("transactions after return = %d, want 0", len(transactions))
+	}
+
+	// İade edilmiş sipariş iptal edilemez ve yeniden tamamlanamaz
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path+"/cancel", nil))
+	if w.Code != http.StatusConflict {
+		t.Errorf("cancel returned order: status = %d, want 409", w.Code)
+	}
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, statusRequest(path, models.OrderCompleted))
+	if w.Code != http.StatusConflict {
+		t.Errorf("complete returned order: status = %d, want 409", w.Code)
+	}
+
+	// Başka işletmenin siparişi bulunamadı sayılır
+	w = httptest.NewRecorder()
+	orderTestRouter(store, otherID).ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("other business: status = %d, want 404", w.Code)
+	}
+}
@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umutaraz/tradesman-app/internal/middleware"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/money"
+	"github.com/umutaraz/tradesman-app/internal/repository"
+)
+
+// Ürün listesi (API)
+func (h *Handler) GetProductsAPI(c *gin.Context) {
+	q, err := h.parseListQuery(c, repository.ProductList)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	products, page, err := h.products.List(middleware.BusinessID(c), q)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"products": products, "pagination": page})
+}
+
+// Ürün detayı (API)
+func (h *Handler) GetProductAPI(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	product, err := h.products.Get(middleware.BusinessID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, product)
+}
+
+// Ürün ekle
+func (h *Handler) CreateProduct(c *gin.Context) {
+	var product models.Product
+	if err := c.ShouldBindJSON(&product); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if product.Unit == "" {
+		product.Unit = "adet"
+	}
+
+	businessID := middleware.BusinessID(c)
+	currency, err := h.resolveCurrency(businessID, product.Currency)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	product.Currency = currency
+	product.UserID = businessID
+	id, err := h.products.Create(&product)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	created, err := h.products.Get(businessID, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// Ürün güncelle
+func (h *Handler) UpdateProduct(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	var product models.Product
+	if err := c.ShouldBindJSON(&product); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if product.Unit == "" {
+		product.Unit = "adet"
+	}
+
+	businessID := middleware.BusinessID(c)
+	existing, err := h.products.Get(businessID, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// Para birimi verilmezse değişmez
+	if product.Currency == "" {
+		product.Currency = existing.Currency
+	}
+	if product.Currency, err = h.resolveCurrency(businessID, product.Currency); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if err := h.products.Update(businessID, id, &product); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	updated, err := h.products.Get(businessID, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// Ürün sil
+func (h *Handler) DeleteProduct(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.products.Delete(middleware.BusinessID(c), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// productForm ürün ekleme formu (products.html)
+type productForm struct {
+	Name            string      `form:"name" binding:"required"`
+	Category        string      `form:"category"`
+	Price           money.Money `form:"price" binding:"gte=0"`
+	Currency        string      `form:"currency"` // Boşsa işletmenin ana para birimi
+	Stock           int         `form:"stock" binding:"gte=0"`
+	Unit            string      `form:"unit"`
+	ReorderLevel    string      `form:"reorder_level"` // Boşsa varsayılan eşik kullanılır
+	ReorderQuantity int         `form:"reorder_quantity" binding:"gte=0"`
+	Description     string      `form:"description"`
+}
+
+// Ürün ekle (form)
+func (h *Handler) AddProductForm(c *gin.Context) {
+	var form productForm
+	if err := c.ShouldBind(&form); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Lütfen zorunlu alanları doğru doldurun"})
+		return
+	}
+
+	var reorderLevel *int
+	if form.ReorderLevel != "" {
+		level, err := strconv.Atoi(form.ReorderLevel)
+		if err != nil || level < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Kritik stok seviyesi sıfır veya pozitif bir sayı olmalıdır"})
+			return
+		}
+		reorderLevel = &level
+	}
+
+	businessID := middleware.BusinessID(c)
+	currency, err := h.resolveCurrency(businessID, money.Currency(form.Currency))
+	if err != nil {
+		status, message := errorResponse(err)
+		c.JSON(status, gin.H{"success": false, "message": message})
+		return
+	}
+
+	product := models.Product{
+		UserID:          businessID,
+		Name:            form.Name,
+		Description:     form.Description,
+		Price:           form.Price,
+		Currency:        currency,
+		Category:        form.Category,
+		StockQuantity:   form.Stock,
+		Unit:            form.Unit,
+		IsService:       form.Category == "Hizmet",
+		ReorderLevel:    reorderLevel,
+		ReorderQuantity: form.ReorderQuantity,
+	}
+	if product.Unit == "" {
+		product.Unit = "adet"
+	}
+
+	id, err := h.products.Create(&product)
+	if err != nil {
+		status, message := errorResponse(err)
+		c.JSON(status, gin.H{"success": false, "message": message})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "id": id})
+}
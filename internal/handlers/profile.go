@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/middleware"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// maxAvatarSize profil fotoğrafının en büyük boyutu
+const maxAvatarSize = 2 << 20
+
+// defaultAvatar profil fotoğrafı yüklememiş kullanıcılar için gösterilen görsel
+const defaultAvatar = "/assets/media/avatars/blank.png"
+
+type updateProfileRequest struct {
+	Name         string `form:"name" json:"name" binding:"required,max=100"`
+	BusinessName string `form:"business_name" json:"business_name" binding:"max=200"`
+	Email        string `form:"email" json:"email" binding:"required,email,max=254"`
+	Phone        string `form:"phone" json:"phone" binding:"max=50"`
+	Address      string `form:"address" json:"address" binding:"max=500"`
+}
+
+type changePasswordRequest struct {
+	CurrentPassword string `form:"current_password" json:"current_password" binding:"required"`
+	NewPassword     string `form:"new_password" json:"new_password" binding:"required,min=6"`
+	ConfirmPassword string `form:"confirm_password" json:"confirm_password" binding:"required"`
+}
+
+// Profil
+func (h *Handler) Profile(c *gin.Context) {
+	user, err := h.getUserByID(middleware.UserID(c))
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"error": err.Error()})
+		return
+	}
+
+	h.render(c, "profile.html", gin.H{
+		"user":   user,
+		"title":  "Profil - Esnaf Yönetim Sistemi",
+		"active": "profile",
+	})
+}
+
+// Profil Güncelle; gönderilmeyen alanlar değişmez
+func (h *Handler) UpdateProfile(c *gin.Context) {
+	user, err := h.getUserByID(middleware.UserID(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	req := updateProfileRequest{
+		Name:         user.Name,
+		BusinessName: user.BusinessName,
+		Email:        user.Email,
+		Phone:        user.Phone,
+		Address:      user.Address,
+	}
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	req.BusinessName = strings.TrimSpace(req.BusinessName)
+	req.Email = strings.ToLower(strings.TrimSpace(req.Email))
+	req.Phone = strings.TrimSpace(req.Phone)
+	req.Address = strings.TrimSpace(req.Address)
+
+	if req.Name == "" {
+		respondError(c, newValidationError("İsim boş olamaz"))
+		return
+	}
+
+	// İşletme adı sahibin kaydında tutulur ve çalışanlara kopyalanır
+	isOwner := user.OwnerID == nil
+	if !isOwner && req.BusinessName != user.BusinessName {
+		c.JSON(http.StatusForbidden, gin.H{"error": "İşletme adını yalnızca işletme sahibi değiştirebilir"})
+		return
+	}
+
+	if existing, err := h.getUserByEmail(req.Email); err == nil && existing.ID != user.ID {
+		respondError(c, newConflictError("Bu e-posta adresi zaten kullanılıyor"))
+		return
+	} else if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		respondError(c, err)
+		return
+	}
+
+	if err := h.saveProfile(user.ID, isOwner, req); err != nil {
+		// Kontrolden sonra aynı adresi alan başka bir kayıt olabilir
+		if database.IsUniqueViolation(err) {
+			err = newConflictError("Bu e-posta adresi zaten kullanılıyor")
+		}
+		respondError(c, err)
+		return
+	}
+
+	user, err = h.getUserByID(user.ID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Profil başarıyla güncellendi",
+		"data":    user,
+	})
+}
+
+func (h *Handler) saveProfile(userID int, isOwner bool, req updateProfileRequest) error {
+	tx, err := h.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		UPDATE users SET name = ?, business_name = ?, email = ?, phone = ?, address = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`, req.Name, req.BusinessName, req.Email, req.Phone, req.Address, userID)
+	if err != nil {
+		return err
+	}
+
+	if isOwner {
+		_, err = tx.Exec(`
+			UPDATE users SET business_name = ?, updated_at = CURRENT_TIMESTAMP
+			WHERE owner_id = ? AND COALESCE(business_name, '') <> ?
+		`, req.BusinessName, userID, req.BusinessName)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Şifre Değiştir; başarılı olursa kullanıcının diğer oturumları kapatılır
+func (h *Handler) ChangePassword(c *gin.Context) {
+	var req changePasswordRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.getUserByID(middleware.UserID(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.CurrentPassword)) != nil {
+		respondError(c, newValidationError("Mevcut şifre hatalı"))
+		return
+	}
+	if req.NewPassword != req.ConfirmPassword {
+		respondError(c, newValidationError("Yeni şifreler eşleşmiyor"))
+		return
+	}
+	if req.NewPassword == req.CurrentPassword {
+		respondError(c, newValidationError("Yeni şifre mevcut şifreyle aynı olamaz"))
+		return
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	_, err = h.db.Exec("UPDATE users SET password_hash = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?", string(hash), user.ID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	token, _ := c.Cookie(middleware.SessionCookieName)
+	if err := h.db.DeleteUserSessions(user.ID, token); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Şifreniz değiştirildi. Diğer cihazlardaki oturumlarınız kapatıldı.",
+	})
+}
+
+// Profil fotoğrafı; yüklenmemişse varsayılan görsele yönlendirir
+func (h *Handler) Avatar(c *gin.Context) {
+	avatar, err := h.db.UserAvatar(middleware.UserID(c))
+	if errors.Is(err, database.ErrAvatarNotFound) {
+		c.Redirect(http.StatusFound, defaultAvatar)
+		return
+	}
+	if err != nil {
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Header("Content-Type", avatar.ContentType)
+	c.Header("Cache-Control", "private, no-cache")
+	http.ServeContent(c.Writer, c.Request, "", avatar.UpdatedAt, bytes.NewReader(avatar.Data))
+}
+
+// Profil fotoğrafı yükle
+func (h *Handler) UploadAvatar(c *gin.Context) {
+	file, err := c.FormFile("avatar")
+	if err != nil {
+		respondError(c, newValidationError("Profil fotoğrafı seçilmedi"))
+		return
+	}
+	if file.Size > maxAvatarSize {
+		respondError(c, newValidationError("Profil fotoğrafı en fazla 2 MB olabilir"))
+		return
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, maxAvatarSize+1))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if len(data) > maxAvatarSize {
+		respondError(c, newValidationError("Profil fotoğrafı en fazla 2 MB olabilir"))
+		return
+	}
+
+	contentType, err := avatarContentType(data)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if err := h.db.SaveUserAvatar(middleware.UserID(c), contentType, data); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Profil fotoğrafı güncellendi"})
+}
+
+// Profil fotoğrafını kaldır
+func (h *Handler) DeleteAvatar(c *gin.Context) {
+	if err := h.db.DeleteUserAvatar(middleware.UserID(c)); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Profil fotoğrafı kaldırıldı"})
+}
+
+// avatarContentType dosyanın gerçekten bir PNG ya da JPEG görseli olduğunu doğrular
+func avatarContentType(data []byte) (string, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || (format != "png" && format != "jpeg") {
+		return "", newValidationError("Profil fotoğrafı PNG ya da JPEG olmalıdır")
+	}
+	if config.Width > 4096 || config.Height > 4096 {
+		return "", newValidationError("Profil fotoğrafı en fazla 4096x4096 piksel olabilir")
+	}
+	return "image/" + format, nil
+}
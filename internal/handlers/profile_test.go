@@ -0,0 +1,266 @@
+package handlers
+
+import (
+	"bytes"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/database/dbtest"
+	"github.com/umutaraz/tradesman-app/internal/middleware"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// profileTestRouter profil uçlarını gerçek oturum doğrulamasıyla çalıştırır
+func profileTestRouter(db *database.DB) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := testHandler(db)
+
+	r := gin.New()
+	g := r.Group("/", middleware.Auth(db, func(int) time.Duration { return 0 }))
+	g.POST("/profile", h.UpdateProfile)
+	g.POST("/profile/password", h.ChangePassword)
+	g.GET("/profile/avatar", h.Avatar)
+	g.POST("/profile/avatar", h.UploadAvatar)
+	g.DELETE("/profile/avatar", h.DeleteAvatar)
+	return r
+}
+
+// staffUser işletmeye bağlı bir çalışan ekler
+func staffUser(t *testing.T, db *database.DB, ownerID int, email string) int {
+	t.Helper()
+	result, err := db.Exec(`
+		INSERT INTO users (name, email, password_hash, role, owner_id, business_name)
+		SELECT ?, ?, '', 'staff', id, business_name FROM users WHERE id = ?
+	`, email, email, ownerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, _ := result.LastInsertId()
+	return int(id)
+}
+
+func formRequest(t *testing.T, db *database.DB, userID int, path string, form url.Values) *http.Request {
+	t.Helper()
+	req := apiRequest(t, db, userID, http.MethodPost, path, form.Encode())
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+func TestUpdateProfile(t *testing.T) {
+	db := dbtest.New(t)
+	ownerID := dbtest.User(t, db, "sahip@example.com")
+	staffID := staffUser(t, db, ownerID, "calisan@example.com")
+	dbtest.User(t, db, "diger@example.com")
+	r := profileTestRouter(db)
+
+	tests := []struct {
+		name   string
+		userID int
+		form   url.Values
+		status int
+	}{
+		{"staff renames business", staffID, url.Values{"business_name": {"Yeni Ad"}}, http.StatusForbidden},
+		{"taken email", ownerID, url.Values{"email": {"Diger@Example.com"}}, http.StatusConflict},
+		{"blank name", ownerID, url.Values{"name": {"   "}}, http.StatusBadRequest},
+		{"invalid email", ownerID, url.Values{"email": {"sahip"}}, http.StatusBadRequest},
+		{"owner renames business", ownerID, url.Values{"business_name": {" Demir Tesisat "}}, http.StatusOK},
+		{"staff updates phone", staffID, url.Values{"phone": {"0533 111 22 33"}}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, formRequest(t, db, tt.userID, "/profile", tt.form))
+		if w.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d: %s", tt.name, w.Code, tt.status, w.Body)
+		}
+	}
+
+	// Gönderilmeyen alanlar değişmez; işletme adı çalışanlara kopyalanır
+	var email, business, phone string
+	if err := db.QueryRow("SELECT email, business_name, COALESCE(phone, '') FROM users WHERE id = ?", staffID).
+		Scan(&email, &business, &phone); err != nil {
+		t.Fatal(err)
+	}
+	if email != "calisan@example.com" || business != "Demir Tesisat" || phone != "0533 111 22 33" {
+		t.Errorf("staff = %s, %s, %s", email, business, phone)
+	}
+}
+
+// Ön kontrolden sonra aynı adresi alan kayıt UNIQUE kısıtıyla reddedilir ve çakışma olarak bildirilir
+func TestSaveProfileRejectsTakenEmail(t *testing.T) {
+	db := dbtest.New(t)
+	userID := dbtest.User(t, db, "sahip@example.com")
+	dbtest.User(t, db, "diger@example.com")
+
+	h := &Handler{db: db}
+	err := h.saveProfile(userID, true, updateProfileRequest{Name: "Sahip", Email: "diger@example.com"})
+	if !database.IsUniqueViolation(err) {
+		t.Fatalf("saveProfile with taken email: err = %v, want unique violation", err)
+	}
+
+	if err := h.saveProfile(userID, true, updateProfileRequest{Name: "Sahip", Email: "yeni@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	db := dbtest.New(t)
+	userID := dbtest.User(t, db, "sahip@example.com")
+	hash, err := bcrypt.GenerateFromPassword([]byte("eski-sifre"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("UPDATE users SET password_hash = ? WHERE id = ?", string(hash), userID); err != nil {
+		t.Fatal(err)
+	}
+	otherDevice, _, err := db.CreateSession(userID, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := profileTestRouter(db)
+
+	form := func(current, next, confirm string) url.Values {
+		return url.Values{"current_password": {current}, "new_password": {next}, "confirm_password": {confirm}}
+	}
+	tests := []struct {
+		name   string
+		form   url.Values
+		status int
+	}{
+		{"wrong current password", form("yanlis", "yeni-sifre", "yeni-sifre"), http.StatusBadRequest},
+		{"mismatch", form("eski-sifre", "yeni-sifre", "yeni-sifre2"), http.StatusBadRequest},
+		{"too short", form("eski-sifre", "kisa", "kisa"), http.StatusBadRequest},
+		{"unchanged", form("eski-sifre", "eski-sifre", "eski-sifre"), http.StatusBadRequest},
+		{"changed", form("eski-sifre", "yeni-sifre", "yeni-sifre"), http.StatusOK},
+	}
+	var current *http.Request
+	for _, tt := range tests {
+		current = formRequest(t, db, userID, "/profile/password", tt.form)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, current)
+		if w.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d: %s", tt.name, w.Code, tt.status, w.Body)
+		}
+	}
+
+	var stored string
+	if err := db.QueryRow("SELECT password_hash FROM users WHERE id = ?", userID).Scan(&stored); err != nil {
+		t.Fatal(err)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(stored), []byte("yeni-sifre")) != nil {
+		t.Error("new password does not match the stored hash")
+	}
+
+	// Diğer cihazdaki oturum kapanır, şifreyi değiştiren oturum açık kalır
+	if _, err := db.SessionUser(otherDevice); err == nil {
+		t.Error("session on another device is still open")
+	}
+	cookie, err := current.Cookie(middleware.SessionCookieName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.SessionUser(cookie.Value); err != nil {
+		t.Errorf("current session: %v", err)
+	}
+}
+
+func encodeImage(t *testing.T, format string, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case "png":
+		err = png.Encode(&buf, img)
+	case "jpeg":
+		err = jpeg.Encode(&buf, img, nil)
+	case "gif":
+		err = gif.Encode(&buf, img, nil)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestAvatarContentType(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"png", encodeImage(t, "png", 64, 64), "image/png"},
+		{"jpeg", encodeImage(t, "jpeg", 64, 64), "image/jpeg"},
+		{"gif", encodeImage(t, "gif", 64, 64), ""},
+		{"too large", encodeImage(t, "png", 4097, 1), ""},
+		{"not an image", []byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"), ""},
+	}
+	for _, tt := range tests {
+		got, err := avatarContentType(tt.data)
+		if got != tt.want || (tt.want == "") != (err != nil) {
+			t.Errorf("%s: avatarContentType = %q, %v; want %q", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestAvatarUploadAndDelete(t *testing.T) {
+	db := dbtest.New(t)
+	userID := dbtest.User(t, db, "sahip@example.com")
+	r := profileTestRouter(db)
+
+	upload := func(data []byte) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		part, err := mw.CreateFormFile("avatar", "foto.png")
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write(data)
+		mw.Close()
+		req := apiRequest(t, db, userID, http.MethodPost, "/profile/avatar", body.String())
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	get := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, apiRequest(t, db, userID, http.MethodGet, "/profile/avatar", ""))
+		return w
+	}
+
+	if w := get(); w.Code != http.StatusFound || w.Header().Get("Location") != defaultAvatar {
+		t.Errorf("without avatar: %d %s", w.Code, w.Header().Get("Location"))
+	}
+	if w := upload([]byte("metin dosyası")); w.Code != http.StatusBadRequest {
+		t.Errorf("upload text: status = %d", w.Code)
+	}
+
+	photo := encodeImage(t, "png", 32, 32)
+	if w := upload(photo); w.Code != http.StatusOK {
+		t.Fatalf("upload png: status = %d: %s", w.Code, w.Body)
+	}
+	w := get()
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "image/png" || !bytes.Equal(w.Body.Bytes(), photo) {
+		t.Errorf("avatar = %d %s, %d bytes", w.Code, w.Header().Get("Content-Type"), w.Body.Len())
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, apiRequest(t, db, userID, http.MethodDelete, "/profile/avatar", ""))
+	if w.Code != http.StatusOK {
+		t.Fatalf("delete: status = %d", w.Code)
+	}
+	if w := get(); !strings.HasSuffix(w.Header().Get("Location"), "blank.png") {
+		t.Errorf("after delete: %d %s", w.Code, w.Header().Get("Location"))
+	}
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umutaraz/tradesman-app/internal/middleware"
+	"github.com/umutaraz/tradesman-app/internal/receivables"
+)
+
+// recentNoticeLimit raporlar sayfasında ve API'de varsayılan olarak listelenen hatırlatma sayısı
+const recentNoticeLimit = 20
+
+// Raporlar
+func (h *Handler) Reports(c *gin.Context) {
+	businessID := middleware.BusinessID(c)
+	asOf, err := reportDate(c.Query("as_of"))
+	if err != nil {
+		c.HTML(http.StatusBadRequest, "error.html", gin.H{"error": err.Error()})
+		return
+	}
+
+	aging, err := receivables.Aging(h.db, businessID, asOf)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"error": err.Error()})
+		return
+	}
+
+	notices, err := receivables.RecentNotices(h.db, businessID, recentNoticeLimit)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"error": err.Error()})
+		return
+	}
+
+	from, to, err := reportPeriod(c.Query("from"), c.Query("to"))
+	if err != nil {
+		c.HTML(http.StatusBadRequest, "error.html", gin.H{"error": err.Error()})
+		return
+	}
+	financial, err := h.financialSummary(businessID, from, to)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"error": err.Error()})
+		return
+	}
+
+	h.render(c, "reports.html", gin.H{
+		"aging":     aging,
+		"notices":   notices,
+		"financial": financial,
+		"title":     "Raporlar - Esnaf Yönetim Sistemi",
+		"active":    "reports",
+	})
+}
+
+// GetAgingReportAPI açık faturaların gecikme dilimlerine göre dağılımını döndürür
+func (h *Handler) GetAgingReportAPI(c *gin.Context) {
+	asOf, err := reportDate(c.Query("as_of"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	report, err := receivables.Aging(h.db, middleware.BusinessID(c), asOf)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GetFinancialReportAPI dönemin gelir, gider ve kârını ana para biriminde döndürür; from ve to
+// (dahil) YYYY-AA-GG biçimindedir, verilmezse içinde bulunulan ay kullanılır
+func (h *Handler) GetFinancialReportAPI(c *gin.Context) {
+	from, to, err := reportPeriod(c.Query("from"), c.Query("to"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	summary, err := h.financialSummary(middleware.BusinessID(c), from, to)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// reportPeriod rapor dönemini [from, to) aralığına çevirir; to günü döneme dahildir
+func reportPeriod(fromValue, toValue string) (time.Time, time.Time, error) {
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	to := from.AddDate(0, 1, 0)
+
+	if fromValue != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", fromValue, time.Local)
+		if err != nil {
+			return from, to, newValidationError("Geçersiz başlangıç tarihi, YYYY-AA-GG biçiminde olmalıdır")
+		}
+		from = parsed
+	}
+	if toValue != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", toValue, time.Local)
+		if err != nil {
+			return from, to, newValidationError("Geçersiz bitiş tarihi, YYYY-AA-GG biçiminde olmalıdır")
+		}
+		to = parsed.AddDate(0, 0, 1)
+	}
+	if !from.Before(to) {
+		return from, to, newValidationError("Bitiş tarihi başlangıç tarihinden önce olamaz")
+	}
+
+	return from, to, nil
+}
+
+// GetDunningNoticesAPI son gönderilen ödeme hatırlatmalarını döndürür
+func (h *Handler) GetDunningNoticesAPI(c *gin.Context) {
+	limit := recentNoticeLimit
+	if value := c.Query("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			respondError(c, newValidationError("Geçersiz limit"))
+			return
+		}
+		limit = n
+	}
+
+	notices, err := receivables.RecentNotices(h.db, middleware.BusinessID(c), limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, notices)
+}
+
+// reportDate raporun hesaplanacağı günü okur; boşsa bugün kullanılır
+func reportDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Now(), nil
+	}
+
+	asOf, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, newValidationError("Geçersiz rapor tarihi, YYYY-AA-GG biçiminde olmalıdır")
+	}
+	return asOf, nil
+}
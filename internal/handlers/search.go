@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umutaraz/tradesman-app/internal/middleware"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/permissions"
+)
+
+// Genel arama sonuç sayısı sınırları
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 50
+)
+
+// searchTypes arama sonuç türleri, görüntülemek için gereken yetki ve detay sayfası
+var searchTypes = []struct {
+	kind string
+	perm permissions.Permission
+	url  string
+}{
+	{models.SearchCustomer, permissions.ManageCustomers, "/customers/detail/%d"},
+	{models.SearchProduct, permissions.ViewProducts, "/products/detail/%d"},
+	{models.SearchOrder, permissions.CreateOrders, "/orders/detail/%d"},
+	{models.SearchInvoice, permissions.ManageInvoices, "/invoices/%d"},
+}
+
+// Search müşteri, ürün, sipariş ve faturalarda arama yapar; kullanıcı yalnızca görüntüleme
+// yetkisi olan türlerdeki sonuçları görür. type parametresi virgülle ayrılmış türlerle sonucu daraltır.
+func (h *Handler) Search(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		respondError(c, newValidationError("Arama metni boş olamaz"))
+		return
+	}
+
+	limit := defaultSearchLimit
+	if value := c.Query("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			respondError(c, newValidationError("Geçersiz limit"))
+			return
+		}
+		if n < maxSearchLimit {
+			limit = n
+		} else {
+			limit = maxSearchLimit
+		}
+	}
+
+	requested := make(map[string]bool)
+	if value := c.Query("type"); value != "" {
+		for _, kind := range strings.Split(value, ",") {
+			requested[strings.TrimSpace(kind)] = true
+		}
+	}
+
+	var kinds []string
+	urls := make(map[string]string)
+	for _, t := range searchTypes {
+		if len(requested) > 0 && !requested[t.kind] {
+			continue
+		}
+		if middleware.Can(c, t.perm) {
+			kinds = append(kinds, t.kind)
+			urls[t.kind] = t.url
+		}
+	}
+	if len(kinds) == 0 {
+		c.JSON(http.StatusOK, []models.SearchResult{})
+		return
+	}
+
+	results, err := h.db.Search(middleware.BusinessID(c), query, kinds, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	for i := range results {
+		results[i].URL = fmt.Sprintf(urls[results[i].Type], results[i].ID)
+	}
+
+	c.JSON(http.StatusOK, results)
+}
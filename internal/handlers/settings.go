@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umutaraz/tradesman-app/internal/backup"
+	"github.com/umutaraz/tradesman-app/internal/listquery"
+	"github.com/umutaraz/tradesman-app/internal/messaging"
+	"github.com/umutaraz/tradesman-app/internal/middleware"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/money"
+	"github.com/umutaraz/tradesman-app/internal/settings"
+)
+
+// render sayfayı işletmenin ayarlarıyla birlikte çizer; şablonlar tarih biçimi, tema ve dashboard
+// kartları için .settings'i kullanır
+func (h *Handler) render(c *gin.Context, name string, data gin.H) {
+	s, err := h.settings.Get(middleware.BusinessID(c))
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"error": err.Error()})
+		return
+	}
+
+	data["settings"] = s
+	c.HTML(http.StatusOK, name, data)
+}
+
+// IdleTimeout işletmenin otomatik çıkış süresini döndürür; oturum doğrulamasında kullanılır
+func (h *Handler) IdleTimeout(businessID int) time.Duration {
+	s, err := h.settings.Get(businessID)
+	if err != nil {
+		return 0
+	}
+	return s.AutoLogout()
+}
+
+// defaultTaxRate oranı verilmemiş fatura satırlarına uygulanan KDV oranı
+func (h *Handler) defaultTaxRate(businessID int) (int, error) {
+	s, err := h.settings.Get(businessID)
+	if err != nil {
+		return 0, err
+	}
+	return s.TaxRate, nil
+}
+
+// Ayarlar
+func (h *Handler) Settings(c *gin.Context) {
+	users, err := h.getBusinessUsers(middleware.BusinessID(c))
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"error": err.Error()})
+		return
+	}
+
+	messageTemplates, err := h.getMessageTemplates(middleware.BusinessID(c))
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"error": err.Error()})
+		return
+	}
+
+	// Yedekler ve yedekleme ayarları yalnızca sistem yöneticilerine gösterilir
+	systemAdmin, err := h.isSystemAdmin(c)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"error": err.Error()})
+		return
+	}
+	var backups []backup.Info
+	if systemAdmin {
+		if backups, err = h.backups.List(); err != nil {
+			c.HTML(http.StatusInternalServerError, "error.html", gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	h.render(c, "settings.html", gin.H{
+		"users":        users,
+		"systemAdmin":  systemAdmin,
+		"backups":      backups,
+		"currencies":   money.Currencies,
+		"kdvRates":     models.KDVRates,
+		"pageSizes":    listquery.PageSizes,
+		"templates":    messageTemplates,
+		"placeholders": messaging.Placeholders,
+		"title":        "Ayarlar - Esnaf Yönetim Sistemi",
+		"active":       "settings",
+	})
+}
+
+// Ayarlar (API)
+func (h *Handler) GetSettingsAPI(c *gin.Context) {
+	s, err := h.settings.Get(middleware.BusinessID(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, s)
+}
+
+// Ayarları güncelle (API); gönderilmeyen alanlar değişmez
+func (h *Handler) UpdateSettingsAPI(c *gin.Context) {
+	businessID := middleware.BusinessID(c)
+	s, err := h.settings.Get(businessID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if err := json.NewDecoder(c.Request.Body).Decode(&s); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := s.Validate(); err != nil {
+		respondError(c, newValidationError(err.Error()))
+		return
+	}
+	if err := h.settings.Save(businessID, s); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, s)
+}
+
+// Genel ayarları kaydet (form)
+func (h *Handler) UpdateGeneralSettings(c *gin.Context) {
+	h.saveSettingsForm(c, func(s *settings.Settings) string {
+		// Cari hareket ya da kesilmiş fatura varsa ana para birimi değişmez; Save ErrCurrencyLocked döndürür
+		s.Currency = money.Currency(c.PostForm("currency"))
+		s.DateFormat = c.PostForm("date_format")
+
+		var ok bool
+		if s.TaxRate, ok = formInt(c, "tax_rate"); !ok {
+			return "Geçersiz KDV oranı"
+		}
+		if s.LowStockLevel, ok = formInt(c, "low_stock_level"); !ok {
+			return "Düşük stok uyarı seviyesi sıfır veya pozitif bir sayı olmalıdır"
+		}
+		if s.AutoLogoutMinutes, ok = formInt(c, "auto_logout_minutes"); !ok {
+			return "Otomatik çıkış süresi dakika cinsinden bir sayı olmalıdır"
+		}
+		return ""
+	})
+}
+
+// Bildirim ayarlarını kaydet (form)
+func (h *Handler) UpdateNotificationSettings(c *gin.Context) {
+	h.saveSettingsForm(c, func(s *settings.Settings) string {
+		s.DunningStages = c.PostForm("dunning_stages")
+		s.ReminderEmail = c.PostForm("reminder_email") != ""
+		s.ReminderSMS = c.PostForm("reminder_sms") != ""
+		s.DunningSMS = c.PostForm("dunning_sms") != ""
+		return ""
+	})
+}
+
+// Görünüm ayarlarını kaydet (form)
+func (h *Handler) UpdateAppearanceSettings(c *gin.Context) {
+	h.saveSettingsForm(c, func(s *settings.Settings) string {
+		s.ThemeMode = c.PostForm("theme_mode")
+		s.ThemeColor = c.PostForm("theme_color")
+		s.DashboardCards = c.PostFormArray("dashboard_cards")
+
+		var ok bool
+		if s.PageSize, ok = formInt(c, "page_size"); !ok {
+			return "Geçersiz sayfa başına öğe sayısı"
+		}
+		return ""
+	})
+}
+
+// Yedekleme ayarlarını kaydet (form); otomatik yedekler sistem yöneticisinin ayarlarıyla alınır
+func (h *Handler) UpdateBackupSettings(c *gin.Context) {
+	admin, err := h.isSystemAdmin(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	if !admin {
+		c.JSON(http.StatusForbidden, gin.H{"success": false, "message": "Yedekleme ayarlarını yalnızca sistem yöneticisi değiştirebilir"})
+		return
+	}
+
+	h.saveSettingsForm(c, func(s *settings.Settings) string {
+		s.BackupEnabled = c.PostForm("backup_enabled") != ""
+		s.BackupFrequency = c.PostForm("backup_frequency")
+
+		var ok bool
+		if s.BackupKeep, ok = formInt(c, "backup_keep"); !ok {
+			return "Maksimum yedek sayısı bir sayı olmalıdır"
+		}
+		return ""
+	})
+}
+
+// saveSettingsForm ayar formunun alanlarını mevcut ayarların üzerine uygular ve kaydeder; apply
+// boş olmayan bir hata mesajı döndürürse ayarlar kaydedilmez
+func (h *Handler) saveSettingsForm(c *gin.Context, apply func(s *settings.Settings) string) {
+	businessID := middleware.BusinessID(c)
+	s, err := h.settings.Get(businessID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+
+	if message := apply(&s); message != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": message})
+		return
+	}
+	if err := s.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	if err := h.settings.Save(businessID, s); err != nil {
+		status, message := errorResponse(err)
+		c.JSON(status, gin.H{"success": false, "message": message})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Ayarlar kaydedildi"})
+}
+
+// formInt form alanını tam sayı olarak okur
+func formInt(c *gin.Context, name string) (int, bool) {
+	n, err := strconv.Atoi(c.PostForm(name))
+	return n, err == nil
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umutaraz/tradesman-app/internal/middleware"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/repository"
+)
+
+// Gelir/gider listesi (API)
+func (h *Handler) GetTransactionsAPI(c *gin.Context) {
+	q, err := h.parseListQuery(c, repository.TransactionList)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	transactions, page, err := h.transactions.List(middleware.BusinessID(c), q)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"transactions": transactions, "pagination": page})
+}
+
+// Gelir/gider detayı (API)
+func (h *Handler) GetTransactionAPI(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	transaction, err := h.transactions.Get(middleware.BusinessID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, transaction)
+}
+
+// Gelir/gider kaydı ekle
+func (h *Handler) CreateTransaction(c *gin.Context) {
+	var transaction models.Transaction
+	if err := c.ShouldBindJSON(&transaction); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if transaction.TransactionDate.IsZero() {
+		transaction.TransactionDate = time.Now()
+	}
+
+	businessID := middleware.BusinessID(c)
+	currency, err := h.resolveCurrency(businessID, transaction.Currency)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	transaction.Currency = currency
+	transaction.UserID = businessID
+	id, err := h.transactions.Create(&transaction)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	created, err := h.transactions.Get(businessID, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// Gelir/gider kaydını güncelle
+func (h *Handler) UpdateTransaction(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	var transaction models.Transaction
+	if err := c.ShouldBindJSON(&transaction); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	businessID := middleware.BusinessID(c)
+	existing, err := h.transactions.Get(businessID, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if transaction.TransactionDate.IsZero() {
+		transaction.TransactionDate = existing.TransactionDate
+	}
+	// Para birimi verilmezse değişmez
+	if transaction.Currency == "" {
+		transaction.Currency = existing.Currency
+	}
+	if transaction.Currency, err = h.resolveCurrency(businessID, transaction.Currency); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if err := h.transactions.Update(businessID, id, &transaction); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	updated, err := h.transactions.Get(businessID, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// Gelir/gider kaydını sil
+func (h *Handler) DeleteTransaction(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.transactions.Delete(middleware.BusinessID(c), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
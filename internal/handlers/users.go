@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umutaraz/tradesman-app/internal/middleware"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/permissions"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const userColumns = `id, owner_id, name, email, password_hash, role, business_name, phone, address,
+	last_login_at, created_at, updated_at`
+
+// rowScanner *sql.Row ve *sql.Rows için ortak arayüz
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+type createUserRequest struct {
+	Name     string `form:"name" json:"name" binding:"required"`
+	Email    string `form:"email" json:"email" binding:"required,email"`
+	Password string `form:"password" json:"password" binding:"required,min=6"`
+	Role     string `form:"role" json:"role" binding:"required"`
+}
+
+type updateUserRoleRequest struct {
+	Role string `form:"role" json:"role" binding:"required"`
+}
+
+// Çalışan listesi
+func (h *Handler) GetUsersAPI(c *gin.Context) {
+	users, err := h.getBusinessUsers(middleware.BusinessID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, users)
+}
+
+// Yeni çalışan ekle
+func (h *Handler) CreateUser(c *gin.Context) {
+	var req createUserRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	actor := middleware.CurrentUser(c)
+	if !permissions.CanAssign(actor.EffectiveRole(), req.Role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Bu rolü atama yetkiniz yok"})
+		return
+	}
+
+	if existing, err := h.getUserByEmail(req.Email); err == nil && existing != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Bu e-posta adresi zaten kullanılıyor"})
+		return
+	} else if err != nil && err != sql.ErrNoRows {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Çalışanlar işletme sahibinin verilerini paylaşır
+	ownerID := actor.BusinessID()
+	owner, err := h.getUserByID(ownerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.db.Exec(`
+		INSERT INTO users (owner_id, name, email, password_hash, role, business_name)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, ownerID, strings.TrimSpace(req.Name), strings.ToLower(strings.TrimSpace(req.Email)), string(hash), req.Role, owner.BusinessName)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.getUserByID(int(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Kullanıcı başarıyla eklendi",
+		"data":    user,
+	})
+}
+
+// Çalışanın rolünü güncelle
+func (h *Handler) UpdateUserRole(c *gin.Context) {
+	var req updateUserRoleRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	actor := middleware.CurrentUser(c)
+	staff, ok := h.loadStaffMember(c, actor)
+	if !ok {
+		return
+	}
+
+	if !permissions.CanAssign(actor.EffectiveRole(), staff.Role) ||
+		!permissions.CanAssign(actor.EffectiveRole(), req.Role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Bu rolü atama yetkiniz yok"})
+		return
+	}
+
+	_, err := h.db.Exec("UPDATE users SET role = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?", req.Role, staff.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	staff.Role = req.Role
+	c.JSON(http.StatusOK, staff)
+}
+
+// Çalışanı sil
+func (h *Handler) DeleteUser(c *gin.Context) {
+	actor := middleware.CurrentUser(c)
+	staff, ok := h.loadStaffMember(c, actor)
+	if !ok {
+		return
+	}
+
+	if !permissions.CanAssign(actor.EffectiveRole(), staff.Role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Bu kullanıcıyı silme yetkiniz yok"})
+		return
+	}
+
+	if _, err := h.db.Exec("DELETE FROM sessions WHERE user_id = ?", staff.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.db.DeleteUserAvatar(staff.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if _, err := h.db.Exec("DELETE FROM users WHERE id = ?", staff.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// loadStaffMember URL'deki kullanıcının aynı işletmeye bağlı bir çalışan olduğunu doğrular
+func (h *Handler) loadStaffMember(c *gin.Context, actor *models.User) (*models.User, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz kullanıcı ID"})
+		return nil, false
+	}
+
+	if id == actor.ID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Kendi hesabınız üzerinde bu işlemi yapamazsınız"})
+		return nil, false
+	}
+
+	staff, err := h.getUserByID(id)
+	if err != nil || staff.OwnerID == nil || *staff.OwnerID != actor.BusinessID() {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Kullanıcı bulunamadı"})
+		return nil, false
+	}
+
+	return staff, true
+}
+
+// İşletme sahibi ve çalışanlarını getir
+func (h *Handler) getBusinessUsers(businessID int) ([]models.User, error) {
+	rows, err := h.db.Query("SELECT "+userColumns+" FROM users WHERE id = ? OR owner_id = ? ORDER BY id", businessID, businessID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, *user)
+	}
+
+	return users, rows.Err()
+}
+
+func (h *Handler) getUserByID(id int) (*models.User, error) {
+	return scanUser(h.db.QueryRow("SELECT "+userColumns+" FROM users WHERE id = ?", id))
+}
+
+func (h *Handler) getUserByEmail(email string) (*models.User, error) {
+	return scanUser(h.db.QueryRow("SELECT "+userColumns+" FROM users WHERE lower(email) = lower(?)", strings.TrimSpace(email)))
+}
+
+func scanUser(rs rowScanner) (*models.User, error) {
+	var user models.User
+	var ownerID sql.NullInt64
+	var businessName, phone, address sql.NullString
+	var lastLoginAt sql.NullTime
+	err := rs.Scan(&user.ID, &ownerID, &user.Name, &user.Email, &user.PasswordHash, &user.Role,
+		&businessName, &phone, &address, &lastLoginAt, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if ownerID.Valid {
+		id := int(ownerID.Int64)
+		user.OwnerID = &id
+	}
+	if lastLoginAt.Valid {
+		user.LastLoginAt = &lastLoginAt.Time
+	}
+	user.BusinessName = businessName.String
+	user.Phone = phone.String
+	user.Address = address.String
+
+	return &user, nil
+}
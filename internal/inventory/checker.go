@@ -0,0 +1,145 @@
+// Package inventory stok seviyelerini izler ve eşiğin altına inen ürünler için bildirim üretir.
+package inventory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/notify"
+	"github.com/umutaraz/tradesman-app/internal/permissions"
+)
+
+// Checker ürün stoklarını düzenli aralıklarla kontrol eder. Bir ürün eşiğe indiğinde
+// stock_alerts tablosunda açık uyarı oluşturulur; stok eşiğin üzerine çıkana kadar aynı
+// ürün için yeniden bildirim üretilmez. Bildirimler ürünleri yönetebilen kullanıcılara gider.
+type Checker struct {
+	db       *database.DB
+	notifier *notify.Notifier
+	interval time.Duration
+}
+
+// NewChecker stok denetleyicisi oluşturur
+func NewChecker(db *database.DB, notifier *notify.Notifier, interval time.Duration) *Checker {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &Checker{db: db, notifier: notifier, interval: interval}
+}
+
+// Run bağlam iptal edilene kadar stokları denetler
+func (c *Checker) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := c.Check(); err != nil {
+			log.Printf("Stok denetimi hatası: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+type stockState struct {
+	productID int
+	userID    int
+	name      string
+	unit      string
+	stock     int
+	level     int
+	reorder   int
+	alertID   sql.NullInt64
+}
+
+// Check tüm işletmelerin stoklarını denetler ve yeni açılan uyarı sayısını döndürür
+func (c *Checker) Check() (int, error) {
+	rows, err := c.db.Query(`
+		SELECT products.id, products.user_id, products.name, products.unit, products.stock_quantity,
+		       ` + database.LowStockLevelExpr + `, products.reorder_quantity, a.id
+		FROM products
+		LEFT JOIN stock_alerts a ON a.product_id = products.id AND a.resolved_at IS NULL
+		WHERE products.is_service = 0
+	`)
+	if err != nil {
+		return 0, err
+	}
+
+	var states []stockState
+	for rows.Next() {
+		var s stockState
+		if err := rows.Scan(&s.productID, &s.userID, &s.name, &s.unit, &s.stock, &s.level, &s.reorder,
+			&s.alertID); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		states = append(states, s)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	opened := 0
+	for _, s := range states {
+		low := s.stock <= s.level
+		switch {
+		case low && !s.alertID.Valid:
+			if err := c.openAlert(s); err != nil {
+				return opened, err
+			}
+			opened++
+		case !low && s.alertID.Valid:
+			// Stok yenilendi; bir sonraki düşüşte tekrar uyarı verilir
+			_, err := c.db.Exec("UPDATE stock_alerts SET resolved_at = ? WHERE id = ?", time.Now(), s.alertID.Int64)
+			if err != nil {
+				return opened, err
+			}
+		}
+	}
+
+	return opened, nil
+}
+
+// openAlert ürün için açık uyarı kaydı oluşturur ve yetkili kullanıcılara bildirim gönderir
+func (c *Checker) openAlert(s stockState) error {
+	_, err := c.db.Exec(`
+		INSERT INTO stock_alerts (user_id, product_id, stock_quantity, reorder_level, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, s.userID, s.productID, s.stock, s.level, time.Now())
+	if err != nil {
+		return err
+	}
+
+	severity := models.SeverityWarning
+	if s.stock <= 0 {
+		severity = models.SeverityDanger
+	}
+
+	return c.notifier.SendToBusiness(s.userID, permissions.ManageProducts, models.Notification{
+		Type:     models.NotificationStock,
+		Severity: severity,
+		Title:    "Düşük stok: " + s.name,
+		Message:  lowStockMessage(s),
+		Link:     fmt.Sprintf("/products/detail/%d", s.productID),
+	})
+}
+
+func lowStockMessage(s stockState) string {
+	message := fmt.Sprintf("%s stoğu %d %s kaldı (eşik: %d).", s.name, s.stock, s.unit, s.level)
+	if s.stock <= 0 {
+		message = fmt.Sprintf("%s stoğu tükendi (eşik: %d).", s.name, s.level)
+	}
+	if s.reorder > 0 {
+		message += fmt.Sprintf(" Önerilen sipariş miktarı: %d %s.", s.reorder, s.unit)
+	}
+	return message
+}
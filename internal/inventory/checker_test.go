@@ -0,0 +1,102 @@
+package inventory_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/database/dbtest"
+	"github.com/umutaraz/tradesman-app/internal/inventory"
+	"github.com/umutaraz/tradesman-app/internal/notify"
+)
+
+// messages işletme sahibine gönderilen bildirim mesajlarını sırasıyla döndürür
+func messages(t *testing.T, db *database.DB, userID int) []string {
+	t.Helper()
+	rows, err := db.Query("SELECT message FROM notifications WHERE user_id = ? ORDER BY id", userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var list []string
+	for rows.Next() {
+		var m string
+		if err := rows.Scan(&m); err != nil {
+			t.Fatal(err)
+		}
+		list = append(list, m)
+	}
+	return list
+}
+
+func TestCheckerAlertsOncePerLowStockPeriod(t *testing.T) {
+	db := dbtest.New(t)
+	userID := dbtest.User(t, db, "sahip@example.com")
+	if err := db.SetSetting(userID, database.SettingLowStockLevel, "5"); err != nil {
+		t.Fatal(err)
+	}
+
+	product := func(name string, stock int, reorderLevel interface{}, isService bool) int {
+		result, err := db.Exec(`
+			INSERT INTO products (user_id, name, price, stock_quantity, unit, reorder_level, reorder_quantity, is_service)
+			VALUES (?, ?, 10, ?, 'adet', ?, 20, ?)
+		`, userID, name, stock, reorderLevel, isService)
+		if err != nil {
+			t.Fatal(err)
+		}
+		id, _ := result.LastInsertId()
+		return int(id)
+	}
+	priz := product("Priz", 2, 3, false)
+	product("Sigorta", 4, nil, false) // işletme eşiği (5) geçerli
+	product("Kablo", 50, nil, false)
+	product("Montaj", 0, nil, true)
+
+	checker := inventory.NewChecker(db, notify.New(db, notify.NewHub()), time.Minute)
+	opened, err := checker.Check()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opened != 2 {
+		t.Fatalf("opened = %d, want Priz and Sigorta", opened)
+	}
+	if got := messages(t, db, userID); len(got) != 2 ||
+		got[0] != "Priz stoğu 2 adet kaldı (eşik: 3). Önerilen sipariş miktarı: 20 adet." {
+		t.Errorf("notifications = %q", got)
+	}
+
+	// Açık uyarı varken tekrar bildirim üretilmez
+	if opened, err := checker.Check(); err != nil || opened != 0 {
+		t.Fatalf("second check: opened = %d, err = %v", opened, err)
+	}
+
+	// Stok yenilenince uyarı kapanır, tekrar düşünce yeni uyarı açılır
+	setStock := func(stock int) {
+		if _, err := db.Exec("UPDATE products SET stock_quantity = ? WHERE id = ?", stock, priz); err != nil {
+			t.Fatal(err)
+		}
+	}
+	setStock(10)
+	if opened, err := checker.Check(); err != nil || opened != 0 {
+		t.Fatalf("after restock: opened = %d, err = %v", opened, err)
+	}
+	setStock(0)
+	if opened, err := checker.Check(); err != nil || opened != 1 {
+		t.Fatalf("after running out: opened = %d, err = %v", opened, err)
+	}
+	if got := messages(t, db, userID); len(got) != 3 ||
+		got[2] != "Priz stoğu tükendi (eşik: 3). Önerilen sipariş miktarı: 20 adet." {
+		t.Errorf("notifications = %q", got)
+	}
+
+	var alerts, open int
+	err = db.QueryRow("SELECT COUNT(*), COUNT(*) - COUNT(resolved_at) FROM stock_alerts WHERE product_id = ?", priz).
+		Scan(&alerts, &open)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alerts != 2 || open != 1 {
+		t.Errorf("alerts = %d (open %d), want 2 (open 1)", alerts, open)
+	}
+}
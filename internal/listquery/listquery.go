@@ -0,0 +1,495 @@
+// Package listquery liste sayfaları ve API uçları için ortak sayfalama, sıralama ve süzme
+// parametrelerini çözümler. Desteklenen parametreler:
+//
+//	page=2, limit=25          sayfa numarası ve sayfa başına kayıt
+//	cursor=...                önceki yanıttaki next_cursor; verilirse page yok sayılır ve liste
+//	                          önceki sayfanın son kaydından (sıralama değeri, id) sonra devam eder
+//	sort=-created_at          alan adı, başında "-" varsa azalan sıralama
+//	status=pending,confirmed  alan süzgeci; virgülle ayrılmış değerlerden biri
+//	order_date_from=2024-01-01&order_date_to=2024-01-31
+//	                          tarih, sayı ve tutar alanlarında aralık (iki uç dahil)
+//	price_from=12.50          tutarlar lira cinsinden verilir, kuruşa çevrilerek karşılaştırılır
+//	q=kablo                   genel arama; koşulu veritabanına göre depo ekler (bkz. Query.Search)
+//
+// Yalnızca Spec'te tanımlı alanlar sıralanabilir ve süzülebilir; kolon adları kullanıcı girdisinden
+// değil Spec'ten gelir, değerler her zaman parametre olarak bağlanır.
+package listquery
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/money"
+)
+
+// Sayfa boyutu sınırları
+const (
+	DefaultLimit = 25
+	MaxLimit     = 100
+)
+
+// PageSizes ayarlarda seçilebilen sayfa başına kayıt sayıları
+var PageSizes = []int{10, 25, 50, 100}
+
+// Kind alan değerinin türü; süzgeç değerlerinin nasıl çözümleneceğini belirler
+type Kind int
+
+const (
+	Text Kind = iota
+	Number
+	Money // Kuruş cinsinden saklanan tutar; süzgeç değeri lira cinsindendir
+	Date
+	Bool
+)
+
+// Field listede sıralanabilen ya da süzülebilen bir alan
+type Field struct {
+	Column string // SQL kolonu ya da ifadesi
+	Kind   Kind
+	Sort   bool // sort parametresinde kullanılabilir
+	Filter bool // alan=değer ve aralık süzgeçlerinde kullanılabilir
+}
+
+// Spec bir listenin alanları ve varsayılanları
+type Spec struct {
+	Fields      map[string]Field
+	DefaultSort string // Örn. "-created_at"
+	IDColumn    string // Eşit değerlerde sırayı sabitleyen kolon
+	SearchKind  string // q parametresinin arandığı dizin türü; boşsa q desteklenmez
+	// SearchColumns tam metin dizini olmayan veritabanlarında q'nun aranacağı kolonlar
+	SearchColumns []string
+}
+
+// Error geçersiz liste parametresi
+type Error struct {
+	message string
+}
+
+func (e *Error) Error() string { return e.message }
+
+func errorf(format string, args ...interface{}) error {
+	return &Error{message: fmt.Sprintf(format, args...)}
+}
+
+// IsError hatanın geçersiz parametreden kaynaklanıp kaynaklanmadığını belirtir
+func IsError(err error) bool {
+	var e *Error
+	return errors.As(err, &e)
+}
+
+// Query çözümlenmiş liste parametreleri
+type Query struct {
+	spec      Spec
+	page      int
+	limit     int
+	sort      string
+	desc      bool
+	conds     []string
+	args      []interface{}
+	search    string
+	signature string
+	values    url.Values
+	after     *cursor // İmleçle istenen sayfada önceki sayfanın son kaydı
+}
+
+// Page yanıtla birlikte döndürülen sayfalama bilgisi
+type Page struct {
+	Total      int    `json:"total"`
+	Page       int    `json:"page"`
+	Limit      int    `json:"limit"`
+	Pages      int    `json:"pages"`
+	Sort       string `json:"sort"`
+	NextCursor string `json:"next_cursor,omitempty"`
+
+	values url.Values // Sayfa bağlantılarında korunacak parametreler
+}
+
+// HasPrev önceki sayfanın olup olmadığını belirtir
+func (p Page) HasPrev() bool { return p.Page > 1 }
+
+// HasNext sonraki sayfanın olup olmadığını belirtir
+func (p Page) HasNext() bool { return p.Page < p.Pages }
+
+// From sayfadaki ilk kaydın sırası; liste boşsa 0
+func (p Page) From() int {
+	if p.Total == 0 {
+		return 0
+	}
+	return (p.Page-1)*p.Limit + 1
+}
+
+// To sayfadaki son kaydın sırası
+func (p Page) To() int {
+	if to := p.Page * p.Limit; to < p.Total {
+		return to
+	}
+	return p.Total
+}
+
+// Window sayfa bağlantılarında gösterilen, geçerli sayfanın çevresindeki sayfa numaraları
+func (p Page) Window() []int {
+	first, last := p.Page-2, p.Page+2
+	if first < 1 {
+		first = 1
+	}
+	if last > p.Pages {
+		last = p.Pages
+	}
+	var pages []int
+	for n := first; n <= last; n++ {
+		pages = append(pages, n)
+	}
+	return pages
+}
+
+// PrevURL önceki sayfanın sorgu dizesi
+func (p Page) PrevURL() string { return p.URL(p.Page - 1) }
+
+// NextURL sonraki sayfanın sorgu dizesi
+func (p Page) NextURL() string { return p.URL(p.Page + 1) }
+
+// URL aynı süzgeç ve sıralamayla verilen sayfanın sorgu dizesini döndürür
+func (p Page) URL(page int) string {
+	values := p.with()
+	values.Set("page", strconv.Itoa(page))
+	return "?" + values.Encode()
+}
+
+// SortURL alana göre sıralanmış ilk sayfanın sorgu dizesini döndürür; liste zaten bu alana göre
+// artan sıralıysa azalan sıralama bağlantısı verilir
+func (p Page) SortURL(field string) string {
+	values := p.with()
+	values.Del("page")
+	if p.Sort == field {
+		field = "-" + field
+	}
+	values.Set("sort", field)
+	return "?" + values.Encode()
+}
+
+// SortedBy listenin verilen alana göre sıralı olup olmadığını belirtir: "asc", "desc" ya da boş
+func (p Page) SortedBy(field string) string {
+	switch p.Sort {
+	case field:
+		return "asc"
+	case "-" + field:
+		return "desc"
+	}
+	return ""
+}
+
+// with sayfa bağlantıları için imleçsiz parametre kopyası
+func (p Page) with() url.Values {
+	values := url.Values{}
+	for key, v := range p.values {
+		if key != "cursor" {
+			values[key] = append([]string(nil), v...)
+		}
+	}
+	return values
+}
+
+// cursor sayfanın son kaydının sıralama değeri ve ID'si; sonraki sayfa bu kayıttan sonra başlar.
+// Sayfa numarası yalnızca yanıttaki sayfalama bilgisi için taşınır. Kayıt eklense ya da silinse de
+// sonraki sayfada kayıt atlanmaz ve tekrarlanmaz.
+type cursor struct {
+	Page      int         `json:"p"`
+	Signature string      `json:"s"`
+	Key       interface{} `json:"k"`
+	ID        int64       `json:"i"`
+}
+
+// reserved süzgeç olarak yorumlanmayan parametreler
+var reserved = map[string]bool{"page": true, "limit": true, "cursor": true, "sort": true, "q": true}
+
+// Parse URL parametrelerini spec'e göre çözümler. Tanımsız parametreler yok sayılır; böylece
+// sayfaya özel parametreler (örn. archived) aynı sorgu dizesinde kullanılabilir.
+func Parse(values url.Values, spec Spec, defaultLimit int) (*Query, error) {
+	q := &Query{spec: spec, page: 1, limit: defaultLimit, values: values}
+	if q.limit <= 0 || q.limit > MaxLimit {
+		q.limit = DefaultLimit
+	}
+
+	if value := values.Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			return nil, errorf("Geçersiz limit")
+		}
+		if n > MaxLimit {
+			n = MaxLimit
+		}
+		q.limit = n
+	}
+
+	sortValue := values.Get("sort")
+	if sortValue == "" {
+		sortValue = spec.DefaultSort
+	}
+	q.desc = strings.HasPrefix(sortValue, "-")
+	q.sort = strings.TrimPrefix(sortValue, "-")
+	if field, ok := spec.Fields[q.sort]; !ok || !field.Sort {
+		return nil, errorf("%s alanına göre sıralanamaz", q.sort)
+	}
+
+	if err := q.parseFilters(values); err != nil {
+		return nil, err
+	}
+	if spec.SearchKind != "" {
+		q.search = strings.TrimSpace(values.Get("q"))
+	}
+	q.signature = signature(values, q)
+
+	if value := values.Get("cursor"); value != "" {
+		c, err := decodeCursor(value)
+		if err != nil || c.Signature != q.signature || spec.IDColumn == "" {
+			return nil, errorf("Geçersiz imleç; sıralama ve süzgeçler imlecin alındığı sorguyla aynı olmalıdır")
+		}
+		q.page, q.after = c.Page, &c
+	} else if value := values.Get("page"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			return nil, errorf("Geçersiz sayfa numarası")
+		}
+		q.page = n
+	}
+
+	return q, nil
+}
+
+// parseFilters alan=değer ve alan_from/alan_to süzgeçlerini koşullara çevirir
+func (q *Query) parseFilters(values url.Values) error {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := strings.TrimSpace(values.Get(key))
+		if reserved[key] || value == "" {
+			continue
+		}
+
+		name, op := key, "="
+		if strings.HasSuffix(key, "_from") {
+			name, op = strings.TrimSuffix(key, "_from"), ">="
+		} else if strings.HasSuffix(key, "_to") {
+			name, op = strings.TrimSuffix(key, "_to"), "<="
+		}
+		field, ok := q.spec.Fields[name]
+		if !ok || !field.Filter {
+			continue
+		}
+
+		if op != "=" {
+			if field.Kind != Date && field.Kind != Number && field.Kind != Money {
+				return errorf("%s alanında aralık süzgeci kullanılamaz", name)
+			}
+			arg, err := parseValue(field.Kind, value)
+			if err != nil {
+				return errorf("%s için geçersiz değer: %s", key, value)
+			}
+			// Bitiş tarihi o günün tamamını kapsar
+			if field.Kind == Date && op == "<=" {
+				arg, op = arg.(time.Time).AddDate(0, 0, 1), "<"
+			}
+			q.conds = append(q.conds, fmt.Sprintf("%s %s ?", field.Column, op))
+			q.args = append(q.args, arg)
+			continue
+		}
+
+		var alternatives []string
+		for _, part := range strings.Split(value, ",") {
+			arg, err := parseValue(field.Kind, strings.TrimSpace(part))
+			if err != nil {
+				return errorf("%s için geçersiz değer: %s", name, part)
+			}
+			if field.Kind == Date {
+				// Tarih eşitliği gün bazında yapılır
+				day := arg.(time.Time)
+				alternatives = append(alternatives, fmt.Sprintf("(%s >= ? AND %s < ?)", field.Column, field.Column))
+				q.args = append(q.args, day, day.AddDate(0, 0, 1))
+				continue
+			}
+			alternatives = append(alternatives, fmt.Sprintf("%s = ?", field.Column))
+			q.args = append(q.args, arg)
+		}
+		q.conds = append(q.conds, "("+strings.Join(alternatives, " OR ")+")")
+	}
+
+	return nil
+}
+
+// parseValue süzgeç değerini alan türüne çevirir
+func parseValue(kind Kind, value string) (interface{}, error) {
+	switch kind {
+	case Number:
+		return strconv.ParseFloat(value, 64)
+	case Money:
+		amount, err := money.Parse(value, money.DefaultCurrency)
+		return amount.Minor(), err
+	case Date:
+		return time.ParseInLocation("2006-01-02", value, time.Local)
+	case Bool:
+		return strconv.ParseBool(value)
+	default:
+		return value, nil
+	}
+}
+
+// Search q parametresindeki arama metni; liste aramayı desteklemiyorsa boş döner.
+// Arama koşulu veritabanına özgü olduğundan Where'e dahil değildir.
+func (q *Query) Search() string { return q.search }
+
+// Spec sorgunun çözümlendiği liste tanımı
+func (q *Query) Spec() Spec { return q.spec }
+
+// Where süzgeç koşullarını AND ile birleştirir; süzgeç yoksa boş döner
+func (q *Query) Where() (string, []interface{}) {
+	return strings.Join(q.conds, " AND "), q.args
+}
+
+// HasCursor sayfanın imleçle istenip istenmediğini belirtir
+func (q *Query) HasCursor() bool { return q.after != nil }
+
+// After imleçle istenen sayfada önceki sayfanın son kaydından sonrakileri seçen koşulu döndürür;
+// imleç yoksa boş döner. SQLite NULL değerleri artan sıralamada başa, azalan sıralamada sona koyar.
+func (q *Query) After() (string, []interface{}) {
+	if q.after == nil {
+		return "", nil
+	}
+
+	column, id := q.spec.Fields[q.sort].Column, q.spec.IDColumn
+	op := ">"
+	if q.desc {
+		op = "<"
+	}
+	key := q.after.Key
+	switch {
+	case key == nil && q.desc:
+		return fmt.Sprintf("(%s IS NULL AND %s < ?)", column, id), []interface{}{q.after.ID}
+	case key == nil:
+		return fmt.Sprintf("((%s IS NULL AND %s > ?) OR %s IS NOT NULL)", column, id, column), []interface{}{q.after.ID}
+	case q.desc:
+		return fmt.Sprintf("(%s < ? OR (%s = ? AND %s < ?) OR %s IS NULL)", column, column, id, column),
+			[]interface{}{key, key, q.after.ID}
+	}
+	return fmt.Sprintf("(%s %s ? OR (%s = ? AND %s %s ?))", column, op, column, id, op), []interface{}{key, key, q.after.ID}
+}
+
+// KeyColumns sayfanın son kaydından imleç oluşturmak için seçilecek sıralama değeri ve ID ifadeleri.
+// Tekli artı işareti kolonun tanımlı tipini düşürür; böylece zamanlar sürücüde time.Time'a çevrilmeden
+// saklandıkları metin olarak okunur ve koşulda aynen karşılaştırılır.
+func (q *Query) KeyColumns() string {
+	return "+(" + q.spec.Fields[q.sort].Column + "), " + q.spec.IDColumn
+}
+
+// NextCursor son kaydının sıralama değeri key ve ID'si id olan sayfadan sonraki sayfanın imlecini döndürür
+func (q *Query) NextCursor(key interface{}, id int64) string {
+	if b, ok := key.([]byte); ok {
+		key = string(b)
+	}
+	return encodeCursor(cursor{Page: q.page + 1, Signature: q.signature, Key: key, ID: id})
+}
+
+// OrderBy sıralama ifadesini döndürür; eşit değerler ID'ye göre aynı yönde sıralanır
+func (q *Query) OrderBy() string {
+	dir := "ASC"
+	if q.desc {
+		dir = "DESC"
+	}
+	order := q.spec.Fields[q.sort].Column + " " + dir
+	if q.spec.IDColumn != "" {
+		order += ", " + q.spec.IDColumn + " " + dir
+	}
+	return order
+}
+
+// Limit sayfa başına kayıt sayısı
+func (q *Query) Limit() int { return q.limit }
+
+// Offset sayfanın ilk kaydının sırası; imleçle istenen sayfa After koşuluyla başladığından 0'dır
+func (q *Query) Offset() int {
+	if q.after != nil {
+		return 0
+	}
+	return (q.page - 1) * q.limit
+}
+
+// Page toplam kayıt sayısına göre sayfalama bilgisini oluşturur; NextCursor'ı sayfanın son kaydını
+// okuyan depo doldurur (bkz. NextCursor)
+func (q *Query) Page(total int) Page {
+	return Page{Total: total, Page: q.page, Limit: q.limit, Pages: (total + q.limit - 1) / q.limit, Sort: q.SortParam(),
+		values: q.values}
+}
+
+// SortParam sort parametresinin geçerli değeri
+func (q *Query) SortParam() string {
+	if q.desc {
+		return "-" + q.sort
+	}
+	return q.sort
+}
+
+// signature imlecin yalnızca aynı sıralama ve süzgeçlerle kullanılabilmesi için sorgunun özeti
+func signature(values url.Values, q *Query) string {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s|%d|", q.SortParam(), q.limit)
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if key != "page" && key != "cursor" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(h, "%s=%s&", key, strings.Join(values[key], ","))
+	}
+	return strconv.FormatUint(h.Sum64(), 36)
+}
+
+func encodeCursor(c cursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(value string) (cursor, error) {
+	var c cursor
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return c, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&c); err != nil {
+		return c, err
+	}
+	if c.Page <= 0 {
+		return c, errors.New("geçersiz sayfa")
+	}
+
+	// Tam sayılar kuruş ve sayaç kolonlarıyla kayıpsız karşılaştırılabilsin diye int64 olarak okunur
+	switch key := c.Key.(type) {
+	case nil, string:
+	case json.Number:
+		if n, err := key.Int64(); err == nil {
+			c.Key = n
+		} else if f, err := key.Float64(); err == nil {
+			c.Key = f
+		} else {
+			return c, err
+		}
+	default:
+		return c, errors.New("geçersiz sıralama değeri")
+	}
+	return c, nil
+}
@@ -0,0 +1,210 @@
+package listquery
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testSpec = Spec{
+	Fields: map[string]Field{
+		"name":       {Column: "p.name", Kind: Text, Sort: true, Filter: true},
+		"category":   {Column: "p.category", Kind: Text, Filter: true},
+		"price":      {Column: "p.price", Kind: Number, Sort: true, Filter: true},
+		"is_service": {Column: "p.is_service", Kind: Bool, Filter: true},
+		"created_at": {Column: "p.created_at", Kind: Date, Sort: true, Filter: true},
+		"secret":     {Column: "p.secret", Kind: Text},
+	},
+	DefaultSort: "-created_at",
+	IDColumn:    "p.id",
+}
+
+func parse(t *testing.T, query string) (*Query, error) {
+	t.Helper()
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Parse(values, testSpec, 0)
+}
+
+func TestParseFilters(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 5, d, 0, 0, 0, 0, time.Local) }
+	tests := []struct {
+		query     string
+		wantWhere string
+		wantArgs  []interface{}
+	}{
+		{"", "", nil},
+		{"category=Kablo", "(p.category = ?)", []interface{}{"Kablo"}},
+		{"category=Kablo,Priz", "(p.category = ? OR p.category = ?)", []interface{}{"Kablo", "Priz"}},
+		{"price_from=10&price_to=20.5", "p.price >= ? AND p.price <= ?", []interface{}{10.0, 20.5}},
+		{"is_service=true", "(p.is_service = ?)", []interface{}{true}},
+		// Bitiş tarihi günün tamamını kapsar, tarih eşitliği gün bazındadır
+		{"created_at_from=2026-05-01&created_at_to=2026-05-31", "p.created_at >= ? AND p.created_at < ?",
+			[]interface{}{day(1), day(32)}},
+		{"created_at=2026-05-02", "((p.created_at >= ? AND p.created_at < ?))", []interface{}{day(2), day(3)}},
+		// Tanımsız, süzülemeyen ve boş parametreler yok sayılır
+		{"archived=1&secret=x&name=&page=2&sort=name", "", nil},
+	}
+	for _, tt := range tests {
+		q, err := parse(t, tt.query)
+		if err != nil {
+			t.Errorf("%q: %v", tt.query, err)
+			continue
+		}
+		where, args := q.Where()
+		if where != tt.wantWhere || !reflect.DeepEqual(args, tt.wantArgs) {
+			t.Errorf("%q: where = %q %v, want %q %v", tt.query, where, args, tt.wantWhere, tt.wantArgs)
+		}
+	}
+}
+
+func TestParseRejectsInvalidParameters(t *testing.T) {
+	tests := []string{
+		"sort=secret",
+		"sort=-unknown",
+		"sort=category",
+		"limit=0",
+		"limit=abc",
+		"page=0",
+		"page=x",
+		"price=ucuz",
+		"created_at_from=01.05.2026",
+		"is_service=belki",
+		"name_from=a",
+	}
+	for _, query := range tests {
+		if _, err := parse(t, query); !IsError(err) {
+			t.Errorf("%q: err = %v, want parameter error", query, err)
+		}
+	}
+}
+
+func TestParseSortAndLimit(t *testing.T) {
+	tests := []struct {
+		query        string
+		defaultLimit int
+		wantOrder    string
+		wantLimit    int
+		wantOffset   int
+	}{
+		{"", 0, "p.created_at DESC, p.id DESC", DefaultLimit, 0},
+		{"sort=name&page=3", 10, "p.name ASC, p.id ASC", 10, 20},
+		{"sort=-price&limit=50", 10, "p.price DESC, p.id DESC", 50, 0},
+		{"limit=1000", 10, "p.created_at DESC, p.id DESC", MaxLimit, 0},
+		{"", 500, "p.created_at DESC, p.id DESC", DefaultLimit, 0},
+	}
+	for _, tt := range tests {
+		values, _ := url.ParseQuery(tt.query)
+		q, err := Parse(values, testSpec, tt.defaultLimit)
+		if err != nil {
+			t.Errorf("%q: %v", tt.query, err)
+			continue
+		}
+		if q.OrderBy() != tt.wantOrder || q.Limit() != tt.wantLimit || q.Offset() != tt.wantOffset {
+			t.Errorf("%q: order %q limit %d offset %d, want %q %d %d", tt.query, q.OrderBy(), q.Limit(), q.Offset(),
+				tt.wantOrder, tt.wantLimit, tt.wantOffset)
+		}
+	}
+}
+
+func TestCursorIsBoundToQuery(t *testing.T) {
+	q, err := parse(t, "sort=name&category=Kablo&limit=10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.HasCursor() {
+		t.Fatal("first page has a cursor")
+	}
+	cursor := q.NextCursor("NYM 3x2,5", 42)
+
+	next, err := parse(t, "sort=name&category=Kablo&limit=10&cursor="+cursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// İmleçli sayfa atlama yerine son kayıttan sonrasını seçer; sayfa numarası yalnızca bilgi içindir
+	if next.Offset() != 0 || next.Page(35).Page != 2 {
+		t.Errorf("offset = %d, page = %d; want 0 and 2", next.Offset(), next.Page(35).Page)
+	}
+	if cond, args := next.After(); cond != "(p.name > ? OR (p.name = ? AND p.id > ?))" ||
+		!reflect.DeepEqual(args, []interface{}{"NYM 3x2,5", "NYM 3x2,5", int64(42)}) {
+		t.Errorf("After = %s %v", cond, args)
+	}
+
+	// İmleç başka sıralama, süzgeç ya da sayfa boyutuyla kullanılamaz
+	for _, query := range []string{
+		"sort=-name&category=Kablo&limit=10",
+		"sort=name&category=Priz&limit=10",
+		"sort=name&category=Kablo&limit=20",
+		"sort=name&limit=10",
+	} {
+		if _, err := parse(t, query+"&cursor="+cursor); !IsError(err) {
+			t.Errorf("%q: err = %v, want cursor mismatch", query, err)
+		}
+	}
+	if _, err := parse(t, "sort=name&category=Kablo&limit=10&cursor=bozuk"); !IsError(err) {
+		t.Errorf("malformed cursor: err = %v", err)
+	}
+}
+
+func TestAfter(t *testing.T) {
+	tests := []struct {
+		name     string
+		sort     string
+		key      interface{}
+		wantCond string
+		wantArgs []interface{}
+	}{
+		{"ascending", "price", int64(4550), "(p.price > ? OR (p.price = ? AND p.id > ?))",
+			[]interface{}{int64(4550), int64(4550), int64(7)}},
+		{"descending", "-price", 12.5, "(p.price < ? OR (p.price = ? AND p.id < ?) OR p.price IS NULL)",
+			[]interface{}{12.5, 12.5, int64(7)}},
+		{"ascending after null", "created_at", nil, "((p.created_at IS NULL AND p.id > ?) OR p.created_at IS NOT NULL)",
+			[]interface{}{int64(7)}},
+		{"descending after null", "-created_at", nil, "(p.created_at IS NULL AND p.id < ?)", []interface{}{int64(7)}},
+		{"stored time", "-created_at", []byte("2024-10-17 12:00:00+03:00"),
+			"(p.created_at < ? OR (p.created_at = ? AND p.id < ?) OR p.created_at IS NULL)",
+			[]interface{}{"2024-10-17 12:00:00+03:00", "2024-10-17 12:00:00+03:00", int64(7)}},
+	}
+	for _, tt := range tests {
+		first, err := parse(t, "sort="+tt.sort)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// İmleç JSON'dan okunduğunda sıralama değerinin tipi korunur
+		q, err := parse(t, "sort="+tt.sort+"&cursor="+first.NextCursor(tt.key, 7))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		cond, args := q.After()
+		if cond != tt.wantCond || !reflect.DeepEqual(args, tt.wantArgs) {
+			t.Errorf("%s: After = %s %#v; want %s %#v", tt.name, cond, args, tt.wantCond, tt.wantArgs)
+		}
+		if want := "+(" + testSpec.Fields[strings.TrimPrefix(tt.sort, "-")].Column + "), p.id"; q.KeyColumns() != want {
+			t.Errorf("%s: KeyColumns = %s, want %s", tt.name, q.KeyColumns(), want)
+		}
+	}
+}
+
+func TestPageLinks(t *testing.T) {
+	q, err := parse(t, "sort=name&category=Kablo&page=2&limit=10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := q.Page(45)
+	if p.From() != 11 || p.To() != 20 || !p.HasPrev() || !p.HasNext() {
+		t.Errorf("page = %+v", p)
+	}
+	if got := p.NextURL(); got != "?category=Kablo&limit=10&page=3&sort=name" {
+		t.Errorf("NextURL = %s", got)
+	}
+	if got := p.SortURL("name"); got != "?category=Kablo&limit=10&sort=-name" {
+		t.Errorf("SortURL = %s", got)
+	}
+	if !reflect.DeepEqual(p.Window(), []int{1, 2, 3, 4}) || p.SortedBy("name") != "asc" {
+		t.Errorf("window = %v, sorted = %s", p.Window(), p.SortedBy("name"))
+	}
+}
@@ -0,0 +1,46 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileMailer iletileri göndermek yerine klasöre .eml dosyası olarak yazar.
+// Geliştirme ortamında gönderilen e-postaları incelemek için kullanılır.
+type FileMailer struct {
+	dir  string
+	from string
+}
+
+// NewFile dosya mailer'ı oluşturur
+func NewFile(dir, from string) *FileMailer {
+	if dir == "" {
+		dir = "./mail"
+	}
+	return &FileMailer{dir: dir, from: from}
+}
+
+// Send iletiyi dosyaya yazar
+func (m *FileMailer) Send(ctx context.Context, msg Message) error {
+	if msg.From == "" {
+		msg.From = m.from
+	}
+	if err := msg.Validate(); err != nil {
+		return err
+	}
+
+	data, err := msg.Bytes()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102-150405"), randomID()[:8])
+	return os.WriteFile(filepath.Join(m.dir, name), data, 0o644)
+}
@@ -0,0 +1,75 @@
+// Package mailer e-posta gönderimini soyutlar. Uygulama SMTP üzerinden gönderir;
+// geliştirme ortamında iletiler diske yazılır, testlerde ise bellekte tutulur.
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+)
+
+// Attachment iletiye eklenen dosya
+type Attachment struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Data        []byte `json:"data"`
+}
+
+// Message gönderilecek e-posta
+type Message struct {
+	From        string       `json:"from,omitempty"`
+	ReplyTo     string       `json:"reply_to,omitempty"`
+	To          []string     `json:"to"`
+	Subject     string       `json:"subject"`
+	Body        string       `json:"body"`
+	Attachments []Attachment `json:"attachments,omitempty"`
+}
+
+// Mailer e-posta gönderen servis
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Config mailer seçimi ve SMTP ayarları
+type Config struct {
+	Driver   string // smtp, file ya da memory
+	Dir      string // file sürücüsünün iletileri yazdığı klasör
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// New yapılandırmaya göre mailer oluşturur
+func New(cfg Config) (Mailer, error) {
+	switch cfg.Driver {
+	case "smtp":
+		if cfg.Host == "" {
+			return nil, errors.New("SMTP sunucusu tanımlanmamış")
+		}
+		return NewSMTP(cfg.Host, cfg.Port, cfg.Username, cfg.Password, cfg.From), nil
+	case "file", "":
+		return NewFile(cfg.Dir, cfg.From), nil
+	case "memory":
+		return NewMemory(), nil
+	}
+	return nil, fmt.Errorf("bilinmeyen e-posta sürücüsü: %s", cfg.Driver)
+}
+
+// Validate iletinin gönderilebilir olduğunu kontrol eder
+func (m Message) Validate() error {
+	if len(m.To) == 0 {
+		return errors.New("alıcı adresi boş")
+	}
+	for _, to := range m.To {
+		if _, err := mail.ParseAddress(to); err != nil {
+			return fmt.Errorf("geçersiz e-posta adresi: %s", to)
+		}
+	}
+	if m.Subject == "" {
+		return errors.New("konu boş")
+	}
+	return nil
+}
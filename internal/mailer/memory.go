@@ -0,0 +1,41 @@
+package mailer
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryMailer iletileri bellekte saklar; testlerde gönderilenleri doğrulamak için kullanılır
+type MemoryMailer struct {
+	mu   sync.Mutex
+	sent []Message
+	// Err ayarlanırsa Send bu hatayı döndürür (başarısız gönderim denemesi)
+	Err error
+}
+
+// NewMemory bellek mailer'ı oluşturur
+func NewMemory() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+// Send iletiyi kaydeder
+func (m *MemoryMailer) Send(ctx context.Context, msg Message) error {
+	if err := msg.Validate(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return m.Err
+	}
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+// Sent şimdiye kadar gönderilen iletileri döndürür
+func (m *MemoryMailer) Sent() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Message(nil), m.sent...)
+}
@@ -0,0 +1,126 @@
+package mailer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		msg     Message
+		wantErr bool
+	}{
+		{"valid", Message{To: []string{"Ayşe Demir <ayse@example.com>"}, Subject: "Fatura"}, false},
+		{"no recipient", Message{Subject: "Fatura"}, true},
+		{"bad address", Message{To: []string{"ayse@"}, Subject: "Fatura"}, true},
+		{"no subject", Message{To: []string{"ayse@example.com"}}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.msg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestBytesRoundTrip(t *testing.T) {
+	pdf := bytes.Repeat([]byte("%PDF-1.4 fatura "), 20)
+	msg := Message{
+		From:    "Işık Elektrik <fatura@isik.example.com>",
+		To:      []string{"Ayşe Demir <ayse@example.com>", "muhasebe@example.com"},
+		Subject: "Fatura FTR2026000000001 - Işık Elektrik",
+		Body:    "Sayın Ayşe Hanım,\nfaturanız ektedir. Toplam: 1.250,00 ₺",
+		Attachments: []Attachment{
+			{Filename: "Fatura-Şubat.pdf", ContentType: "application/pdf", Data: pdf},
+		},
+	}
+
+	raw, err := msg.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dec := new(mime.WordDecoder)
+	if subject, err := dec.DecodeHeader(parsed.Header.Get("Subject")); err != nil || subject != msg.Subject {
+		t.Errorf("Subject = %q, %v; want %q", subject, err, msg.Subject)
+	}
+	to, err := parsed.Header.AddressList("To")
+	if err != nil || len(to) != 2 || to[0].Name != "Ayşe Demir" || to[1].Address != "muhasebe@example.com" {
+		t.Errorf("To = %v, %v", to, err)
+	}
+	if id := parsed.Header.Get("Message-ID"); !strings.HasSuffix(id, "@isik.example.com>") {
+		t.Errorf("Message-ID = %q, want the sender domain", id)
+	}
+
+	_, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mr := multipart.NewReader(parsed.Body, params["boundary"])
+
+	part, err := mr.NextPart()
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(part)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := strings.ReplaceAll(msg.Body, "\n", "\r\n"); strings.TrimRight(string(body), "\r\n") != want {
+		t.Errorf("body = %q, want %q", body, want)
+	}
+
+	part, err = mr.NextPart()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filename, err := dec.DecodeHeader(part.FileName()); err != nil || filename != "Fatura-Şubat.pdf" {
+		t.Errorf("attachment name = %q, %v", filename, err)
+	}
+	encoded, err := io.ReadAll(part)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(encoded)), "\r\n") {
+		if len(line) > 76 {
+			t.Errorf("base64 line longer than 76 characters: %d", len(line))
+		}
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(string(encoded), "\r\n", ""))
+	if err != nil || !bytes.Equal(data, pdf) {
+		t.Errorf("attachment data does not round-trip: %v", err)
+	}
+}
+
+func TestBytesWithoutAttachments(t *testing.T) {
+	raw, err := Message{From: "fatura@example.com", To: []string{"ayse@example.com"}, Subject: "Hatırlatma",
+		Body: "Yarın saat 10:00'da randevunuz var."}.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ct := parsed.Header.Get("Content-Type"); ct != "text/plain; charset=utf-8" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	body, err := io.ReadAll(quotedprintable.NewReader(parsed.Body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(string(body)) != "Yarın saat 10:00'da randevunuz var." {
+		t.Errorf("body = %q", body)
+	}
+}
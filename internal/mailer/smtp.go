@@ -0,0 +1,62 @@
+package mailer
+
+import (
+	"context"
+	"net"
+	"net/smtp"
+)
+
+// SMTPMailer iletileri SMTP sunucusu üzerinden gönderir. Sunucu destekliyorsa
+// bağlantı STARTTLS ile şifrelenir (net/smtp.SendMail davranışı).
+type SMTPMailer struct {
+	addr string
+	host string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTP SMTP mailer oluşturur; kullanıcı adı boşsa kimlik doğrulama yapılmaz
+func NewSMTP(host, port, username, password, from string) *SMTPMailer {
+	if port == "" {
+		port = "587"
+	}
+
+	m := &SMTPMailer{addr: net.JoinHostPort(host, port), host: host, from: from}
+	if username != "" {
+		m.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return m
+}
+
+// Send iletiyi gönderir
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	if msg.From == "" {
+		msg.From = m.from
+	}
+	if err := msg.Validate(); err != nil {
+		return err
+	}
+
+	data, err := msg.Bytes()
+	if err != nil {
+		return err
+	}
+
+	to := make([]string, len(msg.To))
+	for i, addr := range msg.To {
+		to[i] = addressOf(addr)
+	}
+
+	// net/smtp bağlam desteklemediğinden gönderim ayrı goroutine'de beklenir
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(m.addr, m.auth, addressOf(msg.From), to, data)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,55 @@
+package messaging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileProvider iletileri göndermek yerine klasöre .txt dosyası olarak yazar.
+// Geliştirme ortamında gönderilen mesajları incelemek için kullanılır.
+type FileProvider struct {
+	dir  string
+	from string
+}
+
+// NewFile dosya sağlayıcısı oluşturur
+func NewFile(dir, from string) *FileProvider {
+	if dir == "" {
+		dir = "./sms"
+	}
+	return &FileProvider{dir: dir, from: from}
+}
+
+// Send iletiyi dosyaya yazar
+func (p *FileProvider) Send(ctx context.Context, msg Message) error {
+	if msg.From == "" {
+		msg.From = p.from
+	}
+	if msg.Channel == "" {
+		msg.Channel = ChannelSMS
+	}
+	if err := msg.Validate(); err != nil {
+		return err
+	}
+	to, _ := NormalizePhone(msg.To)
+
+	if err := os.MkdirAll(p.dir, 0o755); err != nil {
+		return err
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	content := fmt.Sprintf("Channel: %s\nFrom: %s\nTo: %s\nDate: %s\n\n%s\n",
+		msg.Channel, msg.From, to, now.Format(time.RFC3339), msg.Body)
+	name := fmt.Sprintf("%s-%s.txt", now.Format("20060102-150405"), hex.EncodeToString(suffix))
+	return os.WriteFile(filepath.Join(p.dir, name), []byte(content), 0o644)
+}
@@ -0,0 +1,81 @@
+package messaging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// HTTPProvider iletileri JSON istekle bir SMS sağlayıcısına gönderir. İstek gövdesi
+// {"channel", "from", "to", "text"} alanlarından oluşur; token tanımlıysa Bearer
+// yetkilendirmesiyle gönderilir. 2xx dışındaki yanıtlar başarısız sayılır.
+type HTTPProvider struct {
+	url    string
+	token  string
+	from   string
+	client *http.Client
+}
+
+// NewHTTP HTTP sağlayıcısı oluşturur
+func NewHTTP(url, token, from string) *HTTPProvider {
+	return &HTTPProvider{
+		url:    url,
+		token:  token,
+		from:   from,
+		client: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+type httpRequest struct {
+	Channel string `json:"channel"`
+	From    string `json:"from,omitempty"`
+	To      string `json:"to"`
+	Text    string `json:"text"`
+}
+
+// Send iletiyi sağlayıcıya iletir
+func (p *HTTPProvider) Send(ctx context.Context, msg Message) error {
+	if err := msg.Validate(); err != nil {
+		return err
+	}
+
+	req := httpRequest{Channel: msg.Channel, From: msg.From, To: msg.To, Text: msg.Body}
+	if req.Channel == "" {
+		req.Channel = ChannelSMS
+	}
+	if req.From == "" {
+		req.From = p.from
+	}
+	req.To, _ = NormalizePhone(msg.To)
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if p.token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+p.token)
+	}
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("SMS sağlayıcısı hata döndürdü: %s %s", resp.Status, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
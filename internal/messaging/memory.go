@@ -0,0 +1,41 @@
+package messaging
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryProvider iletileri bellekte saklar; testlerde gönderilenleri doğrulamak için kullanılır
+type MemoryProvider struct {
+	mu   sync.Mutex
+	sent []Message
+	// Err ayarlanırsa Send bu hatayı döndürür (başarısız gönderim denemesi)
+	Err error
+}
+
+// NewMemory bellek sağlayıcısı oluşturur
+func NewMemory() *MemoryProvider {
+	return &MemoryProvider{}
+}
+
+// Send iletiyi kaydeder
+func (p *MemoryProvider) Send(ctx context.Context, msg Message) error {
+	if err := msg.Validate(); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.Err != nil {
+		return p.Err
+	}
+	p.sent = append(p.sent, msg)
+	return nil
+}
+
+// Sent şimdiye kadar gönderilen iletileri döndürür
+func (p *MemoryProvider) Sent() []Message {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Message(nil), p.sent...)
+}
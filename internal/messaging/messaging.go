@@ -0,0 +1,104 @@
+// Package messaging müşterilere kısa mesaj (SMS ya da WhatsApp) gönderimini soyutlar.
+// Uygulama HTTP tabanlı bir SMS sağlayıcısı üzerinden gönderir; geliştirme ortamında
+// iletiler diske yazılır, testlerde ise bellekte tutulur.
+package messaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Mesaj kanalları
+const (
+	ChannelSMS      = "sms"
+	ChannelWhatsApp = "whatsapp"
+)
+
+// MaxLength tek iletide gönderilebilecek en fazla karakter (birleştirilmiş SMS sınırı)
+const MaxLength = 1000
+
+// Message gönderilecek kısa mesaj
+type Message struct {
+	Channel string `json:"channel,omitempty"` // Boşsa sms
+	From    string `json:"from,omitempty"`
+	To      string `json:"to"` // +90 ile başlayan telefon numarası
+	Body    string `json:"body"`
+}
+
+// Provider kısa mesaj gönderen servis
+type Provider interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Config sağlayıcı seçimi ve HTTP sağlayıcı ayarları
+type Config struct {
+	Driver string // http, file ya da memory
+	Dir    string // file sürücüsünün iletileri yazdığı klasör
+	URL    string
+	Token  string
+	From   string // Gönderici adı (SMS başlığı)
+}
+
+// New yapılandırmaya göre sağlayıcı oluşturur
+func New(cfg Config) (Provider, error) {
+	switch cfg.Driver {
+	case "http":
+		if cfg.URL == "" {
+			return nil, errors.New("SMS sağlayıcı adresi tanımlanmamış")
+		}
+		return NewHTTP(cfg.URL, cfg.Token, cfg.From), nil
+	case "file", "":
+		return NewFile(cfg.Dir, cfg.From), nil
+	case "memory":
+		return NewMemory(), nil
+	}
+	return nil, fmt.Errorf("bilinmeyen SMS sürücüsü: %s", cfg.Driver)
+}
+
+// Validate iletinin gönderilebilir olduğunu kontrol eder
+func (m Message) Validate() error {
+	switch m.Channel {
+	case "", ChannelSMS, ChannelWhatsApp:
+	default:
+		return fmt.Errorf("bilinmeyen mesaj kanalı: %s", m.Channel)
+	}
+	if _, err := NormalizePhone(m.To); err != nil {
+		return err
+	}
+	if strings.TrimSpace(m.Body) == "" {
+		return errors.New("mesaj metni boş")
+	}
+	if utf8.RuneCountInString(m.Body) > MaxLength {
+		return fmt.Errorf("mesaj metni en fazla %d karakter olabilir", MaxLength)
+	}
+	return nil
+}
+
+// NormalizePhone telefon numarasını +905XXXXXXXXX biçimine getirir. Başında ülke kodu
+// olmayan numaralar Türkiye numarası kabul edilir.
+func NormalizePhone(phone string) (string, error) {
+	var digits strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	d := digits.String()
+
+	switch {
+	case strings.HasPrefix(strings.TrimSpace(phone), "+") && len(d) >= 10 && len(d) <= 15:
+		return "+" + d, nil
+	case strings.HasPrefix(d, "00") && len(d) >= 12 && len(d) <= 17:
+		return "+" + d[2:], nil
+	case len(d) == 12 && strings.HasPrefix(d, "90"):
+		return "+" + d, nil
+	case len(d) == 11 && strings.HasPrefix(d, "0"):
+		return "+90" + d[1:], nil
+	case len(d) == 10:
+		return "+90" + d, nil
+	}
+	return "", fmt.Errorf("geçersiz telefon numarası: %s", phone)
+}
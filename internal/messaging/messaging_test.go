@@ -0,0 +1,84 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		in, want string
+		wantErr  bool
+	}{
+		{"0533 111 22 33", "+905331112233", false},
+		{"(533) 111-22-33", "+905331112233", false},
+		{"90 533 111 22 33", "+905331112233", false},
+		{"+90 533 111 22 33", "+905331112233", false},
+		{"0049 30 1234567", "+49301234567", false},
+		{"+44 20 7946 0958", "+442079460958", false},
+		{"111 22 33", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		got, err := NormalizePhone(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("NormalizePhone(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestMessageValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		msg     Message
+		wantErr bool
+	}{
+		{"sms", Message{To: "0533 111 22 33", Body: "Siparişiniz hazır."}, false},
+		{"whatsapp", Message{Channel: ChannelWhatsApp, To: "05331112233", Body: "Merhaba"}, false},
+		{"unknown channel", Message{Channel: "faks", To: "05331112233", Body: "Merhaba"}, true},
+		{"bad phone", Message{To: "123", Body: "Merhaba"}, true},
+		{"blank body", Message{To: "05331112233", Body: "  "}, true},
+		// Sınır bayt değil karakter sayısıdır
+		{"max length", Message{To: "05331112233", Body: strings.Repeat("ş", MaxLength)}, false},
+		{"too long", Message{To: "05331112233", Body: strings.Repeat("ş", MaxLength+1)}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.msg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestHTTPProvider(t *testing.T) {
+	var got httpRequest
+	var auth string
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+		w.WriteHeader(status)
+		w.Write([]byte("bakiye yetersiz"))
+	}))
+	defer srv.Close()
+
+	p := NewHTTP(srv.URL, "gizli", "ISIKELEKTRK")
+	if err := p.Send(context.Background(), Message{To: "0533 111 22 33", Body: "Siparişiniz hazır."}); err != nil {
+		t.Fatal(err)
+	}
+	want := httpRequest{Channel: ChannelSMS, From: "ISIKELEKTRK", To: "+905331112233", Text: "Siparişiniz hazır."}
+	if got != want || auth != "Bearer gizli" {
+		t.Errorf("request = %+v (auth %q), want %+v", got, auth, want)
+	}
+
+	status = http.StatusPaymentRequired
+	err := p.Send(context.Background(), Message{To: "0533 111 22 33", Body: "Siparişiniz hazır."})
+	if err == nil || !strings.Contains(err.Error(), "bakiye yetersiz") {
+		t.Errorf("failed send: err = %v, want provider error with detail", err)
+	}
+}
@@ -0,0 +1,164 @@
+package messaging
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/money"
+)
+
+// Şablon anahtarları
+const (
+	TemplateAppointmentReminder = "appointment_reminder"
+	TemplateOrderReady          = "order_ready"
+	TemplatePaymentDue          = "payment_due"
+)
+
+// Template işletmenin düzenleyebildiği mesaj şablonu
+type Template struct {
+	Key        string `json:"key"`
+	Name       string `json:"name"`
+	Default    string `json:"default"`
+	Body       string `json:"body"`
+	Customized bool   `json:"customized"`
+}
+
+// Templates tanımlı şablonlar; işletme değiştirmediyse varsayılan metin kullanılır
+var Templates = []Template{
+	{
+		Key:     TemplateAppointmentReminder,
+		Name:    "Randevu hatırlatması",
+		Default: "Sayın {musteri}, {tarih} {saat} tarihindeki randevunuzu hatırlatırız. {isletme} {isletme_tel}",
+	},
+	{
+		Key:     TemplateOrderReady,
+		Name:    "Sipariş hazır",
+		Default: "Sayın {musteri}, {siparis_no} numaralı siparişiniz hazırdır. Tutar: {tutar}. {isletme} {isletme_tel}",
+	},
+	{
+		Key:     TemplatePaymentDue,
+		Name:    "Ödeme hatırlatması",
+		Default: "Sayın {musteri}, {fatura_no} numaralı {tutar} tutarındaki faturanızın son ödeme tarihi {son_odeme}. {isletme} {isletme_tel}",
+	},
+}
+
+// FindTemplate anahtara göre şablon tanımını döndürür
+func FindTemplate(key string) (Template, bool) {
+	for _, t := range Templates {
+		if t.Key == key {
+			return t, true
+		}
+	}
+	return Template{}, false
+}
+
+// Placeholders şablonlarda kullanılabilecek alanlar ve açıklamaları
+var Placeholders = []struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}{
+	{"{musteri}", "Müşteri adı"},
+	{"{isletme}", "İşletme adı"},
+	{"{isletme_tel}", "İşletme telefonu"},
+	{"{tarih}", "Randevu tarihi"},
+	{"{saat}", "Randevu saati"},
+	{"{randevu}", "Randevu konusu"},
+	{"{siparis_no}", "Sipariş numarası"},
+	{"{fatura_no}", "Fatura numarası"},
+	{"{tutar}", "Sipariş ya da fatura tutarı"},
+	{"{son_odeme}", "Faturanın son ödeme tarihi"},
+}
+
+// placeholderPattern şablondaki {alan} biçimindeki yer tutucuları bulur
+var placeholderPattern = regexp.MustCompile(`\{[^{}\s]+\}`)
+
+// ValidateTemplate şablonun uzunluğunu ve yalnızca tanımlı yer tutucuları kullandığını kontrol eder;
+// yazım hatalı bir alan gönderilen mesajda olduğu gibi görüneceğinden kaydedilmeden reddedilir
+func ValidateTemplate(body string) error {
+	if utf8.RuneCountInString(body) > MaxLength {
+		return fmt.Errorf("Şablon en fazla %d karakter olabilir", MaxLength)
+	}
+	for _, name := range placeholderPattern.FindAllString(body, -1) {
+		if !isPlaceholder(name) {
+			return fmt.Errorf("Tanımsız alan: %s", name)
+		}
+	}
+	return nil
+}
+
+func isPlaceholder(name string) bool {
+	for _, p := range Placeholders {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultDateLayout işletmenin tarih biçimi verilmediğinde tarihlerin yazıldığı biçim
+const DefaultDateLayout = "02.01.2006"
+
+// Data şablondaki alanların doldurulacağı kayıtlar; verilmeyen kayıtların alanları boş kalır
+type Data struct {
+	Customer      *models.Customer
+	BusinessName  string
+	BusinessPhone string
+	Appointment   *models.Appointment
+	Order         *models.Order
+	Invoice       *models.Invoice
+	DateLayout    string // İşletmenin tarih biçimi (settings.Settings.DateLayout); boşsa DefaultDateLayout
+}
+
+// Render şablondaki {alan} yer tutucularını doldurur; tanımsız yer tutucular olduğu gibi kalır
+func Render(body string, data Data) string {
+	values := make(map[string]string, len(Placeholders))
+	for _, p := range Placeholders {
+		values[p.Name] = ""
+	}
+	values["{isletme}"] = data.BusinessName
+	values["{isletme_tel}"] = data.BusinessPhone
+	layout := data.DateLayout
+	if layout == "" {
+		layout = DefaultDateLayout
+	}
+	if data.Customer != nil {
+		values["{musteri}"] = data.Customer.Name
+	}
+	if a := data.Appointment; a != nil {
+		values["{tarih}"] = a.StartTime.In(time.Local).Format(layout)
+		values["{saat}"] = a.StartTime.In(time.Local).Format("15:04")
+		values["{randevu}"] = a.Title
+	}
+	if o := data.Order; o != nil {
+		values["{siparis_no}"] = o.OrderNumber
+		values["{tutar}"] = FormatAmount(o.TotalAmount)
+	}
+	if i := data.Invoice; i != nil {
+		values["{fatura_no}"] = i.InvoiceNumber
+		values["{tutar}"] = FormatAmount(i.TotalAmount)
+		if i.DueDate != nil {
+			values["{son_odeme}"] = i.DueDate.In(time.Local).Format(layout)
+		}
+	}
+
+	pairs := make([]string, 0, len(values)*2)
+	for placeholder, value := range values {
+		pairs = append(pairs, placeholder, value)
+	}
+	rendered := strings.NewReplacer(pairs...).Replace(body)
+
+	// Boş kalan alanların bıraktığı fazla boşlukları temizle
+	return strings.Join(strings.Fields(rendered), " ")
+}
+
+// FormatAmount tutarı Türkçe biçimde yazar: 1.234,56 TL, dövizde 1.234,56 USD
+func FormatAmount(amount money.Money) string {
+	if amount.Currency() == money.TRY {
+		return amount.Number() + " TL"
+	}
+	return amount.Number() + " " + string(amount.Currency())
+}
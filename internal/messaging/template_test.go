@@ -0,0 +1,94 @@
+package messaging
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/money"
+)
+
+func TestRender(t *testing.T) {
+	due := time.Date(2026, 11, 5, 0, 0, 0, 0, time.Local)
+	customer := &models.Customer{Name: "Ayşe Demir"}
+
+	tests := []struct {
+		name string
+		body string
+		data Data
+		want string
+	}{
+		{"appointment", "Sayın {musteri}, {tarih} {saat} {randevu}. {isletme} {isletme_tel}",
+			Data{Customer: customer, BusinessName: "Işık Elektrik", BusinessPhone: "0212 555 00 00",
+				Appointment: &models.Appointment{Title: "Kombi bakımı", StartTime: time.Date(2026, 10, 20, 9, 30, 0, 0, time.Local)}},
+			"Sayın Ayşe Demir, 20.10.2026 09:30 Kombi bakımı. Işık Elektrik 0212 555 00 00"},
+		{"order", "{siparis_no} hazır, tutar {tutar}",
+			Data{Order: &models.Order{OrderNumber: "SIP-2026-007", TotalAmount: money.TL(123456789)}},
+			"SIP-2026-007 hazır, tutar 1.234.567,89 TL"},
+		{"invoice", "{fatura_no}: {tutar}, son ödeme {son_odeme}",
+			Data{Invoice: &models.Invoice{InvoiceNumber: "FTR2026000000001", TotalAmount: money.TL(120000), DueDate: &due}},
+			"FTR2026000000001: 1.200,00 TL, son ödeme 05.11.2026"},
+		{"business date layout", "{son_odeme}",
+			Data{Invoice: &models.Invoice{DueDate: &due}, DateLayout: "2006-01-02"}, "2026-11-05"},
+		// Verilmeyen kayıtların alanları boş kalır ve fazla boşluklar temizlenir
+		{"missing data", "Sayın {musteri},  {isletme} {isletme_tel}", Data{BusinessName: "Işık Elektrik"},
+			"Sayın , Işık Elektrik"},
+		{"unknown placeholder", "{bilinmeyen} {isletme}", Data{BusinessName: "Işık"}, "{bilinmeyen} Işık"},
+	}
+	for _, tt := range tests {
+		if got := Render(tt.body, tt.data); got != tt.want {
+			t.Errorf("%s: Render = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDefaultTemplatesRender(t *testing.T) {
+	for _, tmpl := range Templates {
+		if err := ValidateTemplate(tmpl.Default); err != nil {
+			t.Errorf("default %s template: %v", tmpl.Key, err)
+		}
+		if found, ok := FindTemplate(tmpl.Key); !ok || found.Default != tmpl.Default {
+			t.Errorf("FindTemplate(%s) = %+v, %v", tmpl.Key, found, ok)
+		}
+	}
+	if _, ok := FindTemplate("bilinmeyen"); ok {
+		t.Error("FindTemplate found an unknown key")
+	}
+}
+
+func TestValidateTemplate(t *testing.T) {
+	tests := []struct {
+		body    string
+		wantErr string
+	}{
+		{"Sayın {musteri}, siparişiniz {siparis_no} hazır.", ""},
+		{"Kampanya: %20 indirim {isletme}", ""},
+		{"Yalnızca düz metin", ""},
+		{"Sayın {muşteri}, randevunuz {tarih}", "Tanımsız alan: {muşteri}"},
+		{"{tutar} {Tutar}", "Tanımsız alan: {Tutar}"},
+		{strings.Repeat("a", MaxLength+1), "Şablon en fazla 1000 karakter olabilir"},
+	}
+	for _, tt := range tests {
+		err := ValidateTemplate(tt.body)
+		if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+			t.Errorf("ValidateTemplate(%.30q) = %v, want %q", tt.body, err, tt.wantErr)
+		}
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	tests := map[int64]string{
+		0:        "0,00 TL",
+		550:      "5,50 TL",
+		99999:    "999,99 TL",
+		123450:   "1.234,50 TL",
+		-4500025: "-45.000,25 TL",
+	}
+	for kurus, want := range tests {
+		amount := money.TL(kurus)
+		if got := FormatAmount(amount); got != want {
+			t.Errorf("FormatAmount(%v) = %q, want %q", amount, got, want)
+		}
+	}
+}
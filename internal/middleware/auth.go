@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/models"
+)
+
+const (
+	// SessionCookieName oturum token'ının tutulduğu çerez adı
+	SessionCookieName = "esnaf_session"
+
+	userContextKey = "currentUser"
+)
+
+// Auth oturum çerezini doğrular ve giriş yapmış kullanıcıyı gin context'ine yerleştirir.
+// Oturumu olmayan istekler API için 401, sayfalar için /login'e yönlendirme alır. idleTimeout
+// işletmenin otomatik çıkış süresini verir; bu süreden uzun süre kullanılmayan oturumlar kapanır.
+func Auth(db *database.DB, idleTimeout func(businessID int) time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, err := c.Cookie(SessionCookieName)
+		if err != nil || token == "" {
+			unauthorized(c)
+			return
+		}
+
+		user, err := db.SessionUser(token)
+		if err != nil {
+			unauthorized(c)
+			return
+		}
+		if err := db.TouchSession(token, idleTimeout(user.BusinessID())); err != nil {
+			unauthorized(c)
+			return
+		}
+
+		SetCurrentUser(c, user)
+		c.Next()
+	}
+}
+
+// SetCurrentUser isteği yapan kullanıcıyı bağlama ekler
+func SetCurrentUser(c *gin.Context, user *models.User) {
+	c.Set(userContextKey, user)
+}
+
+// CurrentUser isteği yapan kullanıcıyı döndürür
+func CurrentUser(c *gin.Context) *models.User {
+	if v, ok := c.Get(userContextKey); ok {
+		if user, ok := v.(*models.User); ok {
+			return user
+		}
+	}
+	return nil
+}
+
+// UserID isteği yapan kullanıcının ID'sini döndürür
+func UserID(c *gin.Context) int {
+	if user := CurrentUser(c); user != nil {
+		return user.ID
+	}
+	return 0
+}
+
+// BusinessID isteği yapan kullanıcının bağlı olduğu işletmenin ID'sini döndürür.
+// Veri sorguları bu ID ile sınırlandırılır.
+func BusinessID(c *gin.Context) int {
+	if user := CurrentUser(c); user != nil {
+		return user.BusinessID()
+	}
+	return 0
+}
+
+func unauthorized(c *gin.Context) {
+	if strings.HasPrefix(c.Request.URL.Path, "/api/") {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Oturum açmanız gerekiyor"})
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/login?next="+url.QueryEscape(c.Request.URL.RequestURI()))
+	c.Abort()
+}
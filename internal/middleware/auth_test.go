@@ -0,0 +1,78 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umutaraz/tradesman-app/internal/database/dbtest"
+	"github.com/umutaraz/tradesman-app/internal/middleware"
+)
+
+func TestAuth(t *testing.T) {
+	db := dbtest.New(t)
+	userID := dbtest.User(t, db, "sahip@example.com")
+	token, _, err := db.CreateSession(userID, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Otomatik çıkış süresini aşan hareketsiz oturum
+	idleID := dbtest.User(t, db, "uykuda@example.com")
+	idleToken, _, err := db.CreateSession(idleID, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("UPDATE sessions SET last_seen_at = ? WHERE user_id = ?", time.Now().Add(-45*time.Minute), idleID); err != nil {
+		t.Fatal(err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.Auth(db, func(int) time.Duration { return 30 * time.Minute }))
+	whoami := func(c *gin.Context) { c.String(http.StatusOK, middleware.CurrentUser(c).Email) }
+	r.GET("/api/v1/me", whoami)
+	r.GET("/customers", whoami)
+
+	tests := []struct {
+		name     string
+		path     string
+		token    string
+		status   int
+		location string
+	}{
+		{"api without session", "/api/v1/me", "", http.StatusUnauthorized, ""},
+		{"page without session", "/customers?page=2", "", http.StatusFound, "/login?next=%2Fcustomers%3Fpage%3D2"},
+		{"unknown token", "/api/v1/me", "bilinmeyen", http.StatusUnauthorized, ""},
+		{"valid session", "/api/v1/me", token, http.StatusOK, ""},
+		{"idle session", "/api/v1/me", idleToken, http.StatusUnauthorized, ""},
+		{"idle session is closed", "/api/v1/me", idleToken, http.StatusUnauthorized, ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		if tt.token != "" {
+			req.AddCookie(&http.Cookie{Name: middleware.SessionCookieName, Value: tt.token})
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d", tt.name, w.Code, tt.status)
+		}
+		if loc := w.Header().Get("Location"); loc != tt.location {
+			t.Errorf("%s: Location = %q, want %q", tt.name, loc, tt.location)
+		}
+		if tt.status == http.StatusOK && w.Body.String() != "sahip@example.com" {
+			t.Errorf("%s: user = %q", tt.name, w.Body)
+		}
+	}
+
+	var sessions int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sessions WHERE user_id = ?", idleID).Scan(&sessions); err != nil {
+		t.Fatal(err)
+	}
+	if sessions != 0 {
+		t.Errorf("idle sessions = %d, want the expired session deleted", sessions)
+	}
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SameOrigin oturum çerezini taşıyan değiştirici istekleri (POST, PUT, PATCH, DELETE) yalnızca
+// uygulamanın kendi sayfalarından kabul eder. Tarayıcı başka bir siteden gönderilen formda
+// Origin ya da Referer başlığını ekler; bu başlıkların adresi sunucunun adresiyle eşleşmezse
+// istek 403 ile reddedilir. İki başlığı da göndermeyen istemciler (curl, betikler) tarayıcı
+// olmadığından çerezle kandırılamaz ve geçirilir.
+func SameOrigin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if isSafeMethod(c.Request.Method) || sameOrigin(c.Request) {
+			c.Next()
+			return
+		}
+
+		if strings.HasPrefix(c.Request.URL.Path, "/api/") {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "İstek başka bir siteden gönderildiği için reddedildi"})
+			return
+		}
+
+		c.String(http.StatusForbidden, "İstek başka bir siteden gönderildiği için reddedildi")
+		c.Abort()
+	}
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// sameOrigin Origin başlığını, yoksa Referer başlığını isteğin Host değeriyle karşılaştırır
+func sameOrigin(r *http.Request) bool {
+	source := r.Header.Get("Origin")
+	if source == "" {
+		source = r.Header.Get("Referer")
+	}
+	if source == "" {
+		return true
+	}
+
+	// Gizlilik ayarlı sayfalardan gönderilen formlarda Origin "null" olur
+	u, err := url.Parse(source)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	return strings.EqualFold(u.Host, r.Host)
+}
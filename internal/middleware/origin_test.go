@@ -0,0 +1,53 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umutaraz/tradesman-app/internal/middleware"
+)
+
+func TestSameOrigin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.SameOrigin())
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/customers", ok)
+	r.POST("/customers", ok)
+	r.DELETE("/api/v1/customers/1", ok)
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		origin  string
+		referer string
+		status  int
+	}{
+		{"safe method from another site", http.MethodGet, "/customers", "https://kotu.example", "", http.StatusOK},
+		{"same origin", http.MethodPost, "/customers", "http://esnaf.local:8080", "", http.StatusOK},
+		{"same origin referer", http.MethodPost, "/customers", "", "http://esnaf.local:8080/customers/new", http.StatusOK},
+		{"no browser headers", http.MethodPost, "/customers", "", "", http.StatusOK},
+		{"cross-site form", http.MethodPost, "/customers", "https://kotu.example", "", http.StatusForbidden},
+		{"cross-site referer", http.MethodPost, "/customers", "", "https://kotu.example/form", http.StatusForbidden},
+		{"other port", http.MethodPost, "/customers", "http://esnaf.local:9090", "", http.StatusForbidden},
+		{"null origin", http.MethodPost, "/customers", "null", "http://esnaf.local:8080/", http.StatusForbidden},
+		{"cross-site api", http.MethodDelete, "/api/v1/customers/1", "https://kotu.example", "", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, "http://esnaf.local:8080"+tt.path, nil)
+		if tt.origin != "" {
+			req.Header.Set("Origin", tt.origin)
+		}
+		if tt.referer != "" {
+			req.Header.Set("Referer", tt.referer)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d", tt.name, w.Code, tt.status)
+		}
+	}
+}
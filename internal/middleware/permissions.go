@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umutaraz/tradesman-app/internal/permissions"
+)
+
+// Can isteği yapan kullanıcının verilen yetkiye sahip olup olmadığını döndürür
+func Can(c *gin.Context, perm permissions.Permission) bool {
+	user := CurrentUser(c)
+	return user != nil && permissions.Has(user.EffectiveRole(), perm)
+}
+
+// RequirePermission yetkisi olmayan kullanıcıların isteğini 403 ile reddeder
+func RequirePermission(perm permissions.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if Can(c, perm) {
+			c.Next()
+			return
+		}
+
+		if strings.HasPrefix(c.Request.URL.Path, "/api/") {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Bu işlem için yetkiniz yok"})
+			return
+		}
+
+		c.String(http.StatusForbidden, "Bu sayfayı görüntüleme yetkiniz yok")
+		c.Abort()
+	}
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/permissions"
+)
+
+func TestRequirePermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		role   string
+		path   string
+		status int
+	}{
+		{"legacy user role is owner", "user", "/api/v1/users", http.StatusOK},
+		{"manager", models.RoleManager, "/api/v1/users", http.StatusOK},
+		{"cashier api", models.RoleCashier, "/api/v1/users", http.StatusForbidden},
+		{"technician page", models.RoleTechnician, "/users", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		r := gin.New()
+		r.Use(func(c *gin.Context) {
+			c.Set(userContextKey, &models.User{ID: 1, Role: tt.role})
+		})
+		r.GET(tt.path, RequirePermission(permissions.ManageUsers), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if w.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d", tt.name, w.Code, tt.status)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/money"
+)
+
+// Kur kaynakları
+const (
+	RateSourceManual = "manual" // Elle girildi
+	RateSourceTCMB   = "tcmb"   // TCMB kur dosyasından aktarıldı
+	RateSourceCSV    = "csv"    // CSV dosyasından aktarıldı
+)
+
+// ExchangeRate bir günün döviz kuru; TCMB gibi 1 birim dövizin Türk lirası karşılığıdır
+type ExchangeRate struct {
+	ID        int            `json:"id" db:"id"`
+	UserID    int            `json:"user_id" db:"user_id"`
+	Currency  money.Currency `json:"currency" db:"currency" binding:"required"`
+	Date      string         `json:"rate_date" db:"rate_date" binding:"required"` // YYYY-AA-GG
+	Rate      float64        `json:"rate" db:"rate" binding:"required,gt=0"`
+	Source    string         `json:"source" db:"source"`
+	CreatedAt time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// TransactionTotal bir günün belirli türdeki (gelir/gider) kayıtlarının para birimi bazında toplamı
+type TransactionTotal struct {
+	Type   string      `json:"type"`
+	Date   time.Time   `json:"date"`
+	Amount money.Money `json:"amount"`
+}
+
+// CurrencyTotal bir para birimindeki gelir ve giderin kendi birimindeki ve ana para birimindeki karşılığı
+type CurrencyTotal struct {
+	Currency    money.Currency `json:"currency"`
+	Income      money.Money    `json:"income"`
+	Expense     money.Money    `json:"expense"`
+	BaseIncome  money.Money    `json:"base_income"`
+	BaseExpense money.Money    `json:"base_expense"`
+	MissingRate bool           `json:"missing_rate,omitempty"` // Kur bulunamadı; tutarlar toplama katılmadı
+}
+
+// FinancialSummary bir dönemin gelir, gider ve kârı; döviz kayıtları işlem günündeki kurla ana para
+// birimine çevrilmiştir
+type FinancialSummary struct {
+	From         time.Time       `json:"from"`
+	To           time.Time       `json:"to"` // Hariç
+	BaseCurrency money.Currency  `json:"base_currency"`
+	Income       money.Money     `json:"income"`
+	Expense      money.Money     `json:"expense"`
+	Profit       money.Money     `json:"profit"`
+	ByCurrency   []CurrencyTotal `json:"by_currency"`
+}
+
+// HasMissingRates kuru bulunamadığı için toplama katılmayan tutar varsa true döner
+func (s FinancialSummary) HasMissingRates() bool {
+	for _, t := range s.ByCurrency {
+		if t.MissingRate {
+			return true
+		}
+	}
+	return false
+}
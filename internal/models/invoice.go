@@ -0,0 +1,125 @@
+package models
+
+import (
+	"sort"
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/money"
+)
+
+// Fatura durumları
+const (
+	InvoiceDraft   = "draft"   // Taslak; düzenlenebilir, numara almamış
+	InvoiceIssued  = "issued"  // Kesildi; artık değiştirilemez
+	InvoicePaid    = "paid"    // Ödendi
+	InvoiceOverdue = "overdue" // Vadesi geçti
+	InvoiceVoid    = "void"    // İptal edildi; numarası korunur
+)
+
+// Fatura türleri
+const (
+	InvoiceTypeSales      = "sales"       // Satış faturası
+	InvoiceTypeCreditNote = "credit_note" // İade / düzeltme faturası
+)
+
+// KDVRates geçerli KDV oranları (yüzde)
+var KDVRates = []int{1, 10, 20}
+
+// DefaultKDVRate oran belirtilmediğinde kullanılan genel KDV oranı
+const DefaultKDVRate = 20
+
+// IsValidKDVRate oranın geçerli KDV oranlarından biri olup olmadığını döndürür
+func IsValidKDVRate(rate int) bool {
+	for _, r := range KDVRates {
+		if r == rate {
+			return true
+		}
+	}
+	return false
+}
+
+type Invoice struct {
+	ID            int            `json:"id" db:"id"`
+	UserID        int            `json:"user_id" db:"user_id"`
+	CustomerID    int            `json:"customer_id" db:"customer_id"`
+	OrderID       *int           `json:"order_id,omitempty" db:"order_id"`
+	InvoiceNumber string         `json:"invoice_number" db:"invoice_number"` // Kesilince atanır (FTR2024000000001)
+	Type          string         `json:"type" db:"invoice_type"`
+	CreditForID   *int           `json:"credit_for_id,omitempty" db:"credit_for_id"` // İade faturasının düzelttiği fatura
+	Status        string         `json:"status" db:"status"`
+	InvoiceDate   time.Time      `json:"invoice_date" db:"invoice_date"`
+	DueDate       *time.Time     `json:"due_date" db:"due_date"`
+	Subtotal      money.Money    `json:"subtotal" db:"subtotal"`
+	TaxAmount     money.Money    `json:"tax_amount" db:"tax_amount"`
+	TotalAmount   money.Money    `json:"total_amount" db:"total_amount"`
+	Currency      money.Currency `json:"currency" db:"currency"`
+	BaseCurrency  money.Currency `json:"base_currency" db:"base_currency"`         // Kesildiği gün işletmenin ana para birimi
+	ExchangeRate  float64        `json:"exchange_rate" db:"exchange_rate"`         // Kesildiği gün 1 birimin ana para birimi karşılığı
+	BaseTotal     money.Money    `json:"base_total_amount" db:"base_total_amount"` // Cari hesaba yazılan ana para birimi tutarı
+	Notes         string         `json:"notes" db:"notes"`
+	IssuedAt      *time.Time     `json:"issued_at" db:"issued_at"`
+	PaidAt        *time.Time     `json:"paid_at" db:"paid_at"`
+	VoidedAt      *time.Time     `json:"voided_at" db:"voided_at"`
+	CreatedAt     time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at" db:"updated_at"`
+	Customer      *Customer      `json:"customer,omitempty"`
+	Lines         []InvoiceLine  `json:"lines,omitempty"`
+}
+
+type InvoiceLine struct {
+	ID          int         `json:"id" db:"id"`
+	InvoiceID   int         `json:"invoice_id" db:"invoice_id"`
+	ProductID   *int        `json:"product_id,omitempty" db:"product_id"`
+	Description string      `json:"description" db:"description"`
+	Quantity    float64     `json:"quantity" db:"quantity"`
+	Unit        string      `json:"unit" db:"unit"`
+	UnitPrice   money.Money `json:"unit_price" db:"unit_price"`
+	TaxRate     int         `json:"tax_rate" db:"tax_rate"`
+	Subtotal    money.Money `json:"subtotal" db:"subtotal"`     // KDV hariç tutar
+	TaxAmount   money.Money `json:"tax_amount" db:"tax_amount"` // KDV tutarı
+	Total       money.Money `json:"total" db:"total"`           // KDV dahil tutar
+}
+
+// TaxLine fatura toplamındaki bir KDV oranının matrah ve vergi tutarı
+type TaxLine struct {
+	Rate      int         `json:"rate"`
+	Base      money.Money `json:"base"`
+	TaxAmount money.Money `json:"tax_amount"`
+}
+
+// IsEditable faturanın taslak olup olmadığını döndürür; kesilmiş faturalar değiştirilemez
+func (i Invoice) IsEditable() bool {
+	return i.Status == InvoiceDraft
+}
+
+// IsForeign fatura işletmenin ana para biriminden farklı bir para birimindeyse true döner
+func (i Invoice) IsForeign() bool {
+	return i.Currency != i.BaseCurrency
+}
+
+// IsCreditNote faturanın iade faturası olup olmadığını döndürür
+func (i Invoice) IsCreditNote() bool {
+	return i.Type == InvoiceTypeCreditNote
+}
+
+// TaxBreakdown KDV tutarlarını orana göre gruplayarak döndürür
+func (i Invoice) TaxBreakdown() []TaxLine {
+	byRate := map[int]*TaxLine{}
+	for _, line := range i.Lines {
+		t, ok := byRate[line.TaxRate]
+		if !ok {
+			t = &TaxLine{Rate: line.TaxRate}
+			byRate[line.TaxRate] = t
+		}
+		t.Base = t.Base.Add(line.Subtotal)
+		t.TaxAmount = t.TaxAmount.Add(line.TaxAmount)
+	}
+
+	breakdown := make([]TaxLine, 0, len(byRate))
+	for _, t := range byRate {
+		breakdown = append(breakdown, *t)
+	}
+	sort.Slice(breakdown, func(a, b int) bool { return breakdown[a].Rate < breakdown[b].Rate })
+
+	return breakdown
+}
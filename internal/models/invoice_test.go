@@ -0,0 +1,33 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/umutaraz/tradesman-app/internal/money"
+)
+
+func TestIsValidKDVRate(t *testing.T) {
+	for rate, want := range map[int]bool{0: false, 1: true, 8: false, 10: true, 18: false, 20: true} {
+		if got := IsValidKDVRate(rate); got != want {
+			t.Errorf("IsValidKDVRate(%d) = %v, want %v", rate, got, want)
+		}
+	}
+}
+
+func TestTaxBreakdown(t *testing.T) {
+	invoice := Invoice{Lines: []InvoiceLine{
+		{TaxRate: 20, Subtotal: money.TL(10000), TaxAmount: money.TL(2000)},
+		{TaxRate: 1, Subtotal: money.TL(5000), TaxAmount: money.TL(50)},
+		{TaxRate: 20, Subtotal: money.TL(4000), TaxAmount: money.TL(800)},
+	}}
+
+	want := []TaxLine{{Rate: 1, Base: money.TL(5000), TaxAmount: money.TL(50)},
+		{Rate: 20, Base: money.TL(14000), TaxAmount: money.TL(2800)}}
+	if got := invoice.TaxBreakdown(); !reflect.DeepEqual(got, want) {
+		t.Errorf("TaxBreakdown() = %+v, want %+v", got, want)
+	}
+	if got := (Invoice{}).TaxBreakdown(); len(got) != 0 {
+		t.Errorf("empty invoice breakdown = %+v", got)
+	}
+}
@@ -0,0 +1,125 @@
+package models
+
+import (
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/money"
+)
+
+// Cari hesap hareket yönleri; bakiye borçlar toplamı eksi alacaklar toplamıdır,
+// pozitif bakiye müşterinin işletmeye borcunu gösterir
+const (
+	LedgerDebit  = "debit"  // Borç: veresiye satış, kesilen fatura, müşteriye yapılan ödeme
+	LedgerCredit = "credit" // Alacak: tahsilat, iade faturası, fatura iptali
+)
+
+// Cari hesap hareket kaynakları
+const (
+	LedgerSourceInvoice     = "invoice"      // Kesilen satış faturası
+	LedgerSourceCreditNote  = "credit_note"  // Kesilen iade faturası
+	LedgerSourceInvoiceVoid = "invoice_void" // İptal edilen faturanın ters kaydı
+	LedgerSourceOrder       = "order"        // Teslim edilen veresiye sipariş
+	LedgerSourcePayment     = "payment"      // Müşteriden tahsilat
+	LedgerSourceRefund      = "refund"       // Müşteriye yapılan ödeme
+)
+
+// Ödeme yöntemleri
+const (
+	PaymentCash         = "cash"
+	PaymentBankTransfer = "bank_transfer"
+	PaymentCreditCard   = "credit_card"
+	PaymentOther        = "other"
+)
+
+var paymentMethodLabels = map[string]string{
+	PaymentCash:         "Nakit",
+	PaymentBankTransfer: "Banka Transferi",
+	PaymentCreditCard:   "Kredi Kartı",
+	PaymentOther:        "Diğer",
+}
+
+// IsValidPaymentMethod ödeme yönteminin tanımlı olup olmadığını kontrol eder
+func IsValidPaymentMethod(method string) bool {
+	_, ok := paymentMethodLabels[method]
+	return ok
+}
+
+// PaymentMethodLabel ödeme yönteminin Türkçe karşılığını döndürür
+func PaymentMethodLabel(method string) string {
+	if label, ok := paymentMethodLabels[method]; ok {
+		return label
+	}
+	return method
+}
+
+// LedgerEntry müşteri cari hesabındaki bir hareket
+type LedgerEntry struct {
+	ID            int                 `json:"id" db:"id"`
+	UserID        int                 `json:"user_id" db:"user_id"`
+	CustomerID    int                 `json:"customer_id" db:"customer_id"`
+	Type          string              `json:"entry_type" db:"entry_type"`
+	Source        string              `json:"source" db:"source"`
+	Amount        money.Money         `json:"amount" db:"amount"`
+	Description   string              `json:"description" db:"description"`
+	PaymentMethod string              `json:"payment_method,omitempty" db:"payment_method"`
+	EntryDate     time.Time           `json:"entry_date" db:"entry_date"`
+	InvoiceID     *int                `json:"invoice_id,omitempty" db:"invoice_id"`
+	OrderID       *int                `json:"order_id,omitempty" db:"order_id"`
+	CreatedBy     int                 `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt     time.Time           `json:"created_at" db:"created_at"`
+	Allocated     money.Money         `json:"allocated,omitempty"`   // Faturalara dağıtılan tutar (tahsilatlarda)
+	Balance       money.Money         `json:"balance"`               // Hareket sonrası yürüyen bakiye
+	Allocations   []PaymentAllocation `json:"allocations,omitempty"` // Tahsilatın dağıtıldığı faturalar
+}
+
+// IsDebit hareketin borç yönünde olup olmadığını belirtir
+func (e LedgerEntry) IsDebit() bool {
+	return e.Type == LedgerDebit
+}
+
+// IsManual elle girilen (tahsilat ya da müşteriye ödeme) hareketleri belirler;
+// fatura ve siparişlerden oluşan hareketler yalnızca kaynak belge üzerinden değişir
+func (e LedgerEntry) IsManual() bool {
+	return e.Source == LedgerSourcePayment || e.Source == LedgerSourceRefund
+}
+
+// Unallocated tahsilatın henüz faturaya dağıtılmamış kısmı
+func (e LedgerEntry) Unallocated() money.Money {
+	if e.Source != LedgerSourcePayment {
+		return money.Money{}
+	}
+	return e.Amount.Sub(e.Allocated)
+}
+
+// PaymentAllocation tahsilatın bir faturaya dağıtılan kısmı
+type PaymentAllocation struct {
+	ID            int         `json:"id" db:"id"`
+	EntryID       int         `json:"entry_id" db:"entry_id"`
+	InvoiceID     int         `json:"invoice_id" db:"invoice_id"`
+	InvoiceNumber string      `json:"invoice_number,omitempty"`
+	Amount        money.Money `json:"amount" db:"amount"`
+	CreatedAt     time.Time   `json:"created_at" db:"created_at"`
+}
+
+// OpenInvoice ödenmemiş tutarı kalan fatura
+type OpenInvoice struct {
+	InvoiceID     int         `json:"invoice_id"`
+	InvoiceNumber string      `json:"invoice_number"`
+	InvoiceDate   time.Time   `json:"invoice_date"`
+	DueDate       *time.Time  `json:"due_date,omitempty"`
+	Status        string      `json:"status"`
+	Total         money.Money `json:"total"`
+	Outstanding   money.Money `json:"outstanding"` // Tahsilat ve iadeler düşüldükten sonra kalan
+}
+
+// CustomerStatement müşterinin tarih aralığındaki hesap ekstresi
+type CustomerStatement struct {
+	CustomerID     int           `json:"customer_id"`
+	From           *time.Time    `json:"from,omitempty"`
+	To             *time.Time    `json:"to,omitempty"`
+	OpeningBalance money.Money   `json:"opening_balance"` // Aralık başındaki bakiye
+	TotalDebit     money.Money   `json:"total_debit"`
+	TotalCredit    money.Money   `json:"total_credit"`
+	ClosingBalance money.Money   `json:"closing_balance"` // Aralık sonundaki bakiye
+	Entries        []LedgerEntry `json:"entries"`
+}
@@ -1,92 +1,241 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/money"
+)
+
+// Kullanıcı rolleri
+const (
+	RoleOwner      = "owner"      // İşletme sahibi
+	RoleManager    = "manager"    // Yönetici
+	RoleCashier    = "cashier"    // Kasiyer
+	RoleTechnician = "technician" // Teknisyen
+)
 
 type User struct {
-	ID           int       `json:"id" db:"id"`
-	Name         string    `json:"name" db:"name"`
-	Email        string    `json:"email" db:"email"`
-	PasswordHash string    `json:"-" db:"password_hash"`
-	Role         string    `json:"role" db:"role"`
-	BusinessName string    `json:"business_name" db:"business_name"`
-	Phone        string    `json:"phone" db:"phone"`
-	Address      string    `json:"address" db:"address"`
-	CreatedAt    time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+	ID           int        `json:"id" db:"id"`
+	OwnerID      *int       `json:"owner_id,omitempty" db:"owner_id"`
+	Name         string     `json:"name" db:"name"`
+	Email        string     `json:"email" db:"email"`
+	PasswordHash string     `json:"-" db:"password_hash"`
+	Role         string     `json:"role" db:"role"`
+	BusinessName string     `json:"business_name" db:"business_name"`
+	Phone        string     `json:"phone" db:"phone"`
+	Address      string     `json:"address" db:"address"`
+	LastLoginAt  *time.Time `json:"last_login_at,omitempty" db:"last_login_at"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// BusinessID kullanıcının verilerinin ait olduğu işletmenin (sahibin) ID'sini döndürür.
+// Çalışanlar kendi verilerine değil, bağlı oldukları işletme sahibinin verilerine erişir.
+func (u User) BusinessID() int {
+	if u.OwnerID != nil {
+		return *u.OwnerID
+	}
+	return u.ID
+}
+
+// EffectiveRole eski kayıtlardaki varsayılan 'user' rolünü işletme sahibi olarak yorumlar
+func (u User) EffectiveRole() string {
+	if u.Role == "" || u.Role == "user" {
+		return RoleOwner
+	}
+	return u.Role
 }
 
 type Customer struct {
-	ID        int       `json:"id" db:"id"`
-	UserID    int       `json:"user_id" db:"user_id"`
-	Name      string    `json:"name" db:"name"`
-	Email     string    `json:"email" db:"email"`
-	Phone     string    `json:"phone" db:"phone"`
-	Address   string    `json:"address" db:"address"`
-	Notes     string    `json:"notes" db:"notes"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	ID         int         `json:"id" db:"id"`
+	UserID     int         `json:"user_id" db:"user_id"`
+	Name       string      `json:"name" db:"name" binding:"required"`
+	Email      string      `json:"email" db:"email" binding:"omitempty,email"`
+	Phone      string      `json:"phone" db:"phone"`
+	Address    string      `json:"address" db:"address"`
+	Notes      string      `json:"notes" db:"notes"`
+	ArchivedAt *time.Time  `json:"archived_at,omitempty" db:"archived_at"` // Geçmiş kaydı olan müşteri silinmez, arşivlenir
+	Balance    money.Money `json:"balance"`                                // Ödenmemiş faturaların toplamı
+	CreatedAt  time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time   `json:"updated_at" db:"updated_at"`
+}
+
+// IsArchived müşteri arşivlendiyse true döner
+func (c Customer) IsArchived() bool {
+	return c.ArchivedAt != nil
+}
+
+// CustomerStats müşteri detay sayfasındaki özet bilgiler
+type CustomerStats struct {
+	TotalOrders  int         `json:"total_orders"`            // İptal ve iade edilenler hariç
+	TotalSpent   money.Money `json:"total_spent"`             // Ana para biriminde
+	MissingRates bool        `json:"missing_rates,omitempty"` // Kuru girilmemiş döviz siparişleri toplama katılmadı
 }
 
 type Product struct {
-	ID            int       `json:"id" db:"id"`
-	UserID        int       `json:"user_id" db:"user_id"`
-	Name          string    `json:"name" db:"name"`
-	Description   string    `json:"description" db:"description"`
-	Price         float64   `json:"price" db:"price"`
-	Category      string    `json:"category" db:"category"`
-	StockQuantity int       `json:"stock_quantity" db:"stock_quantity"`
-	Unit          string    `json:"unit" db:"unit"`
-	CreatedAt     time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+	ID              int            `json:"id" db:"id"`
+	UserID          int            `json:"user_id" db:"user_id"`
+	Name            string         `json:"name" db:"name" binding:"required"`
+	Description     string         `json:"description" db:"description"`
+	Price           money.Money    `json:"price" db:"price" binding:"gte=0"`
+	Currency        money.Currency `json:"currency" db:"currency"` // Boşsa işletmenin ana para birimi
+	Category        string         `json:"category" db:"category"`
+	StockQuantity   int            `json:"stock_quantity" db:"stock_quantity" binding:"gte=0"`
+	Unit            string         `json:"unit" db:"unit"`
+	IsService       bool           `json:"is_service" db:"is_service"`                                 // Hizmetlerde stok takibi yapılmaz
+	ReorderLevel    *int           `json:"reorder_level" db:"reorder_level" binding:"omitempty,gte=0"` // Boşsa işletmenin varsayılan eşiği geçerlidir
+	ReorderQuantity int            `json:"reorder_quantity" db:"reorder_quantity" binding:"gte=0"`     // Önerilen sipariş miktarı
+	LowStockLevel   int            `json:"low_stock_level"`                                            // Geçerli düşük stok eşiği
+	SoldQuantity    int            `json:"sold_quantity,omitempty"`                                    // En çok satanlar listesinde satılan miktar
+	SoldAmount      money.Money    `json:"sold_amount"`
+	CreatedAt       time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// IsLowStock stok takibi yapılan ürünün stoğu eşiğe inmişse true döner
+func (p Product) IsLowStock() bool {
+	return !p.IsService && p.StockQuantity <= p.LowStockLevel
 }
 
 type Order struct {
-	ID           int         `json:"id" db:"id"`
-	UserID       int         `json:"user_id" db:"user_id"`
-	CustomerID   int         `json:"customer_id" db:"customer_id"`
-	OrderNumber  string      `json:"order_number" db:"order_number"`
-	Status       string      `json:"status" db:"status"`
-	TotalAmount  float64     `json:"total_amount" db:"total_amount"`
-	Notes        string      `json:"notes" db:"notes"`
-	OrderDate    time.Time   `json:"order_date" db:"order_date"`
-	DeliveryDate *time.Time  `json:"delivery_date" db:"delivery_date"`
-	CreatedAt    time.Time   `json:"created_at" db:"created_at"`
-	UpdatedAt    time.Time   `json:"updated_at" db:"updated_at"`
-	Customer     *Customer   `json:"customer,omitempty"`
-	Items        []OrderItem `json:"items,omitempty"`
+	ID           int                 `json:"id" db:"id"`
+	UserID       int                 `json:"user_id" db:"user_id"`
+	CustomerID   int                 `json:"customer_id" db:"customer_id" binding:"required"`
+	OrderNumber  string              `json:"order_number" db:"order_number"`
+	Status       string              `json:"status" db:"status" binding:"omitempty,oneof=pending processing shipped delivered completed cancelled returned"`
+	TotalAmount  money.Money         `json:"total_amount" db:"total_amount"`
+	Currency     money.Currency      `json:"currency" db:"currency"` // Kalemlerin fiyatları bu para biriminde olmalıdır
+	Notes        string              `json:"notes" db:"notes"`
+	OrderDate    time.Time           `json:"order_date" db:"order_date"`
+	DeliveryDate *time.Time          `json:"delivery_date" db:"delivery_date"`
+	OnCredit     bool                `json:"on_credit" db:"on_credit"` // Veresiye; teslimde müşterinin cari hesabına borç yazılır
+	CreatedAt    time.Time           `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at" db:"updated_at"`
+	Customer     *Customer           `json:"customer,omitempty"`
+	Items        []OrderItem         `json:"items,omitempty" binding:"dive"`
+	History      []OrderStatusChange `json:"history,omitempty"`
 }
 
 type OrderItem struct {
-	ID         int      `json:"id" db:"id"`
-	OrderID    int      `json:"order_id" db:"order_id"`
-	ProductID  int      `json:"product_id" db:"product_id"`
-	Quantity   int      `json:"quantity" db:"quantity"`
-	UnitPrice  float64  `json:"unit_price" db:"unit_price"`
-	TotalPrice float64  `json:"total_price" db:"total_price"`
-	Product    *Product `json:"product,omitempty"`
+	ID         int         `json:"id" db:"id"`
+	OrderID    int         `json:"order_id" db:"order_id"`
+	ProductID  int         `json:"product_id" db:"product_id" binding:"required"`
+	Quantity   int         `json:"quantity" db:"quantity" binding:"required,gt=0"`
+	UnitPrice  money.Money `json:"unit_price" db:"unit_price" binding:"gte=0"`
+	TotalPrice money.Money `json:"total_price" db:"total_price"`
+	Product    *Product    `json:"product,omitempty"`
 }
 
 type Transaction struct {
-	ID              int       `json:"id" db:"id"`
-	UserID          int       `json:"user_id" db:"user_id"`
-	Type            string    `json:"type" db:"type"` // income, expense
-	Category        string    `json:"category" db:"category"`
-	Amount          float64   `json:"amount" db:"amount"`
-	Description     string    `json:"description" db:"description"`
-	TransactionDate time.Time `json:"transaction_date" db:"transaction_date"`
-	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	ID              int            `json:"id" db:"id"`
+	UserID          int            `json:"user_id" db:"user_id"`
+	Type            string         `json:"type" db:"type" binding:"required,oneof=income expense"` // income, expense
+	Category        string         `json:"category" db:"category" binding:"required"`
+	Amount          money.Money    `json:"amount" db:"amount" binding:"required,gt=0"`
+	Currency        money.Currency `json:"currency" db:"currency"`
+	Description     string         `json:"description" db:"description"`
+	TransactionDate time.Time      `json:"transaction_date" db:"transaction_date"`
+	OrderID         *int           `json:"order_id,omitempty" db:"order_id"` // Siparişten otomatik oluşan gelir kaydı
+	CreatedAt       time.Time      `json:"created_at" db:"created_at"`
+}
+
+// Randevu durumları
+const (
+	AppointmentNew       = "new"       // Yeni
+	AppointmentConfirmed = "confirmed" // Onaylandı
+	AppointmentCompleted = "completed" // Tamamlandı
+	AppointmentCanceled  = "canceled"  // İptal edildi
+)
+
+type Appointment struct {
+	ID          int       `json:"id" db:"id"`
+	UserID      int       `json:"user_id" db:"user_id"`
+	CustomerID  int       `json:"customer_id" db:"customer_id" binding:"required"`
+	StaffID     int       `json:"staff_id" db:"staff_id"` // Randevuyu üstlenen personel
+	Title       string    `json:"title" db:"title" binding:"required"`
+	Description string    `json:"description" db:"description"`
+	StartTime   time.Time `json:"start_time" db:"start_time" binding:"required"`
+	EndTime     time.Time `json:"end_time" db:"end_time" binding:"required"`
+	Status      string    `json:"status" db:"status" binding:"omitempty,oneof=new confirmed completed canceled"`
+	Reminder    int       `json:"reminder" db:"reminder" binding:"gte=0"` // Kaç dakika önce hatırlatılacağı
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	Customer    *Customer `json:"customer,omitempty"`
+	StaffName   string    `json:"staff_name,omitempty"`
+}
+
+// Duration randevu süresini dakika olarak döndürür
+func (a Appointment) Duration() int {
+	return int(a.EndTime.Sub(a.StartTime).Minutes())
+}
+
+// Müşteri etkinlik türleri
+const (
+	ActivityInvoiceEmailed = "invoice_emailed" // Fatura e-postayla gönderildi
+	ActivityMessageSent    = "message_sent"    // Kısa mesaj gönderildi
+)
+
+// CustomerActivity müşteri etkinlik geçmişindeki bir kayıt
+type CustomerActivity struct {
+	ID             int       `json:"id" db:"id"`
+	UserID         int       `json:"user_id" db:"user_id"`
+	CustomerID     int       `json:"customer_id" db:"customer_id"`
+	Type           string    `json:"activity_type" db:"activity_type"`
+	Description    string    `json:"description" db:"description"`
+	InvoiceID      *int      `json:"invoice_id,omitempty" db:"invoice_id"`
+	OutboxID       *int      `json:"outbox_id,omitempty" db:"outbox_id"`
+	DeliveryStatus string    `json:"delivery_status,omitempty"` // Kuyruktaki iletinin durumu
+	DeliveryError  string    `json:"delivery_error,omitempty"`
+	CreatedBy      int       `json:"created_by,omitempty" db:"created_by"`
+	CreatedByName  string    `json:"created_by_name,omitempty"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+}
+
+// Bildirim türleri (notifications.html filtreleriyle aynı)
+const (
+	NotificationStock       = "stock"       // Düşük stok
+	NotificationAppointment = "appointment" // Randevu oluşturuldu, değişti, yaklaşıyor
+	NotificationInvoice     = "invoice"     // Vadesi geçen fatura
+	NotificationSystem      = "system"
+)
+
+// Bildirim önem dereceleri (Metronic renk sınıflarıyla eşleşir)
+const (
+	SeverityInfo    = "info"
+	SeveritySuccess = "success"
+	SeverityWarning = "warning"
+	SeverityDanger  = "danger"
+)
+
+// Notification kullanıcıya gösterilen bildirim
+type Notification struct {
+	ID        int        `json:"id" db:"id"`
+	UserID    int        `json:"user_id" db:"user_id"` // Bildirimi alan kullanıcı
+	Type      string     `json:"type" db:"type"`
+	Severity  string     `json:"severity" db:"severity"`
+	Title     string     `json:"title" db:"title"`
+	Message   string     `json:"message" db:"message"`
+	Link      string     `json:"link,omitempty" db:"link"`
+	ReadAt    *time.Time `json:"read_at" db:"read_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// IsRead bildirim okunduysa true döner
+func (n Notification) IsRead() bool {
+	return n.ReadAt != nil
 }
 
 // Dashboard için özet veriler
 type DashboardStats struct {
-	TotalCustomers   int       `json:"total_customers"`
-	TotalProducts    int       `json:"total_products"`
-	TotalOrders      int       `json:"total_orders"`
-	PendingOrders    int       `json:"pending_orders"`
-	MonthlyRevenue   float64   `json:"monthly_revenue"`
-	MonthlyExpenses  float64   `json:"monthly_expenses"`
-	MonthlyProfit    float64   `json:"monthly_profit"`
-	RecentOrders     []Order   `json:"recent_orders"`
-	TopProducts      []Product `json:"top_products"`
-	LowStockProducts []Product `json:"low_stock_products"`
+	TotalCustomers   int         `json:"total_customers"`
+	TotalProducts    int         `json:"total_products"`
+	TotalOrders      int         `json:"total_orders"`
+	PendingOrders    int         `json:"pending_orders"`
+	MonthlyRevenue   money.Money `json:"monthly_revenue"`
+	MonthlyExpenses  money.Money `json:"monthly_expenses"`
+	MonthlyProfit    money.Money `json:"monthly_profit"`
+	MissingRates     bool        `json:"missing_rates,omitempty"` // Kuru girilmemiş döviz kayıtları toplama katılmadı
+	RecentOrders     []Order     `json:"recent_orders"`
+	TopProducts      []Product   `json:"top_products"`
+	LowStockProducts []Product   `json:"low_stock_products"`
 }
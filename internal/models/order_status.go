@@ -0,0 +1,87 @@
+package models
+
+import "time"
+
+// Sipariş durumları
+const (
+	OrderPending    = "pending"    // Beklemede
+	OrderProcessing = "processing" // Hazırlanıyor
+	OrderShipped    = "shipped"    // Kargoya verildi
+	OrderDelivered  = "delivered"  // Teslim edildi
+	OrderCompleted  = "completed"  // Tamamlandı
+	OrderCancelled  = "cancelled"  // İptal edildi
+	OrderReturned   = "returned"   // İade edildi
+)
+
+// orderStatusLabels durumların ekranda gösterilen adları
+var orderStatusLabels = map[string]string{
+	OrderPending:    "Beklemede",
+	OrderProcessing: "Hazırlanıyor",
+	OrderShipped:    "Kargoya Verildi",
+	OrderDelivered:  "Teslim Edildi",
+	OrderCompleted:  "Tamamlandı",
+	OrderCancelled:  "İptal Edildi",
+	OrderReturned:   "İade Edildi",
+}
+
+// orderTransitions izin verilen durum geçişleri
+var orderTransitions = map[string][]string{
+	OrderPending:    {OrderProcessing, OrderCompleted, OrderCancelled},
+	OrderProcessing: {OrderShipped, OrderCompleted, OrderCancelled},
+	OrderShipped:    {OrderDelivered, OrderReturned},
+	OrderDelivered:  {OrderCompleted, OrderReturned},
+	OrderCompleted:  {OrderReturned},
+	OrderCancelled:  {},
+	OrderReturned:   {},
+}
+
+// IsValidOrderStatus durumun tanımlı olup olmadığını döndürür
+func IsValidOrderStatus(status string) bool {
+	_, ok := orderTransitions[status]
+	return ok
+}
+
+// CanTransitionOrder siparişin from durumundan to durumuna geçip geçemeyeceğini döndürür
+func CanTransitionOrder(from, to string) bool {
+	for _, next := range orderTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// NextOrderStatuses from durumundan geçilebilecek durumları döndürür
+func NextOrderStatuses(from string) []string {
+	return orderTransitions[from]
+}
+
+// OrderStatusLabel durumun Türkçe adını döndürür
+func OrderStatusLabel(status string) string {
+	if label, ok := orderStatusLabels[status]; ok {
+		return label
+	}
+	return status
+}
+
+// IsOrderSettled ödemesi alınmış (gelir kaydı oluşturulan) durumları belirler
+func IsOrderSettled(status string) bool {
+	return status == OrderDelivered || status == OrderCompleted
+}
+
+// IsOrderReversed stokların iade edildiği ve gelirin geri alındığı durumları belirler
+func IsOrderReversed(status string) bool {
+	return status == OrderCancelled || status == OrderReturned
+}
+
+// OrderStatusChange sipariş durum geçmişindeki bir kayıt
+type OrderStatusChange struct {
+	ID            int       `json:"id" db:"id"`
+	OrderID       int       `json:"order_id" db:"order_id"`
+	FromStatus    string    `json:"from_status" db:"from_status"`
+	ToStatus      string    `json:"to_status" db:"to_status"`
+	ChangedBy     int       `json:"changed_by" db:"changed_by"`
+	ChangedByName string    `json:"changed_by_name"`
+	Note          string    `json:"note" db:"note"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
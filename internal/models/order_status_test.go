@@ -0,0 +1,48 @@
+package models
+
+import "testing"
+
+func TestCanTransitionOrder(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{OrderPending, OrderProcessing, true},
+		{OrderPending, OrderShipped, false},
+		{OrderProcessing, OrderCancelled, true},
+		{OrderShipped, OrderCancelled, false},
+		{OrderShipped, OrderReturned, true},
+		{OrderDelivered, OrderCompleted, true},
+		{OrderCompleted, OrderReturned, true},
+		{OrderCompleted, OrderPending, false},
+		{OrderCancelled, OrderPending, false},
+		{OrderReturned, OrderCompleted, false},
+		{"bilinmeyen", OrderPending, false},
+	}
+	for _, tt := range tests {
+		if got := CanTransitionOrder(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanTransitionOrder(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestOrderStatusClassification(t *testing.T) {
+	for status := range orderTransitions {
+		if !IsValidOrderStatus(status) {
+			t.Errorf("IsValidOrderStatus(%s) = false", status)
+		}
+		if OrderStatusLabel(status) == status {
+			t.Errorf("status %s has no label", status)
+		}
+		if IsOrderSettled(status) && IsOrderReversed(status) {
+			t.Errorf("status %s is both settled and reversed", status)
+		}
+		// Geri alınan siparişler son durumdur
+		if IsOrderReversed(status) && len(NextOrderStatuses(status)) != 0 {
+			t.Errorf("reversed status %s has further transitions", status)
+		}
+	}
+	if IsValidOrderStatus("arsivlendi") || OrderStatusLabel("arsivlendi") != "arsivlendi" {
+		t.Error("unknown status accepted")
+	}
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/money"
+)
+
+// Yaşlandırma dilimleri; gecikme günü vade tarihinden (yoksa fatura tarihinden) itibaren sayılır
+const (
+	Aging0To30  = "0-30"
+	Aging31To60 = "31-60"
+	Aging61To90 = "61-90"
+	AgingOver90 = "90+"
+)
+
+// AgingBucket gecikme gününün düştüğü dilimi döndürür
+func AgingBucket(daysOverdue int) string {
+	switch {
+	case daysOverdue <= 30:
+		return Aging0To30
+	case daysOverdue <= 60:
+		return Aging31To60
+	case daysOverdue <= 90:
+		return Aging61To90
+	default:
+		return AgingOver90
+	}
+}
+
+// AgingBuckets açık tutarların gecikme dilimlerine dağılımı
+type AgingBuckets struct {
+	Days0To30  money.Money `json:"days_0_30"`
+	Days31To60 money.Money `json:"days_31_60"`
+	Days61To90 money.Money `json:"days_61_90"`
+	Over90     money.Money `json:"over_90"`
+	Total      money.Money `json:"total"`
+}
+
+// Add tutarı gecikme gününe göre ilgili dilime ekler
+func (b *AgingBuckets) Add(daysOverdue int, amount money.Money) {
+	switch AgingBucket(daysOverdue) {
+	case Aging0To30:
+		b.Days0To30 = b.Days0To30.Add(amount)
+	case Aging31To60:
+		b.Days31To60 = b.Days31To60.Add(amount)
+	case Aging61To90:
+		b.Days61To90 = b.Days61To90.Add(amount)
+	default:
+		b.Over90 = b.Over90.Add(amount)
+	}
+	b.Total = b.Total.Add(amount)
+}
+
+// AgingInvoice yaşlandırma raporundaki açık fatura
+type AgingInvoice struct {
+	OpenInvoice
+	DaysOverdue int    `json:"days_overdue"` // Vadesi gelmemiş faturalarda 0
+	Bucket      string `json:"bucket"`
+}
+
+// CustomerAging müşterinin açık faturalarının gecikme dağılımı
+type CustomerAging struct {
+	CustomerID    int            `json:"customer_id"`
+	CustomerName  string         `json:"customer_name"`
+	CustomerPhone string         `json:"customer_phone,omitempty"`
+	Balance       money.Money    `json:"balance"` // Cari hesap bakiyesi; faturasız veresiye ve dağıtılmamış tahsilatları da içerir
+	Buckets       AgingBuckets   `json:"buckets"`
+	Invoices      []AgingInvoice `json:"invoices"`
+}
+
+// AgingReport işletmenin alacak yaşlandırma raporu
+type AgingReport struct {
+	AsOf      time.Time       `json:"as_of"`
+	Customers []CustomerAging `json:"customers"`
+	Totals    AgingBuckets    `json:"totals"`
+}
+
+// DunningNotice vadesi geçen fatura için gönderilen ödeme hatırlatması
+type DunningNotice struct {
+	ID            int         `json:"id" db:"id"`
+	UserID        int         `json:"user_id" db:"user_id"`
+	CustomerID    int         `json:"customer_id" db:"customer_id"`
+	CustomerName  string      `json:"customer_name,omitempty"`
+	InvoiceID     int         `json:"invoice_id" db:"invoice_id"`
+	InvoiceNumber string      `json:"invoice_number,omitempty"`
+	Stage         int         `json:"stage" db:"stage"` // Kademenin gecikme günü
+	DaysOverdue   int         `json:"days_overdue" db:"days_overdue"`
+	Outstanding   money.Money `json:"outstanding" db:"outstanding"`
+	OutboxID      *int        `json:"outbox_id,omitempty" db:"outbox_id"`
+	CreatedAt     time.Time   `json:"created_at" db:"created_at"`
+}
+
+// MessageQueued hatırlatmanın müşteriye mesaj olarak da gönderilip gönderilmediğini belirtir
+func (n DunningNotice) MessageQueued() bool {
+	return n.OutboxID != nil
+}
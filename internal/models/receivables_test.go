@@ -0,0 +1,35 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/umutaraz/tradesman-app/internal/money"
+)
+
+func TestAgingBuckets(t *testing.T) {
+	tests := []struct {
+		days int
+		want string
+	}{
+		{0, Aging0To30},
+		{30, Aging0To30},
+		{31, Aging31To60},
+		{60, Aging31To60},
+		{61, Aging61To90},
+		{90, Aging61To90},
+		{91, AgingOver90},
+	}
+	var b AgingBuckets
+	for _, tt := range tests {
+		if got := AgingBucket(tt.days); got != tt.want {
+			t.Errorf("AgingBucket(%d) = %s, want %s", tt.days, got, tt.want)
+		}
+		b.Add(tt.days, money.TL(1000))
+	}
+
+	want := AgingBuckets{Days0To30: money.TL(2000), Days31To60: money.TL(2000), Days61To90: money.TL(2000),
+		Over90: money.TL(1000), Total: money.TL(7000)}
+	if b != want {
+		t.Errorf("buckets = %+v, want %+v", b, want)
+	}
+}
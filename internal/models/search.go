@@ -0,0 +1,19 @@
+package models
+
+// Arama sonucu türleri
+const (
+	SearchCustomer = "customer"
+	SearchProduct  = "product"
+	SearchOrder    = "order"
+	SearchInvoice  = "invoice"
+)
+
+// SearchResult genel aramada eşleşen kayıt
+type SearchResult struct {
+	Type     string  `json:"type"`
+	ID       int     `json:"id"`
+	Title    string  `json:"title"`
+	Subtitle string  `json:"subtitle,omitempty"`
+	URL      string  `json:"url"`
+	Score    float64 `json:"score"` // Yüksek puan daha iyi eşleşme
+}
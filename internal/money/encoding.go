@@ -0,0 +1,88 @@
+package money
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// MarshalJSON tutarı iki ondalıklı JSON sayısı olarak yazar (1234.56)
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.Decimal()), nil
+}
+
+// UnmarshalJSON sayı ya da metin olarak verilmiş tutarı okur: 1234.56, "1234.56", "1.234,56".
+// Metinler Parse kurallarıyla okunur; sayılarda nokta ondalık ayırıcıdır.
+// Para birimi değişmez; sıfır değerde DefaultCurrency'dir.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	text := string(data)
+	var parsed Money
+	var err error
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+		parsed, err = Parse(text, m.currency)
+	} else if f, ferr := strconv.ParseFloat(text, 64); ferr != nil {
+		return fmt.Errorf("geçersiz tutar: %s", text)
+	} else {
+		if bytes.ContainsAny(data, "eE") {
+			// Üslü sayılar (1e3) ondalık yazıma çevrilir
+			text = strconv.FormatFloat(f, 'f', -1, 64)
+		}
+		// JSON sayısında nokta her zaman ondalık ayırıcıdır; 1.234 binlik olarak okunmaz
+		parsed, err = parseAt(text, strings.IndexByte(text, '.'), m.currency)
+	}
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Value tutarı veritabanına kuruş cinsinden tam sayı olarak yazar
+func (m Money) Value() (driver.Value, error) {
+	return m.amount, nil
+}
+
+// Scan kuruş cinsinden saklanmış tutarı okur. SQLite'ın REAL kolonları ve bunların SUM gibi
+// toplamları tam sayı olmayan tipte dönebildiğinden bunlar en yakın kuruşa yuvarlanır.
+func (m *Money) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		m.amount = 0
+	case int64:
+		m.amount = v
+	case float64:
+		m.amount = int64(math.Round(v))
+	case []byte:
+		return m.scanText(string(v))
+	case string:
+		return m.scanText(v)
+	default:
+		return fmt.Errorf("money: %T türü tutar olarak okunamaz", src)
+	}
+	return nil
+}
+
+func (m *Money) scanText(s string) error {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		m.amount = n
+		return nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("money: %q tutar olarak okunamaz", s)
+	}
+	m.amount = int64(math.Round(f))
+	return nil
+}
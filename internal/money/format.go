@@ -0,0 +1,53 @@
+package money
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// String tutarı para birimi işaretiyle Türkçe biçimde yazar: ₺1.234,56, -₺12,50
+func (m Money) String() string {
+	sign, number := m.parts('.', ',')
+	return sign + m.Currency().Symbol() + number
+}
+
+// Number tutarı işaretsiz Türkçe biçimde yazar: 1.234,56. ₺ karakterini gösteremeyen PDF ve SMS
+// metinleri "TL" ekiyle birlikte bunu kullanır.
+func (m Money) Number() string {
+	sign, number := m.parts('.', ',')
+	return sign + number
+}
+
+// Decimal tutarı binlik ayırıcısız, noktalı ondalıkla yazar: 1234.56. Form alanları ve
+// JavaScript'e aktarılan değerler içindir.
+func (m Money) Decimal() string {
+	sign, number := m.parts(0, '.')
+	return sign + number
+}
+
+// parts tutarın işaretini ve verilen ayırıcılarla biçimlenmiş mutlak değerini döndürür;
+// thousands 0 ise binlik ayırıcı kullanılmaz
+func (m Money) parts(thousands, decimal rune) (sign, number string) {
+	amount := m.amount
+	if amount < 0 {
+		sign = "-"
+	}
+
+	// En küçük int64'ün mutlak değeri taşacağından bölme işaretli yapılır
+	whole, cents := amount/100, amount%100
+	if whole < 0 {
+		whole = -whole
+	}
+	if cents < 0 {
+		cents = -cents
+	}
+
+	digits := strconv.FormatUint(uint64(whole), 10)
+	if thousands != 0 {
+		for i := len(digits) - 3; i > 0; i -= 3 {
+			digits = digits[:i] + string(thousands) + digits[i:]
+		}
+	}
+
+	return sign, fmt.Sprintf("%s%c%02d", digits, decimal, cents)
+}
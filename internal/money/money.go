@@ -0,0 +1,367 @@
+// Package money para tutarlarını kayan nokta yerine tam sayı alt birim (kuruş) olarak tutar.
+// Toplama ve çıkarma kesindir; yalnızca oranla çarpma (KDV, kısmi miktar) yuvarlama gerektirir
+// ve yuvarlama kuralı çağıran tarafından açıkça seçilir.
+//
+// Veritabanında tutarlar kuruş cinsinden tam sayı, JSON'da iki ondalıklı sayı (1234.56) olarak
+// saklanır; şablonlarda ₺1.234,56 biçiminde gösterilir.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Currency ISO 4217 para birimi kodu
+type Currency string
+
+// Desteklenen para birimleri
+const (
+	TRY Currency = "TRY"
+	USD Currency = "USD"
+	EUR Currency = "EUR"
+	GBP Currency = "GBP"
+)
+
+// DefaultCurrency para birimi belirtilmemiş tutarların birimi
+const DefaultCurrency = TRY
+
+// Currencies desteklenen para birimleri, seçim listelerindeki sırasıyla. Hepsinin alt birimi
+// ana birimin yüzde biridir.
+var Currencies = []Currency{TRY, USD, EUR, GBP}
+
+// ParseCurrency para birimi kodunu okur (büyük/küçük harf duyarsız); desteklenmeyen kodlar hata döner
+func ParseCurrency(s string) (Currency, error) {
+	c := Currency(strings.ToUpper(strings.TrimSpace(s)))
+	if !c.Valid() {
+		return "", fmt.Errorf("desteklenmeyen para birimi: %s", s)
+	}
+	return c, nil
+}
+
+// Valid para birimi desteklenenlerden biriyse true döner
+func (c Currency) Valid() bool {
+	for _, supported := range Currencies {
+		if c == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// symbols para birimlerinin gösterimde kullanılan işaretleri
+var symbols = map[Currency]string{
+	TRY: "₺",
+	USD: "$",
+	EUR: "€",
+	GBP: "£",
+}
+
+// Symbol para biriminin işaretini döndürür; işareti bilinmeyen birimlerde kodun kendisidir
+func (c Currency) Symbol() string {
+	if s, ok := symbols[c]; ok {
+		return s
+	}
+	return string(c)
+}
+
+// names para birimlerinin Türkçe adları
+var names = map[Currency]string{
+	TRY: "Türk Lirası",
+	USD: "Amerikan Doları",
+	EUR: "Euro",
+	GBP: "İngiliz Sterlini",
+}
+
+// Name para biriminin Türkçe adını döndürür; adı bilinmeyen birimlerde kodun kendisidir
+func (c Currency) Name() string {
+	if n, ok := names[c]; ok {
+		return n
+	}
+	return string(c)
+}
+
+// Rounding alt birimin altında kalan kesrin nasıl yuvarlanacağı
+type Rounding int
+
+const (
+	// HalfUp yarımı sıfırdan uzağa yuvarlar (0,005 → 0,01); faturalarda KDV için kullanılır
+	HalfUp Rounding = iota
+	// HalfEven yarımı en yakın çift kuruşa yuvarlar (banker yuvarlaması); çok sayıda dönüşümde
+	// yuvarlama hatasının tek yöne birikmesini önler
+	HalfEven
+)
+
+// ErrCurrencyMismatch farklı para birimlerindeki tutarlar birlikte kullanıldığında döner
+var ErrCurrencyMismatch = errors.New("para birimleri uyuşmuyor")
+
+// Money bir para birimindeki tutar. Sıfır değeri para birimsiz sıfırdır: toplamlarda başlangıç
+// değeri olarak kullanılabilir, ilk eklenen tutarın birimini alır ve tek başına 0 TL sayılır.
+type Money struct {
+	amount   int64 // Alt birim (kuruş) cinsinden
+	currency Currency
+}
+
+// New alt birim cinsinden tutar oluşturur; para birimi boşsa DefaultCurrency kullanılır
+func New(minor int64, currency Currency) Money {
+	return Money{amount: minor, currency: currency}
+}
+
+// TL kuruş cinsinden Türk lirası tutarı oluşturur
+func TL(kurus int64) Money {
+	return Money{amount: kurus, currency: TRY}
+}
+
+// FromFloat kayan noktalı tutarı verilen kurala göre kuruşa yuvarlar. Yalnızca dış kaynaklardan
+// gelen sayılar için kullanılmalıdır; hesaplamalar Money üzerinde yapılır.
+func FromFloat(amount float64, currency Currency, rounding Rounding) Money {
+	if math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return New(0, currency)
+	}
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(amount, 'f', -1, 64))
+	return New(roundRat(r.Mul(r, big.NewRat(100, 1)), rounding), currency)
+}
+
+// Parse ondalık tutarı okur. Hem 1234.56 hem de Türkçe 1.234,56 yazımı kabul edilir: iki ayırıcı
+// birlikte kullanılmışsa sondaki ondalık ayırıcıdır. Tek başına kullanılan ve ardından tam üç hane
+// gelen nokta (1.234) binlik ayırıcı sayılır. İki haneden fazla ondalık hata sayılır.
+func Parse(s string, currency Currency) (Money, error) {
+	s = strings.TrimSpace(s)
+	decimal := strings.LastIndexAny(s, ".,")
+	if decimal >= 0 && strings.Count(s, s[decimal:decimal+1]) > 1 {
+		// 1.234.567 gibi yalnızca binlik ayırıcı içeren yazım
+		decimal = -1
+	}
+	if decimal >= 0 && s[decimal] == '.' && !strings.Contains(s, ",") && isDigits(s[decimal+1:], 3) {
+		decimal = -1
+	}
+	return parseAt(s, decimal, currency)
+}
+
+// isDigits s tam n haneden oluşuyorsa true döner
+func isDigits(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// parseAt tutarı decimal konumundaki ayırıcıyı ondalık sayarak okur; decimal -1 ise tutar ondalıksızdır
+// ve diğer nokta ile virgüller binlik ayırıcıdır
+func parseAt(s string, decimal int, currency Currency) (Money, error) {
+	if s == "" {
+		return Money{}, errors.New("tutar boş")
+	}
+
+	whole, frac := s, ""
+	if decimal >= 0 {
+		whole, frac = s[:decimal], s[decimal+1:]
+	}
+	whole = strings.NewReplacer(".", "", ",", "").Replace(whole)
+
+	negative := strings.HasPrefix(whole, "-")
+	whole = strings.TrimPrefix(strings.TrimPrefix(whole, "-"), "+")
+	if whole == "" {
+		if frac == "" {
+			return Money{}, fmt.Errorf("geçersiz tutar: %s", s)
+		}
+		whole = "0"
+	}
+	if len(frac) > 2 {
+		return Money{}, fmt.Errorf("tutar en fazla iki ondalık içerebilir: %s", s)
+	}
+	frac += strings.Repeat("0", 2-len(frac))
+
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || strings.ContainsAny(whole, "+-") {
+		return Money{}, fmt.Errorf("geçersiz tutar: %s", s)
+	}
+	cents, err := strconv.ParseInt(frac, 10, 64)
+	if err != nil || strings.ContainsAny(frac, "+-") {
+		return Money{}, fmt.Errorf("geçersiz tutar: %s", s)
+	}
+	if units > (math.MaxInt64-cents)/100 {
+		return Money{}, fmt.Errorf("tutar çok büyük: %s", s)
+	}
+
+	minor := units*100 + cents
+	if negative {
+		minor = -minor
+	}
+	return New(minor, currency), nil
+}
+
+// WithCurrency aynı tutarı verilen para biriminde döndürür. Çevrim yapmaz; veritabanından para
+// birimi ayrı kolonda okunan tutarları etiketlemek içindir.
+func (m Money) WithCurrency(currency Currency) Money {
+	return Money{amount: m.amount, currency: currency}
+}
+
+// Minor tutarı alt birim (kuruş) cinsinden döndürür
+func (m Money) Minor() int64 {
+	return m.amount
+}
+
+// Currency tutarın para birimini döndürür
+func (m Money) Currency() Currency {
+	if m.currency == "" {
+		return DefaultCurrency
+	}
+	return m.currency
+}
+
+// Float64 tutarı ana birim cinsinden yaklaşık olarak döndürür; yalnızca grafik ve dışa aktarım içindir
+func (m Money) Float64() float64 {
+	return float64(m.amount) / 100
+}
+
+// SameCurrency iki tutarın para birimi aynıysa true döner
+func (m Money) SameCurrency(o Money) bool {
+	return m.Currency() == o.Currency()
+}
+
+// join iki tutarın ortak para birimini döndürür. Farklı para birimlerindeki tutarlar
+// karıştırıldığında paniğe geçer; bu bir programlama hatasıdır, çevrim açıkça yapılmalıdır.
+func (m Money) join(o Money) Currency {
+	switch {
+	case m.currency == "" && m.amount == 0:
+		return o.currency
+	case o.currency == "" && o.amount == 0:
+		return m.currency
+	case !m.SameCurrency(o):
+		panic(fmt.Errorf("%w: %s ile %s", ErrCurrencyMismatch, m.Currency(), o.Currency()))
+	}
+	return m.Currency()
+}
+
+// Add iki tutarı toplar; para birimleri aynı olmalıdır
+func (m Money) Add(o Money) Money {
+	return Money{amount: m.amount + o.amount, currency: m.join(o)}
+}
+
+// Sub o'yu m'den çıkarır; para birimleri aynı olmalıdır
+func (m Money) Sub(o Money) Money {
+	return Money{amount: m.amount - o.amount, currency: m.join(o)}
+}
+
+// Neg tutarın işaretini çevirir
+func (m Money) Neg() Money {
+	return Money{amount: -m.amount, currency: m.currency}
+}
+
+// Abs tutarın mutlak değerini döndürür
+func (m Money) Abs() Money {
+	if m.amount < 0 {
+		return m.Neg()
+	}
+	return m
+}
+
+// Times tutarı tam sayı miktarla çarpar; sonuç kesindir
+func (m Money) Times(n int) Money {
+	return Money{amount: m.amount * int64(n), currency: m.currency}
+}
+
+// Mul tutarı ondalık bir çarpanla (kısmi miktar, kur) çarpıp kurala göre kuruşa yuvarlar.
+// Çarpan en kısa ondalık gösterimiyle alınır; 1.1 tam olarak 11/10 sayılır.
+func (m Money) Mul(factor float64, rounding Rounding) Money {
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(factor, 'f', -1, 64))
+	if !ok {
+		return Money{currency: m.currency}
+	}
+	return Money{amount: roundRat(r.Mul(r, new(big.Rat).SetInt64(m.amount)), rounding), currency: m.currency}
+}
+
+// Convert tutarı kurla (1 birim m = rate birim to) çarpıp hedef para birimine çevirir
+func (m Money) Convert(to Currency, rate *big.Rat, rounding Rounding) Money {
+	if m.Currency() == to {
+		return m.WithCurrency(to)
+	}
+	r := new(big.Rat).Mul(rate, new(big.Rat).SetInt64(m.amount))
+	return Money{amount: roundRat(r, rounding), currency: to}
+}
+
+// Percent tutarın yüzde rate'ini kurala göre kuruşa yuvarlayarak döndürür (ör. KDV)
+func (m Money) Percent(rate int, rounding Rounding) Money {
+	return Money{amount: roundRat(big.NewRat(m.amount*int64(rate), 100), rounding), currency: m.currency}
+}
+
+// Cmp m < o ise -1, eşitse 0, büyükse 1 döndürür; para birimleri aynı olmalıdır
+func (m Money) Cmp(o Money) int {
+	m.join(o)
+	switch {
+	case m.amount < o.amount:
+		return -1
+	case m.amount > o.amount:
+		return 1
+	}
+	return 0
+}
+
+// IsZero tutar sıfırsa true döner
+func (m Money) IsZero() bool {
+	return m.amount == 0
+}
+
+// IsPositive tutar sıfırdan büyükse true döner
+func (m Money) IsPositive() bool {
+	return m.amount > 0
+}
+
+// IsNegative tutar sıfırdan küçükse true döner
+func (m Money) IsNegative() bool {
+	return m.amount < 0
+}
+
+// Min iki tutardan küçük olanı döndürür
+func Min(a, b Money) Money {
+	if a.Cmp(b) <= 0 {
+		return a
+	}
+	return b
+}
+
+// Sum tutarları toplar; para birimleri aynı olmalıdır
+func Sum(amounts ...Money) Money {
+	var total Money
+	for _, amount := range amounts {
+		total = total.Add(amount)
+	}
+	return total
+}
+
+// roundRat kesri kurala göre en yakın tam sayıya yuvarlar
+func roundRat(r *big.Rat, rounding Rounding) int64 {
+	num, den := r.Num(), r.Denom()
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Sign() == 0 {
+		return quo.Int64()
+	}
+
+	// Kalanın iki katını paydayla karşılaştırarak yarımdan büyük, küçük ya da tam yarım olduğunu bul
+	half := new(big.Int).Abs(rem)
+	half.Lsh(half, 1)
+	away := false
+	switch half.Cmp(den) {
+	case 1:
+		away = true
+	case 0:
+		away = rounding == HalfUp || quo.Bit(0) == 1
+	}
+	if away {
+		if num.Sign() < 0 {
+			quo.Sub(quo, big.NewInt(1))
+		} else {
+			quo.Add(quo, big.NewInt(1))
+		}
+	}
+	return quo.Int64()
+}
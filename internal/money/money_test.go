@@ -0,0 +1,321 @@
+package money
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{"1234.56", 123456, false},
+		{"1.234,56", 123456, false},
+		{"1,234.56", 123456, false},
+		{"12,5", 1250, false},
+		{" 40 ", 4000, false},
+		{"-3,75", -375, false},
+		{"+7", 700, false},
+		{",5", 50, false},
+		{"1.234.567", 123456700, false},
+		{"1.234.567,89", 123456789, false},
+		// Tek başına, ardından üç hane gelen nokta binlik ayırıcıdır
+		{"1.234", 123400, false},
+		{"-12.500", -1250000, false},
+		{"1.23", 123, false},
+		{"1.2345", 0, true},
+		{"1,234.567", 0, true},
+		{"", 0, true},
+		{"-", 0, true},
+		{"abc", 0, true},
+		{"1.5x", 0, true},
+		{"--5", 0, true},
+		{"1,234", 0, true}, // Virgülden sonra üç hane ondalık sayılır
+		{"99999999999999999999", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in, TRY)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Parse(%q) err = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if err == nil && (got.Minor() != tt.want || got.Currency() != TRY) {
+			t.Errorf("Parse(%q) = %d %s, want %d TRY", tt.in, got.Minor(), got.Currency(), tt.want)
+		}
+	}
+}
+
+func TestRounding(t *testing.T) {
+	tests := []struct {
+		name     string
+		got      func(Rounding) Money
+		halfUp   int64
+		halfEven int64
+	}{
+		{"FromFloat 0.125", func(r Rounding) Money { return FromFloat(0.125, TRY, r) }, 13, 12},
+		{"FromFloat 0.135", func(r Rounding) Money { return FromFloat(0.135, TRY, r) }, 14, 14},
+		{"FromFloat -0.125", func(r Rounding) Money { return FromFloat(-0.125, TRY, r) }, -13, -12},
+		// İkili gösterimde 1.00499… olsa da en kısa ondalık yazımıyla tam yarım sayılır
+		{"FromFloat 1.005", func(r Rounding) Money { return FromFloat(1.005, TRY, r) }, 101, 100},
+		{"FromFloat 2.674", func(r Rounding) Money { return FromFloat(2.674, TRY, r) }, 267, 267},
+		{"FromFloat NaN", func(r Rounding) Money { return FromFloat(math.NaN(), TRY, r) }, 0, 0},
+		{"Percent exact", func(r Rounding) Money { return TL(1005).Percent(20, r) }, 201, 201},
+		{"Percent half", func(r Rounding) Money { return TL(25).Percent(10, r) }, 3, 2},
+		{"Percent negative half", func(r Rounding) Money { return TL(-25).Percent(10, r) }, -3, -2},
+		{"Mul half to even up", func(r Rounding) Money { return TL(333).Mul(1.5, r) }, 500, 500},
+		{"Mul half to even down", func(r Rounding) Money { return TL(335).Mul(1.5, r) }, 503, 502},
+		{"Mul below half", func(r Rounding) Money { return TL(100).Mul(0.333, r) }, 33, 33},
+	}
+	for _, tt := range tests {
+		if got := tt.got(HalfUp).Minor(); got != tt.halfUp {
+			t.Errorf("%s HalfUp = %d, want %d", tt.name, got, tt.halfUp)
+		}
+		if got := tt.got(HalfEven).Minor(); got != tt.halfEven {
+			t.Errorf("%s HalfEven = %d, want %d", tt.name, got, tt.halfEven)
+		}
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	if got := TL(1050).Add(TL(250)).Sub(TL(100)); got != TL(1200) {
+		t.Errorf("Add/Sub = %v, want ₺12,00", got)
+	}
+	if got := TL(450).Times(3); got != TL(1350) {
+		t.Errorf("Times = %v, want ₺13,50", got)
+	}
+	if got := Sum(TL(100), TL(200), TL(-50)); got != TL(250) {
+		t.Errorf("Sum = %v, want ₺2,50", got)
+	}
+	// Sıfır değer ilk eklenen tutarın birimini alır
+	if got := (Money{}).Add(New(500, USD)); got != New(500, USD) {
+		t.Errorf("zero + USD = %#v, want 5 USD", got)
+	}
+	if got := Min(TL(300), TL(-100)); got != TL(-100) {
+		t.Errorf("Min = %v, want -₺1,00", got)
+	}
+	if TL(1).Cmp(TL(2)) != -1 || TL(2).Cmp(TL(2)) != 0 || TL(3).Cmp(TL(2)) != 1 {
+		t.Error("Cmp does not order amounts")
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name     string
+		m        Money
+		to       Currency
+		rate     *big.Rat
+		rounding Rounding
+		want     Money
+	}{
+		{"same currency ignores rate", TL(1000), TRY, big.NewRat(3, 1), HalfEven, TL(1000)},
+		{"zero value takes target", New(1000, ""), TRY, big.NewRat(3, 1), HalfEven, TL(1000)},
+		{"USD to TRY", New(10000, USD), TRY, big.NewRat(342574, 10000), HalfEven, TL(342574)},
+		// 0,125 kuruş yarımı en yakın çifte, yarımdan yukarı kuralında sıfırdan uzağa yuvarlanır
+		{"half to even", New(1, EUR), TRY, big.NewRat(25, 2), HalfEven, TL(12)},
+		{"half up", New(1, EUR), TRY, big.NewRat(25, 2), HalfUp, TL(13)},
+		{"negative", New(-1, EUR), TRY, big.NewRat(25, 2), HalfUp, TL(-13)},
+		{"TRY to USD", TL(342574), USD, big.NewRat(10000, 342574), HalfEven, New(10000, USD)},
+	}
+	for _, tt := range tests {
+		if got := tt.m.Convert(tt.to, tt.rate, tt.rounding); got != tt.want {
+			t.Errorf("%s: Convert = %#v, want %#v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseCurrency(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Currency
+		wantErr bool
+	}{
+		{"TRY", TRY, false},
+		{" usd ", USD, false},
+		{"Eur", EUR, false},
+		{"TL", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseCurrency(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseCurrency(%q) = %q, %v; want %q, wantErr %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestMixedCurrenciesPanic(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func()
+	}{
+		{"Add", func() { TL(100).Add(New(100, USD)) }},
+		{"Sub", func() { New(100, EUR).Sub(TL(100)) }},
+		{"Cmp", func() { TL(100).Cmp(New(100, GBP)) }},
+		{"Sum", func() { Sum(TL(100), New(100, USD)) }},
+	}
+	for _, tt := range tests {
+		func() {
+			defer func() {
+				err, _ := recover().(error)
+				if !errors.Is(err, ErrCurrencyMismatch) {
+					t.Errorf("%s: recovered %v, want ErrCurrencyMismatch", tt.name, err)
+				}
+			}()
+			tt.fn()
+		}()
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		m                       Money
+		str, number, decimalStr string
+	}{
+		{Money{}, "₺0,00", "0,00", "0.00"},
+		{TL(5), "₺0,05", "0,05", "0.05"},
+		{TL(123456), "₺1.234,56", "1.234,56", "1234.56"},
+		{TL(-1250), "-₺12,50", "-12,50", "-12.50"},
+		{TL(-5), "-₺0,05", "-0,05", "-0.05"},
+		{TL(100000000), "₺1.000.000,00", "1.000.000,00", "1000000.00"},
+		{New(999, USD), "$9,99", "9,99", "9.99"},
+		{New(math.MinInt64, TRY), "-₺92.233.720.368.547.758,08", "-92.233.720.368.547.758,08", "-92233720368547758.08"},
+	}
+	for _, tt := range tests {
+		if got := tt.m.String(); got != tt.str {
+			t.Errorf("String(%d) = %q, want %q", tt.m.Minor(), got, tt.str)
+		}
+		if got := tt.m.Number(); got != tt.number {
+			t.Errorf("Number(%d) = %q, want %q", tt.m.Minor(), got, tt.number)
+		}
+		if got := tt.m.Decimal(); got != tt.decimalStr {
+			t.Errorf("Decimal(%d) = %q, want %q", tt.m.Minor(), got, tt.decimalStr)
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	type line struct {
+		Price Money `json:"price"`
+	}
+	data, err := json.Marshal(line{Price: TL(123456)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"price":1234.56}` {
+		t.Errorf("Marshal = %s", data)
+	}
+	var back line
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatal(err)
+	}
+	if back.Price != New(123456, "") || back.Price.Currency() != TRY {
+		t.Errorf("round-trip = %#v", back.Price)
+	}
+
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{`1234.56`, 123456, false},
+		{`-0.05`, -5, false},
+		{`"1234.56"`, 123456, false},
+		{`"1.234,56"`, 123456, false},
+		{`1e3`, 100000, false},
+		{`"1.234"`, 123400, false},
+		// JSON sayısında nokta ondalık ayırıcıdır; üç ondalık hata sayılır
+		{`1.234`, 0, true},
+		{`1.5`, 150, false},
+		{`null`, 0, false},
+		{`"abc"`, 0, true},
+		{`true`, 0, true},
+		{`0.125`, 0, true},
+	}
+	for _, tt := range tests {
+		var m Money
+		err := json.Unmarshal([]byte(tt.in), &m)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Unmarshal(%s) err = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if err == nil && m.Minor() != tt.want {
+			t.Errorf("Unmarshal(%s) = %d, want %d", tt.in, m.Minor(), tt.want)
+		}
+	}
+}
+
+func TestScan(t *testing.T) {
+	tests := []struct {
+		src     interface{}
+		want    int64
+		wantErr bool
+	}{
+		{nil, 0, false},
+		{int64(123456), 123456, false},
+		{float64(1234.6), 1235, false},
+		{[]byte("-250"), -250, false},
+		{"99.5", 100, false},
+		{"abc", 0, true},
+		{true, 0, true},
+	}
+	for _, tt := range tests {
+		m := TL(1)
+		err := m.Scan(tt.src)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Scan(%v) err = %v, wantErr %v", tt.src, err, tt.wantErr)
+			continue
+		}
+		if err == nil && m.Minor() != tt.want {
+			t.Errorf("Scan(%v) = %d, want %d", tt.src, m.Minor(), tt.want)
+		}
+	}
+}
+
+func TestSQLRoundTrip(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Exec("CREATE TABLE amounts (amount INTEGER NOT NULL)"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Kuruş cinsinden tam sayı olarak yazılır, toplamlar da kesin kalır
+	for _, m := range []Money{TL(123456), TL(-5), TL(math.MaxInt64 / 4)} {
+		if _, err := db.Exec("INSERT INTO amounts (amount) VALUES (?)", m); err != nil {
+			t.Fatal(err)
+		}
+		var stored int64
+		var back Money
+		if err := db.QueryRow("SELECT amount, amount FROM amounts WHERE rowid = last_insert_rowid()").Scan(&stored, &back); err != nil {
+			t.Fatal(err)
+		}
+		if stored != m.Minor() || back.Minor() != m.Minor() {
+			t.Errorf("round-trip %d: stored %d, scanned %d", m.Minor(), stored, back.Minor())
+		}
+	}
+	if _, err := db.Exec("DELETE FROM amounts"); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		if _, err := db.Exec("INSERT INTO amounts (amount) VALUES (?)", TL(10)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var total Money
+	if err := db.QueryRow("SELECT SUM(amount) FROM amounts").Scan(&total); err != nil {
+		t.Fatal(err)
+	}
+	if total.Minor() != 100 {
+		t.Errorf("SUM = %d, want 100", total.Minor())
+	}
+}
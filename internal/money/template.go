@@ -0,0 +1,23 @@
+package money
+
+import "html/template"
+
+// TemplateFuncs HTML şablonlarında kullanılan tutar yardımcılarını döndürür:
+//
+//	{{money .Price}}             ₺1.234,56
+//	{{money (times .Price 3)}}   birim fiyat × miktar
+//	{{.Price.Decimal}}           1234.56 (form alanları ve data- öznitelikleri için)
+//	{{range currencies}}         desteklenen para birimleri (seçim listeleri için)
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"money": func(m Money) string {
+			return m.String()
+		},
+		"times": func(m Money, n int) Money {
+			return m.Times(n)
+		},
+		"currencies": func() []Currency {
+			return Currencies
+		},
+	}
+}
@@ -0,0 +1,61 @@
+package notify
+
+import (
+	"sync"
+
+	"github.com/umutaraz/tradesman-app/internal/models"
+)
+
+// subscriberBuffer yavaş bir istemcinin yayını bekletmemesi için abone kanalının kapasitesi
+const subscriberBuffer = 16
+
+// Hub yeni bildirimleri açık SSE bağlantılarına dağıtır. Yalnızca bu süreçteki
+// abonelere ulaşır; kalıcı kayıt veritabanındadır.
+type Hub struct {
+	mu   sync.Mutex
+	subs map[int]map[chan models.Notification]struct{}
+}
+
+// NewHub boş bir dağıtıcı oluşturur
+func NewHub() *Hub {
+	return &Hub{subs: map[int]map[chan models.Notification]struct{}{}}
+}
+
+// Subscribe kullanıcının bildirimlerini alacak bir kanal açar; dönen fonksiyon aboneliği kapatır
+func (h *Hub) Subscribe(userID int) (<-chan models.Notification, func()) {
+	ch := make(chan models.Notification, subscriberBuffer)
+
+	h.mu.Lock()
+	if h.subs[userID] == nil {
+		h.subs[userID] = map[chan models.Notification]struct{}{}
+	}
+	h.subs[userID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subs[userID], ch)
+			if len(h.subs[userID]) == 0 {
+				delete(h.subs, userID)
+			}
+			h.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// Publish bildirimi alıcının açık bağlantılarına iletir. Kanalı dolu olan abone
+// bildirimi kaçırır; sayfa yenilendiğinde listeyi veritabanından alır.
+func (h *Hub) Publish(n models.Notification) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subs[n.UserID] {
+		select {
+		case ch <- n:
+		default:
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// Package notify kullanıcı bildirimlerini kaydeder ve açık tarayıcı sekmelerine anlık iletir.
+package notify
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/permissions"
+)
+
+// Notifier bildirimleri veritabanına yazar ve Hub üzerinden yayınlar
+type Notifier struct {
+	db  *database.DB
+	hub *Hub
+}
+
+// New bildirim servisi oluşturur
+func New(db *database.DB, hub *Hub) *Notifier {
+	return &Notifier{db: db, hub: hub}
+}
+
+// Hub anlık dağıtıcıyı döndürür
+func (n *Notifier) Hub() *Hub {
+	return n.hub
+}
+
+// Send bildirimi kullanıcıya gönderir
+func (n *Notifier) Send(userID int, notification models.Notification) (*models.Notification, error) {
+	notification.UserID = userID
+	if notification.Severity == "" {
+		notification.Severity = models.SeverityInfo
+	}
+
+	if err := database.InsertNotification(n.db, &notification); err != nil {
+		return nil, err
+	}
+
+	n.hub.Publish(notification)
+	return &notification, nil
+}
+
+// SendToBusiness bildirimi işletmede verilen yetkiye sahip tüm kullanıcılara gönderir
+func (n *Notifier) SendToBusiness(businessID int, perm permissions.Permission, notification models.Notification) error {
+	recipients, err := n.recipients(businessID, perm)
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range recipients {
+		if _, err := n.Send(userID, notification); err != nil {
+			return fmt.Errorf("bildirim gönderilemedi (kullanıcı %d): %w", userID, err)
+		}
+	}
+	return nil
+}
+
+// recipients işletme sahibi ve personel arasından yetkili kullanıcıları döndürür
+func (n *Notifier) recipients(businessID int, perm permissions.Permission) ([]int, error) {
+	rows, err := n.db.Query("SELECT id, role FROM users WHERE id = ? OR owner_id = ?", businessID, businessID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var user models.User
+		var role sql.NullString
+		if err := rows.Scan(&user.ID, &role); err != nil {
+			return nil, err
+		}
+		user.Role = role.String
+		if permissions.Has(user.EffectiveRole(), perm) {
+			ids = append(ids, user.ID)
+		}
+	}
+
+	return ids, rows.Err()
+}
@@ -0,0 +1,114 @@
+package notify_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/database/dbtest"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/notify"
+	"github.com/umutaraz/tradesman-app/internal/permissions"
+)
+
+func TestHubDeliversToSubscribersOfUser(t *testing.T) {
+	hub := notify.NewHub()
+	first, unsubscribeFirst := hub.Subscribe(1)
+	second, unsubscribeSecond := hub.Subscribe(1)
+	other, unsubscribeOther := hub.Subscribe(2)
+	defer unsubscribeSecond()
+	defer unsubscribeOther()
+
+	hub.Publish(models.Notification{UserID: 1, Title: "Düşük stok"})
+	for _, ch := range []<-chan models.Notification{first, second} {
+		select {
+		case n := <-ch:
+			if n.Title != "Düşük stok" {
+				t.Errorf("received %q", n.Title)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("notification not delivered")
+		}
+	}
+	select {
+	case n := <-other:
+		t.Errorf("other user received %q", n.Title)
+	default:
+	}
+
+	// Abonelik kapanınca kanal kapanır; tekrar kapatmak güvenlidir
+	unsubscribeFirst()
+	unsubscribeFirst()
+	if _, ok := <-first; ok {
+		t.Error("channel still open after unsubscribe")
+	}
+	hub.Publish(models.Notification{UserID: 1, Title: "Yeni randevu"})
+}
+
+func TestHubDropsWhenSubscriberIsFull(t *testing.T) {
+	hub := notify.NewHub()
+	ch, unsubscribe := hub.Subscribe(1)
+	defer unsubscribe()
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 100; i++ {
+			hub.Publish(models.Notification{UserID: 1})
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("publish blocked on a slow subscriber")
+	}
+	if len(ch) == 0 {
+		t.Error("no notifications buffered")
+	}
+}
+
+func TestSendToBusinessReachesPermittedStaff(t *testing.T) {
+	db := dbtest.New(t)
+	ownerID := dbtest.User(t, db, "sahip@example.com")
+	staff := func(email, role string) int {
+		result, err := db.Exec(`INSERT INTO users (name, email, password_hash, role, owner_id) VALUES (?, ?, '', ?, ?)`,
+			email, email, role, ownerID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		id, _ := result.LastInsertId()
+		return int(id)
+	}
+	managerID := staff("yonetici@example.com", models.RoleManager)
+	technicianID := staff("teknisyen@example.com", models.RoleTechnician)
+	otherOwner := dbtest.User(t, db, "diger@example.com")
+
+	hub := notify.NewHub()
+	live, unsubscribe := hub.Subscribe(managerID)
+	defer unsubscribe()
+
+	n := notify.New(db, hub)
+	err := n.SendToBusiness(ownerID, permissions.ManageProducts, models.Notification{Type: models.NotificationStock,
+		Title: "Düşük stok: Priz", Message: "Priz stoğu 2 adet kaldı."})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for userID, want := range map[int]int{ownerID: 1, managerID: 1, technicianID: 0, otherOwner: 0} {
+		var count int
+		if err := db.QueryRow("SELECT COUNT(*) FROM notifications WHERE user_id = ?", userID).Scan(&count); err != nil {
+			t.Fatal(err)
+		}
+		if count != want {
+			t.Errorf("user %d notifications = %d, want %d", userID, count, want)
+		}
+	}
+
+	select {
+	case got := <-live:
+		if got.UserID != managerID || got.Severity != models.SeverityInfo || got.ID == 0 {
+			t.Errorf("live notification = %+v", got)
+		}
+	default:
+		t.Error("live notification not published")
+	}
+}
@@ -0,0 +1,243 @@
+// Package outbox giden iletileri (e-posta, SMS) veritabanındaki kuyruk tablosu üzerinden
+// gönderir. İstek yalnızca kuyruğa kayıt ekler; gönderimi arka plandaki Worker yapar ve
+// başarısız denemeleri artan bekleme süreleriyle yeniden dener.
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/mailer"
+	"github.com/umutaraz/tradesman-app/internal/messaging"
+)
+
+// Kanallar
+const (
+	ChannelEmail = "email"
+	ChannelSMS   = "sms" // SMS ve WhatsApp iletileri
+)
+
+// Kuyruk kaydı durumları
+const (
+	StatusPending = "pending"
+	StatusSending = "sending"
+	StatusSent    = "sent"
+	StatusFailed  = "failed"
+)
+
+// DefaultMaxAttempts kalıcı olarak başarısız sayılmadan önceki deneme sayısı
+const DefaultMaxAttempts = 6
+
+// Execer *sql.DB ve *sql.Tx tarafından karşılanır; kayıt iş işlemiyle aynı tx içinde eklenebilir
+type Execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// Enqueue iletiyi kuyruğa ekler ve kayıt ID'sini döndürür
+func Enqueue(exec Execer, userID int, channel, recipient, subject string, payload interface{}) (int, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := exec.Exec(`
+		INSERT INTO outbox (user_id, channel, recipient, subject, payload, status, max_attempts, next_attempt_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, userID, channel, recipient, subject, data, StatusPending, DefaultMaxAttempts, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := result.LastInsertId()
+	return int(id), err
+}
+
+// EnqueueEmail e-postayı kuyruğa ekler
+func EnqueueEmail(exec Execer, userID int, msg mailer.Message) (int, error) {
+	if err := msg.Validate(); err != nil {
+		return 0, err
+	}
+	return Enqueue(exec, userID, ChannelEmail, msg.To[0], msg.Subject, msg)
+}
+
+// EnqueueMessage kısa mesajı kuyruğa ekler
+func EnqueueMessage(exec Execer, userID int, msg messaging.Message) (int, error) {
+	if err := msg.Validate(); err != nil {
+		return 0, err
+	}
+	to, _ := messaging.NormalizePhone(msg.To)
+	msg.To = to
+	return Enqueue(exec, userID, ChannelSMS, to, "", msg)
+}
+
+// Sender bir kanalın kuyruktaki iletisini gönderir
+type Sender func(ctx context.Context, payload []byte) error
+
+// EmailSender e-posta kanalını mailer üzerinden gönderir
+func EmailSender(m mailer.Mailer) Sender {
+	return func(ctx context.Context, payload []byte) error {
+		var msg mailer.Message
+		if err := json.Unmarshal(payload, &msg); err != nil {
+			return fmt.Errorf("bozuk e-posta kaydı: %w", err)
+		}
+		return m.Send(ctx, msg)
+	}
+}
+
+// MessageSender kısa mesaj kanalını sağlayıcı üzerinden gönderir
+func MessageSender(p messaging.Provider) Sender {
+	return func(ctx context.Context, payload []byte) error {
+		var msg messaging.Message
+		if err := json.Unmarshal(payload, &msg); err != nil {
+			return fmt.Errorf("bozuk mesaj kaydı: %w", err)
+		}
+		return p.Send(ctx, msg)
+	}
+}
+
+// Backoff deneme sayısına göre bir sonraki denemeye kadar beklenecek süre:
+// 1 dk, 2 dk, 4 dk ... en fazla 1 saat
+func Backoff(attempts int) time.Duration {
+	delay := time.Minute
+	for i := 1; i < attempts && delay < time.Hour; i++ {
+		delay *= 2
+	}
+	if delay > time.Hour {
+		delay = time.Hour
+	}
+	return delay
+}
+
+// Worker kuyruktaki zamanı gelmiş kayıtları düzenli aralıklarla gönderir
+type Worker struct {
+	db       *sql.DB
+	senders  map[string]Sender
+	interval time.Duration
+	batch    int
+}
+
+// NewWorker kuyruk işçisi oluşturur
+func NewWorker(db *sql.DB, interval time.Duration) *Worker {
+	if interval <= 0 {
+		interval = 15 * time.Second
+	}
+	return &Worker{db: db, senders: map[string]Sender{}, interval: interval, batch: 20}
+}
+
+// Handle kanal için gönderici tanımlar
+func (w *Worker) Handle(channel string, sender Sender) {
+	w.senders[channel] = sender
+}
+
+// Run bağlam iptal edilene kadar kuyruğu işler
+func (w *Worker) Run(ctx context.Context) {
+	// Uygulama gönderim sırasında kapandıysa yarım kalan kayıtlar yeniden denenir
+	if _, err := w.db.Exec("UPDATE outbox SET status = ? WHERE status = ?", StatusPending, StatusSending); err != nil {
+		log.Printf("Kuyruk kurtarma hatası: %v", err)
+	}
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.ProcessDue(ctx); err != nil {
+			log.Printf("Kuyruk işleme hatası: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+type entry struct {
+	id          int
+	channel     string
+	payload     []byte
+	attempts    int
+	maxAttempts int
+}
+
+// ProcessDue zamanı gelmiş bekleyen kayıtları gönderir
+func (w *Worker) ProcessDue(ctx context.Context) error {
+	rows, err := w.db.Query(`
+		SELECT id, channel, payload, attempts, max_attempts FROM outbox
+		WHERE status = ? AND next_attempt_at <= ?
+		ORDER BY next_attempt_at, id
+		LIMIT ?
+	`, StatusPending, time.Now(), w.batch)
+	if err != nil {
+		return err
+	}
+
+	var due []entry
+	for rows.Next() {
+		var e entry
+		if err := rows.Scan(&e.id, &e.channel, &e.payload, &e.attempts, &e.maxAttempts); err != nil {
+			rows.Close()
+			return err
+		}
+		due = append(due, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, e := range due {
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err := w.process(ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *Worker) process(ctx context.Context, e entry) error {
+	// Kaydı sahiplen; başka bir işçi aldıysa atla
+	result, err := w.db.Exec(`
+		UPDATE outbox SET status = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND status = ?
+	`, StatusSending, e.id, StatusPending)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return nil
+	}
+
+	sendErr := fmt.Errorf("tanımsız kanal: %s", e.channel)
+	if sender, ok := w.senders[e.channel]; ok {
+		sendCtx, cancel := context.WithTimeout(ctx, time.Minute)
+		sendErr = sender(sendCtx, e.payload)
+		cancel()
+	}
+
+	attempts := e.attempts + 1
+	if sendErr == nil {
+		_, err = w.db.Exec(`
+			UPDATE outbox SET status = ?, attempts = ?, last_error = NULL, sent_at = ?, updated_at = CURRENT_TIMESTAMP
+			WHERE id = ?
+		`, StatusSent, attempts, time.Now(), e.id)
+		return err
+	}
+
+	status := StatusPending
+	if attempts >= e.maxAttempts {
+		status = StatusFailed
+	}
+	log.Printf("Kuyruk kaydı #%d gönderilemedi (deneme %d/%d): %v", e.id, attempts, e.maxAttempts, sendErr)
+
+	_, err = w.db.Exec(`
+		UPDATE outbox SET status = ?, attempts = ?, last_error = ?, next_attempt_at = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`, status, attempts, sendErr.Error(), time.Now().Add(Backoff(attempts)), e.id)
+	return err
+}
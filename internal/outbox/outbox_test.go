@@ -0,0 +1,141 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/database/dbtest"
+	"github.com/umutaraz/tradesman-app/internal/mailer"
+	"github.com/umutaraz/tradesman-app/internal/outbox"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{3, 4 * time.Minute},
+		{6, 32 * time.Minute},
+		{7, time.Hour},
+		{20, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := outbox.Backoff(tt.attempts); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+// outboxRow kuyruk kaydının denemeyle değişen alanları
+type outboxRow struct {
+	status        string
+	attempts      int
+	lastError     string
+	nextAttemptAt time.Time
+}
+
+func readOutbox(t *testing.T, db *database.DB, id int) outboxRow {
+	t.Helper()
+	var r outboxRow
+	err := db.QueryRow("SELECT status, attempts, COALESCE(last_error, ''), next_attempt_at FROM outbox WHERE id = ?", id).
+		Scan(&r.status, &r.attempts, &r.lastError, &r.nextAttemptAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+// makeDue bekleme süresini beklemeden kaydı yeniden denenecek hale getirir
+func makeDue(t *testing.T, db *database.DB, id int) {
+	t.Helper()
+	if _, err := db.Exec("UPDATE outbox SET next_attempt_at = ? WHERE id = ?", time.Now().Add(-time.Second), id); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newEmailWorker(t *testing.T) (*database.DB, *mailer.MemoryMailer, *outbox.Worker, int) {
+	t.Helper()
+	db := dbtest.New(t)
+	userID := dbtest.User(t, db, "sahip@example.com")
+
+	m := mailer.NewMemory()
+	w := outbox.NewWorker(db.DB, time.Minute)
+	w.Handle(outbox.ChannelEmail, outbox.EmailSender(m))
+
+	id, err := outbox.EnqueueEmail(db, userID, mailer.Message{To: []string{"musteri@example.com"}, Subject: "Fatura",
+		Body: "Faturanız ektedir."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db, m, w, id
+}
+
+func TestWorkerRetriesWithBackoff(t *testing.T) {
+	db, m, w, id := newEmailWorker(t)
+	ctx := context.Background()
+
+	m.Err = errors.New("sunucuya bağlanılamadı")
+	before := time.Now()
+	if err := w.ProcessDue(ctx); err != nil {
+		t.Fatal(err)
+	}
+	r := readOutbox(t, db, id)
+	if r.status != outbox.StatusPending || r.attempts != 1 || r.lastError != m.Err.Error() {
+		t.Fatalf("after failed send: %+v", r)
+	}
+	// İlk başarısız denemeden sonra bir dakika beklenir
+	if r.nextAttemptAt.Before(before.Add(time.Minute)) || r.nextAttemptAt.After(time.Now().Add(time.Minute)) {
+		t.Errorf("next attempt at %v, want about a minute after %v", r.nextAttemptAt, before)
+	}
+
+	// Bekleme süresi dolmadan yeniden denenmez
+	m.Err = nil
+	if err := w.ProcessDue(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if r := readOutbox(t, db, id); r.attempts != 1 || len(m.Sent()) != 0 {
+		t.Fatalf("retried before backoff: attempts = %d, sent = %d", r.attempts, len(m.Sent()))
+	}
+
+	makeDue(t, db, id)
+	if err := w.ProcessDue(ctx); err != nil {
+		t.Fatal(err)
+	}
+	r = readOutbox(t, db, id)
+	if r.status != outbox.StatusSent || r.attempts != 2 || r.lastError != "" {
+		t.Fatalf("after retry: %+v", r)
+	}
+	sent := m.Sent()
+	if len(sent) != 1 || sent[0].To[0] != "musteri@example.com" || sent[0].Subject != "Fatura" {
+		t.Errorf("sent = %+v", sent)
+	}
+}
+
+func TestWorkerGivesUpAfterMaxAttempts(t *testing.T) {
+	db, m, w, id := newEmailWorker(t)
+	ctx := context.Background()
+	if _, err := db.Exec("UPDATE outbox SET max_attempts = 2 WHERE id = ?", id); err != nil {
+		t.Fatal(err)
+	}
+
+	m.Err = errors.New("sunucuya bağlanılamadı")
+	for i := 0; i < 3; i++ {
+		makeDue(t, db, id)
+		if err := w.ProcessDue(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := readOutbox(t, db, id)
+	if r.status != outbox.StatusFailed || r.attempts != 2 {
+		t.Errorf("after max attempts: %+v, want failed after 2 attempts", r)
+	}
+	if len(m.Sent()) != 0 {
+		t.Errorf("sent = %d, want 0", len(m.Sent()))
+	}
+}
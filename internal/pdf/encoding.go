@@ -0,0 +1,75 @@
+package pdf
+
+import "strings"
+
+// Standart Helvetica yazı tipi WinAnsi kodlamasıyla Türkçe harfleri içermez. Bu yüzden
+// WinAnsi temel alınır ve cp1254 ile aynı konumlardaki altı karakter /Differences
+// dizisiyle Türkçe gliflere yönlendirilir (Ğ Ġ Ş ğ ı ş).
+var turkishGlyphs = []struct {
+	r    rune
+	code byte
+	name string
+}{
+	{'Ğ', 0xD0, "Gbreve"},
+	{'İ', 0xDD, "Idotaccent"},
+	{'Ş', 0xDE, "Scedilla"},
+	{'ğ', 0xF0, "gbreve"},
+	{'ı', 0xFD, "dotlessi"},
+	{'ş', 0xFE, "scedilla"},
+}
+
+// differences yazı tipi kodlama sözlüğündeki /Differences dizisi
+func differences() string {
+	var b strings.Builder
+	b.WriteString("[")
+	for i, g := range turkishGlyphs {
+		if i > 0 {
+			b.WriteString(" ")
+		}
+		b.WriteString(itoa(int(g.code)))
+		b.WriteString(" /")
+		b.WriteString(g.name)
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+// encode UTF-8 metni yazı tipi kodlamasına çevirir; karşılığı olmayan karakterler '?' olur
+func encode(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		out = append(out, encodeRune(r)...)
+	}
+	return out
+}
+
+func encodeRune(r rune) []byte {
+	for _, g := range turkishGlyphs {
+		if g.r == r {
+			return []byte{g.code}
+		}
+	}
+
+	switch {
+	case r == '₺':
+		// Standart yazı tiplerinde TL simgesi bulunmadığından kısaltma yazılır
+		return []byte("TL")
+	case r == '\t':
+		return []byte{' '}
+	case r >= 0x20 && r < 0x7F:
+		return []byte{byte(r)}
+	case r >= 0xA0 && r <= 0xFF:
+		// Türkçe harflerin kullandığı konumlardaki Latin-1 karakterleri gösterilemez
+		for _, g := range turkishGlyphs {
+			if byte(r) == g.code {
+				return []byte{'?'}
+			}
+		}
+		return []byte{byte(r)}
+	}
+
+	if code, ok := winAnsiSpecials[r]; ok {
+		return []byte{code}
+	}
+	return []byte{'?'}
+}
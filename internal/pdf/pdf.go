@@ -0,0 +1,353 @@
+// Package pdf harici bağımlılık olmadan basit PDF belgeleri (fatura, fiş) üretir.
+// Standart Helvetica yazı tipleri kullanılır; yazı tipi gömülmediğinden dosyalar küçüktür.
+// Koordinatlar punto (1/72 inç) cinsindendir ve sayfanın sol üst köşesinden ölçülür.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sayfa boyutları (punto)
+const (
+	A4Width  = 595.28
+	A4Height = 841.89
+)
+
+// MM milimetreyi puntoya çevirir
+func MM(mm float64) float64 {
+	return mm * 72 / 25.4
+}
+
+// Font belgede kullanılabilecek yazı tipleri
+type Font int
+
+const (
+	Helvetica Font = iota
+	HelveticaBold
+)
+
+func (f Font) resource() string {
+	if f == HelveticaBold {
+		return "F2"
+	}
+	return "F1"
+}
+
+func (f Font) baseFont() string {
+	if f == HelveticaBold {
+		return "Helvetica-Bold"
+	}
+	return "Helvetica"
+}
+
+func (f Font) widths() *[256]uint16 {
+	if f == HelveticaBold {
+		return &helveticaBoldWidths
+	}
+	return &helveticaWidths
+}
+
+// TextWidth metnin verilen yazı tipi ve boyuttaki genişliğini döndürür
+func TextWidth(font Font, size float64, s string) float64 {
+	widths := font.widths()
+	var total int
+	for _, c := range encode(s) {
+		total += int(widths[c])
+	}
+	return float64(total) * size / 1000
+}
+
+// WrapText metni en fazla maxWidth genişliğinde satırlara böler
+func WrapText(font Font, size float64, s string, maxWidth float64) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(s, "\n") {
+		words := strings.Fields(paragraph)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+
+		line := ""
+		for _, word := range words {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if line != "" && TextWidth(font, size, candidate) > maxWidth {
+				lines = append(lines, line)
+				line = word
+			} else {
+				line = candidate
+			}
+
+			// Tek başına sığmayan kelimeyi karakter karakter böl
+			for TextWidth(font, size, line) > maxWidth && len([]rune(line)) > 1 {
+				runes := []rune(line)
+				cut := len(runes) - 1
+				for cut > 1 && TextWidth(font, size, string(runes[:cut])) > maxWidth {
+					cut--
+				}
+				lines = append(lines, string(runes[:cut]))
+				line = string(runes[cut:])
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// Document bir PDF belgesi
+type Document struct {
+	Title  string
+	Author string
+	pages  []*Page
+}
+
+// New boş bir belge oluşturur
+func New() *Document {
+	return &Document{}
+}
+
+// AddPage belgeye yeni sayfa ekler. height 0 verilirse sayfa yüksekliği içeriğe göre
+// belirlenir (termal fiş gibi sürekli kağıtlar için).
+func (d *Document) AddPage(width, height float64) *Page {
+	page := &Page{width: width, height: height}
+	d.pages = append(d.pages, page)
+	return page
+}
+
+// Pages belgenin sayfalarını döndürür
+func (d *Document) Pages() []*Page {
+	return d.pages
+}
+
+// Page belgedeki bir sayfa; çizim komutları üstten ölçülen koordinatlarla kaydedilir
+type Page struct {
+	width, height float64
+	ops           []op
+	bottom        float64 // İçeriğin ulaştığı en alt nokta
+}
+
+type op struct {
+	kind      int
+	x, y      float64
+	x2, y2    float64
+	font      Font
+	size      float64
+	text      []byte
+	lineWidth float64
+	gray      float64
+}
+
+const (
+	opText = iota
+	opLine
+	opRect
+)
+
+// Width sayfa genişliğini döndürür
+func (p *Page) Width() float64 {
+	return p.width
+}
+
+// Height sayfa yüksekliğini döndürür; içeriğe göre boyutlanan sayfalarda 0'dır
+func (p *Page) Height() float64 {
+	return p.height
+}
+
+// Text metni x noktasından başlayarak y taban çizgisine yazar
+func (p *Page) Text(x, y float64, font Font, size float64, s string) {
+	p.ops = append(p.ops, op{kind: opText, x: x, y: y, font: font, size: size, text: encode(s)})
+	p.extend(y + size*0.25)
+}
+
+// TextRight metni sağ kenarı right noktasında olacak şekilde yazar
+func (p *Page) TextRight(right, y float64, font Font, size float64, s string) {
+	p.Text(right-TextWidth(font, size, s), y, font, size, s)
+}
+
+// TextCenter metni center noktasına ortalayarak yazar
+func (p *Page) TextCenter(center, y float64, font Font, size float64, s string) {
+	p.Text(center-TextWidth(font, size, s)/2, y, font, size, s)
+}
+
+// Line iki nokta arasına çizgi çeker
+func (p *Page) Line(x1, y1, x2, y2, width float64) {
+	p.ops = append(p.ops, op{kind: opLine, x: x1, y: y1, x2: x2, y2: y2, lineWidth: width})
+	p.extend(y1)
+	p.extend(y2)
+}
+
+// FillRect dikdörtgeni gri tonla doldurur (0 siyah, 1 beyaz)
+func (p *Page) FillRect(x, y, w, h, gray float64) {
+	p.ops = append(p.ops, op{kind: opRect, x: x, y: y, x2: w, y2: h, gray: gray})
+	p.extend(y + h)
+}
+
+func (p *Page) extend(y float64) {
+	if y > p.bottom {
+		p.bottom = y
+	}
+}
+
+// Bytes belgeyi PDF olarak döndürür
+func (d *Document) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := d.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteTo belgeyi PDF olarak yazar
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	if len(d.pages) == 0 {
+		d.AddPage(A4Width, A4Height)
+	}
+
+	pw := &writer{}
+	pw.raw("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// 1: katalog, 2: sayfa ağacı, 3-4: yazı tipleri, 5: belge bilgisi, ardından sayfalar
+	const firstPageObj = 6
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPageObj+i*2)
+	}
+
+	pw.object(1, "<< /Type /Catalog /Pages 2 0 R >>")
+	pw.object(2, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	for i, font := range []Font{Helvetica, HelveticaBold} {
+		pw.object(3+i, fmt.Sprintf(
+			"<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding << /Type /Encoding /BaseEncoding /WinAnsiEncoding /Differences %s >> >>",
+			font.baseFont(), differences()))
+	}
+	pw.object(5, fmt.Sprintf("<< /Title %s /Author %s /Producer (tradesman-app) /CreationDate (D:%s) >>",
+		literal(encode(d.Title)), literal(encode(d.Author)), time.Now().Format("20060102150405")))
+
+	for i, page := range d.pages {
+		pageObj := firstPageObj + i*2
+		height := page.height
+		if height == 0 {
+			height = page.bottom + MM(5)
+		}
+
+		content, err := page.content(height)
+		if err != nil {
+			return 0, err
+		}
+
+		pw.object(pageObj, fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			num(page.width), num(height), pageObj+1))
+		pw.stream(pageObj+1, content)
+	}
+
+	pw.trailer(firstPageObj+len(d.pages)*2, 1, 5)
+
+	n, err := w.Write(pw.buf.Bytes())
+	return int64(n), err
+}
+
+// content sayfanın sıkıştırılmış içerik akışını üretir; y ekseni PDF'in alttan ölçülen
+// koordinatlarına çevrilir
+func (p *Page) content(height float64) ([]byte, error) {
+	var b bytes.Buffer
+	for _, o := range p.ops {
+		switch o.kind {
+		case opText:
+			fmt.Fprintf(&b, "BT /%s %s Tf %s %s Td %s Tj ET\n",
+				o.font.resource(), num(o.size), num(o.x), num(height-o.y), literal(o.text))
+		case opLine:
+			fmt.Fprintf(&b, "%s w %s %s m %s %s l S\n",
+				num(o.lineWidth), num(o.x), num(height-o.y), num(o.x2), num(height-o.y2))
+		case opRect:
+			fmt.Fprintf(&b, "q %s g %s %s %s %s re f Q\n",
+				num(o.gray), num(o.x), num(height-o.y-o.y2), num(o.x2), num(o.y2))
+		}
+	}
+
+	var out bytes.Buffer
+	zw := zlib.NewWriter(&out)
+	if _, err := zw.Write(b.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// writer nesneleri yazarken çapraz başvuru tablosu için konumlarını tutar
+type writer struct {
+	buf     bytes.Buffer
+	offsets map[int]int
+}
+
+func (w *writer) raw(s string) {
+	w.buf.WriteString(s)
+}
+
+func (w *writer) object(id int, body string) {
+	w.begin(id)
+	w.buf.WriteString(body)
+	w.buf.WriteString("\nendobj\n")
+}
+
+func (w *writer) stream(id int, data []byte) {
+	w.begin(id)
+	fmt.Fprintf(&w.buf, "<< /Length %d /Filter /FlateDecode >>\nstream\n", len(data))
+	w.buf.Write(data)
+	w.buf.WriteString("\nendstream\nendobj\n")
+}
+
+func (w *writer) begin(id int) {
+	if w.offsets == nil {
+		w.offsets = map[int]int{}
+	}
+	w.offsets[id] = w.buf.Len()
+	fmt.Fprintf(&w.buf, "%d 0 obj\n", id)
+}
+
+func (w *writer) trailer(size, root, info int) {
+	xref := w.buf.Len()
+	fmt.Fprintf(&w.buf, "xref\n0 %d\n0000000000 65535 f \n", size)
+	for id := 1; id < size; id++ {
+		fmt.Fprintf(&w.buf, "%010d 00000 n \n", w.offsets[id])
+	}
+	fmt.Fprintf(&w.buf, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		size, root, info, xref)
+}
+
+// literal baytları kaçış karakterleriyle PDF metin dizgesine çevirir
+func literal(b []byte) string {
+	var s strings.Builder
+	s.WriteByte('(')
+	for _, c := range b {
+		switch c {
+		case '(', ')', '\\':
+			s.WriteByte('\\')
+			s.WriteByte(c)
+		default:
+			s.WriteByte(c)
+		}
+	}
+	s.WriteByte(')')
+	return s.String()
+}
+
+// num sayıyı gereksiz sıfırlar olmadan yazar
+func num(f float64) string {
+	return strconv.FormatFloat(math.Round(f*100)/100, 'f', -1, 64)
+}
+
+func itoa(i int) string {
+	return strconv.Itoa(i)
+}
@@ -0,0 +1,107 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestEncodeTurkishGlyphs(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []byte
+	}{
+		{"Fatura", []byte("Fatura")},
+		{"ığüşöç", []byte{0xFD, 0xF0, 0xFC, 0xFE, 0xF6, 0xE7}},
+		{"IĞÜŞÖÇİ", []byte{'I', 0xD0, 0xDC, 0xDE, 0xD6, 0xC7, 0xDD}},
+		{"1.250,00 ₺", []byte("1.250,00 TL")},
+		{"€ 5", []byte{0x80, ' ', '5'}},
+		{"a\tb", []byte("a b")},
+		// Türkçe harflerin yerini aldığı Latin-1 karakterleri ve tabloda olmayanlar gösterilemez
+		{"Ðþý", []byte("???")},
+		{"日本", []byte("??")},
+	}
+	for _, tt := range tests {
+		if got := encode(tt.in); !bytes.Equal(got, tt.want) {
+			t.Errorf("encode(%q) = % x, want % x", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDifferences(t *testing.T) {
+	want := "[208 /Gbreve 221 /Idotaccent 222 /Scedilla 240 /gbreve 253 /dotlessi 254 /scedilla]"
+	if got := differences(); got != want {
+		t.Errorf("differences() = %s, want %s", got, want)
+	}
+}
+
+func TestTextWidthCountsTurkishGlyphs(t *testing.T) {
+	// Türkçe harfler karşılık gelen Latin harflerinin genişliğini kullanır
+	pairs := [][2]string{{"ş", "s"}, {"Ş", "S"}, {"ğ", "g"}, {"İ", "I"}}
+	for _, p := range pairs {
+		if got, want := TextWidth(Helvetica, 10, p[0]), TextWidth(Helvetica, 10, p[1]); got != want {
+			t.Errorf("TextWidth(%s) = %v, want %v", p[0], got, want)
+		}
+	}
+	// Helvetica'da noktasız i, i harfinden geniştir (278/1000 em)
+	if got := TextWidth(Helvetica, 10, "ı"); got != 2.78 {
+		t.Errorf("TextWidth(ı) = %v, want 2.78", got)
+	}
+	if TextWidth(HelveticaBold, 10, "Şirket") <= TextWidth(Helvetica, 10, "Şirket") {
+		t.Error("bold text is not wider than regular text")
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	width := TextWidth(Helvetica, 10, "Kablo kanalı")
+	got := WrapText(Helvetica, 10, "Kablo kanalı montajı\n\nÇok uzunkelimeolanbiraçıklama", width)
+	if len(got) < 4 || got[0] != "Kablo kanalı" || got[1] != "montajı" || got[2] != "" {
+		t.Fatalf("WrapText = %q", got)
+	}
+	for _, line := range got {
+		if TextWidth(Helvetica, 10, line) > width {
+			t.Errorf("line %q is wider than %v", line, width)
+		}
+	}
+	if got[3] != "Çok" || strings.Join(got[4:], "") != "uzunkelimeolanbiraçıklama" {
+		t.Errorf("split word lost characters: %q", got[3:])
+	}
+}
+
+func TestDocumentEncodesPageText(t *testing.T) {
+	doc := New()
+	doc.Title = "Fatura Şişli"
+	page := doc.AddPage(A4Width, A4Height)
+	page.Text(MM(20), MM(20), Helvetica, 10, "Müşteri: Ayşe (Ilgın)")
+
+	data, err := doc.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, []byte("%PDF-1.4")) || !bytes.HasSuffix(data, []byte("%%EOF\n")) {
+		t.Fatal("not a PDF document")
+	}
+	if !bytes.Contains(data, []byte("/Title (Fatura \xDEi\xFEli)")) {
+		t.Error("title is not encoded with Turkish glyph codes")
+	}
+	if !bytes.Contains(data, []byte("/Differences "+differences())) {
+		t.Error("font encoding lacks the Turkish differences")
+	}
+
+	start := bytes.Index(data, []byte("stream\n")) + len("stream\n")
+	end := bytes.Index(data, []byte("\nendstream"))
+	zr, err := zlib.NewReader(bytes.NewReader(data[start:end]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []byte("(M\xFC\xFEteri: Ay\xFEe \\(Ilg\xFDn\\)) Tj")
+	if !bytes.Contains(content, want) {
+		t.Errorf("page content %q does not contain %q", content, want)
+	}
+}
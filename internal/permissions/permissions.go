@@ -0,0 +1,66 @@
+// Package permissions rollerin yetki matrisini tutar. HTTP katmanı (middleware) ve arka plan
+// işleri (bildirimler, hatırlatmalar) hangi kullanıcının neyi yapabileceğini buradan okur.
+package permissions
+
+import "github.com/umutaraz/tradesman-app/internal/models"
+
+// Permission bir kullanıcının yapabileceği işlemi temsil eder
+type Permission string
+
+const (
+	ViewDashboard      Permission = "dashboard.view"
+	ManageCustomers    Permission = "customers.manage"
+	ManageProducts     Permission = "products.manage"
+	ViewProducts       Permission = "products.view"
+	CreateOrders       Permission = "orders.create"
+	ManageOrders       Permission = "orders.manage"
+	ManageAppointments Permission = "appointments.manage"
+	ManageInvoices     Permission = "invoices.manage"
+	ViewAccounting     Permission = "accounting.view"
+	ViewReports        Permission = "reports.view"
+	ManageSettings     Permission = "settings.manage"
+	ManageUsers        Permission = "users.manage"
+)
+
+// rolePermissions rol bazlı yetki matrisi
+var rolePermissions = map[string][]Permission{
+	models.RoleOwner: {
+		ViewDashboard, ManageCustomers, ManageProducts, ViewProducts,
+		CreateOrders, ManageOrders, ManageAppointments, ManageInvoices,
+		ViewAccounting, ViewReports, ManageSettings, ManageUsers,
+	},
+	models.RoleManager: {
+		ViewDashboard, ManageCustomers, ManageProducts, ViewProducts,
+		CreateOrders, ManageOrders, ManageAppointments, ManageInvoices,
+		ViewAccounting, ViewReports, ManageSettings, ManageUsers,
+	},
+	models.RoleCashier: {
+		ViewDashboard, ManageCustomers, ViewProducts, CreateOrders,
+		ManageInvoices,
+	},
+	models.RoleTechnician: {
+		ViewDashboard, ViewProducts, ManageAppointments,
+	},
+}
+
+// Has rolün verilen yetkiye sahip olup olmadığını döndürür
+func Has(role string, perm Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// CanAssign rolün başka bir kullanıcıya verebileceği rolleri belirler.
+// İşletme sahibi rolü hiçbir zaman atanamaz; yöneticiler yalnızca kasiyer ve teknisyen ekleyebilir.
+func CanAssign(actorRole, role string) bool {
+	switch actorRole {
+	case models.RoleOwner:
+		return role == models.RoleManager || role == models.RoleCashier || role == models.RoleTechnician
+	case models.RoleManager:
+		return role == models.RoleCashier || role == models.RoleTechnician
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package permissions
+
+import (
+	"testing"
+
+	"github.com/umutaraz/tradesman-app/internal/models"
+)
+
+func TestHas(t *testing.T) {
+	tests := []struct {
+		role string
+		perm Permission
+		want bool
+	}{
+		{models.RoleOwner, ManageUsers, true},
+		{models.RoleManager, ManageSettings, true},
+		{models.RoleCashier, ManageInvoices, true},
+		{models.RoleCashier, ViewAccounting, false},
+		{models.RoleTechnician, ManageAppointments, true},
+		{models.RoleTechnician, ManageCustomers, false},
+		{"bilinmeyen", ViewDashboard, false},
+	}
+	for _, tt := range tests {
+		if got := Has(tt.role, tt.perm); got != tt.want {
+			t.Errorf("Has(%q, %q) = %v, want %v", tt.role, tt.perm, got, tt.want)
+		}
+	}
+}
+
+func TestCanAssign(t *testing.T) {
+	tests := []struct {
+		actor, role string
+		want        bool
+	}{
+		{models.RoleOwner, models.RoleManager, true},
+		{models.RoleOwner, models.RoleOwner, false},
+		{models.RoleManager, models.RoleCashier, true},
+		{models.RoleManager, models.RoleManager, false},
+		{models.RoleCashier, models.RoleTechnician, false},
+	}
+	for _, tt := range tests {
+		if got := CanAssign(tt.actor, tt.role); got != tt.want {
+			t.Errorf("CanAssign(%q, %q) = %v, want %v", tt.actor, tt.role, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// Package receivables müşteri alacaklarını izler: açık faturaları gecikme günlerine göre
+// yaşlandırır ve vadesi geçen faturalar için kademeli ödeme hatırlatmaları gönderir.
+package receivables
+
+import (
+	"database/sql"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/money"
+)
+
+// Aging işletmenin açık faturalarını asOf gününe göre yaşlandırır. Müşteriler en yüksek açık
+// tutardan başlayarak sıralanır; tamamen ödenmiş ya da iade ile kapanmış faturalar rapora girmez.
+func Aging(db *database.DB, businessID int, asOf time.Time) (*models.AgingReport, error) {
+	rows, err := db.Query(`
+		SELECT customers.id, customers.name, COALESCE(customers.phone, ''), `+database.CustomerBalanceExpr+`,
+		       invoices.id, invoices.invoice_number, invoices.invoice_date, invoices.due_date, invoices.status,
+		       invoices.base_total_amount, `+database.InvoiceOutstandingExpr+`, invoices.base_currency
+		FROM invoices
+		JOIN customers ON customers.id = invoices.customer_id
+		WHERE invoices.user_id = ? AND invoices.invoice_type != ? AND invoices.status IN (?, ?)
+		ORDER BY customers.id, COALESCE(invoices.due_date, invoices.invoice_date), invoices.id
+	`, businessID, models.InvoiceTypeCreditNote, models.InvoiceIssued, models.InvoiceOverdue)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	report := &models.AgingReport{AsOf: dateOnly(asOf), Customers: []models.CustomerAging{}}
+	var current *models.CustomerAging
+	for rows.Next() {
+		var customer models.CustomerAging
+		var invoice models.AgingInvoice
+		var dueDate sql.NullTime
+		var currency money.Currency
+		err := rows.Scan(&customer.CustomerID, &customer.CustomerName, &customer.CustomerPhone, &customer.Balance,
+			&invoice.InvoiceID, &invoice.InvoiceNumber, &invoice.InvoiceDate, &dueDate, &invoice.Status,
+			&invoice.Total, &invoice.Outstanding, &currency)
+		if err != nil {
+			return nil, err
+		}
+		// Tutarlar faturanın kesildiği gün işletmenin ana para birimindedir; ana para birimi kayıt
+		// varken değiştirilemediğinden cari bakiye de aynı birimdedir
+		customer.Balance = customer.Balance.WithCurrency(currency)
+		invoice.Total = invoice.Total.WithCurrency(currency)
+		invoice.Outstanding = invoice.Outstanding.WithCurrency(currency)
+
+		if !invoice.Outstanding.IsPositive() {
+			continue
+		}
+		if dueDate.Valid {
+			invoice.DueDate = &dueDate.Time
+		}
+		invoice.DaysOverdue = DaysOverdue(invoice.OpenInvoice, asOf)
+		invoice.Bucket = models.AgingBucket(invoice.DaysOverdue)
+
+		if current == nil || current.CustomerID != customer.CustomerID {
+			report.Customers = append(report.Customers, customer)
+			current = &report.Customers[len(report.Customers)-1]
+		}
+		current.Invoices = append(current.Invoices, invoice)
+		current.Buckets.Add(invoice.DaysOverdue, invoice.Outstanding)
+		report.Totals.Add(invoice.DaysOverdue, invoice.Outstanding)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(report.Customers, func(i, j int) bool {
+		return report.Customers[i].Buckets.Total.Cmp(report.Customers[j].Buckets.Total) > 0
+	})
+
+	return report, nil
+}
+
+// DaysOverdue faturanın asOf gününde kaç gündür gecikmede olduğunu döndürür. Vade tarihi
+// girilmemiş faturalarda fatura tarihi esas alınır; vadesi gelmemiş faturalar için 0 döner.
+func DaysOverdue(invoice models.OpenInvoice, asOf time.Time) int {
+	due := invoice.InvoiceDate
+	if invoice.DueDate != nil {
+		due = *invoice.DueDate
+	}
+
+	days := daysBetween(due, asOf)
+	if days < 0 {
+		return 0
+	}
+	return days
+}
+
+// daysBetween iki zaman arasındaki takvim günü farkını yerel saate göre döndürür
+func daysBetween(from, to time.Time) int {
+	from, to = dateOnly(from), dateOnly(to)
+	// Yaz saati geçişlerinde gün 23 ya da 25 saat sürebilir
+	return int(math.Round(to.Sub(from).Hours() / 24))
+}
+
+// dateOnly zamanın yerel saate göre gün başlangıcını döndürür
+func dateOnly(t time.Time) time.Time {
+	t = t.In(time.Local)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
@@ -0,0 +1,176 @@
+package receivables_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/database/dbtest"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/money"
+	"github.com/umutaraz/tradesman-app/internal/receivables"
+)
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.Local)
+}
+
+func addCustomer(t *testing.T, db *database.DB, userID int, name, phone string) int {
+	t.Helper()
+	result, err := db.Exec("INSERT INTO customers (user_id, name, phone) VALUES (?, ?, ?)", userID, name, phone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, _ := result.LastInsertId()
+	return int(id)
+}
+
+// addInvoice kesilmiş bir satış faturasını cari hesaba borç olarak yazar
+func addInvoice(t *testing.T, db *database.DB, userID, customerID int, number, status string, due time.Time, kurus int64) int {
+	total := money.TL(kurus)
+	t.Helper()
+	invoiceDate := due.AddDate(0, 0, -5)
+	result, err := db.Exec(`
+		INSERT INTO invoices (user_id, customer_id, invoice_number, status, invoice_date, due_date, subtotal, tax_amount,
+		                      total_amount, base_total_amount)
+		VALUES (?, ?, ?, ?, ?, ?, ?, 0, ?, ?)
+	`, userID, customerID, number, status, invoiceDate, due, total, total, total)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, _ := result.LastInsertId()
+	_, err = db.Exec(`
+		INSERT INTO ledger_entries (user_id, customer_id, entry_type, source, amount, description, entry_date, invoice_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, userID, customerID, models.LedgerDebit, models.LedgerSourceInvoice, total, "Fatura "+number, invoiceDate, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return int(id)
+}
+
+// addPayment tahsilatı cari hesaba yazar ve faturaya dağıtır
+func addPayment(t *testing.T, db *database.DB, userID, customerID, invoiceID int, kurus int64) {
+	amount := money.TL(kurus)
+	t.Helper()
+	result, err := db.Exec(`
+		INSERT INTO ledger_entries (user_id, customer_id, entry_type, source, amount, description, entry_date)
+		VALUES (?, ?, ?, ?, ?, 'Tahsilat', CURRENT_TIMESTAMP)
+	`, userID, customerID, models.LedgerCredit, models.LedgerSourcePayment, amount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entryID, _ := result.LastInsertId()
+	if _, err := db.Exec("INSERT INTO payment_allocations (entry_id, invoice_id, amount) VALUES (?, ?, ?)",
+		entryID, invoiceID, amount); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// kurus dilimleri karşılaştırma için kuruş cinsinden döndürür
+func kurus(b models.AgingBuckets) [5]int64 {
+	return [5]int64{b.Days0To30.Minor(), b.Days31To60.Minor(), b.Days61To90.Minor(), b.Over90.Minor(), b.Total.Minor()}
+}
+
+func TestAging(t *testing.T) {
+	db := dbtest.New(t)
+	userID := dbtest.User(t, db, "sahip@example.com")
+	otherID := dbtest.User(t, db, "diger@example.com")
+	asOf := day(2026, 6, 30)
+
+	ayse := addCustomer(t, db, userID, "Ayşe Demir", "")
+	partial := addInvoice(t, db, userID, ayse, "FTR2026000000001", models.InvoiceIssued, day(2026, 6, 20), 10000)
+	addPayment(t, db, userID, ayse, partial, 5000)
+	addInvoice(t, db, userID, ayse, "FTR2026000000002", models.InvoiceOverdue, day(2026, 5, 1), 20000)
+	addInvoice(t, db, userID, ayse, "FTR2026000000003", models.InvoiceOverdue, day(2026, 3, 1), 30000)
+	paid := addInvoice(t, db, userID, ayse, "FTR2026000000004", models.InvoicePaid, day(2026, 1, 1), 40000)
+	addPayment(t, db, userID, ayse, paid, 40000)
+
+	mehmet := addCustomer(t, db, userID, "Mehmet Kaya", "")
+	addInvoice(t, db, userID, mehmet, "FTR2026000000005", models.InvoiceIssued, day(2026, 7, 10), 100000)
+
+	// Başka işletmenin faturası rapora girmez
+	foreign := addCustomer(t, db, otherID, "Ali Veli", "")
+	addInvoice(t, db, otherID, foreign, "FTR2026000000006", models.InvoiceOverdue, day(2026, 1, 1), 500000)
+
+	report, err := receivables.Aging(db, userID, asOf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Customers) != 2 {
+		t.Fatalf("customers = %d, want 2", len(report.Customers))
+	}
+
+	// En yüksek açık tutarlı müşteri önce gelir
+	first, second := report.Customers[0], report.Customers[1]
+	if first.CustomerID != mehmet || kurus(first.Buckets) != [5]int64{100000, 0, 0, 0, 100000} {
+		t.Errorf("first customer = %d %+v", first.CustomerID, first.Buckets)
+	}
+	if second.CustomerID != ayse || second.Balance.Minor() != 55000 ||
+		kurus(second.Buckets) != [5]int64{5000, 20000, 0, 30000, 55000} {
+		t.Errorf("second customer = %d balance %v %+v", second.CustomerID, second.Balance, second.Buckets)
+	}
+	if len(second.Invoices) != 3 || second.Invoices[0].InvoiceNumber != "FTR2026000000003" ||
+		second.Invoices[0].DaysOverdue != 121 || second.Invoices[2].Outstanding.Minor() != 5000 {
+		t.Errorf("invoices = %+v", second.Invoices)
+	}
+	want := [5]int64{105000, 20000, 0, 30000, 155000}
+	if kurus(report.Totals) != want {
+		t.Errorf("totals = %+v, want %+v", report.Totals, want)
+	}
+}
+
+// Rapor tutarları faturanın ana para birimiyle etiketlenir; TL varsayılanıyla gösterilmez
+func TestAgingUsesBaseCurrency(t *testing.T) {
+	db := dbtest.New(t)
+	userID := dbtest.User(t, db, "sahip@example.com")
+	customerID := addCustomer(t, db, userID, "Ayşe Demir", "")
+	// Kesilmiş fatura değiştirilemez; ana para birimi fatura taslakken yazılır
+	invoiceID := addInvoice(t, db, userID, customerID, "FTR2026000000001", models.InvoiceDraft, day(2026, 5, 1), 20000)
+	if _, err := db.Exec("UPDATE invoices SET base_currency = ?, status = ? WHERE id = ?",
+		money.USD, models.InvoiceOverdue, invoiceID); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := receivables.Aging(db, userID, day(2026, 6, 30))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Customers) != 1 || len(report.Customers[0].Invoices) != 1 {
+		t.Fatalf("report = %+v", report)
+	}
+	customer := report.Customers[0]
+	invoice := customer.Invoices[0]
+	for name, m := range map[string]money.Money{
+		"balance":     customer.Balance,
+		"total":       invoice.Total,
+		"outstanding": invoice.Outstanding,
+		"bucket":      customer.Buckets.Total,
+		"report":      report.Totals.Total,
+	} {
+		if m.Currency() != money.USD || m.Minor() != 20000 {
+			t.Errorf("%s = %v %s, want 200,00 USD", name, m, m.Currency())
+		}
+	}
+}
+
+func TestDaysOverdue(t *testing.T) {
+	due := day(2026, 3, 20)
+	tests := []struct {
+		name    string
+		invoice models.OpenInvoice
+		asOf    time.Time
+		want    int
+	}{
+		{"not due yet", models.OpenInvoice{InvoiceDate: day(2026, 3, 1), DueDate: &due}, day(2026, 3, 10), 0},
+		{"due today", models.OpenInvoice{InvoiceDate: day(2026, 3, 1), DueDate: &due}, day(2026, 3, 20).Add(23 * time.Hour), 0},
+		// Yaz saati uygulanan saat dilimlerinde geçiş günü sayıyı değiştirmez
+		{"across daylight saving", models.OpenInvoice{InvoiceDate: day(2026, 3, 1), DueDate: &due}, day(2026, 4, 19), 30},
+		{"without due date", models.OpenInvoice{InvoiceDate: day(2026, 3, 1)}, day(2026, 3, 8), 7},
+	}
+	for _, tt := range tests {
+		if got := receivables.DaysOverdue(tt.invoice, tt.asOf); got != tt.want {
+			t.Errorf("%s: DaysOverdue = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,307 @@
+package receivables
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/messaging"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/money"
+	"github.com/umutaraz/tradesman-app/internal/notify"
+	"github.com/umutaraz/tradesman-app/internal/outbox"
+	"github.com/umutaraz/tradesman-app/internal/permissions"
+	"github.com/umutaraz/tradesman-app/internal/settings"
+)
+
+// Dunner vadesi geçen faturalar için işletmenin belirlediği gecikme kademelerinde ödeme
+// hatırlatması gönderir. Her kademe dunning_notices tablosuna fatura başına bir kez yazılır;
+// fatura kapandığında ya da müşterinin cari bakiyesi sıfırlandığında yeni hatırlatma gönderilmez.
+type Dunner struct {
+	db       *database.DB
+	settings *settings.Store
+	notifier *notify.Notifier
+	interval time.Duration
+}
+
+// NewDunner ödeme hatırlatma zamanlayıcısı oluşturur
+func NewDunner(db *database.DB, store *settings.Store, notifier *notify.Notifier, interval time.Duration) *Dunner {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	return &Dunner{db: db, settings: store, notifier: notifier, interval: interval}
+}
+
+// Run bağlam iptal edilene kadar ödeme hatırlatmalarını gönderir
+func (d *Dunner) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.Process(time.Now()); err != nil {
+			log.Printf("Ödeme hatırlatma hatası: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// overdueInvoice hatırlatma için gereken fatura, müşteri ve işletme bilgileri
+type overdueInvoice struct {
+	models.OpenInvoice
+	userID        int
+	customerID    int
+	customerName  string
+	customerPhone string
+	balance       money.Money
+	businessName  string
+	businessPhone string
+}
+
+// dunningPreferences işletmenin ödeme hatırlatma ayarları
+type dunningPreferences struct {
+	stages      []int
+	sms         bool
+	smsTemplate string
+	dateLayout  string // Mesajlardaki tarihlerin işletmenin seçtiği biçimi
+}
+
+// Process vadesi geçen açık faturaları işler ve gönderilen hatırlatma sayısını döndürür.
+// Fatura birden fazla kademeyi birlikte geçtiyse yalnızca ulaşılan en yüksek kademe gönderilir.
+func (d *Dunner) Process(now time.Time) (int, error) {
+	invoices, err := d.overdueInvoices(now)
+	if err != nil {
+		return 0, err
+	}
+
+	prefs := make(map[int]dunningPreferences)
+	sent := 0
+	for _, invoice := range invoices {
+		p, cached := prefs[invoice.userID]
+		if !cached {
+			if p, err = d.preferences(invoice.userID); err != nil {
+				return sent, err
+			}
+			prefs[invoice.userID] = p
+		}
+
+		days := DaysOverdue(invoice.OpenInvoice, now)
+		stage := reachedStage(p.stages, days)
+		if stage == 0 {
+			continue
+		}
+
+		ok, err := d.sendNotice(invoice, p, stage, days)
+		if err != nil {
+			return sent, fmt.Errorf("fatura %d: %w", invoice.InvoiceID, err)
+		}
+		if ok {
+			sent++
+		}
+	}
+
+	return sent, nil
+}
+
+// preferences işletmenin hatırlatma kademelerini, SMS tercihini, şablonunu ve tarih biçimini yükler
+func (d *Dunner) preferences(businessID int) (dunningPreferences, error) {
+	var p dunningPreferences
+	s, err := d.settings.Get(businessID)
+	if err != nil {
+		return p, err
+	}
+	if p.stages, err = settings.ParseStages(s.DunningStages); err != nil {
+		// Elle bozulmuş ayar diğer işletmeleri engellememeli
+		log.Printf("İşletme %d için ödeme hatırlatma kademeleri geçersiz: %v", businessID, err)
+		return p, nil
+	}
+	p.sms = s.DunningSMS
+	p.dateLayout = s.DateLayout()
+
+	tmpl, _ := messaging.FindTemplate(messaging.TemplatePaymentDue)
+	p.smsTemplate, _, err = d.db.GetMessageTemplate(businessID, tmpl.Key, tmpl.Default)
+	return p, err
+}
+
+// overdueInvoices vadesi geçmiş, kalan tutarı olan ve müşterisinin cari bakiyesi borç gösteren
+// faturaları döndürür
+func (d *Dunner) overdueInvoices(now time.Time) ([]overdueInvoice, error) {
+	rows, err := d.db.Query(`
+		SELECT invoices.id, invoices.invoice_number, invoices.invoice_date, invoices.due_date, invoices.status,
+		       invoices.base_total_amount, `+database.InvoiceOutstandingExpr+`, invoices.user_id, customers.id,
+		       customers.name, COALESCE(customers.phone, ''), `+database.CustomerBalanceExpr+`,
+		       COALESCE(NULLIF(u.business_name, ''), u.name), COALESCE(u.phone, ''), invoices.base_currency
+		FROM invoices
+		JOIN customers ON customers.id = invoices.customer_id
+		JOIN users u ON u.id = invoices.user_id
+		WHERE invoices.invoice_type != ? AND invoices.status IN (?, ?)
+		  AND invoices.due_date IS NOT NULL AND invoices.due_date < ?
+		ORDER BY invoices.due_date, invoices.id
+	`, models.InvoiceTypeCreditNote, models.InvoiceIssued, models.InvoiceOverdue, dateOnly(now))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var invoices []overdueInvoice
+	for rows.Next() {
+		var i overdueInvoice
+		var dueDate time.Time
+		var currency money.Currency
+		if err := rows.Scan(&i.InvoiceID, &i.InvoiceNumber, &i.InvoiceDate, &dueDate, &i.Status, &i.Total,
+			&i.Outstanding, &i.userID, &i.customerID, &i.customerName, &i.customerPhone, &i.balance,
+			&i.businessName, &i.businessPhone, &currency); err != nil {
+			return nil, err
+		}
+		i.DueDate = &dueDate
+		i.Total = i.Total.WithCurrency(currency)
+		i.Outstanding = i.Outstanding.WithCurrency(currency)
+		i.balance = i.balance.WithCurrency(currency)
+		// Borç kapandıysa hatırlatma durur; dağıtılmamış tahsilat da bakiyeyi kapatabilir
+		if !i.Outstanding.IsPositive() || !i.balance.IsPositive() {
+			continue
+		}
+		invoices = append(invoices, i)
+	}
+
+	return invoices, rows.Err()
+}
+
+// sendNotice kademeyi işaretler, isteğe bağlı SMS'i kuyruğa ekler ve faturaları yönetebilen
+// kullanıcılara bildirim gönderir; kademe daha önce gönderildiyse false döner
+func (d *Dunner) sendNotice(invoice overdueInvoice, p dunningPreferences, stage, days int) (bool, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		INSERT OR IGNORE INTO dunning_notices (user_id, customer_id, invoice_id, stage, days_overdue, outstanding)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, invoice.userID, invoice.customerID, invoice.InvoiceID, stage, days, invoice.Outstanding)
+	if err != nil {
+		return false, err
+	}
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+	noticeID, err := result.LastInsertId()
+	if err != nil {
+		return false, err
+	}
+
+	if p.sms && invoice.customerPhone != "" {
+		if err := queueMessage(tx, invoice, p, noticeID); err != nil {
+			return false, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	err = d.notifier.SendToBusiness(invoice.userID, permissions.ManageInvoices, models.Notification{
+		Type:     models.NotificationInvoice,
+		Severity: models.SeverityWarning,
+		Title:    fmt.Sprintf("Ödeme hatırlatması (%d gün): %s", stage, invoice.InvoiceNumber),
+		Message: fmt.Sprintf("%s, %s tutarındaki ödemeyi %d gündür geciktiriyor.", invoice.customerName,
+			messaging.FormatAmount(invoice.Outstanding), days),
+		Link: fmt.Sprintf("/customers/detail/%d", invoice.customerID),
+	})
+	return true, err
+}
+
+// queueMessage müşteriye ödeme hatırlatma SMS'ini kuyruğa ekler ve etkinlik geçmişine yazar.
+// Şablondaki {tutar} faturanın kalan tutarıyla doldurulur.
+func queueMessage(tx *sql.Tx, invoice overdueInvoice, p dunningPreferences, noticeID int64) error {
+	text := messaging.Render(p.smsTemplate, messaging.Data{
+		Customer:      &models.Customer{ID: invoice.customerID, Name: invoice.customerName, Phone: invoice.customerPhone},
+		BusinessName:  invoice.businessName,
+		BusinessPhone: invoice.businessPhone,
+		Invoice: &models.Invoice{
+			ID:            invoice.InvoiceID,
+			InvoiceNumber: invoice.InvoiceNumber,
+			DueDate:       invoice.DueDate,
+			TotalAmount:   invoice.Outstanding,
+		},
+		DateLayout: p.dateLayout,
+	})
+	msg := messaging.Message{Channel: messaging.ChannelSMS, To: invoice.customerPhone, Body: text}
+	if err := msg.Validate(); err != nil {
+		// Geçersiz numara bildirimi engellememeli
+		log.Printf("Fatura %d için ödeme hatırlatma SMS'i gönderilemedi: %v", invoice.InvoiceID, err)
+		return nil
+	}
+
+	outboxID, err := outbox.EnqueueMessage(tx, invoice.userID, msg)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE dunning_notices SET outbox_id = ? WHERE id = ?", outboxID, noticeID); err != nil {
+		return err
+	}
+
+	return database.InsertCustomerActivity(tx, models.CustomerActivity{
+		UserID:     invoice.userID,
+		CustomerID: invoice.customerID,
+		Type:       models.ActivityMessageSent,
+		Description: fmt.Sprintf("%s numarasına %s numaralı fatura için ödeme hatırlatma SMS'i gönderildi: %s",
+			invoice.customerPhone, invoice.InvoiceNumber, text),
+		OutboxID: &outboxID,
+	})
+}
+
+// reachedStage gecikme gününün ulaştığı en yüksek kademeyi döndürür; hiçbir kademeye ulaşılmadıysa 0
+func reachedStage(stages []int, days int) int {
+	reached := 0
+	for _, stage := range stages {
+		if stage <= days {
+			reached = stage
+		}
+	}
+	return reached
+}
+
+// RecentNotices işletmenin son gönderilen ödeme hatırlatmalarını döndürür
+func RecentNotices(db *database.DB, businessID, limit int) ([]models.DunningNotice, error) {
+	rows, err := db.Query(`
+		SELECT n.id, n.user_id, n.customer_id, c.name, n.invoice_id, i.invoice_number, n.stage, n.days_overdue,
+		       n.outstanding, i.base_currency, n.outbox_id, n.created_at
+		FROM dunning_notices n
+		JOIN customers c ON c.id = n.customer_id
+		JOIN invoices i ON i.id = n.invoice_id
+		WHERE n.user_id = ?
+		ORDER BY n.created_at DESC, n.id DESC
+		LIMIT ?
+	`, businessID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notices := []models.DunningNotice{}
+	for rows.Next() {
+		var n models.DunningNotice
+		var outboxID sql.NullInt64
+		var currency money.Currency
+		if err := rows.Scan(&n.ID, &n.UserID, &n.CustomerID, &n.CustomerName, &n.InvoiceID, &n.InvoiceNumber,
+			&n.Stage, &n.DaysOverdue, &n.Outstanding, &currency, &outboxID, &n.CreatedAt); err != nil {
+			return nil, err
+		}
+		n.Outstanding = n.Outstanding.WithCurrency(currency)
+		if outboxID.Valid {
+			id := int(outboxID.Int64)
+			n.OutboxID = &id
+		}
+		notices = append(notices, n)
+	}
+
+	return notices, rows.Err()
+}
@@ -0,0 +1,101 @@
+package receivables_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/database/dbtest"
+	"github.com/umutaraz/tradesman-app/internal/messaging"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/notify"
+	"github.com/umutaraz/tradesman-app/internal/outbox"
+	"github.com/umutaraz/tradesman-app/internal/receivables"
+	"github.com/umutaraz/tradesman-app/internal/settings"
+)
+
+func TestDunnerSendsReachedStageOnce(t *testing.T) {
+	db := dbtest.New(t)
+	userID := dbtest.User(t, db, "sahip@example.com")
+	if err := db.SetSetting(userID, database.SettingDunningStages, "7,30"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SetSetting(userID, database.SettingDunningSMS, "true"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SetSetting(userID, database.SettingDateFormat, settings.DateFormatSlash); err != nil {
+		t.Fatal(err)
+	}
+
+	// 35 gün gecikmiş fatura ve henüz vadesi gelmemiş fatura
+	now := time.Now()
+	customerID := addCustomer(t, db, userID, "Ayşe Demir", "0533 111 22 33")
+	dueDate := now.AddDate(0, 0, -35)
+	invoiceID := addInvoice(t, db, userID, customerID, "FTR2026000000001", models.InvoiceOverdue, dueDate, 120000)
+	addInvoice(t, db, userID, customerID, "FTR2026000000002", models.InvoiceIssued, now.AddDate(0, 0, 10), 30000)
+
+	dunner := receivables.NewDunner(db, settings.NewStore(db), notify.New(db, notify.NewHub()), time.Hour)
+	sent, err := dunner.Process(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent != 1 {
+		t.Fatalf("sent = %d, want 1", sent)
+	}
+	// Yalnızca ulaşılan en yüksek kademe yazılır ve ikinci çalışma tekrar göndermez
+	var stage int
+	if err := db.QueryRow("SELECT stage FROM dunning_notices WHERE invoice_id = ?", invoiceID).Scan(&stage); err != nil {
+		t.Fatal(err)
+	}
+	if stage != 30 {
+		t.Errorf("stage = %d, want 30", stage)
+	}
+	if sent, err := dunner.Process(now.Add(time.Hour)); err != nil || sent != 0 {
+		t.Fatalf("second run: sent = %d, err = %v", sent, err)
+	}
+
+	p := messaging.NewMemory()
+	w := outbox.NewWorker(db.DB, time.Minute)
+	w.Handle(outbox.ChannelSMS, outbox.MessageSender(p))
+	if err := w.ProcessDue(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	messages := p.Sent()
+	if len(messages) != 1 {
+		t.Fatalf("messages = %d, want 1", len(messages))
+	}
+	for _, want := range []string{"FTR2026000000001", "1.200,00 TL", dueDate.In(time.Local).Format("02/01/2006")} {
+		if !strings.Contains(messages[0].Body, want) {
+			t.Errorf("message %q does not contain %q", messages[0].Body, want)
+		}
+	}
+}
+
+func TestDunnerSkipsSettledCustomers(t *testing.T) {
+	db := dbtest.New(t)
+	userID := dbtest.User(t, db, "sahip@example.com")
+	now := time.Now()
+
+	// Tahsilatla kapanan fatura için hatırlatma gönderilmez
+	paid := addCustomer(t, db, userID, "Ayşe Demir", "")
+	invoiceID := addInvoice(t, db, userID, paid, "FTR2026000000001", models.InvoiceOverdue, now.AddDate(0, 0, -40), 50000)
+	addPayment(t, db, userID, paid, invoiceID, 50000)
+
+	// Hatırlatmalar kapatılmış işletme
+	otherID := dbtest.User(t, db, "diger@example.com")
+	if err := db.SetSetting(otherID, database.SettingDunningStages, ""); err != nil {
+		t.Fatal(err)
+	}
+	other := addCustomer(t, db, otherID, "Mehmet Kaya", "")
+	addInvoice(t, db, otherID, other, "FTR2026000000002", models.InvoiceOverdue, now.AddDate(0, 0, -40), 50000)
+
+	sent, err := receivables.NewDunner(db, settings.NewStore(db), notify.New(db, notify.NewHub()), time.Hour).Process(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent != 0 {
+		t.Errorf("sent = %d, want 0", sent)
+	}
+}
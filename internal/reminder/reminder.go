@@ -0,0 +1,317 @@
+// Package reminder yaklaşan randevular için hatırlatma gönderir. Hatırlatma süresi randevunun
+// reminder alanında (dakika) tutulur; zamanı gelen randevular veritabanından okunduğu için
+// sunucu kapalıyken kaçırılan hatırlatmalar, randevu başlamadıysa yeniden açılışta gönderilir.
+package reminder
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/mailer"
+	"github.com/umutaraz/tradesman-app/internal/messaging"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/notify"
+	"github.com/umutaraz/tradesman-app/internal/outbox"
+	"github.com/umutaraz/tradesman-app/internal/settings"
+)
+
+// Hatırlatma kanalları
+const (
+	ChannelNotification = "notification" // Sorumlu personele uygulama içi bildirim
+	ChannelEmail        = outbox.ChannelEmail
+	ChannelSMS          = outbox.ChannelSMS
+)
+
+// MaxLead taranan en uzun hatırlatma süresi; daha erken hatırlatmalar bu süreye kısaltılır
+const MaxLead = 7 * 24 * time.Hour
+
+// Scheduler zamanı gelen randevu hatırlatmalarını düzenli aralıklarla gönderir. Her gönderim
+// appointment_reminders tablosuna kaydedilir; kayıt ve bildirim/kuyruk kaydı aynı tx içinde
+// eklendiğinden yeniden başlatma ya da eşzamanlı çalışma aynı hatırlatmayı iki kez göndermez.
+type Scheduler struct {
+	db       *database.DB
+	settings *settings.Store
+	notifier *notify.Notifier
+	interval time.Duration
+}
+
+// NewScheduler hatırlatma zamanlayıcısı oluşturur
+func NewScheduler(db *database.DB, store *settings.Store, notifier *notify.Notifier, interval time.Duration) *Scheduler {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &Scheduler{db: db, settings: store, notifier: notifier, interval: interval}
+}
+
+// Run bağlam iptal edilene kadar hatırlatmaları gönderir
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Process(time.Now()); err != nil {
+			log.Printf("Randevu hatırlatma hatası: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// dueAppointment hatırlatma için gereken randevu, müşteri ve işletme bilgileri
+type dueAppointment struct {
+	models.Appointment
+	customerName  string
+	customerEmail string
+	customerPhone string
+	businessName  string
+	businessEmail string
+	businessPhone string
+}
+
+// preferences işletmenin müşteriye hatırlatma gönderme tercihleri
+type preferences struct {
+	email       bool
+	sms         bool
+	smsTemplate string
+	dateLayout  string // Mesajlardaki tarihlerin işletmenin seçtiği biçimi
+}
+
+// Process hatırlatma zamanı gelmiş ve henüz başlamamış randevuları işler, gönderilen
+// hatırlatma sayısını döndürür
+func (s *Scheduler) Process(now time.Time) (int, error) {
+	due, err := s.dueAppointments(now)
+	if err != nil {
+		return 0, err
+	}
+
+	prefs := make(map[int]preferences)
+	sent := 0
+	for _, a := range due {
+		p, cached := prefs[a.UserID]
+		if !cached {
+			if p, err = s.preferences(a.UserID); err != nil {
+				return sent, err
+			}
+			prefs[a.UserID] = p
+		}
+
+		ok, err := s.sendNotification(a, p)
+		if err == nil && ok {
+			sent++
+		}
+		if err == nil && p.email && a.customerEmail != "" {
+			ok, err = s.queueEmail(a, p)
+			if err == nil && ok {
+				sent++
+			}
+		}
+		if err == nil && p.sms && a.customerPhone != "" {
+			ok, err = s.queueMessage(a, p)
+			if err == nil && ok {
+				sent++
+			}
+		}
+		if err != nil {
+			return sent, fmt.Errorf("randevu %d: %w", a.ID, err)
+		}
+	}
+
+	return sent, nil
+}
+
+// preferences işletmenin hatırlatma ayarlarını, SMS şablonunu ve tarih biçimini yükler
+func (s *Scheduler) preferences(businessID int) (preferences, error) {
+	var p preferences
+	prefs, err := s.settings.Get(businessID)
+	if err != nil {
+		return p, err
+	}
+	p.email = prefs.ReminderEmail
+	p.sms = prefs.ReminderSMS
+	p.dateLayout = prefs.DateLayout()
+
+	tmpl, _ := messaging.FindTemplate(messaging.TemplateAppointmentReminder)
+	p.smsTemplate, _, err = s.db.GetMessageTemplate(businessID, tmpl.Key, tmpl.Default)
+	return p, err
+}
+
+// dueAppointments hatırlatma zamanı gelmiş, iptal edilmemiş ve başlamamış randevuları döndürür
+func (s *Scheduler) dueAppointments(now time.Time) ([]dueAppointment, error) {
+	rows, err := s.db.Query(`
+		SELECT a.id, a.user_id, a.customer_id, a.staff_id, a.title, a.start_time, a.end_time, a.status, a.reminder,
+		       c.name, COALESCE(c.email, ''), COALESCE(c.phone, ''), COALESCE(NULLIF(u.business_name, ''), u.name), u.email,
+		       COALESCE(u.phone, '')
+		FROM appointments a
+		JOIN customers c ON c.id = a.customer_id
+		JOIN users u ON u.id = a.user_id
+		WHERE a.reminder > 0 AND a.status IN (?, ?) AND a.start_time > ? AND a.start_time <= ?
+		ORDER BY a.start_time
+	`, models.AppointmentNew, models.AppointmentConfirmed, now, now.Add(MaxLead))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var due []dueAppointment
+	for rows.Next() {
+		var a dueAppointment
+		if err := rows.Scan(&a.ID, &a.UserID, &a.CustomerID, &a.StaffID, &a.Title, &a.StartTime, &a.EndTime,
+			&a.Status, &a.Reminder, &a.customerName, &a.customerEmail, &a.customerPhone, &a.businessName, &a.businessEmail,
+			&a.businessPhone); err != nil {
+			return nil, err
+		}
+
+		lead := time.Duration(a.Reminder) * time.Minute
+		if lead > MaxLead {
+			lead = MaxLead
+		}
+		if a.StartTime.Add(-lead).After(now) {
+			continue
+		}
+		due = append(due, a)
+	}
+
+	return due, rows.Err()
+}
+
+// claim hatırlatmayı gönderilmiş olarak işaretler ve kayıt ID'sini döndürür; daha önce gönderildiyse 0 döner
+func claim(tx *sql.Tx, a dueAppointment, channel string) (int64, error) {
+	result, err := tx.Exec(`
+		INSERT OR IGNORE INTO appointment_reminders (user_id, appointment_id, channel, start_time)
+		VALUES (?, ?, ?, ?)
+	`, a.UserID, a.ID, channel, a.StartTime)
+	if err != nil {
+		return 0, err
+	}
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// sendNotification sorumlu personele uygulama içi hatırlatma gönderir
+func (s *Scheduler) sendNotification(a dueAppointment, p preferences) (bool, error) {
+	notification := models.Notification{
+		UserID:   a.StaffID,
+		Type:     models.NotificationAppointment,
+		Severity: models.SeverityInfo,
+		Title:    "Yaklaşan randevu: " + a.Title,
+		Message: fmt.Sprintf("%s, %s - %s", a.customerName,
+			a.StartTime.In(time.Local).Format(p.dateLayout+" 15:04"), a.EndTime.In(time.Local).Format("15:04")),
+		Link: "/appointments",
+	}
+
+	sent, err := s.withClaim(a, ChannelNotification, func(tx *sql.Tx, reminderID int64) error {
+		if err := database.InsertNotification(tx, &notification); err != nil {
+			return err
+		}
+		_, err := tx.Exec("UPDATE appointment_reminders SET notification_id = ? WHERE id = ?", notification.ID, reminderID)
+		return err
+	})
+	if sent {
+		s.notifier.Hub().Publish(notification)
+	}
+	return sent, err
+}
+
+// queueEmail müşteriye gönderilecek hatırlatma e-postasını kuyruğa ekler
+func (s *Scheduler) queueEmail(a dueAppointment, p preferences) (bool, error) {
+	msg := mailer.Message{
+		ReplyTo: a.businessEmail,
+		To:      []string{a.customerEmail},
+		Subject: fmt.Sprintf("%s - Randevu hatırlatması", a.businessName),
+		Body:    mailBody(a, p.dateLayout),
+	}
+
+	return s.withClaim(a, ChannelEmail, func(tx *sql.Tx, reminderID int64) error {
+		outboxID, err := outbox.EnqueueEmail(tx, a.UserID, msg)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec("UPDATE appointment_reminders SET outbox_id = ? WHERE id = ?", outboxID, reminderID)
+		return err
+	})
+}
+
+// queueMessage müşteriye gönderilecek hatırlatma SMS'ini kuyruğa ekler ve etkinlik geçmişine yazar
+func (s *Scheduler) queueMessage(a dueAppointment, p preferences) (bool, error) {
+	text := messaging.Render(p.smsTemplate, messaging.Data{
+		Customer:      &models.Customer{ID: a.CustomerID, Name: a.customerName, Phone: a.customerPhone},
+		BusinessName:  a.businessName,
+		BusinessPhone: a.businessPhone,
+		Appointment:   &a.Appointment,
+		DateLayout:    p.dateLayout,
+	})
+	msg := messaging.Message{Channel: messaging.ChannelSMS, To: a.customerPhone, Body: text}
+	if err := msg.Validate(); err != nil {
+		// Geçersiz numara diğer hatırlatmaları engellememeli
+		log.Printf("Randevu %d için SMS hatırlatması gönderilemedi: %v", a.ID, err)
+		return false, nil
+	}
+
+	return s.withClaim(a, ChannelSMS, func(tx *sql.Tx, reminderID int64) error {
+		outboxID, err := outbox.EnqueueMessage(tx, a.UserID, msg)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec("UPDATE appointment_reminders SET outbox_id = ? WHERE id = ?", outboxID, reminderID); err != nil {
+			return err
+		}
+
+		return database.InsertCustomerActivity(tx, models.CustomerActivity{
+			UserID:      a.UserID,
+			CustomerID:  a.CustomerID,
+			Type:        models.ActivityMessageSent,
+			Description: fmt.Sprintf("%s numarasına randevu hatırlatma SMS'i gönderildi: %s", a.customerPhone, text),
+			OutboxID:    &outboxID,
+		})
+	})
+}
+
+// withClaim hatırlatmayı işaretler ve aynı tx içinde send'i çalıştırır
+func (s *Scheduler) withClaim(a dueAppointment, channel string, send func(tx *sql.Tx, reminderID int64) error) (bool, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	reminderID, err := claim(tx, a, channel)
+	if err != nil || reminderID == 0 {
+		return false, err
+	}
+
+	if err := send(tx, reminderID); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// mailBody müşteriye gönderilen hatırlatma e-postasının metnini tarihi işletmenin biçimiyle yazarak oluşturur
+func mailBody(a dueAppointment, dateLayout string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Sayın %s,\n\n", a.customerName)
+	fmt.Fprintf(&b, "%s tarihinde saat %s - %s arasında randevunuz bulunmaktadır.\n\n",
+		a.StartTime.In(time.Local).Format(dateLayout), a.StartTime.In(time.Local).Format("15:04"),
+		a.EndTime.In(time.Local).Format("15:04"))
+	fmt.Fprintf(&b, "Konu: %s\n", a.Title)
+	b.WriteString("\nRandevunuza gelemeyecekseniz lütfen bize haber veriniz.\n")
+
+	fmt.Fprintf(&b, "\nSaygılarımızla,\n%s\n", a.businessName)
+	if a.businessPhone != "" {
+		fmt.Fprintf(&b, "Tel: %s\n", a.businessPhone)
+	}
+	return b.String()
+}
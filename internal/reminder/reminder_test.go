@@ -0,0 +1,122 @@
+package reminder_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/database/dbtest"
+	"github.com/umutaraz/tradesman-app/internal/mailer"
+	"github.com/umutaraz/tradesman-app/internal/messaging"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/notify"
+	"github.com/umutaraz/tradesman-app/internal/outbox"
+	"github.com/umutaraz/tradesman-app/internal/reminder"
+	"github.com/umutaraz/tradesman-app/internal/settings"
+)
+
+// addAppointment müşteri ve verilen sürede başlayan, reminder dakika önce hatırlatılacak randevu ekler
+func addAppointment(t *testing.T, db *database.DB, userID int, email string, in time.Duration, reminder int) time.Time {
+	t.Helper()
+	result, err := db.Exec("INSERT INTO customers (user_id, name, email, phone) VALUES (?, ?, ?, ?)",
+		userID, "Ayşe Demir", email, "0533 111 22 33")
+	if err != nil {
+		t.Fatal(err)
+	}
+	customerID, _ := result.LastInsertId()
+
+	start := time.Now().Add(in).Truncate(time.Minute)
+	_, err = db.Exec(`
+		INSERT INTO appointments (user_id, customer_id, staff_id, title, start_time, end_time, status, reminder)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, userID, customerID, userID, "Kombi bakımı", start, start.Add(time.Hour), models.AppointmentNew, reminder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return start
+}
+
+func TestSchedulerSendsRemindersOnce(t *testing.T) {
+	db := dbtest.New(t)
+	userID := dbtest.User(t, db, "sahip@example.com")
+	if err := db.SetSetting(userID, database.SettingReminderSMS, "true"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SetSetting(userID, database.SettingDateFormat, settings.DateFormatISO); err != nil {
+		t.Fatal(err)
+	}
+	start := addAppointment(t, db, userID, "ayse@example.com", 30*time.Minute, 60)
+	// Hatırlatma zamanı henüz gelmemiş randevu
+	addAppointment(t, db, userID, "mehmet@example.com", 3*time.Hour, 60)
+
+	now := time.Now()
+	scheduler := reminder.NewScheduler(db, settings.NewStore(db), notify.New(db, notify.NewHub()), time.Minute)
+	sent, err := scheduler.Process(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent != 3 {
+		t.Fatalf("sent = %d, want notification, e-mail and SMS", sent)
+	}
+	// Yeniden çalışma aynı hatırlatmayı tekrar göndermez
+	if sent, err := scheduler.Process(now.Add(time.Minute)); err != nil || sent != 0 {
+		t.Fatalf("second run: sent = %d, err = %v", sent, err)
+	}
+
+	var notifications int
+	if err := db.QueryRow("SELECT COUNT(*) FROM notifications WHERE user_id = ?", userID).Scan(&notifications); err != nil {
+		t.Fatal(err)
+	}
+	if notifications != 1 {
+		t.Errorf("notifications = %d, want 1", notifications)
+	}
+
+	mails, messages := deliver(t, db)
+	// Tarihler işletmenin seçtiği biçimde yazılır
+	date := start.In(time.Local).Format("2006-01-02")
+	if len(mails) != 1 || mails[0].To[0] != "ayse@example.com" || !strings.Contains(mails[0].Body, date) {
+		t.Errorf("mails = %+v, want one reminder dated %s", mails, date)
+	}
+	if len(messages) != 1 || !strings.Contains(messages[0].Body, date+" "+start.In(time.Local).Format("15:04")) {
+		t.Errorf("messages = %+v, want one reminder dated %s", messages, date)
+	}
+}
+
+func TestSchedulerRespectsEmailSetting(t *testing.T) {
+	db := dbtest.New(t)
+	userID := dbtest.User(t, db, "sahip@example.com")
+	if err := db.SetSetting(userID, database.SettingReminderEmail, "false"); err != nil {
+		t.Fatal(err)
+	}
+	addAppointment(t, db, userID, "ayse@example.com", 30*time.Minute, 60)
+	// Başlamış randevuya hatırlatma gönderilmez
+	addAppointment(t, db, userID, "mehmet@example.com", -10*time.Minute, 60)
+
+	scheduler := reminder.NewScheduler(db, settings.NewStore(db), notify.New(db, notify.NewHub()), time.Minute)
+	sent, err := scheduler.Process(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent != 1 {
+		t.Errorf("sent = %d, want only the notification", sent)
+	}
+	if mails, messages := deliver(t, db); len(mails) != 0 || len(messages) != 0 {
+		t.Errorf("mails = %d, messages = %d; want none", len(mails), len(messages))
+	}
+}
+
+// deliver kuyruktaki iletileri bellek göndericileriyle gönderir
+func deliver(t *testing.T, db *database.DB) ([]mailer.Message, []messaging.Message) {
+	t.Helper()
+	m := mailer.NewMemory()
+	p := messaging.NewMemory()
+	w := outbox.NewWorker(db.DB, time.Minute)
+	w.Handle(outbox.ChannelEmail, outbox.EmailSender(m))
+	w.Handle(outbox.ChannelSMS, outbox.MessageSender(p))
+	if err := w.ProcessDue(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return m.Sent(), p.Sent()
+}
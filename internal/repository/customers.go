@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/listquery"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/money"
+)
+
+type customerRepo struct {
+	*store
+}
+
+// customerColumns scanCustomer sırasıyla müşteri kolonları
+var customerColumns = `customers.id, customers.user_id, customers.name, COALESCE(customers.email, ''),
+	COALESCE(customers.phone, ''), COALESCE(customers.address, ''), COALESCE(customers.notes, ''),
+	customers.archived_at, ` + database.CustomerBalanceExpr + `, ` + database.CustomerCurrencyExpr + `,
+	customers.created_at, customers.updated_at`
+
+func (r *customerRepo) List(userID int, archived bool, q *listquery.Query) ([]models.Customer, listquery.Page, error) {
+	source := " FROM customers WHERE user_id = ? AND archived_at IS NULL"
+	if archived {
+		source = " FROM customers WHERE user_id = ? AND archived_at IS NOT NULL"
+	}
+
+	query, args, page, err := r.listPage(q, customerColumns, source, userID)
+	if err != nil {
+		return nil, page, err
+	}
+	customers, err := r.selectCustomers(query, args...)
+	return customers, page, err
+}
+
+func (r *customerRepo) Active(userID int) ([]models.Customer, error) {
+	return r.selectCustomers("SELECT "+customerColumns+" FROM customers WHERE user_id = ? AND archived_at IS NULL"+
+		" ORDER BY created_at DESC", userID)
+}
+
+func (r *customerRepo) Get(userID, id int) (*models.Customer, error) {
+	customer, err := scanCustomer(r.conn().queryRow("SELECT "+customerColumns+" FROM customers WHERE id = ? AND user_id = ?",
+		id, userID))
+	return customer, notFound(err)
+}
+
+func (r *customerRepo) Create(customer *models.Customer) (int, error) {
+	return r.conn().insert(`
+		INSERT INTO customers (user_id, name, email, phone, address, notes)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, customer.UserID, customer.Name, customer.Email, customer.Phone, customer.Address, customer.Notes)
+}
+
+func (r *customerRepo) Update(userID, id int, customer *models.Customer) error {
+	result, err := r.conn().exec(`
+		UPDATE customers SET name = ?, email = ?, phone = ?, address = ?, notes = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND user_id = ?
+	`, customer.Name, customer.Email, customer.Phone, customer.Address, customer.Notes, id, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *customerRepo) HasHistory(userID, id int) (bool, error) {
+	for _, table := range customerRefs {
+		n, err := r.conn().count("SELECT COUNT(*) FROM "+table+" WHERE customer_id = ?", id)
+		if err != nil || n > 0 {
+			return n > 0, err
+		}
+	}
+	return false, nil
+}
+
+func (r *customerRepo) Archive(userID, id int) error {
+	_, err := r.conn().exec(`
+		UPDATE customers SET archived_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND user_id = ? AND archived_at IS NULL
+	`, id, userID)
+	return err
+}
+
+func (r *customerRepo) Restore(userID, id int) error {
+	result, err := r.conn().exec(`
+		UPDATE customers SET archived_at = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND user_id = ? AND archived_at IS NOT NULL
+	`, id, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n > 0 {
+		return nil
+	}
+
+	if _, err := r.Get(userID, id); err != nil {
+		return err
+	}
+	return ConflictError("Müşteri arşivde değil")
+}
+
+func (r *customerRepo) Delete(userID, id int) error {
+	return r.withTx(func(tx conn) error {
+		for _, table := range customerOwned {
+			if _, err := tx.exec("DELETE FROM "+table+" WHERE customer_id = ? AND user_id = ?", id, userID); err != nil {
+				return err
+			}
+		}
+		_, err := tx.exec("DELETE FROM customers WHERE id = ? AND user_id = ?", id, userID)
+		return err
+	})
+}
+
+func (r *customerRepo) Count(userID int) (int, error) {
+	return r.conn().count("SELECT COUNT(*) FROM customers WHERE user_id = ? AND archived_at IS NULL", userID)
+}
+
+// selectCustomers customerColumns ile seçilen müşterileri döndürür
+func (r *customerRepo) selectCustomers(query string, args ...interface{}) ([]models.Customer, error) {
+	rows, err := r.conn().query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	customers := []models.Customer{}
+	for rows.Next() {
+		customer, err := scanCustomer(rows)
+		if err != nil {
+			return nil, err
+		}
+		customers = append(customers, *customer)
+	}
+
+	return customers, rows.Err()
+}
+
+// scanCustomer customerColumns sırasıyla müşteriyi okur
+func scanCustomer(rs rowScanner) (*models.Customer, error) {
+	var customer models.Customer
+	var archivedAt sql.NullTime
+	var currency money.Currency
+	err := rs.Scan(&customer.ID, &customer.UserID, &customer.Name, &customer.Email, &customer.Phone,
+		&customer.Address, &customer.Notes, &archivedAt, &customer.Balance, &currency, &customer.CreatedAt,
+		&customer.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	customer.Balance = customer.Balance.WithCurrency(currency)
+
+	customer.ArchivedAt = nullTimePtr(archivedAt)
+	return &customer, nil
+}
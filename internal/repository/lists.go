@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/listquery"
+	"github.com/umutaraz/tradesman-app/internal/models"
+)
+
+// Liste sayfaları ve API uçlarında sıralanabilen ve süzülebilen alanlar. Kolonlar her iki
+// veritabanında da geçerli SQL olmalıdır.
+
+var CustomerList = listquery.Spec{
+	Fields: map[string]listquery.Field{
+		"name":       {Column: "customers.name", Kind: listquery.Text, Sort: true, Filter: true},
+		"email":      {Column: "customers.email", Kind: listquery.Text, Filter: true},
+		"phone":      {Column: "customers.phone", Kind: listquery.Text, Filter: true},
+		"balance":    {Column: database.CustomerBalanceExpr, Kind: listquery.Money, Sort: true, Filter: true},
+		"created_at": {Column: "customers.created_at", Kind: listquery.Date, Sort: true, Filter: true},
+		"updated_at": {Column: "customers.updated_at", Kind: listquery.Date, Sort: true, Filter: true},
+	},
+	DefaultSort:   "-created_at",
+	IDColumn:      "customers.id",
+	SearchKind:    models.SearchCustomer,
+	SearchColumns: []string{"customers.name", "customers.email", "customers.phone"},
+}
+
+var ProductList = listquery.Spec{
+	Fields: map[string]listquery.Field{
+		"name":           {Column: "products.name", Kind: listquery.Text, Sort: true, Filter: true},
+		"category":       {Column: "products.category", Kind: listquery.Text, Sort: true, Filter: true},
+		"price":          {Column: "products.price", Kind: listquery.Money, Sort: true, Filter: true},
+		"currency":       {Column: "products.currency", Kind: listquery.Text, Filter: true},
+		"stock_quantity": {Column: "products.stock_quantity", Kind: listquery.Number, Sort: true, Filter: true},
+		"unit":           {Column: "products.unit", Kind: listquery.Text, Filter: true},
+		"is_service":     {Column: "products.is_service", Kind: listquery.Bool, Filter: true},
+		"low_stock": {Column: "(NOT products.is_service AND products.stock_quantity <= " +
+			database.LowStockLevelExpr + ")", Kind: listquery.Bool, Filter: true},
+		"created_at": {Column: "products.created_at", Kind: listquery.Date, Sort: true, Filter: true},
+	},
+	DefaultSort:   "-created_at",
+	IDColumn:      "products.id",
+	SearchKind:    models.SearchProduct,
+	SearchColumns: []string{"products.name", "products.category", "products.description"},
+}
+
+var OrderList = listquery.Spec{
+	Fields: map[string]listquery.Field{
+		"order_number":  {Column: "o.order_number", Kind: listquery.Text, Sort: true, Filter: true},
+		"status":        {Column: "o.status", Kind: listquery.Text, Sort: true, Filter: true},
+		"customer_id":   {Column: "o.customer_id", Kind: listquery.Number, Filter: true},
+		"customer":      {Column: "c.name", Kind: listquery.Text, Sort: true},
+		"total_amount":  {Column: "o.total_amount", Kind: listquery.Money, Sort: true, Filter: true},
+		"currency":      {Column: "o.currency", Kind: listquery.Text, Filter: true},
+		"on_credit":     {Column: "o.on_credit", Kind: listquery.Bool, Filter: true},
+		"order_date":    {Column: "o.order_date", Kind: listquery.Date, Sort: true, Filter: true},
+		"delivery_date": {Column: "o.delivery_date", Kind: listquery.Date, Sort: true, Filter: true},
+		"created_at":    {Column: "o.created_at", Kind: listquery.Date, Sort: true, Filter: true},
+	},
+	DefaultSort:   "-created_at",
+	IDColumn:      "o.id",
+	SearchKind:    models.SearchOrder,
+	SearchColumns: []string{"o.order_number", "o.notes", "c.name"},
+}
+
+var TransactionList = listquery.Spec{
+	Fields: map[string]listquery.Field{
+		"type":             {Column: "type", Kind: listquery.Text, Sort: true, Filter: true},
+		"category":         {Column: "category", Kind: listquery.Text, Sort: true, Filter: true},
+		"amount":           {Column: "amount", Kind: listquery.Money, Sort: true, Filter: true},
+		"currency":         {Column: "currency", Kind: listquery.Text, Filter: true},
+		"order_id":         {Column: "order_id", Kind: listquery.Number, Filter: true},
+		"transaction_date": {Column: "transaction_date", Kind: listquery.Date, Sort: true, Filter: true},
+		"created_at":       {Column: "created_at", Kind: listquery.Date, Sort: true, Filter: true},
+	},
+	DefaultSort: "-transaction_date",
+	IDColumn:    "id",
+}
@@ -0,0 +1,1112 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/listquery"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/money"
+)
+
+// Bellek içi depolar veritabanı gerektirmeden handler testlerinde kullanılır. Stok, gelir kaydı,
+// veresiye borcu ve durum geçmişi SQL depolarındaki kurallarla güncellenir; yazan her yöntem hata
+// döndürürse yaptığı değişiklikler geri alınır. Listelerde arama ve sayfalama desteklenir, süzgeçler
+// desteklenmez ve kayıtlar sort parametresinden bağımsız olarak yeniden eskiye döner. Faturalar,
+// randevular ve kullanıcılar depolarda olmadığından silme kontrollerinde yalnızca siparişlere bakılır.
+
+// memoryData bellek içi depoların verisi
+type memoryData struct {
+	lastID       int
+	customers    map[int]models.Customer
+	products     map[int]models.Product
+	orders       map[int]models.Order // Kalemleri ve durum geçmişiyle birlikte
+	transactions map[int]models.Transaction
+	debts        map[int]money.Money // Veresiye siparişlerin cari hesap borcu, sipariş ID'sine göre
+	sequences    map[[2]int]int      // İşletme ve yıla göre son sipariş numarası
+}
+
+type memory struct {
+	mu sync.Mutex
+	memoryData
+}
+
+type memoryCustomerRepo struct{ *memory }
+
+type memoryProductRepo struct{ *memory }
+
+type memoryOrderRepo struct{ *memory }
+
+type memoryTransactionRepo struct{ *memory }
+
+// NewMemory boş bellek içi depolar oluşturur
+func NewMemory() Store {
+	m := &memory{memoryData: memoryData{
+		customers:    map[int]models.Customer{},
+		products:     map[int]models.Product{},
+		orders:       map[int]models.Order{},
+		transactions: map[int]models.Transaction{},
+		debts:        map[int]money.Money{},
+		sequences:    map[[2]int]int{},
+	}}
+	return Store{
+		Customers:    &memoryCustomerRepo{m},
+		Products:     &memoryProductRepo{m},
+		Orders:       &memoryOrderRepo{m},
+		Transactions: &memoryTransactionRepo{m},
+	}
+}
+
+// withTx fn'i kilit altında çalıştırır; fn hata döndürürse veriler önceki haline döner
+func (m *memory) withTx(fn func() error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	saved := m.memoryData.clone()
+	if err := fn(); err != nil {
+		m.memoryData = saved
+		return err
+	}
+	return nil
+}
+
+func (d memoryData) clone() memoryData {
+	c := memoryData{
+		lastID:       d.lastID,
+		customers:    make(map[int]models.Customer, len(d.customers)),
+		products:     make(map[int]models.Product, len(d.products)),
+		orders:       make(map[int]models.Order, len(d.orders)),
+		transactions: make(map[int]models.Transaction, len(d.transactions)),
+		debts:        make(map[int]money.Money, len(d.debts)),
+		sequences:    make(map[[2]int]int, len(d.sequences)),
+	}
+	for id, customer := range d.customers {
+		c.customers[id] = customer
+	}
+	for id, product := range d.products {
+		c.products[id] = product
+	}
+	for id, order := range d.orders {
+		order.Items = append([]models.OrderItem(nil), order.Items...)
+		order.History = append([]models.OrderStatusChange(nil), order.History...)
+		c.orders[id] = order
+	}
+	for id, transaction := range d.transactions {
+		c.transactions[id] = transaction
+	}
+	for id, debt := range d.debts {
+		c.debts[id] = debt
+	}
+	for key, last := range d.sequences {
+		c.sequences[key] = last
+	}
+	return c
+}
+
+func (m *memory) nextID() int {
+	m.lastID++
+	return m.lastID
+}
+
+// memoryPage toplam kayıt sayısına göre istenen sayfanın [from, to) aralığını döndürür
+func memoryPage(q *listquery.Query, total int) (from, to int, page listquery.Page, err error) {
+	if where, _ := q.Where(); where != "" {
+		return 0, 0, page, ValidationError("Bellek içi depolar liste süzgeçlerini desteklemez")
+	}
+	if q.HasCursor() {
+		return 0, 0, page, ValidationError("Bellek içi depolar imleçle sayfalamayı desteklemez")
+	}
+
+	from, to = q.Offset(), q.Offset()+q.Limit()
+	if from > total {
+		from = total
+	}
+	if to > total {
+		to = total
+	}
+	return from, to, q.Page(total), nil
+}
+
+// memoryMatch aranan her kelime alanlardan birinde geçiyorsa true döner
+func memoryMatch(text string, fields ...string) bool {
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		found := false
+		for _, field := range fields {
+			if strings.Contains(strings.ToLower(field), word) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// newestFirst ID'leri yeniden eskiye sıralar
+func newestFirst(ids []int) []int {
+	sort.Sort(sort.Reverse(sort.IntSlice(ids)))
+	return ids
+}
+
+// Müşteriler
+
+// customer müşteriyi cari hesap bakiyesiyle döndürür
+func (m *memory) customer(id int) models.Customer {
+	customer := m.customers[id]
+
+	var balance int64
+	for orderID, debt := range m.debts {
+		if m.orders[orderID].CustomerID == id {
+			balance += debt.Minor()
+		}
+	}
+	customer.Balance = money.New(balance, "")
+	return customer
+}
+
+func (r *memoryCustomerRepo) List(userID int, archived bool, q *listquery.Query) ([]models.Customer, listquery.Page, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var ids []int
+	for id, customer := range r.customers {
+		if customer.UserID != userID || customer.IsArchived() != archived {
+			continue
+		}
+		if !memoryMatch(q.Search(), customer.Name, customer.Email, customer.Phone) {
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	from, to, page, err := memoryPage(q, len(ids))
+	if err != nil {
+		return nil, page, err
+	}
+	customers := []models.Customer{}
+	for _, id := range newestFirst(ids)[from:to] {
+		customers = append(customers, r.customer(id))
+	}
+	return customers, page, nil
+}
+
+func (r *memoryCustomerRepo) Active(userID int) ([]models.Customer, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var ids []int
+	for id, customer := range r.customers {
+		if customer.UserID == userID && !customer.IsArchived() {
+			ids = append(ids, id)
+		}
+	}
+	customers := []models.Customer{}
+	for _, id := range newestFirst(ids) {
+		customers = append(customers, r.customer(id))
+	}
+	return customers, nil
+}
+
+func (r *memoryCustomerRepo) Get(userID, id int) (*models.Customer, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if customer, ok := r.customers[id]; !ok || customer.UserID != userID {
+		return nil, ErrNotFound
+	}
+	customer := r.customer(id)
+	return &customer, nil
+}
+
+func (r *memoryCustomerRepo) Create(customer *models.Customer) (int, error) {
+	var id int
+	err := r.withTx(func() error {
+		now := time.Now()
+		created := *customer
+		created.ID, created.ArchivedAt, created.CreatedAt, created.UpdatedAt = r.nextID(), nil, now, now
+		r.customers[created.ID] = created
+		id = created.ID
+		return nil
+	})
+	return id, err
+}
+
+func (r *memoryCustomerRepo) Update(userID, id int, customer *models.Customer) error {
+	return r.withTx(func() error {
+		existing, ok := r.customers[id]
+		if !ok || existing.UserID != userID {
+			return ErrNotFound
+		}
+		existing.Name, existing.Email, existing.Phone = customer.Name, customer.Email, customer.Phone
+		existing.Address, existing.Notes, existing.UpdatedAt = customer.Address, customer.Notes, time.Now()
+		r.customers[id] = existing
+		return nil
+	})
+}
+
+func (r *memoryCustomerRepo) HasHistory(userID, id int) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, order := range r.orders {
+		if order.CustomerID == id {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryCustomerRepo) Archive(userID, id int) error {
+	return r.withTx(func() error {
+		customer, ok := r.customers[id]
+		if ok && customer.UserID == userID && !customer.IsArchived() {
+			now := time.Now()
+			customer.ArchivedAt, customer.UpdatedAt = &now, now
+			r.customers[id] = customer
+		}
+		return nil
+	})
+}
+
+func (r *memoryCustomerRepo) Restore(userID, id int) error {
+	return r.withTx(func() error {
+		customer, ok := r.customers[id]
+		if !ok || customer.UserID != userID {
+			return ErrNotFound
+		}
+		if !customer.IsArchived() {
+			return ConflictError("Müşteri arşivde değil")
+		}
+		customer.ArchivedAt, customer.UpdatedAt = nil, time.Now()
+		r.customers[id] = customer
+		return nil
+	})
+}
+
+func (r *memoryCustomerRepo) Delete(userID, id int) error {
+	return r.withTx(func() error {
+		if customer, ok := r.customers[id]; ok && customer.UserID == userID {
+			delete(r.customers, id)
+		}
+		return nil
+	})
+}
+
+func (r *memoryCustomerRepo) Count(userID int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := 0
+	for _, customer := range r.customers {
+		if customer.UserID == userID && !customer.IsArchived() {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// Ürünler
+
+// product ürünü geçerli düşük stok eşiğiyle döndürür; işletme ayarları depoda olmadığından ürüne
+// özel eşik yoksa varsayılan eşik kullanılır
+func (m *memory) product(id int) models.Product {
+	product := m.products[id]
+	product.LowStockLevel = database.DefaultLowStockLevel
+	if product.ReorderLevel != nil {
+		level := *product.ReorderLevel
+		product.ReorderLevel = &level
+		product.LowStockLevel = level
+	}
+	product.Price = product.Price.WithCurrency(product.Currency)
+	return product
+}
+
+// setProduct ürünün kaydedilen alanlarını günceller
+func setProduct(dst *models.Product, src *models.Product) {
+	dst.Name, dst.Description, dst.Category = src.Name, src.Description, src.Category
+	dst.Currency = currencyOf(src.Currency)
+	dst.Price = src.Price.WithCurrency(dst.Currency)
+	dst.StockQuantity, dst.Unit, dst.IsService = src.StockQuantity, src.Unit, src.IsService
+	dst.ReorderLevel, dst.ReorderQuantity = nil, src.ReorderQuantity
+	if src.ReorderLevel != nil {
+		level := *src.ReorderLevel
+		dst.ReorderLevel = &level
+	}
+}
+
+func (r *memoryProductRepo) List(userID int, q *listquery.Query) ([]models.Product, listquery.Page, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var ids []int
+	for id, product := range r.products {
+		if product.UserID == userID && memoryMatch(q.Search(), product.Name, product.Category, product.Description) {
+			ids = append(ids, id)
+		}
+	}
+
+	from, to, page, err := memoryPage(q, len(ids))
+	if err != nil {
+		return nil, page, err
+	}
+	products := []models.Product{}
+	for _, id := range newestFirst(ids)[from:to] {
+		products = append(products, r.product(id))
+	}
+	return products, page, nil
+}
+
+func (r *memoryProductRepo) All(userID int) ([]models.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.userProducts(userID), nil
+}
+
+// userProducts işletmenin ürünlerini yeniden eskiye döndürür
+func (r *memoryProductRepo) userProducts(userID int) []models.Product {
+	var ids []int
+	for id, product := range r.products {
+		if product.UserID == userID {
+			ids = append(ids, id)
+		}
+	}
+	products := []models.Product{}
+	for _, id := range newestFirst(ids) {
+		products = append(products, r.product(id))
+	}
+	return products
+}
+
+func (r *memoryProductRepo) Get(userID, id int) (*models.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if product, ok := r.products[id]; !ok || product.UserID != userID {
+		return nil, ErrNotFound
+	}
+	product := r.product(id)
+	return &product, nil
+}
+
+func (r *memoryProductRepo) Create(product *models.Product) (int, error) {
+	var id int
+	err := r.withTx(func() error {
+		now := time.Now()
+		created := models.Product{ID: r.nextID(), UserID: product.UserID, CreatedAt: now, UpdatedAt: now}
+		setProduct(&created, product)
+		r.products[created.ID] = created
+		id = created.ID
+		return nil
+	})
+	return id, err
+}
+
+func (r *memoryProductRepo) Update(userID, id int, product *models.Product) error {
+	return r.withTx(func() error {
+		existing, ok := r.products[id]
+		if !ok || existing.UserID != userID {
+			return ErrNotFound
+		}
+		setProduct(&existing, product)
+		existing.UpdatedAt = time.Now()
+		r.products[id] = existing
+		return nil
+	})
+}
+
+func (r *memoryProductRepo) Delete(userID, id int) error {
+	return r.withTx(func() error {
+		if product, ok := r.products[id]; !ok || product.UserID != userID {
+			return ErrNotFound
+		}
+		for _, order := range r.orders {
+			for _, item := range order.Items {
+				if item.ProductID == id {
+					return ConflictError("Siparişlerde kullanılan ürün silinemez")
+				}
+			}
+		}
+		delete(r.products, id)
+		return nil
+	})
+}
+
+func (r *memoryProductRepo) LowStock(userID, limit int) ([]models.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	products := []models.Product{}
+	for _, product := range r.userProducts(userID) {
+		if product.IsLowStock() {
+			products = append(products, product)
+		}
+	}
+	sort.SliceStable(products, func(i, j int) bool {
+		a, b := products[i], products[j]
+		if da, db := a.StockQuantity-a.LowStockLevel, b.StockQuantity-b.LowStockLevel; da != db {
+			return da < db
+		}
+		return a.Name < b.Name
+	})
+	if limit > 0 && len(products) > limit {
+		products = products[:limit]
+	}
+	return products, nil
+}
+
+func (r *memoryProductRepo) TopSelling(userID int, since time.Time, limit int) ([]models.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	quantities, amounts := map[int]int{}, map[int]int64{}
+	for _, order := range r.orders {
+		if order.UserID != userID || order.OrderDate.Before(since) || models.IsOrderReversed(order.Status) {
+			continue
+		}
+		for _, item := range order.Items {
+			quantities[item.ProductID] += item.Quantity
+			if r.products[item.ProductID].Currency == order.Currency {
+				amounts[item.ProductID] += item.TotalPrice.Minor()
+			}
+		}
+	}
+
+	products := []models.Product{}
+	for id, quantity := range quantities {
+		if _, ok := r.products[id]; !ok {
+			continue
+		}
+		product := r.product(id)
+		product.SoldQuantity = quantity
+		product.SoldAmount = money.New(amounts[id], product.Currency)
+		products = append(products, product)
+	}
+	sort.Slice(products, func(i, j int) bool {
+		a, b := products[i], products[j]
+		if a.SoldQuantity != b.SoldQuantity {
+			return a.SoldQuantity > b.SoldQuantity
+		}
+		if c := a.SoldAmount.Minor() - b.SoldAmount.Minor(); c != 0 {
+			return c > 0
+		}
+		return a.ID < b.ID
+	})
+	if len(products) > limit {
+		products = products[:limit]
+	}
+	return products, nil
+}
+
+func (r *memoryProductRepo) Count(userID int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := 0
+	for _, product := range r.products {
+		if product.UserID == userID {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// Siparişler
+
+// order siparişi müşterisiyle döndürür; detail ise kalemler ürün adıyla ve durum geçmişi eklenir
+func (m *memory) order(id int, detail bool) models.Order {
+	order := m.orders[id]
+	customer := m.customers[order.CustomerID]
+	order.TotalAmount = order.TotalAmount.WithCurrency(order.Currency)
+
+	if !detail {
+		order.Customer = &models.Customer{Name: customer.Name}
+		order.Items, order.History = nil, nil
+		return order
+	}
+
+	order.Customer = &models.Customer{ID: customer.ID, Name: customer.Name, Email: customer.Email, Phone: customer.Phone}
+	items := []models.OrderItem{}
+	for _, item := range order.Items {
+		product := m.products[item.ProductID]
+		item.Product = &models.Product{ID: item.ProductID, Name: product.Name, Unit: product.Unit}
+		items = append(items, item)
+	}
+	order.Items = items
+	order.History = append([]models.OrderStatusChange(nil), order.History...)
+	return order
+}
+
+func (r *memoryOrderRepo) List(userID int, q *listquery.Query) ([]models.Order, listquery.Page, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var ids []int
+	for id, order := range r.orders {
+		if order.UserID != userID {
+			continue
+		}
+		if !memoryMatch(q.Search(), order.OrderNumber, order.Notes, r.customers[order.CustomerID].Name) {
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	from, to, page, err := memoryPage(q, len(ids))
+	if err != nil {
+		return nil, page, err
+	}
+	orders := []models.Order{}
+	for _, id := range newestFirst(ids)[from:to] {
+		orders = append(orders, r.order(id, false))
+	}
+	return orders, page, nil
+}
+
+func (r *memoryOrderRepo) ListByCustomer(userID, customerID int) ([]models.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var ids []int
+	for id, order := range r.orders {
+		if order.UserID == userID && order.CustomerID == customerID {
+			ids = append(ids, id)
+		}
+	}
+	orders := []models.Order{}
+	for _, id := range newestFirst(ids) {
+		orders = append(orders, r.order(id, false))
+	}
+	return orders, nil
+}
+
+func (r *memoryOrderRepo) Get(userID, id int) (*models.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if order, ok := r.orders[id]; !ok || order.UserID != userID {
+		return nil, ErrNotFound
+	}
+	order := r.order(id, true)
+	return &order, nil
+}
+
+func (r *memoryOrderRepo) Create(order *models.Order, changedBy int) (int, error) {
+	var id int
+	err := r.withTx(func() error {
+		if customer, ok := r.customers[order.CustomerID]; !ok || customer.UserID != order.UserID {
+			return ValidationError("Müşteri bulunamadı")
+		}
+
+		key := [2]int{order.UserID, order.OrderDate.Year()}
+		r.sequences[key]++
+
+		now := time.Now()
+		id = r.nextID()
+		r.orders[id] = models.Order{
+			ID:           id,
+			UserID:       order.UserID,
+			CustomerID:   order.CustomerID,
+			OrderNumber:  fmt.Sprintf("SIP-%d-%03d", key[1], r.sequences[key]),
+			Status:       order.Status,
+			Currency:     currencyOf(order.Currency),
+			Notes:        order.Notes,
+			OrderDate:    order.OrderDate,
+			DeliveryDate: order.DeliveryDate,
+			OnCredit:     order.OnCredit,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		}
+
+		for i := range order.Items {
+			if err := r.insertItem(order.UserID, id, &order.Items[i]); err != nil {
+				return err
+			}
+		}
+
+		r.recalculateTotal(id)
+		r.syncIncome(id)
+		r.recordStatus(id, "", order.Status, changedBy, "")
+		return nil
+	})
+	return id, err
+}
+
+func (r *memoryOrderRepo) Update(userID, id int, order *models.Order, changedBy int) error {
+	return r.withTx(func() error {
+		existing, ok := r.orders[id]
+		if !ok || existing.UserID != userID {
+			return ErrNotFound
+		}
+		if customer, ok := r.customers[order.CustomerID]; !ok || customer.UserID != userID {
+			return ValidationError("Müşteri bulunamadı")
+		}
+
+		existing.CustomerID, existing.Notes = order.CustomerID, order.Notes
+		existing.OrderDate, existing.DeliveryDate, existing.UpdatedAt = order.OrderDate, order.DeliveryDate, time.Now()
+		r.orders[id] = existing
+
+		if order.Status == existing.Status {
+			r.syncLedger(id)
+			return nil
+		}
+		return r.changeStatus(id, order.Status, changedBy, "")
+	})
+}
+
+func (r *memoryOrderRepo) ChangeStatus(userID, id int, status string, changedBy int, note string) error {
+	return r.withTx(func() error {
+		existing, ok := r.orders[id]
+		if !ok || existing.UserID != userID {
+			return ErrNotFound
+		}
+		if existing.Status == status {
+			return ConflictError("Sipariş zaten bu durumda: " + status)
+		}
+		return r.changeStatus(id, status, changedBy, note)
+	})
+}
+
+func (r *memoryOrderRepo) Delete(userID, id int) error {
+	return r.withTx(func() error {
+		existing, ok := r.orders[id]
+		if !ok || existing.UserID != userID {
+			return ErrNotFound
+		}
+
+		// İptal ya da iade edilmemiş siparişin stokları geri eklenir
+		if !models.IsOrderReversed(existing.Status) {
+			for _, item := range existing.Items {
+				r.restoreStock(item.ProductID, item.Quantity)
+			}
+		}
+
+		r.deleteIncome(id)
+		delete(r.debts, id)
+		delete(r.orders, id)
+		return nil
+	})
+}
+
+func (r *memoryOrderRepo) AddItem(userID, orderID int, item *models.OrderItem) error {
+	return r.withTx(func() error {
+		if err := r.checkEditable(userID, orderID); err != nil {
+			return err
+		}
+		if err := r.insertItem(userID, orderID, item); err != nil {
+			return err
+		}
+		r.recalculateTotal(orderID)
+		r.syncIncome(orderID)
+		return nil
+	})
+}
+
+func (r *memoryOrderRepo) UpdateItem(userID, orderID int, item *models.OrderItem) error {
+	return r.withTx(func() error {
+		if err := r.checkEditable(userID, orderID); err != nil {
+			return err
+		}
+		order := r.orders[orderID]
+		i := itemIndex(order.Items, item.ID)
+		if i < 0 {
+			return ErrNotFound
+		}
+
+		// Eski kalemin stoğunu iade edip yenisini düş
+		r.restoreStock(order.Items[i].ProductID, order.Items[i].Quantity)
+		unitPrice, err := r.productPrice(userID, orderID, item.ProductID)
+		if err != nil {
+			return err
+		}
+		if err := r.decrementStock(item.ProductID, item.Quantity); err != nil {
+			return err
+		}
+
+		item.OrderID = orderID
+		item.UnitPrice = unitPrice
+		item.TotalPrice = unitPrice.Times(item.Quantity)
+
+		order = r.orders[orderID]
+		order.Items[i] = models.OrderItem{ID: item.ID, OrderID: orderID, ProductID: item.ProductID,
+			Quantity: item.Quantity, UnitPrice: item.UnitPrice, TotalPrice: item.TotalPrice}
+		r.orders[orderID] = order
+
+		r.recalculateTotal(orderID)
+		r.syncIncome(orderID)
+		return nil
+	})
+}
+
+func (r *memoryOrderRepo) DeleteItem(userID, orderID, itemID int) error {
+	return r.withTx(func() error {
+		if err := r.checkEditable(userID, orderID); err != nil {
+			return err
+		}
+		order := r.orders[orderID]
+		i := itemIndex(order.Items, itemID)
+		if i < 0 {
+			return ErrNotFound
+		}
+
+		r.restoreStock(order.Items[i].ProductID, order.Items[i].Quantity)
+		order.Items = append(order.Items[:i:i], order.Items[i+1:]...)
+		r.orders[orderID] = order
+
+		r.recalculateTotal(orderID)
+		r.syncIncome(orderID)
+		return nil
+	})
+}
+
+func (r *memoryOrderRepo) Count(userID int) (int, error) {
+	return r.countOrders(userID, func(models.Order) bool { return true }), nil
+}
+
+func (r *memoryOrderRepo) CountByStatus(userID int, status string) (int, error) {
+	return r.countOrders(userID, func(order models.Order) bool { return order.Status == status }), nil
+}
+
+func (r *memoryOrderRepo) countOrders(userID int, match func(models.Order) bool) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := 0
+	for _, order := range r.orders {
+		if order.UserID == userID && match(order) {
+			n++
+		}
+	}
+	return n
+}
+
+// Aşağıdaki yardımcılar order_tx.go'dakilerin karşılığıdır ve withTx içinde çağrılır
+
+// checkEditable kalemleri değiştirilebilecek (iptal ya da iade edilmemiş) siparişi doğrular
+func (r *memoryOrderRepo) checkEditable(userID, id int) error {
+	order, ok := r.orders[id]
+	if !ok || order.UserID != userID {
+		return ErrNotFound
+	}
+	if models.IsOrderReversed(order.Status) {
+		return ConflictError("İptal ya da iade edilen siparişin kalemleri değiştirilemez")
+	}
+	return nil
+}
+
+func itemIndex(items []models.OrderItem, id int) int {
+	for i, item := range items {
+		if item.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// insertItem kalemi ekler; birim fiyat ürünün güncel fiyatından alınır ve stok düşülür
+func (r *memoryOrderRepo) insertItem(userID, orderID int, item *models.OrderItem) error {
+	unitPrice, err := r.productPrice(userID, orderID, item.ProductID)
+	if err != nil {
+		return err
+	}
+	if err := r.decrementStock(item.ProductID, item.Quantity); err != nil {
+		return err
+	}
+
+	item.ID = r.nextID()
+	item.OrderID = orderID
+	item.UnitPrice = unitPrice
+	item.TotalPrice = unitPrice.Times(item.Quantity)
+
+	order := r.orders[orderID]
+	order.Items = append(order.Items, models.OrderItem{ID: item.ID, OrderID: orderID, ProductID: item.ProductID,
+		Quantity: item.Quantity, UnitPrice: item.UnitPrice, TotalPrice: item.TotalPrice})
+	r.orders[orderID] = order
+	return nil
+}
+
+// productPrice ürünün işletmeye ait olduğunu ve fiyatının siparişin para biriminde olduğunu
+// doğrular, güncel fiyatını döndürür
+func (r *memoryOrderRepo) productPrice(userID, orderID, productID int) (money.Money, error) {
+	product, ok := r.products[productID]
+	if !ok || product.UserID != userID {
+		return money.Money{}, ValidationError(fmt.Sprintf("Ürün bulunamadı: %d", productID))
+	}
+	orderCurrency := r.orders[orderID].Currency
+	if product.Currency != orderCurrency {
+		return money.Money{}, ValidationError(fmt.Sprintf("%s ürününün fiyatı %s, sipariş ise %s cinsinden",
+			product.Name, product.Currency, orderCurrency))
+	}
+	return product.Price.WithCurrency(product.Currency), nil
+}
+
+// decrementStock yeterli stok varsa ürün stoğunu düşer; hizmetlerde stok takibi yapılmaz
+func (r *memoryOrderRepo) decrementStock(productID, quantity int) error {
+	product := r.products[productID]
+	if product.IsService {
+		return nil
+	}
+	if product.StockQuantity < quantity {
+		return ValidationError(fmt.Sprintf("Yetersiz stok: %s (mevcut: %d, istenen: %d)", product.Name,
+			product.StockQuantity, quantity))
+	}
+	product.StockQuantity -= quantity
+	product.UpdatedAt = time.Now()
+	r.products[productID] = product
+	return nil
+}
+
+// restoreStock iptal edilen ya da silinen kalemin stoğunu geri ekler
+func (r *memoryOrderRepo) restoreStock(productID, quantity int) {
+	product, ok := r.products[productID]
+	if !ok || product.IsService {
+		return
+	}
+	product.StockQuantity += quantity
+	product.UpdatedAt = time.Now()
+	r.products[productID] = product
+}
+
+// recalculateTotal sipariş toplamını kalemlerden yeniden hesaplar
+func (r *memoryOrderRepo) recalculateTotal(orderID int) {
+	order := r.orders[orderID]
+	var total int64
+	for _, item := range order.Items {
+		total += item.TotalPrice.Minor()
+	}
+	order.TotalAmount = money.New(total, order.Currency)
+	order.UpdatedAt = time.Now()
+	r.orders[orderID] = order
+}
+
+// syncIncome siparişin gelir kaydını durumuna göre oluşturur, günceller ya da siler;
+// veresiye siparişlerin cari hesap borcu da birlikte güncellenir
+func (r *memoryOrderRepo) syncIncome(orderID int) {
+	r.syncLedger(orderID)
+
+	order := r.orders[orderID]
+	if !models.IsOrderSettled(order.Status) {
+		r.deleteIncome(orderID)
+		return
+	}
+
+	for id, transaction := range r.transactions {
+		if transaction.OrderID != nil && *transaction.OrderID == orderID && transaction.Type == "income" {
+			transaction.Amount, transaction.Currency = order.TotalAmount, order.Currency
+			r.transactions[id] = transaction
+			return
+		}
+	}
+
+	now := time.Now()
+	id, ref := r.nextID(), orderID
+	r.transactions[id] = models.Transaction{
+		ID:              id,
+		UserID:          order.UserID,
+		Type:            "income",
+		Category:        "Satış",
+		Amount:          order.TotalAmount,
+		Currency:        order.Currency,
+		Description:     "Sipariş " + order.OrderNumber,
+		TransactionDate: now,
+		OrderID:         &ref,
+		CreatedAt:       now,
+	}
+}
+
+// deleteIncome siparişten oluşan gelir kayıtlarını siler
+func (r *memoryOrderRepo) deleteIncome(orderID int) {
+	for id, transaction := range r.transactions {
+		if transaction.OrderID != nil && *transaction.OrderID == orderID {
+			delete(r.transactions, id)
+		}
+	}
+}
+
+// syncLedger veresiye siparişin borcunu durumuna göre yazar ya da kaldırır
+func (r *memoryOrderRepo) syncLedger(orderID int) {
+	order := r.orders[orderID]
+	if !order.OnCredit || !models.IsOrderSettled(order.Status) || !order.TotalAmount.IsPositive() {
+		delete(r.debts, orderID)
+		return
+	}
+	r.debts[orderID] = order.TotalAmount
+}
+
+// changeStatus durum geçişini doğrular, stok ve gelir kayıtlarını yeni duruma göre düzenler ve
+// geçişi durum geçmişine yazar
+func (r *memoryOrderRepo) changeStatus(orderID int, to string, changedBy int, note string) error {
+	order := r.orders[orderID]
+	from := order.Status
+
+	if !models.IsValidOrderStatus(to) {
+		return ValidationError(fmt.Sprintf("Geçersiz sipariş durumu: %s", to))
+	}
+	if !models.CanTransitionOrder(from, to) {
+		return ConflictError(fmt.Sprintf("Sipariş durumu %s → %s olarak değiştirilemez", from, to))
+	}
+
+	// İptal ve iadede stoklar geri eklenir
+	if models.IsOrderReversed(to) {
+		for _, item := range order.Items {
+			r.restoreStock(item.ProductID, item.Quantity)
+		}
+	}
+
+	order.Status, order.UpdatedAt = to, time.Now()
+	r.orders[orderID] = order
+
+	r.syncIncome(orderID)
+	r.recordStatus(orderID, from, to, changedBy, note)
+	return nil
+}
+
+// recordStatus durum geçmişine kayıt ekler; kullanıcılar depoda olmadığından ad boş kalır
+func (r *memoryOrderRepo) recordStatus(orderID int, from, to string, changedBy int, note string) {
+	order := r.orders[orderID]
+	order.History = append(order.History, models.OrderStatusChange{
+		ID:         r.nextID(),
+		OrderID:    orderID,
+		FromStatus: from,
+		ToStatus:   to,
+		ChangedBy:  changedBy,
+		Note:       note,
+		CreatedAt:  time.Now(),
+	})
+	r.orders[orderID] = order
+}
+
+// Gelir/gider kayıtları
+
+func (r *memoryTransactionRepo) List(userID int, q *listquery.Query) ([]models.Transaction, listquery.Page, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	transactions := []models.Transaction{}
+	for _, transaction := range r.transactions {
+		if transaction.UserID == userID {
+			transactions = append(transactions, transaction)
+		}
+	}
+	// Varsayılan sıralama gibi işlem tarihine göre yeniden eskiye
+	sort.Slice(transactions, func(i, j int) bool {
+		a, b := transactions[i], transactions[j]
+		if !a.TransactionDate.Equal(b.TransactionDate) {
+			return a.TransactionDate.After(b.TransactionDate)
+		}
+		return a.ID > b.ID
+	})
+
+	from, to, page, err := memoryPage(q, len(transactions))
+	if err != nil {
+		return nil, page, err
+	}
+	return transactions[from:to], page, nil
+}
+
+func (r *memoryTransactionRepo) Get(userID, id int) (*models.Transaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	transaction, ok := r.transactions[id]
+	if !ok || transaction.UserID != userID {
+		return nil, ErrNotFound
+	}
+	return &transaction, nil
+}
+
+func (r *memoryTransactionRepo) Create(transaction *models.Transaction) (int, error) {
+	var id int
+	err := r.withTx(func() error {
+		id = r.nextID()
+		currency := currencyOf(transaction.Currency)
+		r.transactions[id] = models.Transaction{
+			ID:              id,
+			UserID:          transaction.UserID,
+			Type:            transaction.Type,
+			Category:        transaction.Category,
+			Amount:          transaction.Amount.WithCurrency(currency),
+			Currency:        currency,
+			Description:     transaction.Description,
+			TransactionDate: transaction.TransactionDate,
+			CreatedAt:       time.Now(),
+		}
+		return nil
+	})
+	return id, err
+}
+
+func (r *memoryTransactionRepo) Update(userID, id int, transaction *models.Transaction) error {
+	return r.withTx(func() error {
+		existing, ok := r.transactions[id]
+		if !ok || existing.UserID != userID {
+			return ErrNotFound
+		}
+		existing.Type, existing.Category, existing.Currency = transaction.Type, transaction.Category,
+			currencyOf(transaction.Currency)
+		existing.Amount = transaction.Amount.WithCurrency(existing.Currency)
+		existing.Description, existing.TransactionDate = transaction.Description, transaction.TransactionDate
+		r.transactions[id] = existing
+		return nil
+	})
+}
+
+func (r *memoryTransactionRepo) Delete(userID, id int) error {
+	return r.withTx(func() error {
+		if transaction, ok := r.transactions[id]; !ok || transaction.UserID != userID {
+			return ErrNotFound
+		}
+		delete(r.transactions, id)
+		return nil
+	})
+}
+
+func (r *memoryTransactionRepo) Totals(userID int, from, to time.Time) ([]models.TransactionTotal, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	type key struct {
+		day      string
+		kind     string
+		currency money.Currency
+	}
+	sums := map[key]int64{}
+	for _, transaction := range r.transactions {
+		date := transaction.TransactionDate
+		if transaction.UserID != userID || date.Before(from) || !date.Before(to) {
+			continue
+		}
+		sums[key{date.Local().Format("2006-01-02"), transaction.Type, transaction.Currency}] += transaction.Amount.Minor()
+	}
+
+	keys := make([]key, 0, len(sums))
+	for k := range sums {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.day != b.day {
+			return a.day < b.day
+		}
+		if a.kind != b.kind {
+			return a.kind < b.kind
+		}
+		return a.currency < b.currency
+	})
+
+	totals := []models.TransactionTotal{}
+	for _, k := range keys {
+		date, err := time.ParseInLocation("2006-01-02", k.day, time.Local)
+		if err != nil {
+			return nil, err
+		}
+		totals = append(totals, models.TransactionTotal{Type: k.kind, Date: date, Amount: money.New(sums[k], k.currency)})
+	}
+	return totals, nil
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/database/dbtest"
+	"github.com/umutaraz/tradesman-app/internal/models"
+)
+
+func testCustomer(t *testing.T, db *database.DB, userID int) int {
+	t.Helper()
+
+	result, err := db.Exec("INSERT INTO customers (user_id, name) VALUES (?, ?)", userID, "Müşteri")
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return int(id)
+}
+
+func TestOrderNumbersAreConcurrentSafe(t *testing.T) {
+	db := dbtest.New(t)
+	store := NewSQLite(db.DB)
+	userID := dbtest.User(t, db, "esnaf@example.com")
+	customerID := testCustomer(t, db, userID)
+
+	const n = 12
+	date := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	numbers := make([]string, n)
+	errs := make([]error, n)
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			order := &models.Order{UserID: userID, CustomerID: customerID, Status: "pending", OrderDate: date}
+			id, err := store.Orders.Create(order, userID)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			errs[i] = db.QueryRow("SELECT order_number FROM orders WHERE id = ?", id).Scan(&numbers[i])
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			t.Fatalf("sipariş oluşturulamadı: %v", err)
+		}
+	}
+
+	sort.Strings(numbers)
+	for i, number := range numbers {
+		want := fmt.Sprintf("SIP-2024-%03d", i+1)
+		if number != want {
+			t.Fatalf("numbers[%d] = %s, want %s", i, number, want)
+		}
+	}
+}
+
+func TestOrderNumbersArePerBusiness(t *testing.T) {
+	db := dbtest.New(t)
+	store := NewSQLite(db.DB)
+
+	date := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	for _, email := range []string{"bir@example.com", "iki@example.com"} {
+		userID := dbtest.User(t, db, email)
+		customerID := testCustomer(t, db, userID)
+
+		for _, want := range []string{"SIP-2024-001", "SIP-2024-002"} {
+			order := &models.Order{UserID: userID, CustomerID: customerID, Status: "pending", OrderDate: date}
+			id, err := store.Orders.Create(order, userID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var number string
+			if err := db.QueryRow("SELECT order_number FROM orders WHERE id = ?", id).Scan(&number); err != nil {
+				t.Fatal(err)
+			}
+			if number != want {
+				t.Errorf("%s: order number = %s, want %s", email, number, want)
+			}
+		}
+	}
+
+	// Yeni yılda sayaç baştan başlar
+	userID := dbtest.User(t, db, "uc@example.com")
+	customerID := testCustomer(t, db, userID)
+	order := &models.Order{UserID: userID, CustomerID: customerID, Status: "pending", OrderDate: date.AddDate(1, 0, 0)}
+	id, err := store.Orders.Create(order, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var number string
+	if err := db.QueryRow("SELECT order_number FROM orders WHERE id = ?", id).Scan(&number); err != nil {
+		t.Fatal(err)
+	}
+	if number != "SIP-2025-001" {
+		t.Errorf("order number = %s, want SIP-2025-001", number)
+	}
+}
@@ -0,0 +1,292 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/money"
+)
+
+// Sipariş, kalem, stok, gelir ve veresiye borç kayıtlarını birlikte tutarlı tutan yardımcılar.
+// Hepsi çağıranın açtığı veritabanı işlemi (tx) içinde çalışır.
+
+// insertOrderItem kalemi ekler; birim fiyat ürünün güncel fiyatından alınır ve stok düşülür
+func insertOrderItem(tx conn, userID, orderID int, item *models.OrderItem) error {
+	unitPrice, err := productPrice(tx, userID, orderID, item.ProductID)
+	if err != nil {
+		return err
+	}
+
+	if err := decrementStock(tx, item.ProductID, item.Quantity); err != nil {
+		return err
+	}
+
+	item.OrderID = orderID
+	item.UnitPrice = unitPrice
+	item.TotalPrice = unitPrice.Times(item.Quantity)
+
+	id, err := tx.insert(`
+		INSERT INTO order_items (order_id, product_id, quantity, unit_price, total_price)
+		VALUES (?, ?, ?, ?, ?)
+	`, orderID, item.ProductID, item.Quantity, item.UnitPrice, item.TotalPrice)
+	if err != nil {
+		return err
+	}
+	item.ID = id
+
+	return nil
+}
+
+// productPrice ürünün işletmeye ait olduğunu ve fiyatının siparişin para biriminde olduğunu
+// doğrular, güncel fiyatını döndürür
+func productPrice(tx conn, userID, orderID, productID int) (money.Money, error) {
+	var name string
+	var price money.Money
+	var currency, orderCurrency money.Currency
+	err := tx.queryRow(`
+		SELECT p.name, p.price, p.currency, o.currency
+		FROM products p, orders o
+		WHERE p.id = ? AND p.user_id = ? AND o.id = ?
+	`, productID, userID, orderID).Scan(&name, &price, &currency, &orderCurrency)
+	if err == sql.ErrNoRows {
+		return price, ValidationError(fmt.Sprintf("Ürün bulunamadı: %d", productID))
+	}
+	if err != nil {
+		return price, err
+	}
+	if currency != orderCurrency {
+		return price, ValidationError(fmt.Sprintf("%s ürününün fiyatı %s, sipariş ise %s cinsinden", name, currency,
+			orderCurrency))
+	}
+	return price.WithCurrency(currency), nil
+}
+
+// decrementStock yeterli stok varsa ürün stoğunu düşer; hizmetlerde stok takibi yapılmaz
+func decrementStock(tx conn, productID, quantity int) error {
+	result, err := tx.exec(`
+		UPDATE products SET stock_quantity = stock_quantity - ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND NOT is_service AND stock_quantity >= ?
+	`, quantity, productID, quantity)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n > 0 {
+		return nil
+	}
+
+	var name string
+	var stock int
+	var isService bool
+	err = tx.queryRow("SELECT name, stock_quantity, is_service FROM products WHERE id = ?", productID).
+		Scan(&name, &stock, &isService)
+	if err != nil {
+		return err
+	}
+	if isService {
+		return nil
+	}
+
+	return ValidationError(fmt.Sprintf("Yetersiz stok: %s (mevcut: %d, istenen: %d)", name, stock, quantity))
+}
+
+// restoreStock iptal edilen ya da silinen kalemin stoğunu geri ekler
+func restoreStock(tx conn, productID, quantity int) error {
+	_, err := tx.exec(`
+		UPDATE products SET stock_quantity = stock_quantity + ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND NOT is_service
+	`, quantity, productID)
+	return err
+}
+
+// recalculateOrderTotal sipariş toplamını kalemlerden yeniden hesaplar
+func recalculateOrderTotal(tx conn, orderID int) error {
+	_, err := tx.exec(`
+		UPDATE orders SET total_amount = (
+			SELECT COALESCE(SUM(total_price), 0) FROM order_items WHERE order_id = ?
+		), updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`, orderID, orderID)
+	return err
+}
+
+// syncOrderIncome siparişin gelir kaydını durumuna göre oluşturur, günceller ya da siler;
+// veresiye siparişlerin cari hesap borcu da birlikte güncellenir
+func syncOrderIncome(tx conn, orderID int) error {
+	if err := syncOrderLedger(tx, orderID); err != nil {
+		return err
+	}
+
+	var userID int
+	var status, orderNumber string
+	var total money.Money
+	var currency money.Currency
+	err := tx.queryRow("SELECT user_id, status, order_number, total_amount, currency FROM orders WHERE id = ?", orderID).
+		Scan(&userID, &status, &orderNumber, &total, &currency)
+	if err != nil {
+		return err
+	}
+
+	if !models.IsOrderSettled(status) {
+		_, err := tx.exec("DELETE FROM transactions WHERE order_id = ?", orderID)
+		return err
+	}
+
+	result, err := tx.exec("UPDATE transactions SET amount = ?, currency = ? WHERE order_id = ? AND type = 'income'",
+		total, currency, orderID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n > 0 {
+		return nil
+	}
+
+	_, err = tx.exec(`
+		INSERT INTO transactions (user_id, type, category, amount, currency, description, transaction_date, order_id)
+		VALUES (?, 'income', 'Satış', ?, ?, ?, ?, ?)
+	`, userID, total, currency, "Sipariş "+orderNumber, time.Now(), orderID)
+	return err
+}
+
+// syncOrderLedger veresiye siparişin borç kaydını durumuna göre oluşturur, günceller ya da siler.
+// Sipariş teslim edilip tamamlandığında borç yazılır; iptal ya da iadede kayıt kaldırılır.
+func syncOrderLedger(tx conn, orderID int) error {
+	var userID, customerID int
+	var status, orderNumber string
+	var total money.Money
+	var onCredit bool
+	err := tx.queryRow(`
+		SELECT user_id, customer_id, status, order_number, total_amount, on_credit
+		FROM orders WHERE id = ?
+	`, orderID).Scan(&userID, &customerID, &status, &orderNumber, &total, &onCredit)
+	if err != nil {
+		return err
+	}
+
+	if !onCredit || !models.IsOrderSettled(status) || !total.IsPositive() {
+		_, err := tx.exec("DELETE FROM ledger_entries WHERE order_id = ? AND source = ?", orderID, models.LedgerSourceOrder)
+		return err
+	}
+
+	result, err := tx.exec(`
+		UPDATE ledger_entries SET customer_id = ?, amount = ? WHERE order_id = ? AND source = ?
+	`, customerID, total, orderID, models.LedgerSourceOrder)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n > 0 {
+		return nil
+	}
+
+	now := time.Now()
+	_, err = tx.exec(`
+		INSERT INTO ledger_entries (user_id, customer_id, entry_type, source, amount, description, entry_date,
+		                            order_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, userID, customerID, models.LedgerDebit, models.LedgerSourceOrder, total,
+		"Veresiye sipariş "+orderNumber, now, orderID, now)
+	return err
+}
+
+// changeOrderStatus durum geçişini doğrular, stok ve gelir kayıtlarını yeni duruma göre
+// düzenler ve geçişi durum geçmişine yazar
+func changeOrderStatus(tx conn, orderID int, to string, changedBy int, note string) error {
+	var from string
+	if err := tx.queryRow("SELECT status FROM orders WHERE id = ?", orderID).Scan(&from); err != nil {
+		return err
+	}
+
+	if !models.IsValidOrderStatus(to) {
+		return ValidationError(fmt.Sprintf("Geçersiz sipariş durumu: %s", to))
+	}
+	if !models.CanTransitionOrder(from, to) {
+		return ConflictError(fmt.Sprintf("Sipariş durumu %s → %s olarak değiştirilemez", from, to))
+	}
+
+	// İptal ve iadede stoklar geri eklenir
+	if models.IsOrderReversed(to) {
+		if err := restoreOrderStock(tx, orderID); err != nil {
+			return err
+		}
+	}
+
+	_, err := tx.exec("UPDATE orders SET status = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?", to, orderID)
+	if err != nil {
+		return err
+	}
+
+	if err := syncOrderIncome(tx, orderID); err != nil {
+		return err
+	}
+
+	return recordOrderStatus(tx, orderID, from, to, changedBy, note)
+}
+
+// recordOrderStatus durum geçmişine kayıt ekler
+func recordOrderStatus(tx conn, orderID int, from, to string, changedBy int, note string) error {
+	_, err := tx.exec(`
+		INSERT INTO order_status_history (order_id, from_status, to_status, changed_by, note, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, orderID, from, to, changedBy, note, time.Now())
+	return err
+}
+
+// restoreOrderStock siparişteki tüm kalemlerin stoğunu geri ekler
+func restoreOrderStock(tx conn, orderID int) error {
+	rows, err := tx.query("SELECT product_id, quantity FROM order_items WHERE order_id = ?", orderID)
+	if err != nil {
+		return err
+	}
+
+	type line struct{ productID, quantity int }
+	var lines []line
+	for rows.Next() {
+		var l line
+		if err := rows.Scan(&l.productID, &l.quantity); err != nil {
+			rows.Close()
+			return err
+		}
+		lines = append(lines, l)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, l := range lines {
+		if err := restoreStock(tx, l.productID, l.quantity); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// nextOrderNumber işletmenin o yılki sıradaki sipariş numarasını üretir (SIP-2024-001). Sayaç okunmadan
+// önce artırıldığından aynı anda açılan siparişler aynı numarayı alamaz.
+func nextOrderNumber(tx conn, userID int, date time.Time) (string, error) {
+	year := date.Year()
+	_, err := tx.exec(`
+		INSERT INTO order_sequences (user_id, year, last_number) VALUES (?, ?, 0)
+		ON CONFLICT (user_id, year) DO NOTHING
+	`, userID, year)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = tx.exec("UPDATE order_sequences SET last_number = last_number + 1 WHERE user_id = ? AND year = ?",
+		userID, year)
+	if err != nil {
+		return "", err
+	}
+
+	var next int
+	err = tx.queryRow("SELECT last_number FROM order_sequences WHERE user_id = ? AND year = ?", userID, year).
+		Scan(&next)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("SIP-%d-%03d", year, next), nil
+}
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/database/dbtest"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/money"
+)
+
+// orderTxFixture bir işletme için müşteri, stoklu ürün, hizmet ve boş sipariş oluşturur
+type orderTxFixture struct {
+	db                           *database.DB
+	store                        *store
+	userID, orderID              int
+	productID, serviceID, lampID int
+}
+
+func newOrderTxFixture(t *testing.T) orderTxFixture {
+	t.Helper()
+	db := dbtest.New(t)
+	f := orderTxFixture{db: db, store: &store{db: db.DB}, userID: dbtest.User(t, db, "sahip@example.com")}
+
+	insert := func(query string, args ...interface{}) int {
+		result, err := db.Exec(query, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		id, _ := result.LastInsertId()
+		return int(id)
+	}
+	customerID := insert("INSERT INTO customers (user_id, name) VALUES (?, 'Ayşe Demir')", f.userID)
+	f.productID = insert("INSERT INTO products (user_id, name, price, stock_quantity) VALUES (?, 'Priz', 4000, 5)", f.userID)
+	f.lampID = insert("INSERT INTO products (user_id, name, price, stock_quantity) VALUES (?, 'LED Ampul', 4550, 10)", f.userID)
+	f.serviceID = insert("INSERT INTO products (user_id, name, price, stock_quantity, is_service) VALUES (?, 'Montaj', 15000, 0, 1)",
+		f.userID)
+	f.orderID = insert(`INSERT INTO orders (user_id, customer_id, order_number, status, total_amount)
+		VALUES (?, ?, 'SIP-2026-001', 'pending', 0)`, f.userID, customerID)
+	return f
+}
+
+// inTx fonksiyonu deponun işleminde çalıştırır
+func (f orderTxFixture) inTx(t *testing.T, fn func(tx conn) error) error {
+	t.Helper()
+	return f.store.withTx(fn)
+}
+
+func (f orderTxFixture) stock(t *testing.T, productID int) int {
+	t.Helper()
+	var stock int
+	if err := f.db.QueryRow("SELECT stock_quantity FROM products WHERE id = ?", productID).Scan(&stock); err != nil {
+		t.Fatal(err)
+	}
+	return stock
+}
+
+func (f orderTxFixture) income(t *testing.T) (count int, amount money.Money) {
+	t.Helper()
+	err := f.db.QueryRow("SELECT COUNT(*), COALESCE(SUM(amount), 0) FROM transactions WHERE order_id = ? AND type = 'income'",
+		f.orderID).Scan(&count, &amount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return count, amount
+}
+
+func TestInsertOrderItemChecksStock(t *testing.T) {
+	f := newOrderTxFixture(t)
+
+	items := []models.OrderItem{
+		{ProductID: f.productID, Quantity: 3},
+		{ProductID: f.lampID, Quantity: 4},
+		{ProductID: f.serviceID, Quantity: 2},
+	}
+	err := f.inTx(t, func(tx conn) error {
+		for i := range items {
+			if err := insertOrderItem(tx, f.userID, f.orderID, &items[i]); err != nil {
+				return err
+			}
+		}
+		return recalculateOrderTotal(tx, f.orderID)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if items[1].UnitPrice.Minor() != 4550 || items[1].TotalPrice.Minor() != 18200 {
+		t.Errorf("lamp line = %v x %v, want price from the product", items[1].UnitPrice, items[1].TotalPrice)
+	}
+	if got := f.stock(t, f.productID); got != 2 {
+		t.Errorf("stock = %d, want 2", got)
+	}
+	if got := f.stock(t, f.serviceID); got != 0 {
+		t.Errorf("service stock = %d, want untouched", got)
+	}
+	var total money.Money
+	if err := f.db.QueryRow("SELECT total_amount FROM orders WHERE id = ?", f.orderID).Scan(&total); err != nil {
+		t.Fatal(err)
+	}
+	if total.Minor() != 12000+18200+30000 {
+		t.Errorf("total = %v, want 602", total)
+	}
+
+	// Yetersiz stokta işlem geri alınır, daha önce düşülen stok da geri gelir
+	err = f.inTx(t, func(tx conn) error {
+		if err := insertOrderItem(tx, f.userID, f.orderID, &models.OrderItem{ProductID: f.lampID, Quantity: 1}); err != nil {
+			return err
+		}
+		return insertOrderItem(tx, f.userID, f.orderID, &models.OrderItem{ProductID: f.productID, Quantity: 3})
+	})
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("insufficient stock: err = %v, want validation error", err)
+	}
+	if got := f.stock(t, f.lampID); got != 6 {
+		t.Errorf("lamp stock = %d, want 6 after rollback", got)
+	}
+
+	// Başka işletmenin ürünü sipariş edilemez
+	otherID := dbtest.User(t, f.db, "diger@example.com")
+	err = f.inTx(t, func(tx conn) error {
+		return insertOrderItem(tx, otherID, f.orderID, &models.OrderItem{ProductID: f.productID, Quantity: 1})
+	})
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("foreign product: err = %v, want validation error", err)
+	}
+}
+
+func TestOrderIncomeFollowsStatus(t *testing.T) {
+	f := newOrderTxFixture(t)
+	err := f.inTx(t, func(tx conn) error {
+		if err := insertOrderItem(tx, f.userID, f.orderID, &models.OrderItem{ProductID: f.productID, Quantity: 2}); err != nil {
+			return err
+		}
+		return recalculateOrderTotal(tx, f.orderID)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		name   string
+		run    func(tx conn) error
+		count  int
+		amount int64 // kuruş
+		stock  int
+	}{
+		{"pending", func(tx conn) error { return syncOrderIncome(tx, f.orderID) }, 0, 0, 3},
+		{"completed", func(tx conn) error {
+			return changeOrderStatus(tx, f.orderID, models.OrderCompleted, f.userID, "")
+		}, 1, 8000, 3},
+		{"completed again", func(tx conn) error { return syncOrderIncome(tx, f.orderID) }, 1, 8000, 3},
+		{"returned", func(tx conn) error {
+			return changeOrderStatus(tx, f.orderID, models.OrderReturned, f.userID, "Kusurlu ürün")
+		}, 0, 0, 5},
+	}
+	for _, s := range steps {
+		if err := f.inTx(t, s.run); err != nil {
+			t.Fatalf("%s: %v", s.name, err)
+		}
+		if count, amount := f.income(t); count != s.count || amount.Minor() != s.amount {
+			t.Errorf("%s: income = %d rows, %d; want %d, %d", s.name, count, amount.Minor(), s.count, s.amount)
+		}
+		if got := f.stock(t, f.productID); got != s.stock {
+			t.Errorf("%s: stock = %d, want %d", s.name, got, s.stock)
+		}
+	}
+}
+
+func TestChangeOrderStatusRejectsInvalidTransitions(t *testing.T) {
+	f := newOrderTxFixture(t)
+
+	tests := []struct {
+		to   string
+		want error
+	}{
+		{"arsivlendi", ErrValidation},
+		{models.OrderShipped, ErrConflict},
+		{models.OrderCancelled, nil},
+		{models.OrderCompleted, ErrConflict},
+	}
+	for _, tt := range tests {
+		err := f.inTx(t, func(tx conn) error {
+			return changeOrderStatus(tx, f.orderID, tt.to, f.userID, "")
+		})
+		if !errors.Is(err, tt.want) {
+			t.Errorf("change to %s: err = %v, want %v", tt.to, err, tt.want)
+		}
+	}
+
+	var history int
+	if err := f.db.QueryRow("SELECT COUNT(*) FROM order_status_history WHERE order_id = ?", f.orderID).Scan(&history); err != nil {
+		t.Fatal(err)
+	}
+	if history != 1 {
+		t.Errorf("history rows = %d, want only the accepted transition", history)
+	}
+}
+
+func TestNextOrderNumber(t *testing.T) {
+	f := newOrderTxFixture(t)
+	// Sayaç order_sequences tablosundan okunur; fikstürdeki sipariş doğrudan eklendiği için sayılmaz
+	if _, err := f.db.Exec("INSERT INTO order_sequences (user_id, year, last_number) VALUES (?, 2026, 1)", f.userID); err != nil {
+		t.Fatal(err)
+	}
+	var number string
+	err := f.inTx(t, func(tx conn) (err error) {
+		number, err = nextOrderNumber(tx, f.userID, time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if number != "SIP-2026-002" {
+		t.Errorf("next number = %q, want SIP-2026-002", number)
+	}
+}
@@ -0,0 +1,391 @@
+package repository
+
+import (
+	"github.com/umutaraz/tradesman-app/internal/listquery"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/money"
+)
+
+type orderRepo struct {
+	*store
+}
+
+// orderColumns selectOrders sırasıyla sipariş kolonları; orders "o", customers "c" takma adıyla kullanılır
+const orderColumns = `o.id, o.user_id, o.customer_id, o.order_number, o.status, o.total_amount, o.currency, o.notes,
+	o.order_date, o.delivery_date, o.on_credit, o.created_at, o.updated_at, c.name as customer_name`
+
+// orderSource siparişleri müşterileriyle birleştiren FROM ve işletme koşulu
+const orderSource = " FROM orders o JOIN customers c ON o.customer_id = c.id WHERE o.user_id = ?"
+
+func (r *orderRepo) List(userID int, q *listquery.Query) ([]models.Order, listquery.Page, error) {
+	query, args, page, err := r.listPage(q, orderColumns, orderSource, userID)
+	if err != nil {
+		return nil, page, err
+	}
+	orders, err := r.selectOrders(query, args...)
+	return orders, page, err
+}
+
+func (r *orderRepo) ListByCustomer(userID, customerID int) ([]models.Order, error) {
+	return r.selectOrders("SELECT "+orderColumns+orderSource+" AND o.customer_id = ? ORDER BY o.created_at DESC",
+		userID, customerID)
+}
+
+func (r *orderRepo) Get(userID, id int) (*models.Order, error) {
+	order := models.Order{Customer: &models.Customer{}}
+	err := r.conn().queryRow(`
+		SELECT o.id, o.user_id, o.customer_id, o.order_number, o.status, o.total_amount, o.currency,
+		       o.notes, o.order_date, o.delivery_date, o.on_credit, o.created_at, o.updated_at,
+		       c.id, c.name, c.email, c.phone
+		FROM orders o
+		JOIN customers c ON o.customer_id = c.id
+		WHERE o.id = ? AND o.user_id = ?
+	`, id, userID).Scan(&order.ID, &order.UserID, &order.CustomerID, &order.OrderNumber,
+		&order.Status, &order.TotalAmount, &order.Currency, &order.Notes, &order.OrderDate,
+		&order.DeliveryDate, &order.OnCredit, &order.CreatedAt, &order.UpdatedAt,
+		&order.Customer.ID, &order.Customer.Name, &order.Customer.Email, &order.Customer.Phone)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	order.TotalAmount = order.TotalAmount.WithCurrency(order.Currency)
+
+	items, err := r.items(order.ID, order.Currency)
+	if err != nil {
+		return nil, err
+	}
+	order.Items = items
+
+	history, err := r.history(order.ID)
+	if err != nil {
+		return nil, err
+	}
+	order.History = history
+
+	return &order, nil
+}
+
+func (r *orderRepo) Create(order *models.Order, changedBy int) (int, error) {
+	if err := r.checkCustomer(order.UserID, order.CustomerID); err != nil {
+		return 0, err
+	}
+
+	var id int
+	err := r.withTx(func(tx conn) error {
+		orderNumber, err := nextOrderNumber(tx, order.UserID, order.OrderDate)
+		if err != nil {
+			return err
+		}
+
+		id, err = tx.insert(`
+			INSERT INTO orders (user_id, customer_id, order_number, status, total_amount, currency, notes, order_date,
+			                    delivery_date, on_credit)
+			VALUES (?, ?, ?, ?, 0, ?, ?, ?, ?, ?)
+		`, order.UserID, order.CustomerID, orderNumber, order.Status, currencyOf(order.Currency), order.Notes,
+			order.OrderDate, order.DeliveryDate, order.OnCredit)
+		if err != nil {
+			return err
+		}
+
+		// Kalemleri ekle, stoktan düş ve toplamı sunucuda hesapla
+		for i := range order.Items {
+			if err := insertOrderItem(tx, order.UserID, id, &order.Items[i]); err != nil {
+				return err
+			}
+		}
+
+		if err := recalculateOrderTotal(tx, id); err != nil {
+			return err
+		}
+
+		// Ödemesi alınmış siparişler için gelir kaydı oluştur
+		if err := syncOrderIncome(tx, id); err != nil {
+			return err
+		}
+
+		return recordOrderStatus(tx, id, "", order.Status, changedBy, "")
+	})
+	return id, err
+}
+
+func (r *orderRepo) Update(userID, id int, order *models.Order, changedBy int) error {
+	existing, err := r.Get(userID, id)
+	if err != nil {
+		return err
+	}
+	if err := r.checkCustomer(userID, order.CustomerID); err != nil {
+		return err
+	}
+
+	return r.withTx(func(tx conn) error {
+		_, err := tx.exec(`
+			UPDATE orders SET customer_id = ?, notes = ?, order_date = ?, delivery_date = ?,
+			       updated_at = CURRENT_TIMESTAMP
+			WHERE id = ? AND user_id = ?
+		`, order.CustomerID, order.Notes, order.OrderDate, order.DeliveryDate, id, userID)
+		if err != nil {
+			return err
+		}
+
+		if order.Status == existing.Status {
+			// Müşteri değiştiyse veresiye borcu yeni müşteriye taşınır
+			return syncOrderLedger(tx, id)
+		}
+		return changeOrderStatus(tx, id, order.Status, changedBy, "")
+	})
+}
+
+func (r *orderRepo) ChangeStatus(userID, id int, status string, changedBy int, note string) error {
+	existing, err := r.Get(userID, id)
+	if err != nil {
+		return err
+	}
+	if existing.Status == status {
+		return ConflictError("Sipariş zaten bu durumda: " + status)
+	}
+
+	return r.withTx(func(tx conn) error {
+		return changeOrderStatus(tx, id, status, changedBy, note)
+	})
+}
+
+func (r *orderRepo) Delete(userID, id int) error {
+	existing, err := r.Get(userID, id)
+	if err != nil {
+		return err
+	}
+
+	for _, table := range orderRefs {
+		n, err := r.conn().count("SELECT COUNT(*) FROM "+table+" WHERE order_id = ?", id)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return ConflictError("Faturası bulunan sipariş silinemez")
+		}
+	}
+
+	return r.withTx(func(tx conn) error {
+		// İptal ya da iade edilmemiş siparişin stokları geri eklenir
+		if !models.IsOrderReversed(existing.Status) {
+			if err := restoreOrderStock(tx, id); err != nil {
+				return err
+			}
+		}
+
+		for _, table := range []string{"transactions", "ledger_entries", "order_status_history", "order_items"} {
+			if _, err := tx.exec("DELETE FROM "+table+" WHERE order_id = ?", id); err != nil {
+				return err
+			}
+		}
+		_, err := tx.exec("DELETE FROM orders WHERE id = ? AND user_id = ?", id, userID)
+		return err
+	})
+}
+
+func (r *orderRepo) AddItem(userID, orderID int, item *models.OrderItem) error {
+	if err := r.checkEditable(userID, orderID); err != nil {
+		return err
+	}
+
+	return r.withTx(func(tx conn) error {
+		if err := insertOrderItem(tx, userID, orderID, item); err != nil {
+			return err
+		}
+		if err := recalculateOrderTotal(tx, orderID); err != nil {
+			return err
+		}
+		return syncOrderIncome(tx, orderID)
+	})
+}
+
+func (r *orderRepo) UpdateItem(userID, orderID int, item *models.OrderItem) error {
+	if err := r.checkEditable(userID, orderID); err != nil {
+		return err
+	}
+
+	return r.withTx(func(tx conn) error {
+		var oldProductID, oldQuantity int
+		err := tx.queryRow("SELECT product_id, quantity FROM order_items WHERE id = ? AND order_id = ?",
+			item.ID, orderID).Scan(&oldProductID, &oldQuantity)
+		if err != nil {
+			return notFound(err)
+		}
+
+		// Eski kalemin stoğunu iade edip yenisini düş
+		if err := restoreStock(tx, oldProductID, oldQuantity); err != nil {
+			return err
+		}
+
+		unitPrice, err := productPrice(tx, userID, orderID, item.ProductID)
+		if err != nil {
+			return err
+		}
+		if err := decrementStock(tx, item.ProductID, item.Quantity); err != nil {
+			return err
+		}
+
+		item.OrderID = orderID
+		item.UnitPrice = unitPrice
+		item.TotalPrice = unitPrice.Times(item.Quantity)
+
+		_, err = tx.exec(`
+			UPDATE order_items SET product_id = ?, quantity = ?, unit_price = ?, total_price = ?
+			WHERE id = ? AND order_id = ?
+		`, item.ProductID, item.Quantity, item.UnitPrice, item.TotalPrice, item.ID, orderID)
+		if err != nil {
+			return err
+		}
+
+		if err := recalculateOrderTotal(tx, orderID); err != nil {
+			return err
+		}
+		return syncOrderIncome(tx, orderID)
+	})
+}
+
+func (r *orderRepo) DeleteItem(userID, orderID, itemID int) error {
+	if err := r.checkEditable(userID, orderID); err != nil {
+		return err
+	}
+
+	return r.withTx(func(tx conn) error {
+		var productID, quantity int
+		err := tx.queryRow("SELECT product_id, quantity FROM order_items WHERE id = ? AND order_id = ?", itemID, orderID).
+			Scan(&productID, &quantity)
+		if err != nil {
+			return notFound(err)
+		}
+
+		if _, err := tx.exec("DELETE FROM order_items WHERE id = ?", itemID); err != nil {
+			return err
+		}
+		if err := restoreStock(tx, productID, quantity); err != nil {
+			return err
+		}
+		if err := recalculateOrderTotal(tx, orderID); err != nil {
+			return err
+		}
+		return syncOrderIncome(tx, orderID)
+	})
+}
+
+func (r *orderRepo) Count(userID int) (int, error) {
+	return r.conn().count("SELECT COUNT(*) FROM orders WHERE user_id = ?", userID)
+}
+
+func (r *orderRepo) CountByStatus(userID int, status string) (int, error) {
+	return r.conn().count("SELECT COUNT(*) FROM orders WHERE user_id = ? AND status = ?", userID, status)
+}
+
+// checkCustomer siparişin müşterisinin işletmeye ait olduğunu doğrular
+func (r *orderRepo) checkCustomer(userID, customerID int) error {
+	n, err := r.conn().count("SELECT COUNT(*) FROM customers WHERE id = ? AND user_id = ?", customerID, userID)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ValidationError("Müşteri bulunamadı")
+	}
+	return nil
+}
+
+// checkEditable kalemleri değiştirilebilecek (iptal ya da iade edilmemiş) siparişi doğrular
+func (r *orderRepo) checkEditable(userID, id int) error {
+	var status string
+	err := r.conn().queryRow("SELECT status FROM orders WHERE id = ? AND user_id = ?", id, userID).Scan(&status)
+	if err != nil {
+		return notFound(err)
+	}
+	if models.IsOrderReversed(status) {
+		return ConflictError("İptal ya da iade edilen siparişin kalemleri değiştirilemez")
+	}
+	return nil
+}
+
+// selectOrders orderColumns ile seçilen siparişleri döndürür
+func (r *orderRepo) selectOrders(query string, args ...interface{}) ([]models.Order, error) {
+	rows, err := r.conn().query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := []models.Order{}
+	for rows.Next() {
+		var order models.Order
+		var customerName string
+		err := rows.Scan(&order.ID, &order.UserID, &order.CustomerID, &order.OrderNumber,
+			&order.Status, &order.TotalAmount, &order.Currency, &order.Notes, &order.OrderDate,
+			&order.DeliveryDate, &order.OnCredit, &order.CreatedAt, &order.UpdatedAt, &customerName)
+		if err != nil {
+			return nil, err
+		}
+		order.TotalAmount = order.TotalAmount.WithCurrency(order.Currency)
+		order.Customer = &models.Customer{Name: customerName}
+		orders = append(orders, order)
+	}
+
+	return orders, rows.Err()
+}
+
+// items siparişin kalemlerini ürün adı ve birimiyle getirir; tutarlar siparişin para birimindedir
+func (r *orderRepo) items(orderID int, currency money.Currency) ([]models.OrderItem, error) {
+	rows, err := r.conn().query(`
+		SELECT oi.id, oi.order_id, oi.product_id, oi.quantity, oi.unit_price, oi.total_price,
+		       p.name as product_name, p.unit as product_unit
+		FROM order_items oi
+		JOIN products p ON oi.product_id = p.id
+		WHERE oi.order_id = ?
+		ORDER BY oi.id
+	`, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.OrderItem{}
+	for rows.Next() {
+		var item models.OrderItem
+		var productName, productUnit string
+		err := rows.Scan(&item.ID, &item.OrderID, &item.ProductID, &item.Quantity,
+			&item.UnitPrice, &item.TotalPrice, &productName, &productUnit)
+		if err != nil {
+			return nil, err
+		}
+		item.UnitPrice = item.UnitPrice.WithCurrency(currency)
+		item.TotalPrice = item.TotalPrice.WithCurrency(currency)
+		item.Product = &models.Product{ID: item.ProductID, Name: productName, Unit: productUnit}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// history siparişin durum geçmişini eskiden yeniye getirir
+func (r *orderRepo) history(orderID int) ([]models.OrderStatusChange, error) {
+	rows, err := r.conn().query(`
+		SELECT sh.id, sh.order_id, COALESCE(sh.from_status, ''), sh.to_status, COALESCE(sh.changed_by, 0),
+		       COALESCE(u.name, ''), COALESCE(sh.note, ''), sh.created_at
+		FROM order_status_history sh
+		LEFT JOIN users u ON sh.changed_by = u.id
+		WHERE sh.order_id = ?
+		ORDER BY sh.created_at, sh.id
+	`, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []models.OrderStatusChange
+	for rows.Next() {
+		var change models.OrderStatusChange
+		err := rows.Scan(&change.ID, &change.OrderID, &change.FromStatus, &change.ToStatus,
+			&change.ChangedBy, &change.ChangedByName, &change.Note, &change.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, change)
+	}
+
+	return history, rows.Err()
+}
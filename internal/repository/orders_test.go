@@ -0,0 +1,200 @@
+package repository_test
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/database/dbtest"
+	"github.com/umutaraz/tradesman-app/internal/listquery"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/money"
+	"github.com/umutaraz/tradesman-app/internal/repository"
+)
+
+func TestOrderRepoLifecycle(t *testing.T) {
+	db := dbtest.New(t)
+	store := repository.NewSQLite(db.DB)
+	userID := dbtest.User(t, db, "sahip@example.com")
+	otherID := dbtest.User(t, db, "diger@example.com")
+
+	customerID, err := store.Customers.Create(&models.Customer{UserID: userID, Name: "Ayşe Demir"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	productID, err := store.Products.Create(&models.Product{UserID: userID, Name: "LED Ampul", Price: money.TL(4550),
+		StockQuantity: 10, Unit: "adet"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Başka işletmenin müşterisine sipariş açılamaz
+	_, err = store.Orders.Create(&models.Order{UserID: otherID, CustomerID: customerID, Status: models.OrderPending,
+		OrderDate: time.Now()}, otherID)
+	if !errors.Is(err, repository.ErrValidation) {
+		t.Errorf("order for a foreign customer: err = %v, want validation error", err)
+	}
+
+	orderID, err := store.Orders.Create(&models.Order{UserID: userID, CustomerID: customerID, Status: models.OrderPending,
+		OrderDate: time.Now(), Items: []models.OrderItem{{ProductID: productID, Quantity: 4}}}, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Orders.ChangeStatus(userID, orderID, models.OrderCompleted, userID, "Teslim edildi"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Orders.ChangeStatus(userID, orderID, models.OrderCompleted, userID, ""); !errors.Is(err, repository.ErrConflict) {
+		t.Errorf("same status: err = %v, want conflict", err)
+	}
+
+	order, err := store.Orders.Get(userID, orderID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.TotalAmount.Minor() != 18200 || len(order.Items) != 1 || len(order.History) != 2 || order.Customer.Name != "Ayşe Demir" {
+		t.Errorf("order = %+v", order)
+	}
+	if last := order.History[len(order.History)-1]; last.ChangedByName != "sahip@example.com" || last.Note != "Teslim edildi" {
+		t.Errorf("history = %+v, want the change with the user's name", last)
+	}
+	if _, err := store.Orders.Get(otherID, orderID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("other business: err = %v, want not found", err)
+	}
+
+	// Tamamlanan siparişin geliri yazılır; silinince stok ve gelir geri alınır
+	from := time.Now().AddDate(0, 0, -1)
+	totals, err := store.Transactions.Totals(userID, from, time.Now().AddDate(0, 0, 1))
+	if err != nil || len(totals) != 1 || totals[0].Type != "income" || totals[0].Amount.Minor() != 18200 {
+		t.Errorf("totals = %+v, %v; want 182 TL income", totals, err)
+	}
+	if err := store.Orders.Delete(userID, orderID); err != nil {
+		t.Fatal(err)
+	}
+	product, err := store.Products.Get(userID, productID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if product.StockQuantity != 10 {
+		t.Errorf("stock after delete = %d, want 10", product.StockQuantity)
+	}
+	q, err := listquery.Parse(url.Values{}, repository.TransactionList, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transactions, page, err := store.Transactions.List(userID, q); err != nil || len(transactions) != 0 || page.Total != 0 {
+		t.Errorf("transactions after delete = %d, %v", len(transactions), err)
+	}
+}
+
+func TestCustomerRepoArchive(t *testing.T) {
+	db := dbtest.New(t)
+	store := repository.NewSQLite(db.DB)
+	userID := dbtest.User(t, db, "sahip@example.com")
+
+	customerID, err := store.Customers.Create(&models.Customer{UserID: userID, Name: "Ayşe Demir"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Customers.Restore(userID, customerID); !errors.Is(err, repository.ErrConflict) {
+		t.Errorf("restore active customer: err = %v, want conflict", err)
+	}
+	if err := store.Customers.Archive(userID, customerID); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := store.Customers.Count(userID); err != nil || n != 0 {
+		t.Errorf("active count = %d, %v; want 0", n, err)
+	}
+	if err := store.Customers.Restore(userID, customerID); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Customers.Update(userID+1, customerID, &models.Customer{Name: "Başkası"}); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("update by another business: err = %v, want not found", err)
+	}
+}
+
+// Cari bakiye işletmenin ana para birimiyle okunur
+func TestCustomerRepoBalanceCurrency(t *testing.T) {
+	db := dbtest.New(t)
+	store := repository.NewSQLite(db.DB)
+	userID := dbtest.User(t, db, "sahip@example.com")
+	customerID, err := store.Customers.Create(&models.Customer{UserID: userID, Name: "Ayşe Demir"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`
+		INSERT INTO ledger_entries (user_id, customer_id, entry_type, source, amount, description, entry_date)
+		VALUES (?, ?, ?, ?, 15000, 'Müşteriye ödeme', CURRENT_TIMESTAMP)
+	`, userID, customerID, models.LedgerDebit, models.LedgerSourceRefund); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		setting  string
+		currency money.Currency
+	}{
+		{"default", "", money.TRY},
+		{"euro business", "EUR", money.EUR},
+	}
+	for _, tt := range tests {
+		if tt.setting != "" {
+			if err := db.SetSetting(userID, database.SettingCurrency, tt.setting); err != nil {
+				t.Fatal(err)
+			}
+		}
+		customer, err := store.Customers.Get(userID, customerID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if customer.Balance.Currency() != tt.currency || customer.Balance.Minor() != 15000 {
+			t.Errorf("%s: balance = %v %s, want 150,00 %s", tt.name, customer.Balance, customer.Balance.Currency(), tt.currency)
+		}
+	}
+}
+
+func TestCustomerRepoHistoryAcrossModules(t *testing.T) {
+	db := dbtest.New(t)
+	store := repository.NewSQLite(db.DB)
+	userID := dbtest.User(t, db, "sahip@example.com")
+
+	// Randevusu olan müşterinin geçmişi vardır; yalnızca etkinlik kaydı olan müşterinin yoktur
+	withAppointment, err := store.Customers.Create(&models.Customer{UserID: userID, Name: "Ayşe Demir"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec(`INSERT INTO appointments (user_id, customer_id, staff_id, title, start_time, end_time)
+		VALUES (?, ?, ?, 'Kombi bakımı', ?, ?)`, userID, withAppointment, userID, time.Now(), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	withActivity, err := store.Customers.Create(&models.Customer{UserID: userID, Name: "Mehmet Kaya"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec(`INSERT INTO customer_activities (user_id, customer_id, activity_type, description)
+		VALUES (?, ?, 'note', 'Aradı')`, userID, withActivity)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if has, err := store.Customers.HasHistory(userID, withAppointment); err != nil || !has {
+		t.Errorf("customer with an appointment: HasHistory = %v, %v", has, err)
+	}
+	if has, err := store.Customers.HasHistory(userID, withActivity); err != nil || has {
+		t.Errorf("customer with only activities: HasHistory = %v, %v", has, err)
+	}
+
+	// Müşteri silinirken etkinlik kayıtları da silinir
+	if err := store.Customers.Delete(userID, withActivity); err != nil {
+		t.Fatal(err)
+	}
+	var activities int
+	if err := db.QueryRow("SELECT COUNT(*) FROM customer_activities WHERE customer_id = ?", withActivity).Scan(&activities); err != nil {
+		t.Fatal(err)
+	}
+	if activities != 0 {
+		t.Errorf("activities after delete = %d, want 0", activities)
+	}
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/listquery"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/money"
+)
+
+type productRepo struct {
+	*store
+}
+
+// productColumns scanProduct sırasıyla ürün kolonları; geçerli düşük stok eşiği hesaplanarak döner
+var productColumns = `id, user_id, name, description, price, currency, category, stock_quantity, unit, is_service,
+	reorder_level, reorder_quantity, ` + database.LowStockLevelExpr + `, created_at, updated_at`
+
+func (r *productRepo) List(userID int, q *listquery.Query) ([]models.Product, listquery.Page, error) {
+	query, args, page, err := r.listPage(q, productColumns, " FROM products WHERE user_id = ?", userID)
+	if err != nil {
+		return nil, page, err
+	}
+	products, err := r.selectProducts(query, args...)
+	return products, page, err
+}
+
+func (r *productRepo) All(userID int) ([]models.Product, error) {
+	return r.selectProducts("SELECT "+productColumns+" FROM products WHERE user_id = ? ORDER BY created_at DESC", userID)
+}
+
+func (r *productRepo) Get(userID, id int) (*models.Product, error) {
+	product, err := scanProduct(r.conn().queryRow("SELECT "+productColumns+" FROM products WHERE id = ? AND user_id = ?",
+		id, userID))
+	return product, notFound(err)
+}
+
+func (r *productRepo) Create(product *models.Product) (int, error) {
+	return r.conn().insert(`
+		INSERT INTO products (user_id, name, description, price, currency, category, stock_quantity, unit, is_service,
+		                      reorder_level, reorder_quantity)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, product.UserID, product.Name, product.Description, product.Price, currencyOf(product.Currency), product.Category,
+		product.StockQuantity, product.Unit, product.IsService, product.ReorderLevel, product.ReorderQuantity)
+}
+
+func (r *productRepo) Update(userID, id int, product *models.Product) error {
+	result, err := r.conn().exec(`
+		UPDATE products SET name = ?, description = ?, price = ?, currency = ?, category = ?, stock_quantity = ?,
+		       unit = ?, is_service = ?, reorder_level = ?, reorder_quantity = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND user_id = ?
+	`, product.Name, product.Description, product.Price, currencyOf(product.Currency), product.Category,
+		product.StockQuantity, product.Unit, product.IsService, product.ReorderLevel, product.ReorderQuantity, id, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *productRepo) Delete(userID, id int) error {
+	if _, err := r.Get(userID, id); err != nil {
+		return err
+	}
+
+	itemCount, err := r.conn().count("SELECT COUNT(*) FROM order_items WHERE product_id = ?", id)
+	if err != nil {
+		return err
+	}
+	if itemCount > 0 {
+		return ConflictError("Siparişlerde kullanılan ürün silinemez")
+	}
+
+	_, err = r.conn().exec("DELETE FROM products WHERE id = ? AND user_id = ?", id, userID)
+	return err
+}
+
+func (r *productRepo) LowStock(userID, limit int) ([]models.Product, error) {
+	query := `
+		SELECT ` + productColumns + ` FROM products
+		WHERE user_id = ? AND NOT is_service AND stock_quantity <= ` + database.LowStockLevelExpr + `
+		ORDER BY stock_quantity - ` + database.LowStockLevelExpr + `, name`
+	args := []interface{}{userID}
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+	return r.selectProducts(query, args...)
+}
+
+func (r *productRepo) TopSelling(userID int, since time.Time, limit int) ([]models.Product, error) {
+	rows, err := r.conn().query(`
+		SELECT `+productColumns+`, sold.quantity, sold.amount
+		FROM products
+		JOIN (
+			SELECT oi.product_id, SUM(oi.quantity) AS quantity,
+			       SUM(CASE WHEN o.currency = p.currency THEN oi.total_price ELSE 0 END) AS amount
+			FROM order_items oi
+			JOIN orders o ON oi.order_id = o.id
+			JOIN products p ON p.id = oi.product_id
+			WHERE o.user_id = ? AND o.order_date >= ? AND o.status NOT IN (?, ?)
+			GROUP BY oi.product_id
+		) sold ON sold.product_id = products.id
+		ORDER BY sold.quantity DESC, sold.amount DESC
+		LIMIT ?
+	`, userID, since, models.OrderCancelled, models.OrderReturned, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := []models.Product{}
+	for rows.Next() {
+		var quantity int
+		var amount money.Money
+		product, err := scanProduct(rows, &quantity, &amount)
+		if err != nil {
+			return nil, err
+		}
+		product.SoldQuantity = quantity
+		product.SoldAmount = amount.WithCurrency(product.Currency)
+		products = append(products, *product)
+	}
+
+	return products, rows.Err()
+}
+
+func (r *productRepo) Count(userID int) (int, error) {
+	return r.conn().count("SELECT COUNT(*) FROM products WHERE user_id = ?", userID)
+}
+
+// selectProducts productColumns ile seçilen ürünleri döndürür
+func (r *productRepo) selectProducts(query string, args ...interface{}) ([]models.Product, error) {
+	rows, err := r.conn().query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := []models.Product{}
+	for rows.Next() {
+		product, err := scanProduct(rows)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, *product)
+	}
+
+	return products, rows.Err()
+}
+
+// scanProduct productColumns sırasıyla ürünü okur; extra sorguya eklenen sonraki kolonları alır
+func scanProduct(rs rowScanner, extra ...interface{}) (*models.Product, error) {
+	var product models.Product
+	var description, category sql.NullString
+	var reorderLevel sql.NullInt64
+	dest := []interface{}{&product.ID, &product.UserID, &product.Name, &description,
+		&product.Price, &product.Currency, &category, &product.StockQuantity, &product.Unit, &product.IsService,
+		&reorderLevel, &product.ReorderQuantity, &product.LowStockLevel,
+		&product.CreatedAt, &product.UpdatedAt}
+	err := rs.Scan(append(dest, extra...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	product.ReorderLevel = nullIntPtr(reorderLevel)
+	product.Price = product.Price.WithCurrency(product.Currency)
+
+	product.Description = description.String
+	product.Category = category.String
+
+	return &product, nil
+}
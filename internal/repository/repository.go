@@ -0,0 +1,125 @@
+// Package repository müşteri, ürün, sipariş ve gelir/gider kayıtlarına erişimi arayüzlerin
+// arkasına alır. Handler'lar yalnızca bu arayüzleri kullanır; uygulama SQLite depolarıyla çalışır,
+// testlerde bellek içi sahte depolar verilebilir.
+//
+// Tüm yöntemler işletme (userID) bazında çalışır; başka işletmenin kaydı bulunamadı sayılır.
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/listquery"
+	"github.com/umutaraz/tradesman-app/internal/models"
+)
+
+// Depo hataları; handler'lar bunları uygun HTTP koduna çevirir
+var (
+	ErrNotFound   = errors.New("kayıt bulunamadı")
+	ErrValidation = errors.New("geçersiz istek")
+	ErrConflict   = errors.New("kayıt mevcut durumla çelişiyor")
+)
+
+// Error kullanıcıya gösterilecek mesajı ve hata türünü (ErrValidation, ErrConflict) taşır
+type Error struct {
+	kind    error
+	message string
+}
+
+func (e *Error) Error() string { return e.message }
+
+func (e *Error) Unwrap() error { return e.kind }
+
+// ValidationError geçersiz girdi hatası oluşturur
+func ValidationError(message string) error {
+	return &Error{kind: ErrValidation, message: message}
+}
+
+// ConflictError kaydın mevcut durumuyla çelişen istek hatası oluşturur
+func ConflictError(message string) error {
+	return &Error{kind: ErrConflict, message: message}
+}
+
+// Store uygulamanın kullandığı depoları bir arada tutar
+type Store struct {
+	Customers    CustomerRepo
+	Products     ProductRepo
+	Orders       OrderRepo
+	Transactions TransactionRepo
+}
+
+// CustomerRepo müşteri kayıtları
+type CustomerRepo interface {
+	// List aktif ya da arşivlenmiş müşterilerin istenen sayfasını döndürür
+	List(userID int, archived bool, q *listquery.Query) ([]models.Customer, listquery.Page, error)
+	// Active arşivlenmemiş tüm müşterileri yeniden eskiye döndürür (seçim listeleri için)
+	Active(userID int) ([]models.Customer, error)
+	Get(userID, id int) (*models.Customer, error)
+	Create(customer *models.Customer) (int, error)
+	// Update iletişim bilgilerini günceller
+	Update(userID, id int, customer *models.Customer) error
+	// HasHistory müşterinin siparişi, randevusu, faturası ya da hesap hareketi varsa true döner
+	HasHistory(userID, id int) (bool, error)
+	Archive(userID, id int) error
+	// Restore arşivdeki müşteriyi geri alır; müşteri arşivde değilse ConflictError döner
+	Restore(userID, id int) error
+	Delete(userID, id int) error
+	// Count arşivlenmemiş müşteri sayısı
+	Count(userID int) (int, error)
+}
+
+// ProductRepo ürün ve hizmet kayıtları
+type ProductRepo interface {
+	List(userID int, q *listquery.Query) ([]models.Product, listquery.Page, error)
+	// All tüm ürünleri yeniden eskiye döndürür (seçim listeleri için)
+	All(userID int) ([]models.Product, error)
+	Get(userID, id int) (*models.Product, error)
+	Create(product *models.Product) (int, error)
+	Update(userID, id int, product *models.Product) error
+	// Delete ürünü siler; siparişlerde kullanılan ürün için ConflictError döner
+	Delete(userID, id int) error
+	// LowStock stoğu eşiğe inmiş ürünleri en kritik olandan başlayarak döndürür; limit 0 ise hepsi
+	LowStock(userID, limit int) ([]models.Product, error)
+	// TopSelling verilen tarihten bu yana satış miktarına göre en çok satan ürünleri döndürür
+	// (iptal ve iade edilen siparişler hariç). Satış tutarı ürünün para birimindedir; ürünün para birimi
+	// sonradan değiştiyse eski para birimindeki siparişler miktara katılır, tutara katılmaz.
+	TopSelling(userID int, since time.Time, limit int) ([]models.Product, error)
+	Count(userID int) (int, error)
+}
+
+// OrderRepo siparişler ve kalemleri. Yazan yöntemler stok, gelir kaydı, veresiye borcu ve
+// durum geçmişini siparişle aynı veritabanı işleminde günceller.
+type OrderRepo interface {
+	List(userID int, q *listquery.Query) ([]models.Order, listquery.Page, error)
+	// ListByCustomer müşterinin siparişlerini yeniden eskiye döndürür
+	ListByCustomer(userID, customerID int) ([]models.Order, error)
+	// Get siparişi müşterisi, kalemleri ve durum geçmişiyle döndürür
+	Get(userID, id int) (*models.Order, error)
+	// Create siparişi numaralandırıp kalemleriyle kaydeder; birim fiyatlar ürünlerin güncel
+	// fiyatından alınır ve stok düşülür
+	Create(order *models.Order, changedBy int) (int, error)
+	// Update müşteri, not ve tarihleri günceller; durum değiştiyse geçiş kurallarına uyulur
+	Update(userID, id int, order *models.Order, changedBy int) error
+	// ChangeStatus durum geçişini doğrular ve uygular
+	ChangeStatus(userID, id int, status string, changedBy int, note string) error
+	// Delete siparişi ve bağlı kayıtlarını siler; faturası olan sipariş için ConflictError döner
+	Delete(userID, id int) error
+	AddItem(userID, orderID int, item *models.OrderItem) error
+	// UpdateItem item.ID'deki kalemi günceller
+	UpdateItem(userID, orderID int, item *models.OrderItem) error
+	DeleteItem(userID, orderID, itemID int) error
+	Count(userID int) (int, error)
+	CountByStatus(userID int, status string) (int, error)
+}
+
+// TransactionRepo gelir/gider kayıtları
+type TransactionRepo interface {
+	List(userID int, q *listquery.Query) ([]models.Transaction, listquery.Page, error)
+	Get(userID, id int) (*models.Transaction, error)
+	Create(transaction *models.Transaction) (int, error)
+	Update(userID, id int, transaction *models.Transaction) error
+	Delete(userID, id int) error
+	// Totals [from, to) aralığındaki gelir ve giderleri gün, tür ve para birimine göre toplar;
+	// döviz tutarları işlem günündeki kurla çevrilebilsin diye günlük verilir
+	Totals(userID int, from, to time.Time) ([]models.TransactionTotal, error)
+}
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/listquery"
+	"github.com/umutaraz/tradesman-app/internal/money"
+)
+
+// querier *sql.DB ve *sql.Tx'in ortak sorgu yöntemleri
+type querier interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// rowScanner *sql.Row ve *sql.Rows için ortak arayüz
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// conn sorguları veritabanında ya da açık işlemde çalıştırır
+type conn struct {
+	q querier
+}
+
+func (c conn) exec(query string, args ...interface{}) (sql.Result, error) {
+	return c.q.Exec(query, args...)
+}
+
+func (c conn) query(query string, args ...interface{}) (*sql.Rows, error) {
+	return c.q.Query(query, args...)
+}
+
+func (c conn) queryRow(query string, args ...interface{}) *sql.Row {
+	return c.q.QueryRow(query, args...)
+}
+
+// insert INSERT ifadesini çalıştırır ve eklenen kaydın ID'sini döndürür
+func (c conn) insert(query string, args ...interface{}) (int, error) {
+	result, err := c.exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	id, err := result.LastInsertId()
+	return int(id), err
+}
+
+// count tek sayı döndüren sorguyu çalıştırır
+func (c conn) count(query string, args ...interface{}) (int, error) {
+	var n int
+	err := c.queryRow(query, args...).Scan(&n)
+	return n, err
+}
+
+// store depoların ortak veritabanı bağlantısı
+type store struct {
+	db *sql.DB
+}
+
+// newStore dört depoyu aynı bağlantıyla oluşturur
+func newStore(db *sql.DB) Store {
+	s := &store{db: db}
+	return Store{
+		Customers:    &customerRepo{s},
+		Products:     &productRepo{s},
+		Orders:       &orderRepo{s},
+		Transactions: &transactionRepo{s},
+	}
+}
+
+func (s *store) conn() conn {
+	return conn{q: s.db}
+}
+
+// withTx fonksiyonu tek bir veritabanı işlemi içinde çalıştırır
+func (s *store) withTx(fn func(tx conn) error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(conn{q: tx}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// listPage source ile başlayan sorgunun (FROM ... WHERE işletme koşulu) toplam kayıt sayısını
+// ve istenen sayfanın sorgusunu hazırlar; sayfa sorgusu selectColumns ile seçilir. Sonraki sayfa
+// varsa imleci için sayfanın son kaydının sıralama anahtarı ayrıca okunur.
+func (s *store) listPage(q *listquery.Query, selectColumns, source string, args ...interface{}) (string, []interface{}, listquery.Page, error) {
+	where, filterArgs := q.Where()
+	if where != "" {
+		source += " AND " + where
+	}
+	args = append(args, filterArgs...)
+
+	if text := q.Search(); text != "" {
+		cond, searchArgs := searchCondition(q.Spec(), text)
+		if cond != "" {
+			source += " AND " + cond
+			args = append(args, searchArgs...)
+		}
+	}
+
+	total, err := s.conn().count("SELECT COUNT(*)"+source, args...)
+	if err != nil {
+		return "", nil, listquery.Page{}, err
+	}
+
+	// İmleçle istenen sayfa önceki sayfanın son kaydından sonra başlar
+	if after, afterArgs := q.After(); after != "" {
+		source += " AND " + after
+		args = append(args, afterArgs...)
+	}
+
+	page := q.Page(total)
+	if page.NextCursor, err = s.nextCursor(q, source, args); err != nil {
+		return "", nil, page, err
+	}
+
+	query := "SELECT " + selectColumns + source + " ORDER BY " + q.OrderBy() + " LIMIT ? OFFSET ?"
+	return query, append(args, q.Limit(), q.Offset()), page, nil
+}
+
+// nextCursor sayfanın son kaydından sonra kayıt varsa sonraki sayfanın imlecini döndürür; imleç
+// son kaydın sıralama değeri ve ID'sidir
+func (s *store) nextCursor(q *listquery.Query, source string, args []interface{}) (string, error) {
+	rows, err := s.conn().query("SELECT "+q.KeyColumns()+source+" ORDER BY "+q.OrderBy()+" LIMIT 2 OFFSET ?",
+		append(args, q.Offset()+q.Limit()-1)...)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	var key interface{}
+	var id int64
+	n := 0
+	for ; rows.Next(); n++ {
+		if n == 0 {
+			if err := rows.Scan(&key, &id); err != nil {
+				return "", err
+			}
+		}
+	}
+	if err := rows.Err(); err != nil || n < 2 {
+		return "", err
+	}
+	return q.NextCursor(key, id), nil
+}
+
+// notFound sql.ErrNoRows'u ErrNotFound'a çevirir
+func notFound(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// nullTimePtr geçerli sql.NullTime değerini yerel saatte işaretçiye çevirir
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	local := t.Time.In(time.Local)
+	return &local
+}
+
+// nullIntPtr geçerli sql.NullInt64 değerini işaretçiye çevirir
+func nullIntPtr(i sql.NullInt64) *int {
+	if !i.Valid {
+		return nil
+	}
+	id := int(i.Int64)
+	return &id
+}
+
+// currencyOf kaydedilecek para birimini döndürür; belirtilmemişse varsayılan para birimidir
+func currencyOf(currency money.Currency) money.Currency {
+	if currency == "" {
+		return money.DefaultCurrency
+	}
+	return currency
+}
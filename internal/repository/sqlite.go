@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/listquery"
+)
+
+// Depolar uygulamanın SQLite veritabanını kullanır. Faturalar, randevular ve cari hesap aynı
+// veritabanında olduğundan müşteri ve sipariş silinirken bu tablolara da bakılır.
+var (
+	// customerRefs customer_id kolonu olan ve müşterinin silinmesini engelleyen tablolar
+	customerRefs = []string{"orders", "appointments", "invoices", "ledger_entries"}
+	// customerOwned müşteri silinirken birlikte silinen tablolar
+	customerOwned = []string{"customer_activities"}
+	// orderRefs order_id kolonu olan ve siparişin silinmesini engelleyen tablolar
+	orderRefs = []string{"invoices"}
+)
+
+// NewSQLite uygulamanın SQLite veritabanı üzerinde depoları oluşturur; şema database.Initialize
+// ile migration'lardan kurulur
+func NewSQLite(db *sql.DB) Store {
+	return newStore(db)
+}
+
+// dayOf zaman kolonunun takvim gününü YYYY-AA-GG metni olarak veren ifadeyi üretir. Zamanlar yerel
+// saatle "2006-01-02 15:04:05-07:00" biçiminde saklanır; date() UTC'ye çevireceğinden gün metnin
+// başından alınır.
+func dayOf(column string) string {
+	return "substr(" + column + ", 1, 10)"
+}
+
+// searchCondition listedeki q aramasını arama dizinindeki eşleşmelerle sınırlayan koşulu üretir
+func searchCondition(spec listquery.Spec, text string) (string, []interface{}) {
+	match := database.SearchMatch(text)
+	if match == "" || spec.SearchKind == "" {
+		return "", nil
+	}
+	return spec.IDColumn + " IN (" + database.SearchIDs + ")", []interface{}{match, spec.SearchKind}
+}
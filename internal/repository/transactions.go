@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/listquery"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/money"
+)
+
+type transactionRepo struct {
+	*store
+}
+
+const transactionColumns = "id, user_id, type, category, amount, currency, description, transaction_date, order_id, created_at"
+
+func (r *transactionRepo) List(userID int, q *listquery.Query) ([]models.Transaction, listquery.Page, error) {
+	query, args, page, err := r.listPage(q, transactionColumns, " FROM transactions WHERE user_id = ?", userID)
+	if err != nil {
+		return nil, page, err
+	}
+
+	rows, err := r.conn().query(query, args...)
+	if err != nil {
+		return nil, page, err
+	}
+	defer rows.Close()
+
+	transactions := []models.Transaction{}
+	for rows.Next() {
+		transaction, err := scanTransaction(rows)
+		if err != nil {
+			return nil, page, err
+		}
+		transactions = append(transactions, *transaction)
+	}
+
+	return transactions, page, rows.Err()
+}
+
+func (r *transactionRepo) Get(userID, id int) (*models.Transaction, error) {
+	transaction, err := scanTransaction(r.conn().queryRow("SELECT "+transactionColumns+
+		" FROM transactions WHERE id = ? AND user_id = ?", id, userID))
+	return transaction, notFound(err)
+}
+
+func (r *transactionRepo) Create(transaction *models.Transaction) (int, error) {
+	return r.conn().insert(`
+		INSERT INTO transactions (user_id, type, category, amount, currency, description, transaction_date)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, transaction.UserID, transaction.Type, transaction.Category, transaction.Amount,
+		currencyOf(transaction.Currency), transaction.Description, transaction.TransactionDate)
+}
+
+func (r *transactionRepo) Update(userID, id int, transaction *models.Transaction) error {
+	result, err := r.conn().exec(`
+		UPDATE transactions SET type = ?, category = ?, amount = ?, currency = ?, description = ?, transaction_date = ?
+		WHERE id = ? AND user_id = ?
+	`, transaction.Type, transaction.Category, transaction.Amount, currencyOf(transaction.Currency),
+		transaction.Description, transaction.TransactionDate, id, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *transactionRepo) Delete(userID, id int) error {
+	result, err := r.conn().exec("DELETE FROM transactions WHERE id = ? AND user_id = ?", id, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *transactionRepo) Totals(userID int, from, to time.Time) ([]models.TransactionTotal, error) {
+	day := dayOf("transaction_date")
+	rows, err := r.conn().query(`
+		SELECT type, currency, `+day+`, SUM(amount)
+		FROM transactions
+		WHERE user_id = ? AND transaction_date >= ? AND transaction_date < ?
+		GROUP BY type, currency, `+day+`
+		ORDER BY `+day+`, type, currency
+	`, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	totals := []models.TransactionTotal{}
+	for rows.Next() {
+		var total models.TransactionTotal
+		var currency money.Currency
+		var date string
+		if err := rows.Scan(&total.Type, &currency, &date, &total.Amount); err != nil {
+			return nil, err
+		}
+		if total.Date, err = time.ParseInLocation("2006-01-02", date, time.Local); err != nil {
+			return nil, err
+		}
+		total.Amount = total.Amount.WithCurrency(currency)
+		totals = append(totals, total)
+	}
+
+	return totals, rows.Err()
+}
+
+func scanTransaction(rs rowScanner) (*models.Transaction, error) {
+	var transaction models.Transaction
+	var description sql.NullString
+	var orderID sql.NullInt64
+	err := rs.Scan(&transaction.ID, &transaction.UserID, &transaction.Type,
+		&transaction.Category, &transaction.Amount, &transaction.Currency, &description,
+		&transaction.TransactionDate, &orderID, &transaction.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction.Amount = transaction.Amount.WithCurrency(transaction.Currency)
+	transaction.Description = description.String
+	transaction.OrderID = nullIntPtr(orderID)
+
+	return &transaction, nil
+}
@@ -2,36 +2,67 @@ package routes
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/umutaraz/tradesman-app/internal/database"
 	"github.com/umutaraz/tradesman-app/internal/handlers"
+	"github.com/umutaraz/tradesman-app/internal/middleware"
+	"github.com/umutaraz/tradesman-app/internal/permissions"
 )
 
-func Setup(r *gin.Engine, h *handlers.Handler) {
+func Setup(e *gin.Engine, h *handlers.Handler, db *database.DB) {
+	// Giriş / Çıkış
+	e.GET("/login", h.LoginPage)
+	e.POST("/login", h.Login)
+	e.GET("/logout", h.Logout)
+	e.POST("/logout", h.Logout)
+
+	// Bundan sonraki tüm route'lar oturum gerektirir
+	r := e.Group("/", middleware.Auth(db, h.IdleTimeout))
+
 	// Ana sayfa - Dashboard
-	r.GET("/", h.Dashboard)
-	r.GET("/dashboard", h.Dashboard)
+	dashboard := r.Group("", middleware.RequirePermission(permissions.ViewDashboard))
+	dashboard.GET("/", h.Dashboard)
+	dashboard.GET("/dashboard", h.Dashboard)
 
 	// Müşteriler
-	r.GET("/customers", h.Customers)
+	customers := r.Group("", middleware.RequirePermission(permissions.ManageCustomers))
+	customers.GET("/customers", h.Customers)
+	customers.GET("/customers/detail/:id", h.CustomerDetail)
+	customers.POST("/customers/add", h.AddCustomerForm)
+	customers.POST("/customers/update/:id", h.UpdateCustomerForm)
+	customers.POST("/customers/payment/:id", h.AddCustomerPaymentForm)
 
 	// Ürünler
-	r.GET("/products", h.Products)
-	r.GET("/products/detail/:id", h.ProductDetail)
+	products := r.Group("", middleware.RequirePermission(permissions.ViewProducts))
+	products.GET("/products", h.Products)
+	products.GET("/products/detail/:id", h.ProductDetail)
+	r.POST("/products/add", middleware.RequirePermission(permissions.ManageProducts), h.AddProductForm)
 
 	// Siparişler
-	r.GET("/orders", h.Orders)
-	r.GET("/orders/detail/:id", h.OrderDetail)
+	orders := r.Group("", middleware.RequirePermission(permissions.CreateOrders))
+	orders.GET("/orders", h.Orders)
+	orders.GET("/orders/detail/:id", h.OrderDetail)
+	orders.GET("/orders/detail/:id/receipt.pdf", h.OrderReceiptPDF)
 
 	// Muhasebe
-	r.GET("/accounting", h.Accounting)
+	accounting := r.Group("", middleware.RequirePermission(permissions.ViewAccounting))
+	accounting.GET("/accounting", h.Accounting)
 
 	// Randevular
-	r.GET("/appointments", h.Appointments)
+	appointments := r.Group("", middleware.RequirePermission(permissions.ManageAppointments))
+	appointments.GET("/appointments", h.Appointments)
+	appointments.GET("/appointments/get/:id", h.GetAppointmentForm)
+	appointments.POST("/appointments/add", h.AddAppointmentForm)
+	appointments.POST("/appointments/update", h.UpdateAppointmentForm)
 
 	// Faturalar
-	r.GET("/invoices", h.Invoices)
+	invoices := r.Group("", middleware.RequirePermission(permissions.ManageInvoices))
+	invoices.GET("/invoices", h.Invoices)
+	invoices.GET("/invoices/:id", h.InvoiceDetail)
+	invoices.GET("/invoices/:id/pdf", h.InvoicePDF)
 
 	// Raporlar
-	r.GET("/reports", h.Reports)
+	reports := r.Group("", middleware.RequirePermission(permissions.ViewReports))
+	reports.GET("/reports", h.Reports)
 
 	// Bildirimler
 	r.GET("/notifications", h.Notifications)
@@ -39,27 +70,146 @@ func Setup(r *gin.Engine, h *handlers.Handler) {
 	// Profil Sayfası
 	r.GET("/profile", h.Profile)
 	r.POST("/profile", h.UpdateProfile)
+	r.POST("/profile/password", h.ChangePassword)
+	r.GET("/profile/avatar", h.Avatar)
+	r.POST("/profile/avatar", h.UploadAvatar)
+	r.DELETE("/profile/avatar", h.DeleteAvatar)
 
 	// Ayarlar Sayfası
-	r.GET("/settings", h.Settings)
+	settings := r.Group("", middleware.RequirePermission(permissions.ManageSettings))
+	settings.GET("/settings", h.Settings)
+	settings.POST("/settings/general", h.UpdateGeneralSettings)
+	settings.POST("/settings/notifications", h.UpdateNotificationSettings)
+	settings.POST("/settings/appearance", h.UpdateAppearanceSettings)
+	settings.POST("/settings/backup", h.UpdateBackupSettings)
+
+	// Kullanıcı Yönetimi
+	users := r.Group("", middleware.RequirePermission(permissions.ManageUsers))
+	users.POST("/settings/users", h.CreateUser)
 
 	// API Routes
 	api := r.Group("/api/v1")
 	{
 		// Müşteri API'leri
-		api.GET("/customers", h.GetCustomersAPI)
-		api.POST("/customers", h.CreateCustomer)
+		customersAPI := api.Group("/customers", middleware.RequirePermission(permissions.ManageCustomers))
+		customersAPI.GET("", h.GetCustomersAPI)
+		customersAPI.POST("", h.CreateCustomer)
+		customersAPI.GET("/:id", h.GetCustomerAPI)
+		customersAPI.PUT("/:id", h.UpdateCustomer)
+		customersAPI.DELETE("/:id", h.DeleteCustomer)
+		customersAPI.POST("/:id/restore", h.RestoreCustomer)
+		customersAPI.GET("/:id/balance", h.GetCustomerBalanceAPI)
+		customersAPI.GET("/:id/statement", h.GetCustomerStatementAPI)
+		customersAPI.POST("/:id/payments", h.CreateCustomerPayment)
+		customersAPI.POST("/:id/payments/:entryId/allocations", h.AllocateCustomerPayment)
+		customersAPI.DELETE("/:id/payments/:entryId", h.DeleteCustomerPayment)
+		customersAPI.GET("/:id/activities", h.GetCustomerActivitiesAPI)
+		customersAPI.POST("/:id/messages", h.SendCustomerMessage)
 
 		// Ürün API'leri
-		// api.GET("/products", h.GetProductsAPI)
-		// api.POST("/products", h.CreateProduct)
+		productsAPI := api.Group("/products", middleware.RequirePermission(permissions.ViewProducts))
+		productsAPI.GET("", h.GetProductsAPI)
+		productsAPI.GET("/:id", h.GetProductAPI)
+
+		manageProductsAPI := api.Group("/products", middleware.RequirePermission(permissions.ManageProducts))
+		manageProductsAPI.POST("", h.CreateProduct)
+		manageProductsAPI.PUT("/:id", h.UpdateProduct)
+		manageProductsAPI.DELETE("/:id", h.DeleteProduct)
 
 		// Sipariş API'leri
-		// api.GET("/orders", h.GetOrdersAPI)
-		// api.POST("/orders", h.CreateOrder)
+		ordersAPI := api.Group("/orders", middleware.RequirePermission(permissions.CreateOrders))
+		ordersAPI.GET("", h.GetOrdersAPI)
+		ordersAPI.GET("/:id", h.GetOrderAPI)
+		ordersAPI.POST("", h.CreateOrder)
+		ordersAPI.GET("/:id/items", h.GetOrderItemsAPI)
+
+		manageOrdersAPI := api.Group("/orders", middleware.RequirePermission(permissions.ManageOrders))
+		manageOrdersAPI.PUT("/:id", h.UpdateOrder)
+		manageOrdersAPI.DELETE("/:id", h.DeleteOrder)
+		manageOrdersAPI.PATCH("/:id/status", h.UpdateOrderStatus)
+		manageOrdersAPI.POST("/:id/cancel", h.CancelOrder)
+		manageOrdersAPI.POST("/:id/items", h.CreateOrderItem)
+		manageOrdersAPI.PUT("/:id/items/:itemId", h.UpdateOrderItem)
+		manageOrdersAPI.DELETE("/:id/items/:itemId", h.DeleteOrderItem)
+
+		// Randevu API'leri
+		appointmentsAPI := api.Group("/appointments", middleware.RequirePermission(permissions.ManageAppointments))
+		appointmentsAPI.GET("", h.GetAppointmentsAPI)
+		appointmentsAPI.GET("/:id", h.GetAppointmentAPI)
+		appointmentsAPI.POST("", h.CreateAppointment)
+		appointmentsAPI.PUT("/:id", h.UpdateAppointment)
+		appointmentsAPI.DELETE("/:id", h.DeleteAppointment)
+
+		// Fatura API'leri
+		invoicesAPI := api.Group("/invoices", middleware.RequirePermission(permissions.ManageInvoices))
+		invoicesAPI.GET("", h.GetInvoicesAPI)
+		invoicesAPI.GET("/:id", h.GetInvoiceAPI)
+		invoicesAPI.POST("", h.CreateInvoice)
+		invoicesAPI.PUT("/:id", h.UpdateInvoice)
+		invoicesAPI.DELETE("/:id", h.DeleteInvoice)
+		invoicesAPI.POST("/:id/issue", h.IssueInvoice)
+		invoicesAPI.POST("/:id/pay", h.PayInvoice)
+		invoicesAPI.POST("/:id/void", h.VoidInvoice)
+		invoicesAPI.POST("/:id/send", h.SendInvoice)
+		invoicesAPI.POST("/:id/credit-note", h.CreateCreditNote)
+		api.POST("/orders/:id/invoice", middleware.RequirePermission(permissions.ManageInvoices), h.CreateInvoiceFromOrder)
 
 		// Muhasebe API'leri
-		// api.GET("/transactions", h.GetTransactionsAPI)
-		// api.POST("/transactions", h.CreateTransaction)
+		transactionsAPI := api.Group("/transactions", middleware.RequirePermission(permissions.ViewAccounting))
+		transactionsAPI.GET("", h.GetTransactionsAPI)
+		transactionsAPI.GET("/:id", h.GetTransactionAPI)
+		transactionsAPI.POST("", h.CreateTransaction)
+		transactionsAPI.PUT("/:id", h.UpdateTransaction)
+		transactionsAPI.DELETE("/:id", h.DeleteTransaction)
+
+		// Döviz kuru API'leri
+		ratesAPI := api.Group("/exchange-rates", middleware.RequirePermission(permissions.ViewAccounting))
+		ratesAPI.GET("", h.GetExchangeRatesAPI)
+		ratesAPI.POST("", h.SaveExchangeRate)
+		ratesAPI.POST("/import", h.ImportExchangeRates)
+		ratesAPI.DELETE("/:id", h.DeleteExchangeRate)
+
+		// Bildirim API'leri; her kullanıcı yalnızca kendi bildirimlerini görür
+		notificationsAPI := api.Group("/notifications")
+		notificationsAPI.GET("", h.GetNotificationsAPI)
+		notificationsAPI.GET("/stream", h.NotificationStream)
+		notificationsAPI.POST("/read-all", h.MarkAllNotificationsRead)
+		notificationsAPI.POST("/:id/read", h.MarkNotificationRead)
+		notificationsAPI.DELETE("/:id", h.DeleteNotification)
+
+		// Genel arama; sonuçlar kullanıcının yetkilerine göre süzülür
+		api.GET("/search", h.Search)
+
+		// Rapor API'leri
+		reportsAPI := api.Group("/reports", middleware.RequirePermission(permissions.ViewReports))
+		reportsAPI.GET("/financial", h.GetFinancialReportAPI)
+		reportsAPI.GET("/aging", h.GetAgingReportAPI)
+		reportsAPI.GET("/dunning", h.GetDunningNoticesAPI)
+
+		// Ayar API'leri
+		settingsAPI := api.Group("/settings", middleware.RequirePermission(permissions.ManageSettings))
+		settingsAPI.GET("", h.GetSettingsAPI)
+		settingsAPI.PUT("", h.UpdateSettingsAPI)
+
+		// Yedek API'leri; yalnızca kurulum sahibi kullanabilir
+		backupsAPI := api.Group("/backups", middleware.RequirePermission(permissions.ManageSettings))
+		backupsAPI.GET("", h.GetBackupsAPI)
+		backupsAPI.POST("", h.CreateBackup)
+		backupsAPI.POST("/restore", h.UploadRestoreBackup)
+		backupsAPI.GET("/:name", h.DownloadBackup)
+		backupsAPI.DELETE("/:name", h.DeleteBackup)
+		backupsAPI.POST("/:name/restore", h.RestoreBackup)
+
+		// Mesaj şablonu API'leri
+		templatesAPI := api.Group("/message-templates", middleware.RequirePermission(permissions.ManageSettings))
+		templatesAPI.GET("", h.GetMessageTemplatesAPI)
+		templatesAPI.PUT("/:key", h.UpdateMessageTemplate)
+
+		// Kullanıcı API'leri
+		usersAPI := api.Group("/users", middleware.RequirePermission(permissions.ManageUsers))
+		usersAPI.GET("", h.GetUsersAPI)
+		usersAPI.POST("", h.CreateUser)
+		usersAPI.PUT("/:id/role", h.UpdateUserRole)
+		usersAPI.DELETE("/:id", h.DeleteUser)
 	}
 }
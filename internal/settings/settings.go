@@ -0,0 +1,262 @@
+// Package settings işletme ayarlarını tipli olarak okur, doğrular ve kaydeder.
+//
+// Ayarlar settings tablosunda işletme başına anahtar/değer olarak saklanır; kaydedilmemiş ya da
+// okunamayan değerler için varsayılanlar kullanılır. Okunan ayarlar işlem içinde önbellekte
+// tutulur ve Save ile kaydedildiğinde önbellekten düşürülür.
+package settings
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/listquery"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/money"
+)
+
+// Tarih biçimleri
+const (
+	DateFormatDots  = "dd.mm.yyyy" // 17.10.2024
+	DateFormatSlash = "dd/mm/yyyy" // 17/10/2024
+	DateFormatUS    = "mm/dd/yyyy" // 10/17/2024
+	DateFormatISO   = "yyyy-mm-dd" // 2024-10-17
+)
+
+// dateLayouts tarih biçimlerinin Go karşılıkları
+var dateLayouts = map[string]string{
+	DateFormatDots:  "02.01.2006",
+	DateFormatSlash: "02/01/2006",
+	DateFormatUS:    "01/02/2006",
+	DateFormatISO:   "2006-01-02",
+}
+
+// Tema modları
+const (
+	ThemeLight  = "light"
+	ThemeDark   = "dark"
+	ThemeSystem = "system" // Tarayıcının tercihine uyar
+)
+
+// ThemeColors seçilebilen ana renkler
+var ThemeColors = map[string]string{
+	"primary": "#009ef7",
+	"success": "#50cd89",
+	"danger":  "#f1416c",
+	"warning": "#ffc700",
+	"info":    "#7239ea",
+}
+
+// Dashboard kartları
+const (
+	CardCustomers = "customers"
+	CardProducts  = "products"
+	CardRevenue   = "revenue"
+	CardOrders    = "orders"
+)
+
+// DashboardCards dashboard'da gösterilebilen kartlar, sayfadaki sırasıyla
+var DashboardCards = []string{CardCustomers, CardProducts, CardRevenue, CardOrders}
+
+// Yedekleme sıklıkları
+const (
+	BackupDaily   = "daily"
+	BackupWeekly  = "weekly"
+	BackupMonthly = "monthly"
+)
+
+// MaxAutoLogoutMinutes otomatik çıkış süresinin üst sınırı (bir gün)
+const MaxAutoLogoutMinutes = 24 * 60
+
+// MaxBackupKeep saklanabilecek en fazla yedek sayısı
+const MaxBackupKeep = 100
+
+// Settings bir işletmenin ayarları
+type Settings struct {
+	Currency          money.Currency `json:"currency"`            // Ana para birimi
+	DateFormat        string         `json:"date_format"`         // Sayfalardaki tarih biçimi
+	TaxRate           int            `json:"tax_rate"`            // Fatura satırlarında varsayılan KDV oranı
+	LowStockLevel     int            `json:"low_stock_level"`     // Ürüne özel eşik yoksa düşük stok seviyesi
+	PageSize          int            `json:"page_size"`           // Liste sayfalarında sayfa başına kayıt
+	AutoLogoutMinutes int            `json:"auto_logout_minutes"` // Hareketsiz oturumun kapanma süresi; 0 kapalı
+	ThemeMode         string         `json:"theme_mode"`
+	ThemeColor        string         `json:"theme_color"`
+	DashboardCards    []string       `json:"dashboard_cards"` // Dashboard'da gösterilen kartlar
+	ReminderEmail     bool           `json:"reminder_email"`  // Randevu hatırlatmaları e-postayla da gönderilir
+	ReminderSMS       bool           `json:"reminder_sms"`    // Randevu hatırlatmaları SMS ile de gönderilir
+	DunningStages     string         `json:"dunning_stages"`  // Ödeme hatırlatması gecikme günleri (7,30,60)
+	DunningSMS        bool           `json:"dunning_sms"`     // Ödeme hatırlatmaları SMS ile de gönderilir
+	BackupEnabled     bool           `json:"backup_enabled"`
+	BackupFrequency   string         `json:"backup_frequency"`
+	BackupKeep        int            `json:"backup_keep"` // Saklanacak en fazla otomatik yedek
+}
+
+// Defaults hiç ayar kaydetmemiş işletmenin ayarlarını döndürür
+func Defaults() Settings {
+	return Settings{
+		Currency:          money.DefaultCurrency,
+		DateFormat:        DateFormatDots,
+		TaxRate:           models.DefaultKDVRate,
+		LowStockLevel:     database.DefaultLowStockLevel,
+		PageSize:          listquery.DefaultLimit,
+		AutoLogoutMinutes: 0,
+		ThemeMode:         ThemeLight,
+		ThemeColor:        "primary",
+		DashboardCards:    append([]string(nil), DashboardCards...),
+		ReminderEmail:     true,
+		ReminderSMS:       false,
+		DunningStages:     database.DefaultDunningStages,
+		DunningSMS:        false,
+		BackupEnabled:     true,
+		BackupFrequency:   BackupWeekly,
+		BackupKeep:        5,
+	}
+}
+
+// Validate ayarları doğrular ve saklanacak biçime getirir
+func (s *Settings) Validate() error {
+	currency, err := money.ParseCurrency(string(s.Currency))
+	if err != nil {
+		return fmt.Errorf("desteklenmeyen para birimi: %s", s.Currency)
+	}
+	s.Currency = currency
+
+	if _, ok := dateLayouts[s.DateFormat]; !ok {
+		return fmt.Errorf("geçersiz tarih biçimi: %s", s.DateFormat)
+	}
+	if !models.IsValidKDVRate(s.TaxRate) {
+		return fmt.Errorf("geçersiz KDV oranı: %%%d (geçerli oranlar: %%1, %%10, %%20)", s.TaxRate)
+	}
+	if s.LowStockLevel < 0 {
+		return errors.New("düşük stok uyarı seviyesi sıfır veya pozitif bir sayı olmalıdır")
+	}
+	if s.PageSize <= 0 || s.PageSize > listquery.MaxLimit {
+		return fmt.Errorf("geçersiz sayfa başına öğe sayısı: %d", s.PageSize)
+	}
+	if s.AutoLogoutMinutes < 0 || s.AutoLogoutMinutes > MaxAutoLogoutMinutes {
+		return fmt.Errorf("otomatik çıkış süresi 0 ile %d dakika arasında olmalıdır", MaxAutoLogoutMinutes)
+	}
+	switch s.ThemeMode {
+	case ThemeLight, ThemeDark, ThemeSystem:
+	default:
+		return fmt.Errorf("geçersiz tema modu: %s", s.ThemeMode)
+	}
+	if _, ok := ThemeColors[s.ThemeColor]; !ok {
+		return fmt.Errorf("geçersiz tema rengi: %s", s.ThemeColor)
+	}
+
+	cards := []string{}
+	for _, card := range DashboardCards {
+		for _, selected := range s.DashboardCards {
+			if selected == card {
+				cards = append(cards, card)
+				break
+			}
+		}
+	}
+	if len(cards) != len(uniq(s.DashboardCards)) {
+		return fmt.Errorf("geçersiz dashboard kartı: %s", strings.Join(s.DashboardCards, ", "))
+	}
+	s.DashboardCards = cards
+
+	stages, err := ParseStages(s.DunningStages)
+	if err != nil {
+		return errors.New("ödeme hatırlatma kademeleri virgülle ayrılmış pozitif gün sayıları olmalıdır")
+	}
+	s.DunningStages = FormatStages(stages)
+
+	switch s.BackupFrequency {
+	case BackupDaily, BackupWeekly, BackupMonthly:
+	default:
+		return fmt.Errorf("geçersiz yedekleme sıklığı: %s", s.BackupFrequency)
+	}
+	if s.BackupKeep < 1 || s.BackupKeep > MaxBackupKeep {
+		return fmt.Errorf("yedek sayısı 1 ile %d arasında olmalıdır", MaxBackupKeep)
+	}
+	return nil
+}
+
+// DateLayout tarih biçiminin Go karşılığı; şablonlarda {{.Date.Format $.settings.DateLayout}}
+func (s Settings) DateLayout() string {
+	if layout, ok := dateLayouts[s.DateFormat]; ok {
+		return layout
+	}
+	return dateLayouts[DateFormatDots]
+}
+
+// DateTimeLayout tarih ve saat biçiminin Go karşılığı
+func (s Settings) DateTimeLayout() string {
+	return s.DateLayout() + " 15:04"
+}
+
+// ShowCard kart dashboard'da gösterilecekse true döner
+func (s Settings) ShowCard(card string) bool {
+	for _, c := range s.DashboardCards {
+		if c == card {
+			return true
+		}
+	}
+	return false
+}
+
+// ThemeColorValue ana rengin CSS değeri
+func (s Settings) ThemeColorValue() string {
+	if color, ok := ThemeColors[s.ThemeColor]; ok {
+		return color
+	}
+	return ThemeColors["primary"]
+}
+
+// AutoLogout hareketsiz oturumun kapanacağı süre; 0 ise oturum yalnızca süresi dolunca kapanır
+func (s Settings) AutoLogout() time.Duration {
+	return time.Duration(s.AutoLogoutMinutes) * time.Minute
+}
+
+// ParseStages virgülle ayrılmış gecikme günlerini küçükten büyüğe sıralı ve tekrarsız döndürür;
+// boş değer hatırlatmaların kapalı olduğunu belirtir
+func ParseStages(value string) ([]int, error) {
+	var stages []int
+	seen := make(map[int]bool)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		days, err := strconv.Atoi(part)
+		if err != nil || days <= 0 {
+			return nil, fmt.Errorf("geçersiz gecikme günü: %q", part)
+		}
+		if !seen[days] {
+			seen[days] = true
+			stages = append(stages, days)
+		}
+	}
+
+	sort.Ints(stages)
+	return stages, nil
+}
+
+// FormatStages kademeleri ayarda saklanan biçime çevirir
+func FormatStages(stages []int) string {
+	parts := make([]string, len(stages))
+	for i, stage := range stages {
+		parts[i] = strconv.Itoa(stage)
+	}
+	return strings.Join(parts, ",")
+}
+
+func uniq(values []string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,243 @@
+package settings_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/database/dbtest"
+	"github.com/umutaraz/tradesman-app/internal/money"
+	"github.com/umutaraz/tradesman-app/internal/settings"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(s *settings.Settings)
+		wantErr bool
+	}{
+		{"defaults", func(s *settings.Settings) {}, false},
+		{"lowercase currency", func(s *settings.Settings) { s.Currency = "eur" }, false},
+		{"unsupported currency", func(s *settings.Settings) { s.Currency = "CHF" }, true},
+		{"unknown date format", func(s *settings.Settings) { s.DateFormat = "dd-mm-yy" }, true},
+		{"invalid tax rate", func(s *settings.Settings) { s.TaxRate = 18 }, true},
+		{"negative stock level", func(s *settings.Settings) { s.LowStockLevel = -1 }, true},
+		{"page size too large", func(s *settings.Settings) { s.PageSize = 1000 }, true},
+		{"auto logout longer than a day", func(s *settings.Settings) { s.AutoLogoutMinutes = 24*60 + 1 }, true},
+		{"unknown theme", func(s *settings.Settings) { s.ThemeMode = "blue" }, true},
+		{"unknown theme color", func(s *settings.Settings) { s.ThemeColor = "#123456" }, true},
+		{"unknown card", func(s *settings.Settings) { s.DashboardCards = []string{"weather"} }, true},
+		{"no cards", func(s *settings.Settings) { s.DashboardCards = nil }, false},
+		{"bad dunning stages", func(s *settings.Settings) { s.DunningStages = "7,x" }, true},
+		{"unknown backup frequency", func(s *settings.Settings) { s.BackupFrequency = "hourly" }, true},
+		{"no backups kept", func(s *settings.Settings) { s.BackupKeep = 0 }, true},
+	}
+	for _, tt := range tests {
+		s := settings.Defaults()
+		tt.change(&s)
+		if err := s.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+
+	// Doğrulama değerleri saklanacak biçime getirir
+	s := settings.Defaults()
+	s.Currency = " usd "
+	s.DashboardCards = []string{settings.CardOrders, settings.CardCustomers, settings.CardOrders}
+	s.DunningStages = "30, 7"
+	if err := s.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if s.Currency != money.USD || len(s.DashboardCards) != 2 || s.DashboardCards[0] != settings.CardCustomers ||
+		s.DunningStages != "7,30" {
+		t.Errorf("normalized = %+v", s)
+	}
+}
+
+func TestDateLayout(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{settings.DateFormatDots, "02.01.2006"},
+		{settings.DateFormatSlash, "02/01/2006"},
+		{settings.DateFormatUS, "01/02/2006"},
+		{settings.DateFormatISO, "2006-01-02"},
+		{"bilinmeyen", "02.01.2006"},
+	}
+	for _, tt := range tests {
+		s := settings.Settings{DateFormat: tt.format}
+		if got := s.DateLayout(); got != tt.want {
+			t.Errorf("DateLayout(%q) = %q, want %q", tt.format, got, tt.want)
+		}
+		if got := s.DateTimeLayout(); got != tt.want+" 15:04" {
+			t.Errorf("DateTimeLayout(%q) = %q", tt.format, got)
+		}
+	}
+}
+
+func TestParseStages(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{"7,30,60", "7,30,60", false},
+		{" 60, 7 ,30,7", "7,30,60", false},
+		{"", "", false},
+		{"7,,30", "7,30", false},
+		{"7,abc", "", true},
+		{"0,30", "", true},
+		{"-7", "", true},
+	}
+	for _, tt := range tests {
+		stages, err := settings.ParseStages(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseStages(%q) err = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got := settings.FormatStages(stages); got != tt.want {
+			t.Errorf("ParseStages(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestStore(t *testing.T) {
+	db := dbtest.New(t)
+	userID := dbtest.User(t, db, "sahip@example.com")
+	otherID := dbtest.User(t, db, "diger@example.com")
+	store := settings.NewStore(db)
+
+	s, err := store.Get(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.DateFormat != settings.DateFormatDots || s.TaxRate != 20 || len(s.DashboardCards) != len(settings.DashboardCards) {
+		t.Errorf("defaults = %+v", s)
+	}
+
+	s.DateFormat = settings.DateFormatISO
+	s.AutoLogoutMinutes = 30
+	s.DashboardCards = []string{settings.CardRevenue}
+	if err := store.Save(userID, s); err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.Get(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.DateFormat != settings.DateFormatISO || got.AutoLogout().Minutes() != 30 || !got.ShowCard(settings.CardRevenue) ||
+		got.ShowCard(settings.CardCustomers) {
+		t.Errorf("saved = %+v", got)
+	}
+
+	// Döndürülen kopyanın değiştirilmesi önbelleği bozmaz
+	got.DashboardCards[0] = settings.CardOrders
+	if again, _ := store.Get(userID); !again.ShowCard(settings.CardRevenue) {
+		t.Errorf("cached cards changed through a returned copy: %v", again.DashboardCards)
+	}
+
+	// Geçersiz ayar kaydedilmez
+	bad := settings.Defaults()
+	bad.TaxRate = 18
+	if err := store.Save(userID, bad); err == nil {
+		t.Error("saving an invalid tax rate succeeded")
+	}
+
+	// Ayarlar işletmeye özeldir
+	if other, _ := store.Get(otherID); other.DateFormat != settings.DateFormatDots {
+		t.Errorf("other business date format = %s", other.DateFormat)
+	}
+}
+
+func TestStoreIgnoresInvalidValues(t *testing.T) {
+	db := dbtest.New(t)
+	userID := dbtest.User(t, db, "sahip@example.com")
+	for key, value := range map[string]string{
+		database.SettingTaxRate:     "18",
+		database.SettingPageSize:    "abc",
+		database.SettingDateFormat:  settings.DateFormatSlash,
+		database.SettingReminderSMS: "true",
+	} {
+		if err := db.SetSetting(userID, key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Bozuk değerlerin yerine varsayılan kalır, diğerleri okunur
+	store := settings.NewStore(db)
+	s, err := store.Get(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defaults := settings.Defaults()
+	if s.TaxRate != defaults.TaxRate || s.PageSize != defaults.PageSize || s.DateFormat != settings.DateFormatSlash || !s.ReminderSMS {
+		t.Errorf("settings = %+v", s)
+	}
+
+	// Doğrudan yazılan değer Invalidate çağrılana kadar önbellekten okunur
+	if err := db.SetSetting(userID, database.SettingDateFormat, settings.DateFormatISO); err != nil {
+		t.Fatal(err)
+	}
+	if s, _ := store.Get(userID); s.DateFormat != settings.DateFormatSlash {
+		t.Errorf("date format before Invalidate = %s", s.DateFormat)
+	}
+	store.Invalidate(userID)
+	if s, _ := store.Get(userID); s.DateFormat != settings.DateFormatISO {
+		t.Errorf("date format after Invalidate = %s", s.DateFormat)
+	}
+}
+
+func TestStoreLocksCurrencyOnceRecordsExist(t *testing.T) {
+	db := dbtest.New(t)
+	userID := dbtest.User(t, db, "sahip@example.com")
+	store := settings.NewStore(db)
+	customer, err := db.Exec("INSERT INTO customers (user_id, name) VALUES (?, 'Ayşe Demir')", userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	customerID, _ := customer.LastInsertId()
+
+	save := func(currency money.Currency) error {
+		s, err := store.Get(userID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.Currency = currency
+		return store.Save(userID, s)
+	}
+
+	// Kayıt yokken ve yalnızca taslak fatura varken ana para birimi değişebilir
+	if err := save(money.USD); err != nil {
+		t.Fatalf("without records: %v", err)
+	}
+	if _, err := db.Exec(`
+		INSERT INTO invoices (user_id, customer_id, status, invoice_date, subtotal, tax_amount, total_amount)
+		VALUES (?, ?, 'draft', CURRENT_TIMESTAMP, 0, 0, 0)
+	`, userID, customerID); err != nil {
+		t.Fatal(err)
+	}
+	if err := save(money.EUR); err != nil {
+		t.Fatalf("with a draft invoice: %v", err)
+	}
+
+	if _, err := db.Exec(`
+		INSERT INTO ledger_entries (user_id, customer_id, entry_type, source, amount, description, entry_date)
+		VALUES (?, ?, 'credit', 'payment', 10000, 'Tahsilat', CURRENT_TIMESTAMP)
+	`, userID, customerID); err != nil {
+		t.Fatal(err)
+	}
+	if err := save(money.TRY); !errors.Is(err, settings.ErrCurrencyLocked) {
+		t.Errorf("with ledger entries: err = %v, want ErrCurrencyLocked", err)
+	}
+
+	// Aynı para birimiyle diğer ayarlar kaydedilebilir
+	s, _ := store.Get(userID)
+	s.TaxRate = 10
+	if err := store.Save(userID, s); err != nil {
+		t.Errorf("saving other settings: %v", err)
+	}
+	if got, _ := store.Get(userID); got.Currency != money.EUR || got.TaxRate != 10 {
+		t.Errorf("settings = %s, %d; want EUR, 10", got.Currency, got.TaxRate)
+	}
+}
@@ -0,0 +1,224 @@
+package settings
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/umutaraz/tradesman-app/internal/database"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/money"
+)
+
+// ErrCurrencyLocked cari hesap hareketi ya da kesilmiş faturası olan işletmenin ana para birimini
+// değiştirmeye çalışınca döner. Bakiyeler, vade raporları ve tahsilat dağıtımları ana para biriminde
+// saklandığından mevcut tutarlar yeni birimde yanlış okunurdu.
+var ErrCurrencyLocked = errors.New("cari hesap hareketi ya da kesilmiş fatura varken ana para birimi değiştirilemez")
+
+// field bir ayarın settings tablosundaki anahtarı ve metne çevrimi
+type field struct {
+	key    string
+	encode func(s *Settings) string
+	decode func(s *Settings, value string) error
+}
+
+var fields = []field{
+	{database.SettingCurrency,
+		func(s *Settings) string { return string(s.Currency) },
+		func(s *Settings, v string) error { s.Currency = money.Currency(v); return nil }},
+	{database.SettingDateFormat,
+		func(s *Settings) string { return s.DateFormat },
+		func(s *Settings, v string) error { s.DateFormat = v; return nil }},
+	{database.SettingTaxRate,
+		func(s *Settings) string { return strconv.Itoa(s.TaxRate) },
+		func(s *Settings, v string) (err error) { s.TaxRate, err = strconv.Atoi(v); return }},
+	{database.SettingLowStockLevel,
+		func(s *Settings) string { return strconv.Itoa(s.LowStockLevel) },
+		func(s *Settings, v string) (err error) { s.LowStockLevel, err = strconv.Atoi(v); return }},
+	{database.SettingPageSize,
+		func(s *Settings) string { return strconv.Itoa(s.PageSize) },
+		func(s *Settings, v string) (err error) { s.PageSize, err = strconv.Atoi(v); return }},
+	{database.SettingAutoLogout,
+		func(s *Settings) string { return strconv.Itoa(s.AutoLogoutMinutes) },
+		func(s *Settings, v string) (err error) { s.AutoLogoutMinutes, err = strconv.Atoi(v); return }},
+	{database.SettingThemeMode,
+		func(s *Settings) string { return s.ThemeMode },
+		func(s *Settings, v string) error { s.ThemeMode = v; return nil }},
+	{database.SettingThemeColor,
+		func(s *Settings) string { return s.ThemeColor },
+		func(s *Settings, v string) error { s.ThemeColor = v; return nil }},
+	{database.SettingDashboardCards,
+		func(s *Settings) string { return strings.Join(s.DashboardCards, ",") },
+		func(s *Settings, v string) error {
+			s.DashboardCards = []string{}
+			for _, card := range strings.Split(v, ",") {
+				if card = strings.TrimSpace(card); card != "" {
+					s.DashboardCards = append(s.DashboardCards, card)
+				}
+			}
+			return nil
+		}},
+	{database.SettingReminderEmail,
+		func(s *Settings) string { return strconv.FormatBool(s.ReminderEmail) },
+		func(s *Settings, v string) (err error) { s.ReminderEmail, err = strconv.ParseBool(v); return }},
+	{database.SettingReminderSMS,
+		func(s *Settings) string { return strconv.FormatBool(s.ReminderSMS) },
+		func(s *Settings, v string) (err error) { s.ReminderSMS, err = strconv.ParseBool(v); return }},
+	{database.SettingDunningStages,
+		func(s *Settings) string { return s.DunningStages },
+		func(s *Settings, v string) error { s.DunningStages = v; return nil }},
+	{database.SettingDunningSMS,
+		func(s *Settings) string { return strconv.FormatBool(s.DunningSMS) },
+		func(s *Settings, v string) (err error) { s.DunningSMS, err = strconv.ParseBool(v); return }},
+	{database.SettingBackupEnabled,
+		func(s *Settings) string { return strconv.FormatBool(s.BackupEnabled) },
+		func(s *Settings, v string) (err error) { s.BackupEnabled, err = strconv.ParseBool(v); return }},
+	{database.SettingBackupFrequency,
+		func(s *Settings) string { return s.BackupFrequency },
+		func(s *Settings, v string) error { s.BackupFrequency = v; return nil }},
+	{database.SettingBackupKeep,
+		func(s *Settings) string { return strconv.Itoa(s.BackupKeep) },
+		func(s *Settings, v string) (err error) { s.BackupKeep, err = strconv.Atoi(v); return }},
+}
+
+// Store işletme ayarlarını veritabanından okur ve önbellekte tutar. Ayarlar yalnızca Save ile
+// değiştirilmelidir; veritabanına doğrudan yazılan değerler Invalidate çağrılana kadar görünmez.
+type Store struct {
+	db    *database.DB
+	mu    sync.RWMutex
+	cache map[int]Settings
+}
+
+// NewStore veritabanına bağlı yeni bir ayar deposu oluşturur
+func NewStore(db *database.DB) *Store {
+	return &Store{db: db, cache: map[int]Settings{}}
+}
+
+// Get işletmenin ayarlarını döndürür. Kaydedilmemiş ya da geçersiz değerlerin yerine varsayılan
+// kullanılır; bir ayarın bozuk olması diğerlerini etkilemez.
+func (st *Store) Get(businessID int) (Settings, error) {
+	st.mu.RLock()
+	s, ok := st.cache[businessID]
+	st.mu.RUnlock()
+	if ok {
+		return s.clone(), nil
+	}
+
+	s, err := st.load(businessID)
+	if err != nil {
+		return Settings{}, err
+	}
+
+	st.mu.Lock()
+	st.cache[businessID] = s
+	st.mu.Unlock()
+	return s.clone(), nil
+}
+
+// Save ayarları doğrular, tek işlemde kaydeder ve önbellekten düşürür. Ana para birimi yalnızca
+// işletmenin ana para biriminde saklanmış kaydı yokken değiştirilebilir; aksi halde ErrCurrencyLocked döner.
+func (st *Store) Save(businessID int, s Settings) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	current, err := st.Get(businessID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := st.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if s.Currency != current.Currency {
+		var locked bool
+		err := tx.QueryRow(`
+			SELECT EXISTS (SELECT 1 FROM ledger_entries WHERE user_id = ?)
+			    OR EXISTS (SELECT 1 FROM invoices WHERE user_id = ? AND status != ?)
+		`, businessID, businessID, models.InvoiceDraft).Scan(&locked)
+		if err != nil {
+			return err
+		}
+		if locked {
+			return ErrCurrencyLocked
+		}
+	}
+
+	for _, f := range fields {
+		_, err := tx.Exec(`
+			INSERT INTO settings (user_id, key, value, updated_at) VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+			ON CONFLICT (user_id, key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at
+		`, businessID, f.key, f.encode(&s))
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	st.Invalidate(businessID)
+	return nil
+}
+
+// Invalidate işletmenin önbellekteki ayarlarını düşürür; sonraki Get veritabanından okur
+func (st *Store) Invalidate(businessID int) {
+	st.mu.Lock()
+	delete(st.cache, businessID)
+	st.mu.Unlock()
+}
+
+// InvalidateAll önbelleği tümüyle boşaltır; veritabanı yedekten geri yüklendiğinde kullanılır
+func (st *Store) InvalidateAll() {
+	st.mu.Lock()
+	st.cache = map[int]Settings{}
+	st.mu.Unlock()
+}
+
+func (st *Store) load(businessID int) (Settings, error) {
+	rows, err := st.db.Query("SELECT key, value FROM settings WHERE user_id = ?", businessID)
+	if err != nil {
+		return Settings{}, err
+	}
+	defer rows.Close()
+
+	values := map[string]string{}
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return Settings{}, err
+		}
+		values[key] = value
+	}
+	if err := rows.Err(); err != nil {
+		return Settings{}, err
+	}
+
+	// Değerler tek tek uygulanır; varsayılanlar geçerli olduğundan bir değer doğrulamayı
+	// bozuyorsa sorun o değerdedir ve yerine varsayılan kalır
+	s := Defaults()
+	for _, f := range fields {
+		value, ok := values[f.key]
+		if !ok {
+			continue
+		}
+		candidate := s.clone()
+		if err := f.decode(&candidate, value); err != nil {
+			continue
+		}
+		if err := candidate.Validate(); err != nil {
+			continue
+		}
+		s = candidate
+	}
+	return s, nil
+}
+
+// clone dilimleri paylaşmayan bir kopya döndürür
+func (s Settings) clone() Settings {
+	s.DashboardCards = append([]string{}, s.DashboardCards...)
+	return s
+}
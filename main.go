@@ -1,23 +1,53 @@
 package main
 
 import (
+	"context"
 	"html/template"
 	"log"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/umutaraz/tradesman-app/internal/backup"
 	"github.com/umutaraz/tradesman-app/internal/config"
 	"github.com/umutaraz/tradesman-app/internal/database"
 	"github.com/umutaraz/tradesman-app/internal/handlers"
+	"github.com/umutaraz/tradesman-app/internal/inventory"
+	"github.com/umutaraz/tradesman-app/internal/mailer"
+	"github.com/umutaraz/tradesman-app/internal/messaging"
 	"github.com/umutaraz/tradesman-app/internal/middleware"
+	"github.com/umutaraz/tradesman-app/internal/models"
+	"github.com/umutaraz/tradesman-app/internal/money"
+	"github.com/umutaraz/tradesman-app/internal/notify"
+	"github.com/umutaraz/tradesman-app/internal/outbox"
+	"github.com/umutaraz/tradesman-app/internal/receivables"
+	"github.com/umutaraz/tradesman-app/internal/reminder"
+	"github.com/umutaraz/tradesman-app/internal/repository"
 	"github.com/umutaraz/tradesman-app/internal/routes"
+	"github.com/umutaraz/tradesman-app/internal/settings"
 )
 
 func main() {
 	// Konfigürasyon yükle
 	cfg := config.Load()
 
+	// Şema yönetimi: tradesman-app migrate status|up|down [n]|to N
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(cfg, os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// Sistem yöneticileri: tradesman-app admin list|grant <e-posta>|revoke <e-posta>
+	if len(os.Args) > 1 && os.Args[1] == "admin" {
+		if err := runAdmin(cfg, os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	// Veritabanını başlat
 	db, err := database.Initialize(cfg.DatabasePath)
 	if err != nil {
@@ -25,6 +55,59 @@ func main() {
 	}
 	defer db.Close()
 
+	// Müşteri, ürün, sipariş ve gelir/gider depoları
+	store := repository.NewSQLite(db.DB)
+
+	// E-posta gönderimi ve giden ileti kuyruğu
+	mail, err := mailer.New(mailer.Config{
+		Driver:   cfg.MailDriver,
+		Dir:      cfg.MailDir,
+		Host:     cfg.SMTPHost,
+		Port:     cfg.SMTPPort,
+		Username: cfg.SMTPUsername,
+		Password: cfg.SMTPPassword,
+		From:     cfg.MailFrom,
+	})
+	if err != nil {
+		log.Fatal("E-posta yapılandırması geçersiz:", err)
+	}
+
+	// Kısa mesaj (SMS/WhatsApp) gönderimi
+	sms, err := messaging.New(messaging.Config{
+		Driver: cfg.SMSDriver,
+		Dir:    cfg.SMSDir,
+		URL:    cfg.SMSURL,
+		Token:  cfg.SMSToken,
+		From:   cfg.SMSFrom,
+	})
+	if err != nil {
+		log.Fatal("SMS yapılandırması geçersiz:", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	worker := outbox.NewWorker(db.DB, cfg.OutboxInterval)
+	worker.Handle(outbox.ChannelEmail, outbox.EmailSender(mail))
+	worker.Handle(outbox.ChannelSMS, outbox.MessageSender(sms))
+	go worker.Run(ctx)
+
+	// İşletme ayarları; hatırlatmalar tarihleri işletmenin biçimiyle yazar
+	prefs := settings.NewStore(db)
+
+	// Bildirimler, düşük stok denetimi, randevu ve ödeme hatırlatmaları
+	notifier := notify.New(db, notify.NewHub())
+	go inventory.NewChecker(db, notifier, cfg.StockCheckInterval).Run(ctx)
+	go reminder.NewScheduler(db, prefs, notifier, cfg.ReminderInterval).Run(ctx)
+	go receivables.NewDunner(db, prefs, notifier, cfg.DunningInterval).Run(ctx)
+
+	// Veritabanı yedekleri
+	backups, err := backup.New(db, backup.Config{Dir: cfg.BackupDir, Compress: cfg.BackupCompress, Key: cfg.BackupKey})
+	if err != nil {
+		log.Fatal("Yedekleme yapılandırması geçersiz:", err)
+	}
+	go backup.NewScheduler(backups, prefs, cfg.BackupInterval).Run(ctx)
+
 	// Gin router'ı başlat
 	r := gin.Default()
 
@@ -32,27 +115,21 @@ func main() {
 	r.Static("/assets", "./assets")
 
 	// Şablon fonksiyonlarını tanımla
-	r.SetFuncMap(template.FuncMap{
-		"now": time.Now,
-		"mul": func(a, b float64) float64 {
-			return a * b
-		},
-		"float64": func(i int) float64 {
-			return float64(i)
-		},
-	})
+	r.SetFuncMap(templateFuncs())
 
 	r.LoadHTMLGlob("templates/*")
 
 	// Middleware'ler
 	r.Use(middleware.Logger())
 	r.Use(middleware.CORS())
+	r.Use(middleware.SameOrigin())
 
 	// Handler'ları başlat
-	h := handlers.New(db)
+	h := handlers.New(db, store, cfg, notifier, prefs, backups)
+	go h.WatchOverdueInvoices(ctx, cfg.InvoiceCheckInterval)
 
 	// Route'ları kaydet
-	routes.Setup(r, h)
+	routes.Setup(r, h, db)
 
 	// Sunucuyu başlat
 	log.Printf("Esnaf Yönetim Uygulaması başlatılıyor... Port: %s", cfg.Port)
@@ -60,3 +137,16 @@ func main() {
 		log.Fatal("Sunucu başlatma hatası:", err)
 	}
 }
+
+// templateFuncs HTML şablonlarında kullanılan fonksiyonları döndürür
+func templateFuncs() template.FuncMap {
+	funcs := template.FuncMap{
+		"now":                time.Now,
+		"orderStatusLabel":   models.OrderStatusLabel,
+		"paymentMethodLabel": models.PaymentMethodLabel,
+	}
+	for name, fn := range money.TemplateFuncs() {
+		funcs[name] = fn
+	}
+	return funcs
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"html/template"
+	"strings"
+	"testing"
+
+	"github.com/umutaraz/tradesman-app/internal/database/dbtest"
+)
+
+func TestErrorTemplate(t *testing.T) {
+	tmpl, err := template.New("").Funcs(templateFuncs()).ParseGlob("templates/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out strings.Builder
+	if err := tmpl.ExecuteTemplate(&out, "error.html", map[string]interface{}{"error": "<Kayıt bulunamadı>"}); err != nil {
+		t.Fatal(err)
+	}
+	// Hata mesajı kaçışlanarak yazılır
+	if !strings.Contains(out.String(), "&lt;Kayıt bulunamadı&gt;") {
+		t.Errorf("error page does not contain the escaped message:\n%s", out.String())
+	}
+}
+
+func TestAdminCommand(t *testing.T) {
+	db := dbtest.New(t)
+	dbtest.User(t, db, "sahip@example.com")
+
+	tests := []struct {
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		{[]string{"list"}, "Sistem yöneticisi yok", false},
+		{[]string{"grant", "Sahip@Example.com"}, "sistem yöneticisi yapıldı", false},
+		{[]string{"list"}, "sahip@example.com", false},
+		{[]string{"revoke", "sahip@example.com"}, "artık sistem yöneticisi değil", false},
+		{[]string{"grant", "yok@example.com"}, "", true},
+		{[]string{"grant"}, "", true},
+		{[]string{"sil"}, "", true},
+	}
+	for _, tt := range tests {
+		var out strings.Builder
+		err := adminCommand(db, &out, tt.args)
+		if (err != nil) != tt.wantErr || !strings.Contains(out.String(), tt.want) {
+			t.Errorf("admin %v = %q, %v; want %q", tt.args, out.String(), err, tt.want)
+		}
+	}
+	if admins, err := db.SystemAdmins(); err != nil || len(admins) != 0 {
+		t.Errorf("admins after revoke = %+v, %v", admins, err)
+	}
+}
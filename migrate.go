@@ -0,0 +1,134 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/umutaraz/tradesman-app/internal/config"
+	"github.com/umutaraz/tradesman-app/internal/database"
+)
+
+const migrateUsage = `Kullanım: tradesman-app migrate <komut>
+
+Komutlar:
+  status     migration'ları ve veritabanındaki durumlarını listeler (veritabanını değiştirmez)
+  up         bekleyen tüm migration'ları uygular
+  down [n]   son n migration'ı geri alır (varsayılan 1); 0001 başlangıç şeması geri alınamaz
+  to N       şemayı N sürümüne getirir (ileri ya da geri, en az 1)`
+
+// runMigrate "migrate" alt komutunu çalıştırır; veritabanı yolu DATABASE_PATH ile belirlenir
+func runMigrate(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	if args[0] == "status" {
+		db, err := database.OpenReadOnly(cfg.DatabasePath)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+		return printMigrationStatus(db)
+	}
+
+	db, err := database.Open(cfg.DatabasePath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	var done []database.Migration
+	direction := "uygulandı"
+	switch args[0] {
+	case "up":
+		done, err = db.MigrateUp()
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps <= 0 {
+				return fmt.Errorf("geçersiz sürüm sayısı: %s", args[1])
+			}
+		}
+		direction = "geri alındı"
+		done, err = db.MigrateDown(steps)
+	case "to":
+		if len(args) < 2 {
+			return errors.New(migrateUsage)
+		}
+		version, convErr := strconv.Atoi(args[1])
+		if convErr != nil || version < 0 {
+			return fmt.Errorf("geçersiz sürüm: %s", args[1])
+		}
+		current, versionErr := db.SchemaVersion()
+		if versionErr != nil {
+			return versionErr
+		}
+		if version < current {
+			direction = "geri alındı"
+		}
+		done, err = db.MigrateTo(version)
+	default:
+		return errors.New(migrateUsage)
+	}
+
+	for _, m := range done {
+		fmt.Printf("%04d_%s %s\n", m.Version, m.Name, direction)
+	}
+	if err != nil {
+		return err
+	}
+	if len(done) == 0 {
+		fmt.Println("Şema zaten güncel")
+	}
+
+	version, err := db.SchemaVersion()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Şema sürümü: %d\n", version)
+	return nil
+}
+
+// printMigrationStatus migration'ları durumlarıyla tablo halinde yazar
+func printMigrationStatus(db *database.DB) error {
+	statuses, err := db.MigrationStatuses()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "SÜRÜM\tAD\tDURUM\tUYGULANMA")
+	for _, s := range statuses {
+		state, appliedAt := "bekliyor", ""
+		if s.Applied {
+			state, appliedAt = "uygulandı", s.AppliedAt.Local().Format("02.01.2006 15:04")
+		}
+		switch {
+		case s.Legacy:
+			state += " (eski şemadan uyarlanacak)"
+		case s.Missing:
+			state += " (dosyası yok)"
+		case s.Modified:
+			state += " (dosya değiştirilmiş)"
+		case !s.Reversible():
+			state += " (geri alınamaz)"
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", s.Version, s.Name, state, appliedAt)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if len(statuses) > 0 && statuses[0].Legacy {
+		fmt.Println("Veritabanı migration sisteminden önceki şemada; ilk açılışta ya da migrate up ile uyarlanacak")
+	}
+
+	version, err := db.SchemaVersion()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Şema sürümü: %d\n", version)
+	return nil
+}